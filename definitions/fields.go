@@ -31,6 +31,10 @@ var fieldArray = []Field{
 		Name: "bids",
 		Type: Type{Expr: "[]", Name: "string"},
 	},
+	{
+		Name: "class",
+		Type: Type{Name: "string"},
+	},
 	{
 		Name: "ctx",
 		Type: Type{Package: "context", Name: "Context"},
@@ -111,6 +115,10 @@ var fieldArray = []Field{
 		Name: "srvf",
 		Type: Type{Package: "types", Name: "ServiceFeatures"},
 	},
+	{
+		Name: "rules",
+		Type: Type{Expr: "[]*", Package: "types", Name: "LifecycleRule"},
+	},
 	{
 		Name: "size",
 		Type: Type{Name: "int64"},
@@ -2,6 +2,7 @@ package definitions
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Xuanwo/gg"
 	log "github.com/sirupsen/logrus"
@@ -38,6 +39,7 @@ func GenerateIterator(path string) {
 		nextFuncName := fmt.Sprintf("Next%sFunc", name)
 		pageStructName := fmt.Sprintf("%sPage", name)
 		iteratorStructName := fmt.Sprintf("%sIterator", name)
+		prefetchedStructName := fmt.Sprintf("prefetched%sPage", name)
 
 		f.AddLineComment(`%s is the func used in iterator.
 
@@ -63,7 +65,17 @@ Notes
 			AddField("index", "int").
 			AddField("done", "bool").
 			AddLine().
-			AddField("o", pageStructName)
+			AddField("o", pageStructName).
+			AddLine().
+			AddLineComment("pages will be set while prefetching, and token is the continuation token of current page.").
+			AddField("pages", "chan "+prefetchedStructName).
+			AddField("token", "string")
+
+		f.AddLineComment("%s is a page fetched in background.", prefetchedStructName)
+		f.NewStruct(prefetchedStructName).
+			AddField("data", "[]"+typ).
+			AddField("token", "string").
+			AddField("err", "error")
 
 		f.NewFunction("New"+iteratorStructName).
 			AddParameter("ctx", "context.Context").
@@ -80,16 +92,32 @@ Notes
 		f.NewFunction("ContinuationToken").
 			WithReceiver("it", "*"+iteratorStructName).
 			AddResult("", "string").
-			AddBody(gg.Return(
-				gg.Call("ContinuationToken").WithOwner("it.o.Status")))
+			AddBody(gg.S(`// Status is owned by the prefetching goroutine, use the token of current page instead.
+if it.pages != nil {
+	return it.token
+}
+// Iterators like merged iterator don't have status.
+if it.o.Status == nil {
+	return ""
+}
+return it.o.Status.ContinuationToken()`))
 
 		f.NewFunction("Next").
 			WithReceiver("it", "*"+iteratorStructName).
 			AddResult("object", typ).
 			AddResult("err", "error").
-			AddBody(
-				gg.LineComment("Consume Data via index."),
-				gg.S(`// Consume Data via index.
+			AddBody(gg.S(`object, err = it.fetch()
+if err != nil && !errors.Is(err, IterateDone) {
+	return nil, fmt.Errorf("iterator next failed: %w", err)
+}
+return`))
+
+		f.AddLineComment("fetch returns the next item without wrapping the error, so that iterators built upon this one will not wrap errors twice.")
+		f.NewFunction("fetch").
+			WithReceiver("it", "*"+iteratorStructName).
+			AddResult("object", typ).
+			AddResult("err", "error").
+			AddBody(gg.S(`// Consume Data via index.
 if it.index < len(it.o.Data) {
 	it.index++
 	return it.o.Data[it.index-1], nil
@@ -102,9 +130,9 @@ if it.done {
 // Reset buf before call next.
 it.o.Data = it.o.Data[:0]
 
-err = it.next(it.ctx ,&it.o)
+err = it.nextPage()
 if err != nil && !errors.Is(err, IterateDone) {
-	return nil, fmt.Errorf("iterator next failed: %w", err)
+	return nil, err
 }
 // Make iterator to done so that we will not fetch from upstream anymore.
 if err != nil {
@@ -117,6 +145,253 @@ if len(it.o.Data) == 0 {
 // Return the first object.
 it.index = 1
 return it.o.Data[0], nil`))
+
+		r := strings.NewReplacer(
+			"$type$", typ,
+			"$page$", pageStructName,
+			"$new$", "New"+iteratorStructName,
+			"$prefetched$", prefetchedStructName,
+		)
+
+		f.AddLineComment("nextPage fetches the next page into it.o.Data.")
+		f.NewFunction("nextPage").
+			WithReceiver("it", "*"+iteratorStructName).
+			AddResult("", "error").
+			AddBody(gg.S(`if it.pages == nil {
+	return it.next(it.ctx, &it.o)
+}
+
+p, ok := <-it.pages
+if !ok {
+	// Prefetching goroutine exits without sending page only if ctx is done.
+	it.done = true
+	if err := it.ctx.Err(); err != nil {
+		return err
+	}
+	return IterateDone
+}
+it.o.Data = p.data
+it.token = p.token
+if p.err != nil && !errors.Is(p.err, IterateDone) {
+	// Prefetching goroutine has exited, fallback to fetch pages synchronously
+	// so that the failed page could be retried.
+	it.pages = nil
+}
+return p.err`))
+
+		f.AddLineComment(`Prefetch enables fetching pages in background while the caller consumes
+current page, at most n pages will be fetched ahead.
+
+ContinuationToken always returns the token of the page being consumed. The
+iterator itself is still not safe for concurrent use.
+
+The prefetching goroutine exits after all pages fetched, an error returned or
+the iterator's context is done, so callers that stop iterating early should
+cancel the context passed to list.`)
+		f.NewFunction("Prefetch").
+			WithReceiver("it", "*"+iteratorStructName).
+			AddParameter("n", "int").
+			AddResult("", "*"+iteratorStructName).
+			AddBody(gg.S(r.Replace(`if n <= 0 || it.done || it.pages != nil {
+	return it
+}
+
+it.token = it.ContinuationToken()
+pages := make(chan $prefetched$, n)
+go func() {
+	defer close(pages)
+
+	page := $page${Status: it.o.Status}
+	for {
+		err := it.next(it.ctx, &page)
+
+		p := $prefetched${data: page.Data, err: err}
+		if page.Status != nil {
+			p.token = page.Status.ContinuationToken()
+		}
+		select {
+		case pages <- p:
+		case <-it.ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+		// Data has been handed over, fetch into a new slice.
+		page.Data = nil
+	}
+}()
+it.pages = pages
+return it`)))
+
+		f.AddLineComment("Collect returns all remaining items in this iterator.")
+		f.NewFunction("Collect").
+			WithReceiver("it", "*"+iteratorStructName).
+			AddResult("", "[]"+typ).
+			AddResult("", "error").
+			AddBody(gg.S(r.Replace(`items := make([]$type$, 0)
+for {
+	o, err := it.Next()
+	if errors.Is(err, IterateDone) {
+		return items, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, o)
+}`)))
+
+		f.AddLineComment(`ForEach calls fn for every remaining item in this iterator.
+
+ForEach stops at the first error returned by fn or while ctx is done.`)
+		f.NewFunction("ForEach").
+			WithReceiver("it", "*"+iteratorStructName).
+			AddParameter("ctx", "context.Context").
+			AddParameter("fn", "func("+typ+") error").
+			AddResult("", "error").
+			AddBody(gg.S(`for {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	o, err := it.Next()
+	if errors.Is(err, IterateDone) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = fn(o); err != nil {
+		return err
+	}
+}`))
+
+		f.AddLineComment("Filter returns an iterator which only contains items that fn returns true.")
+		f.NewFunction("Filter").
+			WithReceiver("it", "*"+iteratorStructName).
+			AddParameter("fn", "func("+typ+") bool").
+			AddResult("", "*"+iteratorStructName).
+			AddBody(gg.S(r.Replace(`return $new$(it.ctx, func(ctx context.Context, page *$page$) error {
+	for {
+		o, err := it.fetch()
+		if err != nil {
+			return err
+		}
+		if fn(o) {
+			page.Data = append(page.Data, o)
+			return nil
+		}
+	}
+}, it)`)))
+
+		f.AddLineComment("Map returns an iterator which contains items converted by fn.")
+		f.NewFunction("Map").
+			WithReceiver("it", "*"+iteratorStructName).
+			AddParameter("fn", "func("+typ+") ("+typ+", error)").
+			AddResult("", "*"+iteratorStructName).
+			AddBody(gg.S(r.Replace(`return $new$(it.ctx, func(ctx context.Context, page *$page$) error {
+	o, err := it.fetch()
+	if err != nil {
+		return err
+	}
+	if o, err = fn(o); err != nil {
+		return err
+	}
+	page.Data = append(page.Data, o)
+	return nil
+}, it)`)))
+
+		f.AddLineComment("Take returns an iterator which contains at most n items.")
+		f.NewFunction("Take").
+			WithReceiver("it", "*"+iteratorStructName).
+			AddParameter("n", "int").
+			AddResult("", "*"+iteratorStructName).
+			AddBody(gg.S(r.Replace(`taken := 0
+return $new$(it.ctx, func(ctx context.Context, page *$page$) error {
+	if taken >= n {
+		return IterateDone
+	}
+	o, err := it.fetch()
+	if err != nil {
+		return err
+	}
+	taken++
+	page.Data = append(page.Data, o)
+	return nil
+}, it)`)))
+
+		f.AddLineComment(`Channel sends every remaining item in this iterator to the returned channel.
+
+The item channel will be closed after all items sent, the error channel will
+receive the error returned by ForEach and be closed after that.`)
+		f.NewFunction("Channel").
+			WithReceiver("it", "*"+iteratorStructName).
+			AddParameter("ctx", "context.Context").
+			AddResult("", "<-chan "+typ).
+			AddResult("", "<-chan error").
+			AddBody(gg.S(r.Replace(`ch := make(chan $type$)
+errc := make(chan error, 1)
+go func() {
+	defer close(errc)
+	defer close(ch)
+
+	err := it.ForEach(ctx, func(o $type$) error {
+		select {
+		case ch <- o:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	if err != nil {
+		errc <- err
+	}
+}()
+return ch, errc`)))
+
+		f.AddLineComment(`Merge%ss merges iterators sorted by less into one sorted iterator.
+
+Items which are equal will be returned in the order of input iterators.`, iteratorStructName)
+		f.NewFunction("Merge"+iteratorStructName+"s").
+			AddParameter("ctx", "context.Context").
+			AddParameter("less", "func(a, b "+typ+") bool").
+			AddParameter("its", "..."+"*"+iteratorStructName).
+			AddResult("", "*"+iteratorStructName).
+			AddBody(gg.S(r.Replace(`heads := make([]$type$, len(its))
+// valid marks iterators that have a head, and stale marks iterators that need to fetch a new head.
+valid := make([]bool, len(its))
+stale := make([]bool, len(its))
+for i := range stale {
+	stale[i] = true
+}
+return $new$(ctx, func(ctx context.Context, page *$page$) error {
+	for i, x := range its {
+		if !stale[i] {
+			continue
+		}
+		o, err := x.fetch()
+		if err != nil && !errors.Is(err, IterateDone) {
+			return err
+		}
+		stale[i] = false
+		heads[i], valid[i] = o, err == nil
+	}
+
+	idx := -1
+	for i := range heads {
+		if valid[i] && (idx < 0 || less(heads[i], heads[idx])) {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return IterateDone
+	}
+	page.Data = append(page.Data, heads[idx])
+	stale[idx] = true
+	return nil
+}, nil)`)))
 	}
 
 	err := g.WriteFile(path)
@@ -0,0 +1,182 @@
+package definitions
+
+import (
+	"strings"
+
+	"github.com/Xuanwo/gg"
+	"github.com/Xuanwo/templateutils"
+	log "github.com/sirupsen/logrus"
+)
+
+func GenerateMiddleware(path string) {
+	g := gg.New()
+	f := g.NewGroup()
+	f.AddLineComment("Code generated by go generate cmd/definitions; DO NOT EDIT.")
+	f.AddPackage("middleware")
+	f.NewImport().
+		AddPath("context").
+		AddPath("io").
+		AddPath("net/http").
+		AddPath("time").
+		AddLine().
+		AddPath("go.beyondstorage.io/v5/types")
+
+	f.NewVar().
+		AddDecl("_", "io.Reader").
+		AddDecl("_", "http.Request").
+		AddDecl("_", "time.Duration")
+
+	ops := SortOperations(OperationsStorage)
+
+	for _, op := range ops {
+		pname := templateutils.ToPascal(op.Name)
+		callName := pname + "Call"
+
+		f.AddLineComment("%s carries the arguments and results of Storager.%s.", callName, pname)
+		st := f.NewStruct(callName)
+		for _, p := range op.Params {
+			st.AddField(callFieldName(p), callFieldType(p))
+		}
+		st.AddLine()
+		for _, r := range op.Results {
+			st.AddField(callFieldName(r), callFieldType(r))
+		}
+		// Local operations don't return error, but we still keep the field
+		// so that all calls could be handled in the same way.
+		if op.Local {
+			st.AddField("Err", "error")
+		}
+
+		f.NewFunction("Op").
+			WithReceiver("c", "*"+callName).
+			AddResult("", "string").
+			AddBody(gg.Return(gg.Lit(op.Name)))
+
+		f.NewFunction("Paths").
+			WithReceiver("c", "*"+callName).
+			AddResult("", "[]string").
+			AddBody(gg.Embed(func() gg.Node {
+				g := gg.NewGroup()
+				g.AddString("ps := make([]string, 0)")
+				for _, p := range op.Params {
+					switch p.Name {
+					case "path", "src", "dst", "target":
+						g.AddString("ps = append(ps, c.%s)", callFieldName(p))
+					case "o":
+						g.NewIf("c.O != nil").AddBody(gg.S("ps = append(ps, c.O.Path)"))
+					}
+				}
+				g.AddString("return ps")
+				return g
+			}))
+
+		f.NewFunction("Result").
+			WithReceiver("c", "*"+callName).
+			AddResult("", "error").
+			AddBody(gg.Return("c.Err"))
+
+		f.NewFunction("SetResult").
+			WithReceiver("c", "*"+callName).
+			AddParameter("err", "error").
+			AddBody(gg.S("c.Err = err"))
+
+		invoke := f.NewFunction("invoke").
+			WithReceiver("c", "*"+callName).
+			AddParameter("ctx", "context.Context").
+			AddParameter("store", "types.Storager")
+		args := make([]string, 0, len(op.Params)+1)
+		method := pname
+		if !op.Local {
+			method += "WithContext"
+			args = append(args, "ctx")
+		}
+		for _, p := range op.Params {
+			if p.Name == "pairs" {
+				args = append(args, "c.Pairs...")
+				continue
+			}
+			args = append(args, "c."+callFieldName(p))
+		}
+		lhs := make([]string, 0, len(op.Results))
+		for _, r := range op.Results {
+			lhs = append(lhs, "c."+callFieldName(r))
+		}
+		invoke.AddBody(gg.S("%s = store.%s(%s)",
+			strings.Join(lhs, ", "), method, strings.Join(args, ", ")))
+	}
+
+	for _, op := range ops {
+		pname := templateutils.ToPascal(op.Name)
+		callName := pname + "Call"
+
+		// Build the call value from all params.
+		fields := make([]string, 0, len(op.Params))
+		for _, p := range op.Params {
+			fields = append(fields, callFieldName(p)+": "+p.Name)
+		}
+		// Build the returning values from all results.
+		rets := make([]interface{}, 0, len(op.Results))
+		for _, r := range op.Results {
+			rets = append(rets, "c."+callFieldName(r))
+		}
+
+		ctx := "context.Background()"
+		if !op.Local {
+			ctx = "ctx"
+
+			xfn := f.NewFunction(pname).
+				WithReceiver("s", "*storager")
+			for _, p := range op.Params {
+				xfn.AddParameter(p.Name, p.Type.FullName())
+			}
+			for _, r := range op.Results {
+				xfn.AddResult(r.Name, r.Type.FullName())
+			}
+			ic := gg.Call(pname + "WithContext").WithOwner("s")
+			ic.AddParameter("context.Background()")
+			for _, p := range op.Params {
+				if p.Name == "pairs" {
+					ic.AddParameter("pairs...")
+					continue
+				}
+				ic.AddParameter(p.Name)
+			}
+			xfn.AddBody(gg.Return(ic))
+
+			pname += "WithContext"
+		}
+
+		xfn := f.NewFunction(pname).
+			WithReceiver("s", "*storager")
+		if !op.Local {
+			xfn.AddParameter("ctx", "context.Context")
+		}
+		for _, p := range op.Params {
+			xfn.AddParameter(p.Name, p.Type.FullName())
+		}
+		for _, r := range op.Results {
+			xfn.AddResult(r.Name, r.Type.FullName())
+		}
+		xfn.AddBody(
+			gg.S("c := &%s{%s}", callName, strings.Join(fields, ", ")),
+			gg.S("s.handler(%s, c)", ctx),
+			gg.Return(rets...),
+		)
+	}
+
+	err := g.WriteFile(path)
+	if err != nil {
+		log.Fatalf("generate to %s: %v", path, err)
+	}
+}
+
+func callFieldName(f Field) string {
+	return templateutils.ToPascal(f.Name)
+}
+
+func callFieldType(f Field) string {
+	if f.Type.Expr == "..." {
+		return "[]" + f.Type.FullName()[3:]
+	}
+	return f.Type.FullName()
+}
@@ -11,11 +11,19 @@ func GeneratePair(path string) {
 	f := g.NewGroup()
 	f.AddLineComment("Code generated by go generate cmd/definitions; DO NOT EDIT.")
 	f.AddPackage("pairs")
-	f.NewImport().
-		AddPath("go.beyondstorage.io/v5/types")
 
 	ps := SortPairs(PairArray)
 
+	// Only import time while pairs use it.
+	imports := f.NewImport()
+	for _, v := range ps {
+		if v.Type.Package == "time" {
+			imports.AddPath("time").AddLine()
+			break
+		}
+	}
+	imports.AddPath("go.beyondstorage.io/v5/types")
+
 	for _, v := range ps {
 		pname := templateutils.ToPascal(v.Name)
 
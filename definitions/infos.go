@@ -109,6 +109,13 @@ ID SHOULD be an absolute path compatible with the target operating system-define
 		Description: `Path is either the absolute path or the relative path towards storage's WorkDir depends on user's input.
 
 Path SHOULD be Unix style.`,
+	},
+	{
+		Name: "restore_status",
+		Type: Type{Name: "string"},
+		Description: `RestoreStatus is the restore status of an archived object.
+
+Available values are "archived", "ongoing" and "restored", see types.RestoreStatusArchived and so on.`,
 	},
 	{
 		Name:        "system_metadata",
@@ -118,7 +125,12 @@ Path SHOULD be Unix style.`,
 	{
 		Name:        "user_metadata",
 		Type:        Type{Name: "map[string]string"},
-		Description: "UserMetadata stores user defined metadata.",
+		Description: "UserMetadata stores user defined metadata, keys are normalized into lower case.",
+	},
+	{
+		Name:        "version_id",
+		Type:        Type{Name: "string"},
+		Description: "VersionID is the version of the object.",
 	},
 }
 
@@ -47,9 +47,11 @@ type Storage struct {
 	CreatePage                     []Pair
 	Delete                         []Pair
 	Fetch                          []Pair
+	GetLifecycle                   []Pair
 	List                           []Pair
 	ListBlock                      []Pair
 	ListMultipart                  []Pair
+	ListVersion                    []Pair
 	Metadata                       []Pair
 	Move                           []Pair
 	QuerySignHTTPCompleteMultipart []Pair
@@ -60,6 +62,9 @@ type Storage struct {
 	QuerySignHTTPWrite             []Pair
 	QuerySignHTTPWriteMultipart    []Pair
 	Read                           []Pair
+	Restore                        []Pair
+	SetLifecycle                   []Pair
+	SetStorageClass                []Pair
 	Stat                           []Pair
 	Write                          []Pair
 	WriteAppend                    []Pair
@@ -96,12 +101,16 @@ func (s Storage) ListPairs(name string) []Pair {
 		return SortPairs(s.Delete)
 	case "fetch":
 		return SortPairs(s.Fetch)
+	case "get_lifecycle":
+		return SortPairs(s.GetLifecycle)
 	case "list":
 		return SortPairs(s.List)
 	case "list_block":
 		return SortPairs(s.ListBlock)
 	case "list_multipart":
 		return SortPairs(s.ListMultipart)
+	case "list_version":
+		return SortPairs(s.ListVersion)
 	case "metadata":
 		return SortPairs(s.Metadata)
 	case "move":
@@ -122,6 +131,12 @@ func (s Storage) ListPairs(name string) []Pair {
 		return SortPairs(s.QuerySignHTTPWriteMultipart)
 	case "read":
 		return SortPairs(s.Read)
+	case "restore":
+		return SortPairs(s.Restore)
+	case "set_lifecycle":
+		return SortPairs(s.SetLifecycle)
+	case "set_storage_class":
+		return SortPairs(s.SetStorageClass)
 	case "stat":
 		return SortPairs(s.Stat)
 	case "write":
@@ -103,7 +103,7 @@ var OperationsStorage = []Operation{
 
 - Delete only delete one and only one object.
   - Service DON'T NEED to support remove all.
-  - User NEED to implement remove_all by themself.
+  - User NEED to implement remove_all by themself, or use pkg/recursive.RemoveAll.
 - Delete is idempotent.
   - Successful delete always return nil error.
   - Delete SHOULD never return ObjectNotExist
@@ -310,12 +310,13 @@ var OperationsStorage = []Operation{
 
 - Copy only copy one and only one object.
   - Service DON'T NEED to support copy a non-empty directory or copy files recursively.
-  - User NEED to implement copy a non-empty directory and copy recursively by themself.
+  - User NEED to implement copy a non-empty directory and copy recursively by themself, or use pkg/recursive.CopyAll.
   - Copy a file to a directory SHOULD return ErrObjectModeInvalid.
 - Copy SHOULD NOT return an error as dst object exists.
   - Service that has native support for overwrite doesn't NEED to check the dst object exists or not.
   - Service that doesn't have native support for overwrite SHOULD check and delete the dst object if exists.
-- A successful copy opration should be complete, which means the dst object's content and metadata should be the same as src object.`,
+- A successful copy opration should be complete, which means the dst object's content and metadata should be the same as src object.
+  - Copy SHOULD replace dst object's user metadata with the user_metadata pair if it's set.`,
 	},
 
 	// Dir related operations
@@ -386,7 +387,7 @@ var OperationsStorage = []Operation{
 
 - Move only move one and only one object.
   - Service DON'T NEED to support move a non-empty directory.
-  - User NEED to implement move a non-empty directory by themself.
+  - User NEED to implement move a non-empty directory by themself, or use pkg/recursive.MoveAll.
   - Move a file to a directory SHOULD return ErrObjectModeInvalid.
 - Move SHOULD NOT return an error as dst object exists.
   - Service that has native support for overwrite doesn't NEED to check the dst object exists or not.
@@ -446,6 +447,87 @@ var OperationsStorage = []Operation{
 		Description: `will list parts belong to this multipart.`,
 	},
 
+	// Version related operations.
+	{
+		Name:      "list_version",
+		Namespace: NamespaceStorage,
+		Params: []Field{
+			getField("path"),
+		},
+		Results: []Field{
+			getField("oi"),
+		},
+		Description: `will list all versions of the object at path.
+
+## Behavior
+
+- ListVersion SHOULD return versions from the newest to the oldest.
+- Every returned object SHOULD carry a VersionID which could be used in read, stat and delete via the version_id pair.
+- ListVersion SHOULD NOT return delete markers.
+- ListVersion SHOULD NOT return an error as the object doesn't exist, an empty iterator will be returned instead.`,
+	},
+
+	// Storage class related operations.
+	{
+		Name:      "restore",
+		Namespace: NamespaceStorage,
+		Params: []Field{
+			getField("path"),
+		},
+		Description: `will restore an archived object so that it could be read.
+
+## Behavior
+
+- Restore SHOULD only start the restoration and return without waiting for it to finish.
+- Restore SHOULD NOT return an error if the object is being restored or has been restored.
+- Stat SHOULD return restore_status so that the progress of restoration could be checked.`,
+	},
+	{
+		Name:      "set_storage_class",
+		Namespace: NamespaceStorage,
+		Params: []Field{
+			getField("path"),
+			getField("class"),
+		},
+		Description: `will change the storage class of an existing object.
+
+## Behavior
+
+- The content and user metadata of the object SHOULD NOT be changed.
+- Class is service specific, and it's the same as the storage_class pair of write.`,
+	},
+
+	// Lifecycle related operations.
+	{
+		Name:      "get_lifecycle",
+		Namespace: NamespaceStorage,
+		Results: []Field{
+			getField("rules"),
+		},
+		Description: `will get lifecycle rules of the storage.
+
+## Behavior
+
+- GetLifecycle SHOULD return an empty slice without error if no rule has been set.
+- Prefix of returned rules SHOULD be relative to the work dir, and rules outside the work dir SHOULD be skipped.
+- Rules which could not be represented by LifecycleRule SHOULD be skipped.`,
+	},
+	{
+		Name:      "set_lifecycle",
+		Namespace: NamespaceStorage,
+		Params: []Field{
+			getField("rules"),
+		},
+		Description: `will replace lifecycle rules of the storage.
+
+## Behavior
+
+- SetLifecycle SHOULD replace all rules returned by GetLifecycle.
+- SetLifecycle SHOULD keep rules invisible to GetLifecycle untouched, like rules outside the work dir or not representable by LifecycleRule.
+- SetLifecycle with empty rules SHOULD remove all rules returned by GetLifecycle.
+- SetLifecycle SHOULD return an error wrapping ErrCapabilityInsufficient if any action of rules is not supported.`,
+	},
+
 	// Page related operations
 	{
 		Name:      "create_page",
@@ -27,14 +27,20 @@ var PairArray = []Pair{
 	PairContinuationToken,
 	PairCredential,
 	PairEndpoint,
+	PairIfMatch,
+	PairIfModifiedSince,
+	PairIfNoneMatch,
 	PairListMode,
 	PairLocation,
 	PairName,
 	PairObjectMode,
 	PairOffset,
+	PairRestoreDays,
 	PairSize,
 	PairMultipartID,
 	PairIoCallback,
+	PairUserMetadata,
+	PairVersionID,
 	PairWorkDir,
 }
 
@@ -85,6 +91,27 @@ var PairEndpoint = Pair{
 	Description: "specify how to provide endpoint for service or storage",
 }
 
+var PairIfMatch = Pair{
+	Name:        "if_match",
+	Type:        Type{Name: "string"},
+	global:      true,
+	Description: "specify the etag that the object must match, or services.ErrPreconditionFailed will be returned",
+}
+
+var PairIfModifiedSince = Pair{
+	Name:        "if_modified_since",
+	Type:        Type{Package: "time", Name: "Time"},
+	global:      true,
+	Description: "specify the time that the object must be modified after, or services.ErrPreconditionFailed will be returned",
+}
+
+var PairIfNoneMatch = Pair{
+	Name:        "if_none_match",
+	Type:        Type{Name: "string"},
+	global:      true,
+	Description: "specify the etag that the object must not match, or services.ErrPreconditionFailed will be returned; `*` means the object must not exist",
+}
+
 var PairListMode = Pair{
 	Name:   "list_mode",
 	Type:   Type{Package: "types", Name: "ListMode"},
@@ -116,6 +143,12 @@ var PairOffset = Pair{
 	global:      true,
 	Description: `specify offset for this request, storage will seek to this offset before read`,
 }
+var PairRestoreDays = Pair{
+	Name:        "restore_days",
+	Type:        Type{Name: "int"},
+	global:      true,
+	Description: "specify the number of days that the restored copy of an archived object will be kept",
+}
 var PairSize = Pair{
 	Name:        "size",
 	Type:        Type{Name: "int64"},
@@ -134,6 +167,20 @@ var PairIoCallback = Pair{
 	global:      true,
 	Description: `specify what todo every time we read data from source`,
 }
+var PairUserMetadata = Pair{
+	Name:   "user_metadata",
+	Type:   Type{Name: "map[string]string"},
+	global: true,
+	Description: `specify the user defined metadata of the object.
+Keys are case-insensitive and will be normalized into lower case.
+For copy, the src object's user metadata will be replaced if this pair is set, otherwise it will be kept.`,
+}
+var PairVersionID = Pair{
+	Name:        "version_id",
+	Type:        Type{Name: "string"},
+	global:      true,
+	Description: "specify the version of the object to operate on, the latest version will be used if not set",
+}
 var PairWorkDir = Pair{
 	Name:   "work_dir",
 	Type:   Type{Name: "string"},
@@ -12,4 +12,5 @@ func main() {
 	def.GenerateOperation("../../../types/operation.generated.go")
 	def.GenerateObject("../../../types/object.generated.go")
 	def.GenerateNamespace("../../../definitions/namespace.generated.go")
+	def.GenerateMiddleware("../../../pkg/middleware/generated.go")
 }
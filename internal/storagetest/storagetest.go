@@ -0,0 +1,619 @@
+/*
+Package storagetest provides an in-memory Storager for tests of packages which
+wrap or drive Storagers.
+
+Objects are stored in a flat map by path. For services without virtual dir,
+parent dirs of objects are recorded like fs, so that list in ListModeDir and
+delete of non-empty dirs behave like real dirs.
+
+All calls are recorded, and Fail could be used to inject errors:
+
+	store := storagetest.New()
+	store.Fail = func(c storagetest.Call) error {
+		if c.Op == "write_multipart" && c.Index == 1 {
+			return services.ErrServiceInternal
+		}
+		return nil
+	}
+*/
+package storagetest
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// Object is an object stored in Storager.
+type Object struct {
+	Data         []byte
+	ContentType  string
+	UserMetadata map[string]string
+	LastModified time.Time
+}
+
+// Call is a recorded operation call.
+type Call struct {
+	Op   string
+	Path string
+	// Index is the index of write_multipart.
+	Index int
+	// Offset and Size are the range of read.
+	Offset int64
+	Size   int64
+}
+
+// Storager is an in-memory Storager.
+type Storager struct {
+	types.UnimplementedStorager
+
+	// StorageFeatures will be returned by Features.
+	StorageFeatures types.StorageFeatures
+	// StorageMeta will be returned by Metadata.
+	StorageMeta *types.StorageMeta
+	// Pairs are the names of supported pairs by operation, operations not
+	// in Pairs accept all pairs.
+	Pairs map[string][]string
+	// Fail will be called before operations, the returned error will be
+	// returned by the operation if not nil.
+	Fail func(c Call) error
+
+	mu      sync.Mutex
+	objects map[string]*Object
+	dirs    map[string]bool
+	uploads map[string]*upload
+	nextID  int
+	calls   []Call
+}
+
+type upload struct {
+	path  string
+	parts map[int][]byte
+}
+
+// New creates an empty Storager without any features.
+func New() *Storager {
+	return &Storager{
+		StorageMeta: types.NewStorageMeta(),
+		objects:     make(map[string]*Object),
+		dirs:        make(map[string]bool),
+		uploads:     make(map[string]*upload),
+	}
+}
+
+// Put stores data at path and returns the object, so that tests could
+// change its metadata.
+func (s *Storager) Put(path string, data []byte) *Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.put(path, &Object{Data: data, LastModified: time.Now()})
+}
+
+// Object returns the object at path, nil means not exist.
+func (s *Storager) Object(path string) *Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.objects[path]
+}
+
+// Data returns the data of the object at path, nil means not exist.
+func (s *Storager) Data(path string) []byte {
+	if o := s.Object(path); o != nil {
+		return o.Data
+	}
+	return nil
+}
+
+// Paths returns the sorted paths of all objects.
+func (s *Storager) Paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ps := make([]string, 0, len(s.objects))
+	for k := range s.objects {
+		ps = append(ps, k)
+	}
+	sort.Strings(ps)
+	return ps
+}
+
+// Dirs returns the sorted paths of all recorded dirs.
+func (s *Storager) Dirs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ps := make([]string, 0, len(s.dirs))
+	for k := range s.dirs {
+		ps = append(ps, k)
+	}
+	sort.Strings(ps)
+	return ps
+}
+
+// Uploads returns the sorted ids of multipart uploads in progress.
+func (s *Storager) Uploads() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.uploads))
+	for k := range s.uploads {
+		ids = append(ids, k)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Calls returns the recorded calls of op.
+func (s *Storager) Calls(op string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := make([]Call, 0)
+	for _, c := range s.calls {
+		if c.Op == op {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// Count returns the number of recorded calls of op.
+func (s *Storager) Count(op string) int {
+	return len(s.Calls(op))
+}
+
+// ResetCalls clears all recorded calls.
+func (s *Storager) ResetCalls() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = nil
+}
+
+// Features implements Storager.Features
+func (s *Storager) Features() types.StorageFeatures {
+	return s.StorageFeatures
+}
+
+// Metadata implements Storager.Metadata
+func (s *Storager) Metadata(ps ...types.Pair) *types.StorageMeta {
+	return s.StorageMeta
+}
+
+func (s *Storager) ListWithContext(ctx context.Context, dir string, ps ...types.Pair) (*types.ObjectIterator, error) {
+	if err := s.call(Call{Op: "list", Path: dir}, ps); err != nil {
+		return nil, err
+	}
+
+	mode := types.ListModeDir
+	for _, p := range ps {
+		if p.Key == "list_mode" {
+			mode = p.Value.(types.ListMode)
+		}
+	}
+
+	s.mu.Lock()
+	objects := make([]*types.Object, 0)
+	if mode.IsPrefix() {
+		for k, v := range s.objects {
+			if strings.HasPrefix(k, dir) {
+				objects = append(objects, s.newObject(k, v))
+			}
+		}
+	} else {
+		dirs := make(map[string]bool)
+		for k := range s.dirs {
+			if parent(k) == dir {
+				dirs[k] = true
+			}
+		}
+		for k, v := range s.objects {
+			if parent(k) == dir {
+				objects = append(objects, s.newObject(k, v))
+				continue
+			}
+			// Dirs of virtual dir services are synthesized from paths.
+			if !s.StorageFeatures.VirtualDir {
+				continue
+			}
+			for p := parent(k); p != ""; p = parent(p) {
+				if parent(p) == dir {
+					dirs[p] = true
+					break
+				}
+			}
+		}
+		for k := range dirs {
+			o := types.NewObject(s, true)
+			o.ID = k
+			o.Path = k
+			o.Mode = types.ModeDir
+			objects = append(objects, o)
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Path < objects[j].Path })
+
+	fn := types.NextObjectFunc(func(ctx context.Context, page *types.ObjectPage) error {
+		page.Data = append(page.Data, objects...)
+		return types.IterateDone
+	})
+	return types.NewObjectIterator(ctx, fn, nil), nil
+}
+
+func (s *Storager) StatWithContext(ctx context.Context, path string, ps ...types.Pair) (*types.Object, error) {
+	if err := s.call(Call{Op: "stat", Path: path}, ps); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id := multipartID(ps); id != "" {
+		if _, ok := s.uploads[id]; !ok {
+			return nil, services.ErrObjectNotExist
+		}
+		return s.newPart(path, id), nil
+	}
+	v, ok := s.objects[path]
+	if !ok {
+		return nil, services.ErrObjectNotExist
+	}
+	return s.newObject(path, v), nil
+}
+
+func (s *Storager) ReadWithContext(ctx context.Context, path string, w io.Writer, ps ...types.Pair) (int64, error) {
+	s.mu.Lock()
+	v, ok := s.objects[path]
+	s.mu.Unlock()
+
+	c := Call{Op: "read", Path: path}
+	if ok {
+		c.Size = int64(len(v.Data))
+	}
+	for _, p := range ps {
+		switch p.Key {
+		case "offset":
+			c.Offset = p.Value.(int64)
+			c.Size -= c.Offset
+		case "size":
+			c.Size = p.Value.(int64)
+		}
+	}
+	if err := s.call(c, ps); err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, services.ErrObjectNotExist
+	}
+
+	end := c.Offset + c.Size
+	if end > int64(len(v.Data)) {
+		end = int64(len(v.Data))
+	}
+	// Data will be replaced instead of modified by write, it's safe to
+	// write it without lock.
+	n, err := w.Write(v.Data[c.Offset:end])
+	if fn := ioCallback(ps); fn != nil {
+		fn(v.Data[c.Offset : c.Offset+int64(n)])
+	}
+	return int64(n), err
+}
+
+func (s *Storager) WriteWithContext(ctx context.Context, path string, r io.Reader, size int64, ps ...types.Pair) (int64, error) {
+	if err := s.call(Call{Op: "write", Path: path, Size: size}, ps); err != nil {
+		return 0, err
+	}
+
+	o := &Object{Data: []byte{}}
+	if r != nil {
+		d, err := io.ReadAll(io.LimitReader(r, size))
+		if err != nil {
+			return 0, err
+		}
+		o.Data = d
+	}
+	if int64(len(o.Data)) != size {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if fn := ioCallback(ps); fn != nil {
+		fn(o.Data)
+	}
+	for _, p := range ps {
+		switch p.Key {
+		case "content_type":
+			o.ContentType = p.Value.(string)
+		case "user_metadata":
+			o.UserMetadata = p.Value.(map[string]string)
+		}
+	}
+	o.LastModified = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(path, o)
+	return size, nil
+}
+
+func (s *Storager) CopyWithContext(ctx context.Context, src, dst string, ps ...types.Pair) error {
+	if err := s.call(Call{Op: "copy", Path: src}, ps); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.objects[src]
+	if !ok {
+		return services.ErrObjectNotExist
+	}
+	o := *v
+	s.put(dst, &o)
+	return nil
+}
+
+func (s *Storager) MoveWithContext(ctx context.Context, src, dst string, ps ...types.Pair) error {
+	if err := s.call(Call{Op: "move", Path: src}, ps); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.objects[src]
+	if !ok {
+		return services.ErrObjectNotExist
+	}
+	delete(s.objects, src)
+	s.put(dst, v)
+	return nil
+}
+
+func (s *Storager) CreateDirWithContext(ctx context.Context, path string, ps ...types.Pair) (*types.Object, error) {
+	if err := s.call(Call{Op: "create_dir", Path: path}, ps); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for p := path; p != ""; p = parent(p) {
+		s.dirs[p] = true
+	}
+	o := types.NewObject(s, true)
+	o.ID = path
+	o.Path = path
+	o.Mode = types.ModeDir
+	return o, nil
+}
+
+// DeleteWithContext deletes the object, the multipart upload or the empty
+// dir at path.
+func (s *Storager) DeleteWithContext(ctx context.Context, path string, ps ...types.Pair) error {
+	if err := s.call(Call{Op: "delete", Path: path}, ps); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id := multipartID(ps); id != "" {
+		delete(s.uploads, id)
+		return nil
+	}
+	if s.dirs[path] {
+		for k := range s.objects {
+			if strings.HasPrefix(k, path+"/") {
+				return errors.New("dir not empty")
+			}
+		}
+		for k := range s.dirs {
+			if strings.HasPrefix(k, path+"/") {
+				return errors.New("dir not empty")
+			}
+		}
+		delete(s.dirs, path)
+		return nil
+	}
+	delete(s.objects, path)
+	return nil
+}
+
+func (s *Storager) CreateMultipartWithContext(ctx context.Context, path string, ps ...types.Pair) (*types.Object, error) {
+	if err := s.call(Call{Op: "create_multipart", Path: path}, ps); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	id := fmt.Sprintf("upload-%d", s.nextID)
+	s.uploads[id] = &upload{path: path, parts: make(map[int][]byte)}
+	return s.newPart(path, id), nil
+}
+
+func (s *Storager) WriteMultipartWithContext(ctx context.Context, o *types.Object, r io.Reader, size int64, index int, ps ...types.Pair) (int64, *types.Part, error) {
+	if err := s.call(Call{Op: "write_multipart", Path: o.Path, Index: index, Size: size}, ps); err != nil {
+		return 0, nil, err
+	}
+
+	d, err := io.ReadAll(io.LimitReader(r, size))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.uploads[o.MustGetMultipartID()]
+	if !ok {
+		return 0, nil, services.ErrObjectNotExist
+	}
+	u.parts[index] = d
+	return int64(len(d)), &types.Part{Index: index, Size: int64(len(d)), ETag: etag(d)}, nil
+}
+
+func (s *Storager) ListMultipartWithContext(ctx context.Context, o *types.Object, ps ...types.Pair) (*types.PartIterator, error) {
+	if err := s.call(Call{Op: "list_multipart", Path: o.Path}, ps); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	u, ok := s.uploads[o.MustGetMultipartID()]
+	if !ok {
+		s.mu.Unlock()
+		return nil, services.ErrObjectNotExist
+	}
+	parts := make([]*types.Part, 0, len(u.parts))
+	for k, v := range u.parts {
+		parts = append(parts, &types.Part{Index: k, Size: int64(len(v)), ETag: etag(v)})
+	}
+	s.mu.Unlock()
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Index < parts[j].Index })
+
+	fn := types.NextPartFunc(func(ctx context.Context, page *types.PartPage) error {
+		page.Data = append(page.Data, parts...)
+		return types.IterateDone
+	})
+	return types.NewPartIterator(ctx, fn, nil), nil
+}
+
+// CompleteMultipartWithContext requires parts to be continuous and in order.
+func (s *Storager) CompleteMultipartWithContext(ctx context.Context, o *types.Object, parts []*types.Part, ps ...types.Pair) error {
+	if err := s.call(Call{Op: "complete_multipart", Path: o.Path}, ps); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := o.MustGetMultipartID()
+	u, ok := s.uploads[id]
+	if !ok {
+		return services.ErrObjectNotExist
+	}
+	var buf bytes.Buffer
+	for k, p := range parts {
+		if p.Index != k {
+			return fmt.Errorf("part %d is out of order", p.Index)
+		}
+		buf.Write(u.parts[p.Index])
+	}
+	s.put(u.path, &Object{Data: buf.Bytes(), LastModified: time.Now()})
+	delete(s.uploads, id)
+	return nil
+}
+
+// call records c, checks pairs and calls Fail.
+func (s *Storager) call(c Call, ps []types.Pair) error {
+	s.mu.Lock()
+	s.calls = append(s.calls, c)
+	names, ok := s.Pairs[c.Op]
+	fail := s.Fail
+	s.mu.Unlock()
+
+	if ok {
+		for _, p := range ps {
+			if !contains(names, p.Key) {
+				return services.PairUnsupportedError{Pair: p}
+			}
+		}
+	}
+	if fail != nil {
+		return fail(c)
+	}
+	return nil
+}
+
+// put stores o at path, caller must hold s.mu.
+func (s *Storager) put(path string, o *Object) *Object {
+	s.objects[path] = o
+	if !s.StorageFeatures.VirtualDir {
+		for p := parent(path); p != ""; p = parent(p) {
+			s.dirs[p] = true
+		}
+	}
+	return o
+}
+
+func (s *Storager) newObject(path string, v *Object) *types.Object {
+	o := types.NewObject(s, true)
+	o.ID = path
+	o.Path = path
+	o.Mode = types.ModeRead
+	o.SetContentLength(int64(len(v.Data)))
+	o.SetEtag(etag(v.Data))
+	if !v.LastModified.IsZero() {
+		o.SetLastModified(v.LastModified)
+	}
+	if v.ContentType != "" {
+		o.SetContentType(v.ContentType)
+	}
+	if len(v.UserMetadata) > 0 {
+		o.SetUserMetadata(v.UserMetadata)
+	}
+	return o
+}
+
+func (s *Storager) newPart(path, id string) *types.Object {
+	o := types.NewObject(s, true)
+	o.ID = path
+	o.Path = path
+	o.Mode = types.ModePart
+	o.SetMultipartID(id)
+	return o
+}
+
+func multipartID(ps []types.Pair) string {
+	for _, p := range ps {
+		if p.Key == "multipart_id" {
+			return p.Value.(string)
+		}
+	}
+	return ""
+}
+
+func parent(p string) string {
+	idx := strings.LastIndex(p, "/")
+	if idx < 0 {
+		return ""
+	}
+	return p[:idx]
+}
+
+func etag(d []byte) string {
+	sum := md5.Sum(d)
+	return hex.EncodeToString(sum[:])
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func ioCallback(ps []types.Pair) func([]byte) {
+	for _, p := range ps {
+		if p.Key == "io_callback" {
+			return p.Value.(func([]byte))
+		}
+	}
+	return nil
+}
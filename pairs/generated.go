@@ -1,7 +1,11 @@
 // Code generated by go generate cmd/definitions; DO NOT EDIT.
 package pairs
 
-import "go.beyondstorage.io/v5/types"
+import (
+	"time"
+
+	"go.beyondstorage.io/v5/types"
+)
 
 // WithContentDisposition will apply content_disposition value to Options.
 //
@@ -87,6 +91,30 @@ func WithEndpoint(v string) (p types.Pair) {
 	return types.Pair{Key: "endpoint", Value: v}
 }
 
+// WithIfMatch will apply if_match value to Options.
+//
+// IfMatch specify the etag that the object must match, or services.ErrPreconditionFailed will
+// be returned
+func WithIfMatch(v string) (p types.Pair) {
+	return types.Pair{Key: "if_match", Value: v}
+}
+
+// WithIfModifiedSince will apply if_modified_since value to Options.
+//
+// IfModifiedSince specify the time that the object must be modified after, or services.ErrPreconditionFailed
+// will be returned
+func WithIfModifiedSince(v time.Time) (p types.Pair) {
+	return types.Pair{Key: "if_modified_since", Value: v}
+}
+
+// WithIfNoneMatch will apply if_none_match value to Options.
+//
+// IfNoneMatch specify the etag that the object must not match, or services.ErrPreconditionFailed
+// will be returned; `*` means the object must not exist
+func WithIfNoneMatch(v string) (p types.Pair) {
+	return types.Pair{Key: "if_none_match", Value: v}
+}
+
 // WithIoCallback will apply io_callback value to Options.
 //
 // IoCallback specify what todo every time we read data from source
@@ -136,6 +164,13 @@ func WithOffset(v int64) (p types.Pair) {
 	return types.Pair{Key: "offset", Value: v}
 }
 
+// WithRestoreDays will apply restore_days value to Options.
+//
+// RestoreDays specify the number of days that the restored copy of an archived object will be kept
+func WithRestoreDays(v int) (p types.Pair) {
+	return types.Pair{Key: "restore_days", Value: v}
+}
+
 // WithSize will apply size value to Options.
 //
 // Size specify size for this request, storage will only read limited content data
@@ -143,6 +178,24 @@ func WithSize(v int64) (p types.Pair) {
 	return types.Pair{Key: "size", Value: v}
 }
 
+// WithUserMetadata will apply user_metadata value to Options.
+//
+// UserMetadata specify the user defined metadata of the object.
+// Keys are case-insensitive and will be normalized into lower case.
+// For copy, the src object's user metadata will be replaced if this pair is set, otherwise it will be
+// kept.
+func WithUserMetadata(v map[string]string) (p types.Pair) {
+	return types.Pair{Key: "user_metadata", Value: v}
+}
+
+// WithVersionID will apply version_id value to Options.
+//
+// VersionID specify the version of the object to operate on, the latest version will be used if not
+// set
+func WithVersionID(v string) (p types.Pair) {
+	return types.Pair{Key: "version_id", Value: v}
+}
+
 // WithWorkDir will apply work_dir value to Options.
 //
 // WorkDir specify the work dir for service or storage, every operation will be relative to this dir.
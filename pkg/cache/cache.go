@@ -0,0 +1,449 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.beyondstorage.io/v5/pkg/middleware"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// Options is the cache supported options.
+type Options struct {
+	// MaxSize is the max total size in bytes of cached objects, 0 means no limit.
+	//
+	// Objects larger than MaxSize will not be cached.
+	MaxSize int64
+	// MaxEntries is the max number of cached objects, 0 means no limit.
+	MaxEntries int
+	// TTL is the duration a cached object is trusted without stat origin again.
+	//
+	// 0 means we will always stat origin to validate the cached object before read.
+	TTL time.Duration
+	// WriteBack means Write will only write into cache, and users need to call Flush
+	// to write them back into origin.
+	WriteBack bool
+}
+
+// Storager is a Storager which caches objects of origin in cache.
+type Storager struct {
+	types.Storager
+
+	origin types.Storager
+	cache  types.Storager
+	opt    Options
+
+	index map[string]*list.Element
+	lru   *list.List
+	used  int64
+	mu    sync.Mutex
+}
+
+// New will create a caching Storager for origin.
+//
+// cache could be any Storager which supports Read, Write and Delete, like
+// memory or fs. Cached objects will be stored by the hash of their path, and
+// cache SHOULD NOT be shared with other Storagers.
+func New(origin, cache types.Storager, o *Options) *Storager {
+	s := &Storager{
+		origin: origin,
+		cache:  cache,
+		index:  make(map[string]*list.Element),
+		lru:    list.New(),
+	}
+	if o != nil {
+		s.opt = *o
+	}
+	s.Storager = middleware.Wrap(origin, s.middleware)
+	return s
+}
+
+type entry struct {
+	path    string
+	key     string
+	size    int64
+	version string
+	dirty   bool
+	checked time.Time
+	// pairs are the pairs of the write-back write, which will be passed to
+	// origin while flushing.
+	pairs []types.Pair
+}
+
+func (s *Storager) middleware(next middleware.Handler) middleware.Handler {
+	return func(ctx context.Context, c middleware.Call) {
+		switch v := c.(type) {
+		case *middleware.ReadCall:
+			v.N, v.Err = s.read(ctx, v, next)
+		case *middleware.StatCall:
+			if e, ok := s.get(v.Path); ok && e.dirty {
+				v.O, v.Err = s.newObject(e), nil
+				return
+			}
+			next(ctx, c)
+		case *middleware.WriteCall:
+			if s.opt.WriteBack {
+				v.N, v.Err = s.writeBack(ctx, v)
+				return
+			}
+			next(ctx, c)
+			s.invalidate(ctx, v.Path)
+		case *middleware.ListCall:
+			// Dirty objects only exist in cache, flush them so that they will be listed.
+			if v.Err = s.flushPrefix(ctx, v.Path); v.Err != nil {
+				return
+			}
+			next(ctx, c)
+		case *middleware.DeleteCall:
+			e, ok := s.get(v.Path)
+			s.invalidate(ctx, v.Path)
+			// Dirty objects that have never been flushed don't exist in origin.
+			if ok && e.dirty {
+				_, err := s.origin.StatWithContext(ctx, v.Path)
+				if errors.Is(err, services.ErrObjectNotExist) {
+					return
+				}
+			}
+			next(ctx, c)
+		case *middleware.CopyCall:
+			if v.Err = s.flush(ctx, v.Src); v.Err != nil {
+				return
+			}
+			next(ctx, c)
+			s.invalidate(ctx, v.Dst)
+		case *middleware.MoveCall:
+			if v.Err = s.flush(ctx, v.Src); v.Err != nil {
+				return
+			}
+			next(ctx, c)
+			s.invalidate(ctx, v.Src)
+			s.invalidate(ctx, v.Dst)
+		case *middleware.CreateAppendCall, *middleware.CreateMultipartCall, *middleware.CreateBlockCall, *middleware.FetchCall:
+			// These operations will overwrite the object, so the cached one must be dropped.
+			for _, p := range c.Paths() {
+				s.invalidate(ctx, p)
+			}
+			next(ctx, c)
+		default:
+			next(ctx, c)
+		}
+	}
+}
+
+// Flush will write all dirty objects back into origin.
+func (s *Storager) Flush(ctx context.Context) error {
+	return s.flushPrefix(ctx, "")
+}
+
+// flushPrefix will write dirty objects with prefix back into origin.
+func (s *Storager) flushPrefix(ctx context.Context, prefix string) error {
+	s.mu.Lock()
+	paths := make([]string, 0)
+	for e := s.lru.Front(); e != nil; e = e.Next() {
+		if x := e.Value.(*entry); x.dirty && strings.HasPrefix(x.path, prefix) {
+			paths = append(paths, x.path)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, p := range paths {
+		if err := s.flush(ctx, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Purge will drop all cached objects, dirty objects will be dropped without flush.
+func (s *Storager) Purge(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for e := s.lru.Front(); e != nil; e = s.lru.Front() {
+		if err := s.remove(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storager) read(ctx context.Context, c *middleware.ReadCall, next middleware.Handler) (n int64, err error) {
+	e, cached := s.get(c.Path)
+	if cached && (e.dirty || (s.opt.TTL > 0 && time.Since(e.checked) < s.opt.TTL)) {
+		return s.readCache(ctx, e, c)
+	}
+
+	o, err := s.origin.StatWithContext(ctx, c.Path)
+	if err != nil {
+		return 0, err
+	}
+	version := formatVersion(o)
+	if cached && e.version == version && version != "" {
+		s.touch(c.Path)
+		return s.readCache(ctx, e, c)
+	}
+	s.invalidate(ctx, c.Path)
+
+	size, ok := o.GetContentLength()
+	if !ok || (s.opt.MaxSize > 0 && size > s.opt.MaxSize) || version == "" {
+		next(ctx, c)
+		return c.N, c.Err
+	}
+
+	e, err = s.fill(ctx, c, size, version)
+	if err != nil {
+		// Fallback to origin if we can't fill the cache.
+		next(ctx, c)
+		return c.N, c.Err
+	}
+	return s.readCache(ctx, e, c)
+}
+
+// fill will read the whole object from origin into cache.
+func (s *Storager) fill(ctx context.Context, c *middleware.ReadCall, size int64, version string) (e entry, err error) {
+	e = entry{
+		path:    c.Path,
+		key:     formatKey(c.Path),
+		size:    size,
+		version: version,
+	}
+
+	// io_callback will be called while reading from cache, so it must not
+	// be called for the whole object here.
+	ps := make([]types.Pair, 0, len(c.Pairs))
+	for _, p := range c.Pairs {
+		if isRangePair(p) || p.Key == "io_callback" {
+			continue
+		}
+		ps = append(ps, p)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := s.origin.ReadWithContext(ctx, c.Path, pw, ps...)
+		pw.CloseWithError(err)
+	}()
+
+	_, err = s.cache.WriteWithContext(ctx, e.key, pr, size)
+	// Make sure the reading goroutine exits.
+	pr.CloseWithError(err)
+	if err != nil {
+		_ = s.cache.DeleteWithContext(ctx, e.key)
+		return e, err
+	}
+
+	return e, s.add(ctx, e)
+}
+
+func (s *Storager) readCache(ctx context.Context, e entry, c *middleware.ReadCall) (int64, error) {
+	ps := make([]types.Pair, 0, len(c.Pairs))
+	for _, p := range c.Pairs {
+		if isRangePair(p) || p.Key == "io_callback" {
+			ps = append(ps, p)
+		}
+	}
+	return s.cache.ReadWithContext(ctx, e.key, c.W, ps...)
+}
+
+func (s *Storager) writeBack(ctx context.Context, c *middleware.WriteCall) (n int64, err error) {
+	s.invalidate(ctx, c.Path)
+
+	if s.opt.MaxSize > 0 && c.Size > s.opt.MaxSize {
+		return s.origin.WriteWithContext(ctx, c.Path, c.R, c.Size, c.Pairs...)
+	}
+
+	e := entry{
+		path:  c.Path,
+		key:   formatKey(c.Path),
+		size:  c.Size,
+		dirty: true,
+		pairs: make([]types.Pair, 0, len(c.Pairs)),
+	}
+	// Pairs like content_type and user_metadata are kept for origin, only
+	// io_callback is applied to the write into cache.
+	ps := make([]types.Pair, 0, 1)
+	for _, p := range c.Pairs {
+		if p.Key == "io_callback" {
+			ps = append(ps, p)
+			continue
+		}
+		e.pairs = append(e.pairs, p)
+	}
+	n, err = s.cache.WriteWithContext(ctx, e.key, c.R, c.Size, ps...)
+	if err != nil {
+		_ = s.cache.DeleteWithContext(ctx, e.key)
+		return
+	}
+	e.size = n
+	return n, s.add(ctx, e)
+}
+
+// flush will write the object back into origin if it's dirty.
+func (s *Storager) flush(ctx context.Context, path string) (err error) {
+	e, ok := s.get(path)
+	if !ok || !e.dirty {
+		return nil
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := s.cache.ReadWithContext(ctx, e.key, pw)
+		pw.CloseWithError(err)
+	}()
+
+	_, err = s.origin.WriteWithContext(ctx, path, pr, e.size, e.pairs...)
+	pr.CloseWithError(err)
+	if err != nil {
+		return err
+	}
+
+	o, err := s.origin.StatWithContext(ctx, path)
+	if err != nil {
+		// The object has been written, we can only drop it from cache.
+		s.invalidate(ctx, path)
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.index[path]
+	if !ok {
+		return nil
+	}
+	x := el.Value.(*entry)
+	x.dirty = false
+	x.version = formatVersion(o)
+	x.checked = time.Now()
+	return s.evict(ctx)
+}
+
+// get returns a copy of the cached entry.
+func (s *Storager) get(path string) (entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.index[path]
+	if !ok {
+		return entry{}, false
+	}
+	return *el.Value.(*entry), true
+}
+
+func (s *Storager) touch(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.index[path]; ok {
+		el.Value.(*entry).checked = time.Now()
+		s.lru.MoveToFront(el)
+	}
+}
+
+func (s *Storager) add(ctx context.Context, e entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.index[e.path]; ok {
+		s.used -= el.Value.(*entry).size
+		s.lru.Remove(el)
+	}
+	e.checked = time.Now()
+	s.index[e.path] = s.lru.PushFront(&e)
+	s.used += e.size
+	return s.evict(ctx)
+}
+
+func (s *Storager) invalidate(ctx context.Context, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.index[path]
+	if !ok {
+		return
+	}
+	// It's safe to ignore the error here, the cached object is not indexed anymore.
+	_ = s.remove(ctx, el)
+}
+
+// evict will remove the least recently used objects until limits are satisfied.
+//
+// Dirty objects will never be evicted, they will be kept until flushed.
+func (s *Storager) evict(ctx context.Context) error {
+	el := s.lru.Back()
+	for el != nil && s.exceeded() {
+		prev := el.Prev()
+		if !el.Value.(*entry).dirty {
+			if err := s.remove(ctx, el); err != nil {
+				return err
+			}
+		}
+		el = prev
+	}
+	return nil
+}
+
+func (s *Storager) exceeded() bool {
+	if s.opt.MaxSize > 0 && s.used > s.opt.MaxSize {
+		return true
+	}
+	if s.opt.MaxEntries > 0 && s.lru.Len() > s.opt.MaxEntries {
+		return true
+	}
+	return false
+}
+
+func (s *Storager) remove(ctx context.Context, el *list.Element) error {
+	e := el.Value.(*entry)
+
+	s.lru.Remove(el)
+	delete(s.index, e.path)
+	s.used -= e.size
+
+	err := s.cache.DeleteWithContext(ctx, e.key)
+	if err != nil {
+		return fmt.Errorf("cache delete %s: %w", e.path, err)
+	}
+	return nil
+}
+
+func (s *Storager) newObject(e entry) *types.Object {
+	o := types.NewObject(s, true)
+	o.ID = e.path
+	o.Path = e.path
+	o.Mode = types.ModeRead
+	o.SetContentLength(e.size)
+	return o
+}
+
+func formatKey(path string) string {
+	h := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(h[:])
+}
+
+// formatVersion returns the version of an object, empty means the object
+// can't be cached because we can't tell whether it has been changed or not.
+func formatVersion(o *types.Object) string {
+	if etag, ok := o.GetEtag(); ok && etag != "" {
+		return "etag:" + etag
+	}
+	if t, ok := o.GetLastModified(); ok && !t.IsZero() {
+		size, _ := o.GetContentLength()
+		return "last_modified:" + strconv.FormatInt(t.UnixNano(), 10) + ":" + strconv.FormatInt(size, 10)
+	}
+	return ""
+}
+
+func isRangePair(p types.Pair) bool {
+	return p.Key == "offset" || p.Key == "size"
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/internal/storagetest"
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+func read(t *testing.T, s types.Storager, path string, ps ...types.Pair) string {
+	var buf bytes.Buffer
+	_, err := s.Read(path, &buf, ps...)
+	assert.Nil(t, err)
+	return buf.String()
+}
+
+func TestReadThrough(t *testing.T) {
+	origin, local := storagetest.New(), storagetest.New()
+	origin.Put("a", []byte("0123456789"))
+
+	s := New(origin, local, nil)
+
+	assert.Equal(t, "0123456789", read(t, s, "a"))
+	assert.Equal(t, "234", read(t, s, "a", pairs.WithOffset(2), pairs.WithSize(3)))
+	assert.Equal(t, 1, origin.Count("read"))
+	assert.Len(t, local.Paths(), 1)
+
+	// Write via cache will invalidate cached object.
+	_, err := s.Write("a", bytes.NewReader([]byte("abc")), 3)
+	assert.Nil(t, err)
+	assert.Len(t, local.Paths(), 0)
+	assert.Equal(t, "abc", read(t, s, "a"))
+	assert.Equal(t, 2, origin.Count("read"))
+
+	// Changes in origin will be detected by etag.
+	origin.Put("a", []byte("xyz"))
+	assert.Equal(t, "xyz", read(t, s, "a"))
+	assert.Equal(t, 3, origin.Count("read"))
+
+	assert.Nil(t, s.Delete("a"))
+	assert.Len(t, local.Paths(), 0)
+}
+
+func TestEviction(t *testing.T) {
+	origin, local := storagetest.New(), storagetest.New()
+	for _, p := range []string{"a", "b", "c"} {
+		origin.Put(p, []byte("0123"))
+	}
+
+	s := New(origin, local, &Options{MaxSize: 8})
+
+	read(t, s, "a")
+	read(t, s, "b")
+	read(t, s, "a")
+	read(t, s, "c")
+
+	// b is the least recently used one.
+	_, ok := s.get("b")
+	assert.False(t, ok)
+	_, ok = s.get("a")
+	assert.True(t, ok)
+	assert.Len(t, local.Paths(), 2)
+}
+
+func TestWriteBack(t *testing.T) {
+	origin, local := storagetest.New(), storagetest.New()
+
+	s := New(origin, local, &Options{WriteBack: true})
+
+	_, err := s.Write("a", bytes.NewReader([]byte("hello")), 5)
+	assert.Nil(t, err)
+	assert.Len(t, origin.Paths(), 0)
+
+	o, err := s.Stat("a")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), o.MustGetContentLength())
+	assert.Equal(t, "hello", read(t, s, "a"))
+
+	assert.Nil(t, s.Flush(context.Background()))
+	assert.Equal(t, "hello", string(origin.Data("a")))
+	assert.Equal(t, "hello", read(t, s, "a"))
+	assert.Equal(t, 0, origin.Count("read"))
+
+	// Dirty objects will be flushed before list.
+	_, err = s.Write("b", bytes.NewReader([]byte("world")), 5)
+	assert.Nil(t, err)
+	it, err := s.List("")
+	assert.Nil(t, err)
+	paths := make([]string, 0)
+	for {
+		o, err := it.Next()
+		if errors.Is(err, types.IterateDone) {
+			break
+		}
+		assert.Nil(t, err)
+		paths = append(paths, o.Path)
+	}
+	assert.Equal(t, []string{"a", "b"}, paths)
+	assert.Equal(t, "world", string(origin.Data("b")))
+
+	// Deleting a dirty object which has never been flushed will not touch origin.
+	_, err = s.Write("c", bytes.NewReader([]byte("!")), 1)
+	assert.Nil(t, err)
+	assert.Nil(t, s.Delete("c"))
+	assert.Equal(t, 0, origin.Count("delete"))
+	_, err = s.Stat("c")
+	assert.ErrorIs(t, err, services.ErrObjectNotExist)
+
+	// Deleting a dirty object will delete the flushed one in origin.
+	_, err = s.Write("a", bytes.NewReader([]byte("x")), 1)
+	assert.Nil(t, err)
+	assert.Nil(t, s.Delete("a"))
+	assert.Equal(t, 1, origin.Count("delete"))
+	assert.Nil(t, origin.Object("a"))
+}
+
+func TestIoCallback(t *testing.T) {
+	origin, local := storagetest.New(), storagetest.New()
+	origin.Put("a", []byte("0123456789"))
+
+	s := New(origin, local, nil)
+
+	var n int
+	fn := pairs.WithIoCallback(func(b []byte) { n += len(b) })
+
+	assert.Equal(t, "0123456789", read(t, s, "a", fn))
+	assert.Equal(t, 10, n)
+
+	// Fallback to origin if the cache can't be filled.
+	n = 0
+	local.Fail = func(c storagetest.Call) error {
+		if c.Op == "write" {
+			return errors.New("cache is full")
+		}
+		return nil
+	}
+	origin.Put("a", []byte("abcde"))
+	assert.Equal(t, "abcde", read(t, s, "a", fn))
+	assert.Equal(t, 5, n)
+}
+
+func TestWriteBackPairs(t *testing.T) {
+	origin, local := storagetest.New(), storagetest.New()
+
+	s := New(origin, local, &Options{WriteBack: true})
+
+	_, err := s.Write("a", bytes.NewReader([]byte("hello")), 5,
+		pairs.WithContentType("text/plain"),
+		pairs.WithUserMetadata(map[string]string{"k": "v"}))
+	assert.Nil(t, err)
+	assert.Nil(t, s.Flush(context.Background()))
+
+	o := origin.Object("a")
+	assert.Equal(t, "hello", string(o.Data))
+	assert.Equal(t, "text/plain", o.ContentType)
+	assert.Equal(t, map[string]string{"k": "v"}, o.UserMetadata)
+}
@@ -0,0 +1,15 @@
+/*
+Package cache provides a read-through caching Storager which stores objects of a remote Storager in a local one.
+
+Read will be served from cache if the cached object is still valid, which is
+checked by the etag or last_modified of the object. Write, Delete, Move and
+other operations that modify objects will invalidate the cached ones.
+
+With WriteBack enabled, Write will only write into cache, and users SHOULD
+call Flush to write dirty objects back into origin. Dirty objects will also be
+flushed before List, Copy and Move which touch them.
+
+The cache index is kept in memory, so cached objects will not be reused
+after the Storager has been dropped.
+*/
+package cache
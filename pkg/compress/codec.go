@@ -0,0 +1,77 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"strings"
+)
+
+// Codec compresses and decompresses blocks.
+type Codec interface {
+	// Name returns the name which will be recorded in objects, it SHOULD be
+	// unique and no longer than MaxCodecNameLength.
+	Name() string
+	// NewWriter returns a WriteCloser which compresses data into w.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	// NewReader returns a ReadCloser which decompresses data from r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// GzipCodec is a Codec which uses gzip.
+type GzipCodec struct {
+	// Level is the gzip compression level, 0 means gzip.DefaultCompression.
+	Level int
+}
+
+// Name implements Codec.Name
+func (c GzipCodec) Name() string {
+	return "gzip"
+}
+
+// NewWriter implements Codec.NewWriter
+func (c GzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+// NewReader implements Codec.NewReader
+func (c GzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+var compressedContentTypes = map[string]bool{
+	"application/gzip":                  true,
+	"application/x-gzip":                true,
+	"application/zip":                   true,
+	"application/x-bzip2":               true,
+	"application/x-xz":                  true,
+	"application/zstd":                  true,
+	"application/x-7z-compressed":       true,
+	"application/x-rar-compressed":      true,
+	"application/vnd.rar":               true,
+	"application/x-compress":            true,
+	"application/x-lz4":                 true,
+	"application/x-snappy-framed":       true,
+	"application/vnd.ms-cab-compressed": true,
+}
+
+// IsCompressedContentType returns whether content type t is already compressed,
+// which includes archives, images, audios and videos.
+func IsCompressedContentType(t string) bool {
+	// Strip parameters like "; charset=utf-8".
+	if idx := strings.IndexByte(t, ';'); idx >= 0 {
+		t = t[:idx]
+	}
+	t = strings.ToLower(strings.TrimSpace(t))
+
+	if strings.HasPrefix(t, "image/") && t != "image/svg+xml" && t != "image/bmp" {
+		return true
+	}
+	if strings.HasPrefix(t, "audio/") || strings.HasPrefix(t, "video/") {
+		return true
+	}
+	return compressedContentTypes[t]
+}
@@ -0,0 +1,332 @@
+package compress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.beyondstorage.io/v5/pkg/iowrap"
+	"go.beyondstorage.io/v5/pkg/middleware"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// Options is the compress supported options.
+type Options struct {
+	// Codec is used to compress new objects, GzipCodec by default.
+	Codec Codec
+	// Codecs are extra codecs used to decompress existing objects.
+	Codecs []Codec
+	// BlockSize is the size of uncompressed blocks, DefaultBlockSize by default.
+	// BlockSize larger than MaxBlockSize will be rejected by New.
+	//
+	// Range reads will need to decompress at least one block.
+	BlockSize int64
+	// SpoolSize is the max compressed size buffered in memory before spilling
+	// into a temp file, 8 MiB by default.
+	SpoolSize int64
+	// SkipContentType returns whether an object with content type t should be
+	// written without compression, IsCompressedContentType by default.
+	SkipContentType func(t string) bool
+}
+
+// Storager is a Storager which compresses objects before writing them into store.
+type Storager struct {
+	types.Storager
+
+	store     types.Storager
+	codec     Codec
+	codecs    map[string]Codec
+	blockSize int64
+	spoolSize int64
+	skip      func(t string) bool
+}
+
+// New will create a compressing Storager for store.
+func New(store types.Storager, o *Options) (*Storager, error) {
+	s := &Storager{
+		store:     store,
+		codec:     GzipCodec{},
+		codecs:    make(map[string]Codec),
+		blockSize: DefaultBlockSize,
+		spoolSize: 8 * 1024 * 1024,
+		skip:      IsCompressedContentType,
+	}
+	if o != nil {
+		if o.Codec != nil {
+			s.codec = o.Codec
+		}
+		for _, c := range o.Codecs {
+			s.codecs[c.Name()] = c
+		}
+		if o.BlockSize > MaxBlockSize {
+			return nil, fmt.Errorf("compress: block size %d exceeds %d", o.BlockSize, MaxBlockSize)
+		}
+		if o.BlockSize > 0 {
+			s.blockSize = o.BlockSize
+		}
+		if o.SpoolSize > 0 {
+			s.spoolSize = o.SpoolSize
+		}
+		if o.SkipContentType != nil {
+			s.skip = o.SkipContentType
+		}
+	}
+	s.codecs[s.codec.Name()] = s.codec
+	s.Storager = middleware.Wrap(store, s.middleware)
+	return s, nil
+}
+
+// Features implements Storager.Features
+func (s *Storager) Features() types.StorageFeatures {
+	f := s.store.Features()
+	// Signed read will return the compressed content.
+	f.QuerySignHTTPRead = false
+	return f
+}
+
+func (s *Storager) middleware(next middleware.Handler) middleware.Handler {
+	return func(ctx context.Context, c middleware.Call) {
+		switch v := c.(type) {
+		case *middleware.ReadCall:
+			v.N, v.Err = s.read(ctx, v, next)
+		case *middleware.WriteCall:
+			v.N, v.Err = s.write(ctx, v, next)
+		case *middleware.StatCall:
+			next(ctx, c)
+			if v.Err == nil {
+				v.Err = s.formatError(c.Op(), s.stat(ctx, v.O), v.Path)
+			}
+		case *middleware.ListCall:
+			next(ctx, c)
+			if v.Err == nil {
+				v.Oi = s.wrapObjectIterator(ctx, v.Path, v.Oi)
+			}
+		case *middleware.QuerySignHTTPReadCall:
+			c.SetResult(s.formatError(c.Op(), services.ErrCapabilityInsufficient, c.Paths()...))
+		default:
+			next(ctx, c)
+		}
+	}
+}
+
+func (s *Storager) read(ctx context.Context, c *middleware.ReadCall, next middleware.Handler) (n int64, err error) {
+	l, err := s.layout(ctx, c.Path, nil)
+	if errors.Is(err, errNotCompressed) {
+		next(ctx, c)
+		return c.N, c.Err
+	}
+	if err != nil {
+		return 0, s.formatError(c.Op(), err, c.Path)
+	}
+	codec, ok := s.codecs[l.codec]
+	if !ok {
+		return 0, s.formatError(c.Op(), fmt.Errorf("codec %s: %w", l.codec, services.ErrCapabilityInsufficient), c.Path)
+	}
+
+	var offset, size int64 = 0, -1
+	ps := make([]types.Pair, 0, len(c.Pairs))
+	w := c.W
+	for _, p := range c.Pairs {
+		switch p.Key {
+		case "offset":
+			offset = p.Value.(int64)
+		case "size":
+			size = p.Value.(int64)
+		case "io_callback":
+			// io_callback should be called with uncompressed data.
+			w = iowrap.CallbackWriter(w, p.Value.(func([]byte)))
+		default:
+			ps = append(ps, p)
+		}
+	}
+	if size < 0 || offset+size > l.size {
+		size = l.size - offset
+	}
+	if size <= 0 {
+		return 0, nil
+	}
+
+	first, last := offset/l.blockSize, (offset+size-1)/l.blockSize
+	start, end := l.offsets[first], l.offsets[last+1]
+	d := &decompressWriter{
+		w:      w,
+		codec:  codec,
+		layout: l,
+		idx:    first,
+		skip:   offset - first*l.blockSize,
+		limit:  size,
+	}
+
+	c.W = d
+	c.Pairs = append(ps, types.Pair{Key: "offset", Value: start}, types.Pair{Key: "size", Value: end - start})
+	next(ctx, c)
+	if c.Err != nil {
+		return d.n, c.Err
+	}
+	return d.n, s.formatError(c.Op(), d.Close(), c.Path)
+}
+
+// layout will read the footer and index of the object, o will be used if it's not nil.
+//
+// errNotCompressed will be returned if the object is not compressed.
+func (s *Storager) layout(ctx context.Context, path string, o *types.Object) (*layout, error) {
+	var err error
+	if o == nil {
+		if o, err = s.store.StatWithContext(ctx, path); err != nil {
+			return nil, err
+		}
+	}
+	if !o.Mode.IsRead() {
+		return nil, errNotCompressed
+	}
+	size, ok := o.GetContentLength()
+	if !ok || size < int64(FooterSize) {
+		return nil, errNotCompressed
+	}
+
+	// Read a bit more than footer so that index of small objects could be read at once.
+	tailSize := int64(FooterSize) + 4*64
+	if tailSize > size {
+		tailSize = size
+	}
+	tail, err := s.readRange(ctx, path, size-tailSize, tailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var f footer
+	if err = f.UnmarshalBinary(tail[len(tail)-FooterSize:]); err != nil {
+		return nil, err
+	}
+	tail = tail[:len(tail)-FooterSize]
+
+	indexSize := f.indexSize()
+	if indexSize+int64(FooterSize) > size {
+		return nil, ErrObjectInvalid
+	}
+	var index []byte
+	if indexSize <= int64(len(tail)) {
+		index = tail[int64(len(tail))-indexSize:]
+	} else if index, err = s.readRange(ctx, path, size-int64(FooterSize)-indexSize, indexSize); err != nil {
+		return nil, err
+	}
+
+	l, err := parseIndex(f, index)
+	if err != nil {
+		return nil, err
+	}
+	if l.offsets[f.blockCount]+indexSize+int64(FooterSize) != size {
+		return nil, ErrObjectInvalid
+	}
+	return l, nil
+}
+
+func (s *Storager) readRange(ctx context.Context, path string, offset, size int64) ([]byte, error) {
+	b := make([]byte, 0, size)
+	w := iowrap.CallbackWriter(io.Discard, func(p []byte) {
+		b = append(b, p...)
+	})
+	n, err := s.store.ReadWithContext(ctx, path, w,
+		types.Pair{Key: "offset", Value: offset}, types.Pair{Key: "size", Value: size})
+	if err != nil {
+		return nil, err
+	}
+	if n != size {
+		return nil, ErrObjectInvalid
+	}
+	return b, nil
+}
+
+func (s *Storager) write(ctx context.Context, c *middleware.WriteCall, next middleware.Handler) (n int64, err error) {
+	// According to GSP-751, nil reader is allowed for empty object.
+	if c.R == nil && c.Size != 0 {
+		return 0, s.formatError(c.Op(), errors.New("reader is nil but size is not nil"), c.Path)
+	}
+
+	for _, p := range c.Pairs {
+		if p.Key == "content_type" && s.skip(p.Value.(string)) {
+			next(ctx, c)
+			return c.N, c.Err
+		}
+	}
+
+	r := c.R
+	ps := make([]types.Pair, 0, len(c.Pairs))
+	for _, p := range c.Pairs {
+		switch p.Key {
+		case "io_callback":
+			r = iowrap.CallbackReader(r, p.Value.(func([]byte)))
+		case "content_md5":
+			// content_md5 of uncompressed data will not match the compressed content.
+		default:
+			ps = append(ps, p)
+		}
+	}
+
+	sp := &spool{limit: s.spoolSize}
+	defer sp.Close()
+	if _, err = compressTo(sp, r, c.Size, s.codec, s.blockSize); err != nil {
+		return 0, s.formatError(c.Op(), err, c.Path)
+	}
+	cr, err := sp.Reader()
+	if err != nil {
+		return 0, s.formatError(c.Op(), err, c.Path)
+	}
+
+	size := c.Size
+	c.R, c.Size, c.Pairs = cr, sp.size, ps
+	next(ctx, c)
+	if c.Err != nil {
+		return 0, c.Err
+	}
+	return size, nil
+}
+
+// stat will replace content_length of o with the uncompressed size.
+func (s *Storager) stat(ctx context.Context, o *types.Object) error {
+	l, err := s.layout(ctx, o.Path, o)
+	if errors.Is(err, errNotCompressed) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	o.SetContentLength(l.size)
+	return nil
+}
+
+func (s *Storager) wrapObjectIterator(ctx context.Context, path string, it *types.ObjectIterator) *types.ObjectIterator {
+	fn := types.NextObjectFunc(func(ctx context.Context, page *types.ObjectPage) error {
+		o, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if err = s.stat(ctx, o); err != nil {
+			return s.formatError("list", err, path)
+		}
+		page.Data = append(page.Data, o)
+		return nil
+	})
+	return types.NewObjectIterator(ctx, fn, it)
+}
+
+func (s *Storager) formatError(op string, err error, path ...string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(services.InternalError); ok {
+		return err
+	}
+	var e services.StorageError
+	if errors.As(err, &e) {
+		return err
+	}
+	return services.StorageError{
+		Op:       op,
+		Err:      err,
+		Storager: s,
+		Path:     path,
+	}
+}
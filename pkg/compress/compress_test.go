@@ -0,0 +1,119 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/internal/storagetest"
+	"go.beyondstorage.io/v5/pairs"
+)
+
+func logContent(size int) []byte {
+	var b bytes.Buffer
+	for i := 0; b.Len() < size; i++ {
+		b.WriteString("2021-09-01T00:00:00Z INFO request handled path=/api/v1/objects status=200\n")
+	}
+	return b.Bytes()[:size]
+}
+
+func TestReadWrite(t *testing.T) {
+	store := storagetest.New()
+	s, err := New(store, &Options{BlockSize: 1024, SpoolSize: 2048})
+	assert.NoError(t, err)
+
+	for _, size := range []int{0, 1, 1024, 1025, 100 * 1024} {
+		content := logContent(size)
+
+		n, err := s.Write("a", bytes.NewReader(content), int64(size))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(size), n)
+		if size >= 1024 {
+			assert.Less(t, len(store.Data("a")), size)
+		}
+
+		o, err := s.Stat("a")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(size), o.MustGetContentLength())
+
+		var buf bytes.Buffer
+		n, err = s.Read("a", &buf)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(size), n)
+		assert.True(t, bytes.Equal(content, buf.Bytes()))
+	}
+}
+
+func TestReadRange(t *testing.T) {
+	s, err := New(storagetest.New(), &Options{BlockSize: 1024})
+	assert.NoError(t, err)
+
+	size := int64(10*1024 + 7)
+	content := logContent(int(size))
+	_, err = s.Write("a", bytes.NewReader(content), size)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		offset, size int64
+	}{
+		{0, 10},
+		{10, 1024},
+		{1023, 2},
+		{3 * 1024, 3 * 1024},
+		{size - 3, 100},
+	}
+	for _, tt := range cases {
+		var buf bytes.Buffer
+		n, err := s.Read("a", &buf, pairs.WithOffset(tt.offset), pairs.WithSize(tt.size))
+		assert.NoError(t, err)
+
+		end := tt.offset + tt.size
+		if end > size {
+			end = size
+		}
+		assert.Equal(t, end-tt.offset, n)
+		assert.Equal(t, content[tt.offset:end], buf.Bytes())
+	}
+}
+
+func TestSkipContentType(t *testing.T) {
+	store := storagetest.New()
+	s, err := New(store, nil)
+	assert.NoError(t, err)
+
+	content := logContent(4096)
+	_, err = s.Write("a.gz", bytes.NewReader(content), 4096, pairs.WithContentType("application/gzip"))
+	assert.NoError(t, err)
+	assert.Equal(t, content, store.Data("a.gz"))
+
+	// Objects not compressed by us will be read as is.
+	store.Put("b", []byte("hello"))
+	for _, p := range []string{"a.gz", "b"} {
+		o, err := s.Stat(p)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(store.Data(p))), o.MustGetContentLength())
+
+		var buf bytes.Buffer
+		_, err = s.Read(p, &buf, pairs.WithOffset(1), pairs.WithSize(3))
+		assert.NoError(t, err)
+		assert.Equal(t, store.Data(p)[1:4], buf.Bytes())
+	}
+}
+
+func TestBlockSize(t *testing.T) {
+	_, err := New(storagetest.New(), &Options{BlockSize: MaxBlockSize})
+	assert.NoError(t, err)
+
+	// Block size must fit in the footer.
+	_, err = New(storagetest.New(), &Options{BlockSize: 4 * 1024 * 1024 * 1024})
+	assert.Error(t, err)
+}
+
+func TestIsCompressedContentType(t *testing.T) {
+	assert.True(t, IsCompressedContentType("application/gzip"))
+	assert.True(t, IsCompressedContentType("image/png"))
+	assert.True(t, IsCompressedContentType("Video/MP4; codecs=avc1"))
+	assert.False(t, IsCompressedContentType("text/plain; charset=utf-8"))
+	assert.False(t, IsCompressedContentType("image/svg+xml"))
+}
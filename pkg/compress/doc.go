@@ -0,0 +1,21 @@
+/*
+Package compress provides a Storager which compresses objects on Write and decompresses them on Read.
+
+Objects are split into blocks of BlockSize and every block is compressed
+independently by a Codec. The layout of a compressed object is:
+
+	block 0 | block 1 | ... | block N | index | footer
+
+index contains the compressed size of every block, and footer contains the
+codec name, the original size and the block size. With the index, Read with
+offset and size pairs will only fetch and decompress the needed blocks, and
+content_length returned by Stat and List is the original size.
+
+Objects whose content type is already compressed will be written as is, and
+objects without footer will be read as is, so existing objects and objects
+written by append, multipart or other operations are still readable.
+
+Stat and List need to read the footer of every object, which means an extra
+ranged Read for every object.
+*/
+package compress
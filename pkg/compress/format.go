@@ -0,0 +1,273 @@
+package compress
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+const (
+	// DefaultBlockSize is the default size of uncompressed blocks.
+	DefaultBlockSize = 1024 * 1024
+	// MaxBlockSize is the max size of uncompressed blocks. Block size and
+	// compressed sizes of blocks are stored as uint32, so the limit leaves
+	// room for blocks which grow after compression.
+	MaxBlockSize = 2 * 1024 * 1024 * 1024
+	// MaxCodecNameLength is the max length of codec names.
+	MaxCodecNameLength = 16
+	// FooterSize is the size of the footer of compressed objects.
+	FooterSize = MaxCodecNameLength + 8 + 4 + 4 + len(magic)
+
+	// magic contains the format version in the last byte.
+	magic = "GSCOMPR\x01"
+)
+
+// ErrObjectInvalid means the compressed object has been corrupted.
+var ErrObjectInvalid = errors.New("compressed object invalid")
+
+type footer struct {
+	codec      string
+	size       int64
+	blockSize  int64
+	blockCount int64
+}
+
+// indexSize returns the size of index.
+func (f footer) indexSize() int64 {
+	return f.blockCount * 4
+}
+
+func (f footer) MarshalBinary() ([]byte, error) {
+	if len(f.codec) > MaxCodecNameLength {
+		return nil, fmt.Errorf("codec name %s is too long", f.codec)
+	}
+
+	b := make([]byte, FooterSize)
+	copy(b, f.codec)
+	x := b[MaxCodecNameLength:]
+	binary.BigEndian.PutUint64(x, uint64(f.size))
+	binary.BigEndian.PutUint32(x[8:], uint32(f.blockSize))
+	binary.BigEndian.PutUint32(x[12:], uint32(f.blockCount))
+	copy(x[16:], magic)
+	return b, nil
+}
+
+// UnmarshalBinary will return errNotCompressed if b doesn't have the magic.
+func (f *footer) UnmarshalBinary(b []byte) error {
+	if len(b) != FooterSize || string(b[FooterSize-len(magic):]) != magic {
+		return errNotCompressed
+	}
+	f.codec = string(bytes.TrimRight(b[:MaxCodecNameLength], "\x00"))
+	x := b[MaxCodecNameLength:]
+	f.size = int64(binary.BigEndian.Uint64(x))
+	f.blockSize = int64(binary.BigEndian.Uint32(x[8:]))
+	f.blockCount = int64(binary.BigEndian.Uint32(x[12:]))
+	if f.blockSize <= 0 || f.blockCount != (f.size+f.blockSize-1)/f.blockSize {
+		return ErrObjectInvalid
+	}
+	return nil
+}
+
+// errNotCompressed means the object is not written by this package.
+var errNotCompressed = errors.New("not compressed")
+
+// layout is the parsed footer and index of a compressed object.
+type layout struct {
+	footer
+	// offsets contains the start offset of every block and the end offset of the last block.
+	offsets []int64
+}
+
+func parseIndex(f footer, b []byte) (*layout, error) {
+	if int64(len(b)) != f.indexSize() {
+		return nil, ErrObjectInvalid
+	}
+	l := &layout{
+		footer:  f,
+		offsets: make([]int64, f.blockCount+1),
+	}
+	for i := int64(0); i < f.blockCount; i++ {
+		l.offsets[i+1] = l.offsets[i] + int64(binary.BigEndian.Uint32(b[i*4:]))
+	}
+	return l, nil
+}
+
+// spool buffers data in memory and spills into a temp file when it's too large.
+type spool struct {
+	limit int64
+	size  int64
+	buf   bytes.Buffer
+	f     *os.File
+}
+
+func (s *spool) Write(p []byte) (n int, err error) {
+	if s.f == nil && int64(s.buf.Len()+len(p)) > s.limit {
+		if s.f, err = ioutil.TempFile("", "go-storage-compress-"); err != nil {
+			return 0, err
+		}
+		if _, err = s.buf.WriteTo(s.f); err != nil {
+			return 0, err
+		}
+	}
+	if s.f != nil {
+		n, err = s.f.Write(p)
+	} else {
+		n, err = s.buf.Write(p)
+	}
+	s.size += int64(n)
+	return n, err
+}
+
+// Reader returns a reader of all written data.
+func (s *spool) Reader() (io.Reader, error) {
+	if s.f == nil {
+		return bytes.NewReader(s.buf.Bytes()), nil
+	}
+	if _, err := s.f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return s.f, nil
+}
+
+// Close will remove the temp file.
+func (s *spool) Close() error {
+	if s.f == nil {
+		return nil
+	}
+	_ = s.f.Close()
+	return os.Remove(s.f.Name())
+}
+
+// compressTo will compress size bytes from r into w and returns the footer.
+func compressTo(w io.Writer, r io.Reader, size int64, codec Codec, blockSize int64) (f footer, err error) {
+	f = footer{
+		codec:      codec.Name(),
+		size:       size,
+		blockSize:  blockSize,
+		blockCount: (size + blockSize - 1) / blockSize,
+	}
+
+	cw := &countWriter{w: w}
+	index := make([]byte, f.indexSize())
+	buf := make([]byte, blockSize)
+	for i := int64(0); i < f.blockCount; i++ {
+		n := blockSize
+		if rest := size - i*blockSize; rest < n {
+			n = rest
+		}
+		if _, err = io.ReadFull(r, buf[:n]); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return
+		}
+
+		start := cw.n
+		zw, err := codec.NewWriter(cw)
+		if err != nil {
+			return f, err
+		}
+		if _, err = zw.Write(buf[:n]); err != nil {
+			return f, err
+		}
+		if err = zw.Close(); err != nil {
+			return f, err
+		}
+		binary.BigEndian.PutUint32(index[i*4:], uint32(cw.n-start))
+	}
+
+	fb, err := f.MarshalBinary()
+	if err != nil {
+		return
+	}
+	if _, err = cw.Write(index); err != nil {
+		return
+	}
+	_, err = cw.Write(fb)
+	return
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (n int, err error) {
+	n, err = w.w.Write(p)
+	w.n += int64(n)
+	return
+}
+
+// decompressWriter decompresses blocks written into it and writes data into w.
+type decompressWriter struct {
+	w      io.Writer
+	codec  Codec
+	layout *layout
+	// idx is the index of the block being written.
+	idx int64
+	// skip is the bytes to skip in the first block.
+	skip int64
+	// limit is the remaining bytes to write.
+	limit int64
+
+	buf bytes.Buffer
+	n   int64
+}
+
+func (d *decompressWriter) Write(p []byte) (n int, err error) {
+	n = len(p)
+	for len(p) > 0 {
+		if d.idx >= d.layout.blockCount {
+			return 0, ErrObjectInvalid
+		}
+		want := d.layout.offsets[d.idx+1] - d.layout.offsets[d.idx] - int64(d.buf.Len())
+		x := int64(len(p))
+		if x > want {
+			x = want
+		}
+		d.buf.Write(p[:x])
+		p = p[x:]
+		if x == want {
+			if err = d.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (d *decompressWriter) flush() error {
+	zr, err := d.codec.NewReader(&d.buf)
+	if err != nil {
+		return fmt.Errorf("block %d: %v: %w", d.idx, err, ErrObjectInvalid)
+	}
+	defer zr.Close()
+
+	if d.skip > 0 {
+		if _, err = io.CopyN(ioutil.Discard, zr, d.skip); err != nil {
+			return fmt.Errorf("block %d: %v: %w", d.idx, err, ErrObjectInvalid)
+		}
+		d.skip = 0
+	}
+	n, err := io.Copy(d.w, io.LimitReader(zr, d.limit))
+	d.n += n
+	d.limit -= n
+	if err != nil {
+		return err
+	}
+	d.idx++
+	d.buf.Reset()
+	return nil
+}
+
+// Close checks whether all required data has been written.
+func (d *decompressWriter) Close() error {
+	if d.limit > 0 || d.buf.Len() > 0 {
+		return ErrObjectInvalid
+	}
+	return nil
+}
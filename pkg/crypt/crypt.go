@@ -0,0 +1,294 @@
+package crypt
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"go.beyondstorage.io/v5/pkg/iowrap"
+	"go.beyondstorage.io/v5/pkg/middleware"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// Storager is a Storager which encrypts objects before writing them into store.
+type Storager struct {
+	types.Storager
+
+	store types.Storager
+	keys  KeyProvider
+}
+
+// New will create an encrypting Storager for store with keys from keys.
+//
+// store SHOULD only contain objects written by this package, other objects
+// will fail to read or stat.
+func New(store types.Storager, keys KeyProvider) *Storager {
+	s := &Storager{
+		store: store,
+		keys:  keys,
+	}
+	s.Storager = middleware.Wrap(store, s.middleware)
+	return s
+}
+
+// Features implements Storager.Features
+//
+// Only operations that work with the encrypted layout are kept.
+func (s *Storager) Features() types.StorageFeatures {
+	f := s.store.Features()
+	return types.StorageFeatures{
+		LoosePair:             f.LoosePair,
+		VirtualDir:            f.VirtualDir,
+		VirtualLink:           f.VirtualLink,
+		VirtualObjectMetadata: f.VirtualObjectMetadata,
+		WriteEmptyObject:      f.WriteEmptyObject,
+		Create:                f.Create,
+		Delete:                f.Delete,
+		Metadata:              f.Metadata,
+		List:                  f.List,
+		Read:                  f.Read,
+		Stat:                  f.Stat,
+		Write:                 f.Write,
+		Copy:                  f.Copy,
+		CreateDir:             f.CreateDir,
+		CreateLink:            f.CreateLink,
+		Move:                  f.Move,
+		QuerySignHTTPDelete:   f.QuerySignHTTPDelete,
+	}
+}
+
+func (s *Storager) middleware(next middleware.Handler) middleware.Handler {
+	return func(ctx context.Context, c middleware.Call) {
+		switch v := c.(type) {
+		case *middleware.ReadCall:
+			v.N, v.Err = s.read(ctx, v, next)
+		case *middleware.WriteCall:
+			v.N, v.Err = s.write(ctx, v, next)
+		case *middleware.StatCall:
+			next(ctx, c)
+			if v.Err == nil {
+				v.Err = s.formatError(c.Op(), decryptObject(v.O), v.Path)
+			}
+		case *middleware.ListCall:
+			next(ctx, c)
+			if v.Err == nil {
+				v.Oi = s.wrapObjectIterator(ctx, v.Path, v.Oi)
+			}
+		case *middleware.CreateCall, *middleware.MetadataCall, *middleware.DeleteCall,
+			*middleware.CopyCall, *middleware.MoveCall, *middleware.CreateDirCall,
+			*middleware.CreateLinkCall, *middleware.QuerySignHTTPDeleteCall:
+			// These operations don't touch object content.
+			next(ctx, c)
+		default:
+			c.SetResult(s.formatError(c.Op(), services.ErrCapabilityInsufficient, c.Paths()...))
+		}
+	}
+}
+
+func (s *Storager) read(ctx context.Context, c *middleware.ReadCall, next middleware.Handler) (n int64, err error) {
+	var offset, size int64 = 0, -1
+	ps := make([]types.Pair, 0, len(c.Pairs))
+	w := c.W
+	for _, p := range c.Pairs {
+		switch p.Key {
+		case "offset":
+			offset = p.Value.(int64)
+		case "size":
+			size = p.Value.(int64)
+		case "io_callback":
+			// io_callback should be called with plaintext.
+			w = iowrap.CallbackWriter(w, p.Value.(func([]byte)))
+		default:
+			ps = append(ps, p)
+		}
+	}
+
+	d := &decryptWriter{w: w, limit: -1, last: true}
+	if offset == 0 && size < 0 {
+		// Read the whole object in one request, header will be parsed while writing.
+		d.open = func(h header) (cipher.AEAD, error) {
+			return s.open(ctx, h)
+		}
+		c.W, c.Pairs = d, ps
+		next(ctx, c)
+		if c.Err != nil {
+			return d.n, c.Err
+		}
+		return d.n, s.formatError(c.Op(), d.Close(), c.Path)
+	}
+
+	o, err := s.store.StatWithContext(ctx, c.Path)
+	if err != nil {
+		return 0, err
+	}
+	encrypted, ok := o.GetContentLength()
+	if !ok {
+		return 0, s.formatError(c.Op(), ErrObjectInvalid, c.Path)
+	}
+	total, err := DecryptedSize(encrypted)
+	if err != nil {
+		return 0, s.formatError(c.Op(), err, c.Path)
+	}
+	if size < 0 || offset+size > total {
+		size = total - offset
+	}
+	if size <= 0 {
+		return 0, nil
+	}
+
+	if d.aead, err = s.readHeader(ctx, c.Path, ps); err != nil {
+		return 0, s.formatError(c.Op(), err, c.Path)
+	}
+
+	first, last := offset/ChunkSize, (offset+size-1)/ChunkSize
+	start := int64(HeaderSize) + first*encryptedChunkSize
+	end := int64(HeaderSize) + (last+1)*encryptedChunkSize
+	if end > encrypted {
+		end = encrypted
+	}
+	d.idx = first
+	d.skip = offset - first*ChunkSize
+	d.limit = size
+	d.last = last == chunkCount(total)-1
+
+	c.W = d
+	c.Pairs = append(ps, types.Pair{Key: "offset", Value: start}, types.Pair{Key: "size", Value: end - start})
+	next(ctx, c)
+	if c.Err != nil {
+		return d.n, c.Err
+	}
+	return d.n, s.formatError(c.Op(), d.Close(), c.Path)
+}
+
+func (s *Storager) readHeader(ctx context.Context, path string, ps []types.Pair) (cipher.AEAD, error) {
+	b := make([]byte, 0, HeaderSize)
+	buf := iowrap.CallbackWriter(io.Discard, func(p []byte) {
+		b = append(b, p...)
+	})
+	ps = append(ps[:len(ps):len(ps)], types.Pair{Key: "offset", Value: int64(0)}, types.Pair{Key: "size", Value: int64(HeaderSize)})
+	if _, err := s.store.ReadWithContext(ctx, path, buf, ps...); err != nil {
+		return nil, err
+	}
+
+	var h header
+	if err := h.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return s.open(ctx, h)
+}
+
+// open will unwrap the data key in header and create the AEAD for chunks.
+func (s *Storager) open(ctx context.Context, h header) (cipher.AEAD, error) {
+	key, err := s.keys.KeyByID(ctx, h.keyID)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := unwrapKey(key, h.wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	return newAEAD(dataKey)
+}
+
+func (s *Storager) write(ctx context.Context, c *middleware.WriteCall, next middleware.Handler) (n int64, err error) {
+	// According to GSP-751, nil reader is allowed for empty object.
+	if c.R == nil && c.Size != 0 {
+		return 0, s.formatError(c.Op(), errors.New("reader is nil but size is not nil"), c.Path)
+	}
+
+	r := c.R
+	ps := make([]types.Pair, 0, len(c.Pairs))
+	for _, p := range c.Pairs {
+		switch p.Key {
+		case "io_callback":
+			r = iowrap.CallbackReader(r, p.Value.(func([]byte)))
+		case "content_md5":
+			// content_md5 of plaintext will not match the encrypted content.
+		default:
+			ps = append(ps, p)
+		}
+	}
+
+	id, key, err := s.keys.Key(ctx)
+	if err != nil {
+		return 0, s.formatError(c.Op(), err, c.Path)
+	}
+	dataKey := make([]byte, KeySize)
+	if _, err = rand.Read(dataKey); err != nil {
+		return 0, s.formatError(c.Op(), err, c.Path)
+	}
+	wrapped, err := wrapKey(key, dataKey)
+	if err != nil {
+		return 0, s.formatError(c.Op(), err, c.Path)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return 0, s.formatError(c.Op(), err, c.Path)
+	}
+	er, err := newEncryptReader(r, c.Size, aead, header{keyID: id, wrappedKey: wrapped})
+	if err != nil {
+		return 0, s.formatError(c.Op(), err, c.Path)
+	}
+
+	size := c.Size
+	c.R, c.Size, c.Pairs = er, EncryptedSize(size), ps
+	next(ctx, c)
+	if c.Err != nil {
+		return 0, c.Err
+	}
+	return size, nil
+}
+
+func (s *Storager) wrapObjectIterator(ctx context.Context, path string, it *types.ObjectIterator) *types.ObjectIterator {
+	fn := types.NextObjectFunc(func(ctx context.Context, page *types.ObjectPage) error {
+		o, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if err = decryptObject(o); err != nil {
+			return s.formatError("list", err, path)
+		}
+		page.Data = append(page.Data, o)
+		return nil
+	})
+	return types.NewObjectIterator(ctx, fn, it)
+}
+
+// decryptObject will replace content_length of o with the plaintext size.
+func decryptObject(o *types.Object) error {
+	if !o.Mode.IsRead() {
+		return nil
+	}
+	size, ok := o.GetContentLength()
+	if !ok {
+		return nil
+	}
+	size, err := DecryptedSize(size)
+	if err != nil {
+		return err
+	}
+	o.SetContentLength(size)
+	return nil
+}
+
+func (s *Storager) formatError(op string, err error, path ...string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(services.InternalError); ok {
+		return err
+	}
+	var e services.StorageError
+	if errors.As(err, &e) {
+		return err
+	}
+	return services.StorageError{
+		Op:       op,
+		Err:      err,
+		Storager: s,
+		Path:     path,
+	}
+}
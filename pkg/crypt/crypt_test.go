@@ -0,0 +1,158 @@
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/internal/storagetest"
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/services"
+)
+
+func newTestStorager(t *testing.T) (*Storager, *storagetest.Storager) {
+	keys, err := NewStaticKeyProvider("v1", bytes.Repeat([]byte{1}, KeySize))
+	assert.NoError(t, err)
+
+	store := storagetest.New()
+	return New(store, keys), store
+}
+
+func TestSize(t *testing.T) {
+	for _, size := range []int64{0, 1, ChunkSize - 1, ChunkSize, ChunkSize + 1, 3 * ChunkSize} {
+		x, err := DecryptedSize(EncryptedSize(size))
+		assert.NoError(t, err)
+		assert.Equal(t, size, x)
+	}
+
+	_, err := DecryptedSize(int64(HeaderSize) + 3)
+	assert.ErrorIs(t, err, ErrObjectInvalid)
+}
+
+func TestReadWrite(t *testing.T) {
+	s, store := newTestStorager(t)
+
+	for _, size := range []int64{0, 1, ChunkSize, ChunkSize + 1, 3*ChunkSize - 5} {
+		content := make([]byte, size)
+		rand.Read(content)
+
+		n, err := s.Write("a", bytes.NewReader(content), size)
+		assert.NoError(t, err)
+		assert.Equal(t, size, n)
+		assert.Equal(t, EncryptedSize(size), int64(len(store.Data("a"))))
+		if size > 16 {
+			assert.False(t, bytes.Contains(store.Data("a"), content[:16]))
+		}
+
+		o, err := s.Stat("a")
+		assert.NoError(t, err)
+		assert.Equal(t, size, o.MustGetContentLength())
+
+		var buf bytes.Buffer
+		n, err = s.Read("a", &buf)
+		assert.NoError(t, err)
+		assert.Equal(t, size, n)
+		assert.True(t, bytes.Equal(content, buf.Bytes()))
+	}
+}
+
+func TestReadRange(t *testing.T) {
+	s, _ := newTestStorager(t)
+
+	size := int64(3*ChunkSize - 5)
+	content := make([]byte, size)
+	rand.Read(content)
+	_, err := s.Write("a", bytes.NewReader(content), size)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		offset, size int64
+	}{
+		{0, 10},
+		{10, ChunkSize},
+		{ChunkSize - 1, 2},
+		{ChunkSize, ChunkSize},
+		{2 * ChunkSize, ChunkSize - 5},
+		{size - 3, 100},
+	}
+	for _, tt := range cases {
+		var buf bytes.Buffer
+		n, err := s.Read("a", &buf, pairs.WithOffset(tt.offset), pairs.WithSize(tt.size))
+		assert.NoError(t, err)
+
+		end := tt.offset + tt.size
+		if end > size {
+			end = size
+		}
+		assert.Equal(t, end-tt.offset, n)
+		assert.Equal(t, content[tt.offset:end], buf.Bytes())
+	}
+
+	// offset without size will read until the end.
+	var buf bytes.Buffer
+	_, err = s.Read("a", &buf, pairs.WithOffset(ChunkSize+7))
+	assert.NoError(t, err)
+	assert.Equal(t, content[ChunkSize+7:], buf.Bytes())
+}
+
+func TestTamper(t *testing.T) {
+	s, store := newTestStorager(t)
+
+	content := make([]byte, 2*ChunkSize)
+	_, err := s.Write("a", bytes.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+
+	// Flip a bit in the last chunk.
+	o := store.Object("a")
+	o.Data[len(o.Data)-1] ^= 1
+	_, err = s.Read("a", ioutil.Discard)
+	assert.ErrorIs(t, err, ErrObjectInvalid)
+
+	// Drop the last chunk.
+	o.Data = o.Data[:HeaderSize+encryptedChunkSize]
+	_, err = s.Read("a", ioutil.Discard)
+	assert.ErrorIs(t, err, ErrObjectInvalid)
+}
+
+func TestKeyRotation(t *testing.T) {
+	store := storagetest.New()
+	old, err := NewStaticKeyProvider("v1", bytes.Repeat([]byte{1}, KeySize))
+	assert.NoError(t, err)
+	_, err = New(store, old).Write("a", bytes.NewReader([]byte("hello")), 5)
+	assert.NoError(t, err)
+
+	keys, err := NewStaticKeyProvider("v2", bytes.Repeat([]byte{2}, KeySize))
+	assert.NoError(t, err)
+	s := New(store, keys)
+
+	_, err = s.Read("a", ioutil.Discard)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	assert.NoError(t, keys.Add("v1", bytes.Repeat([]byte{1}, KeySize)))
+	var buf bytes.Buffer
+	_, err = s.Read("a", &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", buf.String())
+
+	// New objects will be encrypted by the current key.
+	assert.ErrorIs(t, keys.SetCurrent("v3"), ErrKeyNotFound)
+	assert.NoError(t, keys.SetCurrent("v1"))
+	_, err = s.Write("b", bytes.NewReader([]byte("world")), 5)
+	assert.NoError(t, err)
+	buf.Reset()
+	_, err = New(store, old).Read("b", &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "world", buf.String())
+}
+
+func TestUnsupported(t *testing.T) {
+	s, _ := newTestStorager(t)
+
+	_, err := s.CreateMultipart("a")
+	assert.True(t, errors.Is(err, services.ErrCapabilityInsufficient))
+	assert.False(t, s.Features().CreateMultipart)
+}
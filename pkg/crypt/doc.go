@@ -0,0 +1,21 @@
+/*
+Package crypt provides a Storager which encrypts object content before it leaves the process.
+
+Every object is encrypted with its own random data key by AES-256-GCM in
+fixed size chunks, and the data key is wrapped by a key returned by
+KeyProvider. The layout of an encrypted object is:
+
+	header | chunk 0 | chunk 1 | ... | chunk N
+
+header contains the magic, the chunk size, the id of the wrapping key and the
+wrapped data key. Every chunk contains at most ChunkSize bytes plaintext and a
+16 bytes GCM tag. The last chunk is authenticated differently, so that
+truncated objects will be detected.
+
+Read with offset and size pairs will only fetch and decrypt the needed chunks,
+and content_length returned by Stat and List is the plaintext size.
+
+Operations that can't be supported with this layout like append, multipart,
+block, page and signed requests will return ErrCapabilityInsufficient.
+*/
+package crypt
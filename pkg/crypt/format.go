@@ -0,0 +1,330 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// ChunkSize is the max plaintext size of a chunk.
+	ChunkSize = 64 * 1024
+	// MaxKeyIDLength is the max length of key id.
+	MaxKeyIDLength = 64
+	// HeaderSize is the size of the header of encrypted objects.
+	HeaderSize = len(magic) + 4 + 1 + MaxKeyIDLength + wrappedKeySize
+
+	tagSize        = 16
+	nonceSize      = 12
+	wrappedKeySize = nonceSize + KeySize + tagSize
+	// encryptedChunkSize is the size of a full chunk after encryption.
+	encryptedChunkSize = ChunkSize + tagSize
+)
+
+// magic contains the format version in the last byte.
+const magic = "GSCRYPT\x01"
+
+var (
+	// ErrKeyInvalid means the key returned by KeyProvider is invalid.
+	ErrKeyInvalid = errors.New("key invalid")
+	// ErrKeyNotFound means the key used by the object is not found.
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrObjectInvalid means the object is not encrypted by this package or has been corrupted.
+	ErrObjectInvalid = errors.New("encrypted object invalid")
+)
+
+// EncryptedSize returns the size of encrypted object with size bytes plaintext.
+func EncryptedSize(size int64) int64 {
+	return int64(HeaderSize) + size + chunkCount(size)*tagSize
+}
+
+// DecryptedSize returns the plaintext size of an encrypted object with size bytes.
+func DecryptedSize(size int64) (int64, error) {
+	rest := size - int64(HeaderSize)
+	if rest < tagSize {
+		return 0, ErrObjectInvalid
+	}
+	full, last := rest/encryptedChunkSize, rest%encryptedChunkSize
+	if last == 0 {
+		return full * ChunkSize, nil
+	}
+	if last < tagSize {
+		return 0, ErrObjectInvalid
+	}
+	return full*ChunkSize + last - tagSize, nil
+}
+
+// chunkCount returns the count of chunks, an empty object still has one chunk.
+func chunkCount(size int64) int64 {
+	if size == 0 {
+		return 1
+	}
+	return (size + ChunkSize - 1) / ChunkSize
+}
+
+type header struct {
+	keyID      string
+	wrappedKey []byte
+}
+
+func (h header) MarshalBinary() ([]byte, error) {
+	if len(h.keyID) > MaxKeyIDLength {
+		return nil, fmt.Errorf("key id %s is too long: %w", h.keyID, ErrKeyInvalid)
+	}
+
+	b := make([]byte, 0, HeaderSize)
+	b = append(b, magic...)
+	b = append(b, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(b[len(magic):], ChunkSize)
+	b = append(b, byte(len(h.keyID)))
+	b = append(b, h.keyID...)
+	b = append(b, make([]byte, MaxKeyIDLength-len(h.keyID))...)
+	b = append(b, h.wrappedKey...)
+	return b, nil
+}
+
+func (h *header) UnmarshalBinary(b []byte) error {
+	if len(b) != HeaderSize || string(b[:len(magic)]) != magic {
+		return ErrObjectInvalid
+	}
+	b = b[len(magic):]
+	if binary.BigEndian.Uint32(b) != ChunkSize {
+		return ErrObjectInvalid
+	}
+	b = b[4:]
+	n := int(b[0])
+	if n > MaxKeyIDLength {
+		return ErrObjectInvalid
+	}
+	h.keyID = string(b[1 : 1+n])
+	h.wrappedKey = append([]byte(nil), b[1+MaxKeyIDLength:]...)
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrKeyInvalid
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// wrapKey will encrypt the data key with the wrapping key.
+func wrapKey(key, dataKey []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nonceSize, wrappedKeySize)
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, dataKey, []byte(magic)), nil
+}
+
+// unwrapKey will decrypt the wrapped data key with the wrapping key.
+func unwrapKey(key, wrapped []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := aead.Open(nil, wrapped[:nonceSize], wrapped[nonceSize:], []byte(magic))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap key: %w", ErrObjectInvalid)
+	}
+	return dataKey, nil
+}
+
+// chunkNonce returns the nonce of chunk idx.
+//
+// Every object has its own data key, so the chunk index is unique enough.
+func chunkNonce(idx int64) []byte {
+	nonce := make([]byte, nonceSize)
+	binary.BigEndian.PutUint64(nonce[4:], uint64(idx))
+	return nonce
+}
+
+// chunkAD returns the additional data of a chunk, which marks the last chunk.
+func chunkAD(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// encryptReader encrypts plaintext from r and produces the whole encrypted object.
+type encryptReader struct {
+	r    io.Reader
+	aead cipher.AEAD
+	// size is the remaining plaintext size.
+	size int64
+	idx  int64
+	done bool
+
+	plain []byte
+	buf   []byte
+}
+
+func newEncryptReader(r io.Reader, size int64, aead cipher.AEAD, h header) (*encryptReader, error) {
+	hb, err := h.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &encryptReader{
+		r:     r,
+		aead:  aead,
+		size:  size,
+		plain: make([]byte, ChunkSize),
+		buf:   hb,
+	}, nil
+}
+
+func (e *encryptReader) Read(p []byte) (n int, err error) {
+	for len(e.buf) == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+		if err = e.next(); err != nil {
+			return 0, err
+		}
+	}
+	n = copy(p, e.buf)
+	e.buf = e.buf[n:]
+	return n, nil
+}
+
+func (e *encryptReader) next() error {
+	n := int64(ChunkSize)
+	if e.size < n {
+		n = e.size
+	}
+	if n > 0 {
+		if _, err := io.ReadFull(e.r, e.plain[:n]); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+	}
+	e.size -= n
+	e.done = e.size == 0
+
+	// Reuse the underlying array of buf, it has been fully consumed.
+	e.buf = e.aead.Seal(e.buf[:0], chunkNonce(e.idx), e.plain[:n], chunkAD(e.done))
+	e.idx++
+	return nil
+}
+
+// decryptWriter decrypts chunks written into it and writes plaintext into w.
+//
+// The last chunk will only be decrypted in Close, because we can't know
+// whether a chunk is the last one before all data has been written.
+type decryptWriter struct {
+	w    io.Writer
+	aead cipher.AEAD
+	// open will be used to parse the header if aead is nil.
+	open func(h header) (cipher.AEAD, error)
+	idx  int64
+	// skip is the plaintext bytes to skip in the first chunk.
+	skip int64
+	// limit is the remaining plaintext bytes to write, -1 means no limit.
+	limit int64
+	// last is whether the data written contains the last chunk of the object.
+	last bool
+
+	buf   []byte
+	plain []byte
+	n     int64
+}
+
+func (d *decryptWriter) Write(p []byte) (n int, err error) {
+	n = len(p)
+	if d.aead == nil {
+		x := HeaderSize - len(d.buf)
+		if x > len(p) {
+			x = len(p)
+		}
+		d.buf = append(d.buf, p[:x]...)
+		p = p[x:]
+		if len(d.buf) < HeaderSize {
+			return n, nil
+		}
+
+		var h header
+		if err = h.UnmarshalBinary(d.buf); err != nil {
+			return 0, err
+		}
+		if d.aead, err = d.open(h); err != nil {
+			return 0, err
+		}
+		d.buf = d.buf[:0]
+	}
+	for len(p) > 0 {
+		// Keep one full chunk buffered so that the last chunk is always decrypted in Close.
+		if len(d.buf) == encryptedChunkSize {
+			if err = d.flush(false); err != nil {
+				return 0, err
+			}
+		}
+		x := encryptedChunkSize - len(d.buf)
+		if x > len(p) {
+			x = len(p)
+		}
+		d.buf = append(d.buf, p[:x]...)
+		p = p[x:]
+	}
+	return n, nil
+}
+
+// Close will decrypt the buffered chunk.
+func (d *decryptWriter) Close() error {
+	if d.aead == nil {
+		return ErrObjectInvalid
+	}
+	if len(d.buf) == 0 {
+		if d.last {
+			// A valid object always has a last chunk.
+			return ErrObjectInvalid
+		}
+		return nil
+	}
+	return d.flush(d.last)
+}
+
+func (d *decryptWriter) flush(last bool) (err error) {
+	d.plain, err = d.aead.Open(d.plain[:0], chunkNonce(d.idx), d.buf, chunkAD(last))
+	if err != nil {
+		return fmt.Errorf("chunk %d: %w", d.idx, ErrObjectInvalid)
+	}
+	d.idx++
+	d.buf = d.buf[:0]
+
+	plain := d.plain
+	if d.skip > 0 {
+		if d.skip >= int64(len(plain)) {
+			d.skip -= int64(len(plain))
+			return nil
+		}
+		plain = plain[d.skip:]
+		d.skip = 0
+	}
+	if d.limit >= 0 {
+		if int64(len(plain)) > d.limit {
+			plain = plain[:d.limit]
+		}
+		d.limit -= int64(len(plain))
+	}
+	if len(plain) == 0 {
+		return nil
+	}
+	n, err := d.w.Write(plain)
+	d.n += int64(n)
+	return err
+}
@@ -0,0 +1,88 @@
+package crypt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// KeySize is the size of keys returned by KeyProvider, which means AES-256 is used.
+const KeySize = 32
+
+// KeyProvider provides keys to wrap data keys of objects.
+type KeyProvider interface {
+	// Key returns the key which will be used to encrypt new objects.
+	//
+	// id will be stored in the object header, so it SHOULD NOT be secret,
+	// and it's length SHOULD NOT exceed MaxKeyIDLength.
+	Key(ctx context.Context) (id string, key []byte, err error)
+	// KeyByID returns the key identified by id, which is used to decrypt existing objects.
+	KeyByID(ctx context.Context, id string) (key []byte, err error)
+}
+
+// StaticKeyProvider is a KeyProvider which keeps keys in memory.
+//
+// Rotating keys could be done by adding a new key and making it current by
+// SetCurrent, existing objects will still be decrypted by their old keys.
+// It's safe to rotate keys while the provider is in use.
+type StaticKeyProvider struct {
+	current string
+	keys    map[string][]byte
+	mu      sync.RWMutex
+}
+
+// NewStaticKeyProvider will create a StaticKeyProvider with the key as current.
+func NewStaticKeyProvider(id string, key []byte) (*StaticKeyProvider, error) {
+	p := &StaticKeyProvider{keys: make(map[string][]byte)}
+	if err := p.Add(id, key); err != nil {
+		return nil, err
+	}
+	p.current = id
+	return p, nil
+}
+
+// Add will add an extra key which can be used to decrypt objects.
+func (p *StaticKeyProvider) Add(id string, key []byte) error {
+	if len(key) != KeySize {
+		return fmt.Errorf("key %s: %w", id, ErrKeyInvalid)
+	}
+	if len(id) > MaxKeyIDLength {
+		return fmt.Errorf("key id %s is too long: %w", id, ErrKeyInvalid)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys[id] = append([]byte(nil), key...)
+	return nil
+}
+
+// SetCurrent will make the added key id current, new objects will be encrypted by it.
+func (p *StaticKeyProvider) SetCurrent(id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.keys[id]; !ok {
+		return fmt.Errorf("key %s: %w", id, ErrKeyNotFound)
+	}
+	p.current = id
+	return nil
+}
+
+// Key implements KeyProvider.Key
+func (p *StaticKeyProvider) Key(ctx context.Context) (id string, key []byte, err error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.current, p.keys[p.current], nil
+}
+
+// KeyByID implements KeyProvider.KeyByID
+func (p *StaticKeyProvider) KeyByID(ctx context.Context, id string) (key []byte, err error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("key %s: %w", id, ErrKeyNotFound)
+	}
+	return key, nil
+}
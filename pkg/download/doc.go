@@ -0,0 +1,13 @@
+/*
+Package download provides parallel ranged downloads.
+
+Download splits the object into chunks by its content length, and reads
+chunks concurrently with offset and size pairs into an io.WriterAt. The etag
+of the object is checked before and after the download, so that chunks of
+different versions will not be mixed silently.
+
+If CheckpointFile is set, completed chunks will be recorded in it, and an
+interrupted download could be resumed by calling Download again with the same
+destination and CheckpointFile.
+*/
+package download
@@ -0,0 +1,301 @@
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/types"
+)
+
+// DefaultChunkSize is the default size of chunks.
+const DefaultChunkSize = 8 * 1024 * 1024
+
+// ErrObjectChanged means the object has been changed during the download.
+var ErrObjectChanged = errors.New("object changed")
+
+// Options is the options for Download.
+type Options struct {
+	// ChunkSize is the size of every ranged read, DefaultChunkSize by default.
+	ChunkSize int64
+	// Parallelism is the max number of concurrent reads, 4 by default.
+	Parallelism int
+	// CheckpointFile is the local file to record completed chunks, the
+	// download could not be resumed if it's empty.
+	//
+	// CheckpointFile will be removed after the download completed.
+	CheckpointFile string
+}
+
+// Checkpoint is the persisted state of a download.
+type Checkpoint struct {
+	// Path is the path of the object.
+	Path string `json:"path"`
+	// ETag is the etag of the object while the download started.
+	ETag string `json:"etag"`
+	// Size is the size of the object.
+	Size int64 `json:"size"`
+	// ChunkSize is the size of every chunk except the last one.
+	ChunkSize int64 `json:"chunk_size"`
+	// Chunks are the indexes of completed chunks.
+	Chunks []int `json:"chunks"`
+}
+
+// Download will download the object at path into w, and returns the size of the object.
+//
+// If w has a `Sync() error` method like *os.File, it will be called before
+// chunks recorded in the checkpoint.
+func Download(ctx context.Context, store types.Storager, path string, w io.WriterAt, o *Options) (int64, error) {
+	d := &downloader{
+		store:       store,
+		path:        path,
+		w:           w,
+		chunkSize:   DefaultChunkSize,
+		parallelism: 4,
+	}
+	if o != nil {
+		if o.ChunkSize > 0 {
+			d.chunkSize = o.ChunkSize
+		}
+		if o.Parallelism > 0 {
+			d.parallelism = o.Parallelism
+		}
+		d.checkpointFile = o.CheckpointFile
+	}
+
+	err := d.download(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("download %s: %w", path, err)
+	}
+	return d.size, nil
+}
+
+type downloader struct {
+	store          types.Storager
+	path           string
+	w              io.WriterAt
+	chunkSize      int64
+	parallelism    int
+	checkpointFile string
+
+	size int64
+	etag string
+
+	mu   sync.Mutex
+	done []bool
+}
+
+func (d *downloader) download(ctx context.Context) (err error) {
+	d.size, d.etag, err = d.stat(ctx)
+	if err != nil {
+		return err
+	}
+
+	count := int((d.size + d.chunkSize - 1) / d.chunkSize)
+	d.done = make([]bool, count)
+	if err = d.load(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chunks := make(chan int)
+	errs := make(chan error, d.parallelism)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < d.parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range chunks {
+				if err := d.read(ctx, idx); err != nil {
+					errs <- err
+					// Stop other reads on the first error.
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+loop:
+	for idx := range d.done {
+		if d.done[idx] {
+			continue
+		}
+		select {
+		case chunks <- idx:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(chunks)
+	wg.Wait()
+
+	select {
+	case err = <-errs:
+		return err
+	default:
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	// Make sure chunks are from the same version of the object.
+	size, etag, err := d.stat(ctx)
+	if err != nil {
+		return err
+	}
+	if size != d.size || etag != d.etag {
+		// Chunks can't be reused anymore.
+		_ = d.remove()
+		return ErrObjectChanged
+	}
+	return d.remove()
+}
+
+// stat returns the size and etag of the object.
+func (d *downloader) stat(ctx context.Context) (size int64, etag string, err error) {
+	o, err := d.store.StatWithContext(ctx, d.path)
+	if err != nil {
+		return
+	}
+	size, ok := o.GetContentLength()
+	if !ok {
+		return 0, "", fmt.Errorf("content length is missing")
+	}
+	etag, _ = o.GetEtag()
+	return size, etag, nil
+}
+
+// read reads chunk idx into w.
+func (d *downloader) read(ctx context.Context, idx int) error {
+	offset := int64(idx) * d.chunkSize
+	size := d.chunkSize
+	if offset+size > d.size {
+		size = d.size - offset
+	}
+
+	n, err := d.store.ReadWithContext(ctx, d.path, &offsetWriter{w: d.w, offset: offset},
+		pairs.WithOffset(offset), pairs.WithSize(size))
+	if err != nil {
+		return err
+	}
+	if n != size {
+		// Object could have been truncated.
+		return ErrObjectChanged
+	}
+	return d.complete(idx)
+}
+
+// complete marks chunk idx as completed and saves the checkpoint.
+func (d *downloader) complete(idx int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.done[idx] = true
+	if d.checkpointFile == "" {
+		return nil
+	}
+	if s, ok := d.w.(interface{ Sync() error }); ok {
+		if err := s.Sync(); err != nil {
+			return err
+		}
+	}
+
+	c := &Checkpoint{
+		Path:      d.path,
+		ETag:      d.etag,
+		Size:      d.size,
+		ChunkSize: d.chunkSize,
+		Chunks:    make([]int, 0),
+	}
+	for k, v := range d.done {
+		if v {
+			c.Chunks = append(c.Chunks, k)
+		}
+	}
+	content, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file and rename, so that the checkpoint will not be broken by crash.
+	f, err := ioutil.TempFile(filepath.Dir(d.checkpointFile), ".checkpoint-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Remove the temp file if rename failed.
+		_ = os.Remove(f.Name())
+	}()
+	_, err = f.Write(content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), d.checkpointFile)
+}
+
+// load loads completed chunks from the checkpoint, the checkpoint will be
+// ignored if the object has been changed.
+func (d *downloader) load() error {
+	if d.checkpointFile == "" {
+		return nil
+	}
+	content, err := ioutil.ReadFile(d.checkpointFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	c := &Checkpoint{}
+	if err = json.Unmarshal(content, c); err != nil {
+		return err
+	}
+	if c.Path != d.path || c.ETag != d.etag || c.Size != d.size || c.ChunkSize != d.chunkSize {
+		return nil
+	}
+
+	for _, v := range c.Chunks {
+		if v >= 0 && v < len(d.done) {
+			d.done[v] = true
+		}
+	}
+	return nil
+}
+
+// remove removes the checkpoint.
+func (d *downloader) remove() error {
+	if d.checkpointFile == "" {
+		return nil
+	}
+	err := os.Remove(d.checkpointFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// offsetWriter writes into w from offset sequentially.
+type offsetWriter struct {
+	w      io.WriterAt
+	offset int64
+}
+
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	n, err := w.w.WriteAt(p, w.offset)
+	w.offset += int64(n)
+	return n, err
+}
@@ -0,0 +1,179 @@
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// objectStorager is a Storager which contains only one object, read offsets will be recorded.
+type objectStorager struct {
+	types.UnimplementedStorager
+
+	mu      sync.Mutex
+	data    []byte
+	version int
+	reads   []int64
+	// failed makes the read at offset failed-1 fail.
+	failed int64
+	// onRead will be called before every read.
+	onRead func(offset int64)
+}
+
+func (s *objectStorager) StatWithContext(ctx context.Context, path string, ps ...types.Pair) (*types.Object, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o := types.NewObject(s, true)
+	o.Path = path
+	o.Mode = types.ModeRead
+	o.SetContentLength(int64(len(s.data)))
+	o.SetEtag(fmt.Sprintf("etag-%d", s.version))
+	return o, nil
+}
+
+func (s *objectStorager) ReadWithContext(ctx context.Context, path string, w io.Writer, ps ...types.Pair) (int64, error) {
+	var offset, size int64
+	for _, p := range ps {
+		switch p.Key {
+		case "offset":
+			offset = p.Value.(int64)
+		case "size":
+			size = p.Value.(int64)
+		}
+	}
+	if s.onRead != nil {
+		s.onRead(offset)
+	}
+
+	s.mu.Lock()
+	if s.failed > 0 && offset == s.failed-1 {
+		s.mu.Unlock()
+		return 0, services.ErrServiceInternal
+	}
+	s.reads = append(s.reads, offset)
+	data := s.data[offset : offset+size]
+	s.mu.Unlock()
+
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+func newTestObject(size int) *objectStorager {
+	data := make([]byte, size)
+	rand.Read(data)
+	return &objectStorager{data: data}
+}
+
+func newTestFile(t *testing.T) *os.File {
+	f, err := os.Create(filepath.Join(t.TempDir(), "file"))
+	assert.Nil(t, err)
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func readFile(t *testing.T, f *os.File) []byte {
+	content, err := ioutil.ReadFile(f.Name())
+	assert.Nil(t, err)
+	return content
+}
+
+func TestDownload(t *testing.T) {
+	store := newTestObject(1000)
+	f := newTestFile(t)
+
+	n, err := Download(context.Background(), store, "a", f, &Options{ChunkSize: 64, Parallelism: 8})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), n)
+	assert.Equal(t, store.data, readFile(t, f))
+	assert.Len(t, store.reads, 16)
+
+	// Empty object.
+	store = newTestObject(0)
+	n, err = Download(context.Background(), store, "a", newTestFile(t), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), n)
+	assert.Len(t, store.reads, 0)
+}
+
+func TestDownloadResume(t *testing.T) {
+	store := newTestObject(1000)
+	store.failed = 300 + 1
+	f := newTestFile(t)
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+	o := &Options{ChunkSize: 100, Parallelism: 1, CheckpointFile: checkpoint}
+
+	_, err := Download(context.Background(), store, "a", f, o)
+	assert.ErrorIs(t, err, services.ErrServiceInternal)
+
+	content, err := ioutil.ReadFile(checkpoint)
+	assert.Nil(t, err)
+	c := &Checkpoint{}
+	assert.Nil(t, json.Unmarshal(content, c))
+	assert.Equal(t, []int{0, 1, 2}, c.Chunks)
+	assert.Equal(t, "etag-0", c.ETag)
+
+	// Only the remaining chunks will be read.
+	store.failed = 0
+	store.reads = nil
+	_, err = Download(context.Background(), store, "a", f, o)
+	assert.Nil(t, err)
+	assert.Equal(t, store.data, readFile(t, f))
+	sort.Slice(store.reads, func(i, j int) bool { return store.reads[i] < store.reads[j] })
+	assert.Equal(t, []int64{300, 400, 500, 600, 700, 800, 900}, store.reads)
+
+	_, err = os.Stat(checkpoint)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDownloadResumeChanged(t *testing.T) {
+	store := newTestObject(1000)
+	store.failed = 300 + 1
+	f := newTestFile(t)
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+	o := &Options{ChunkSize: 100, Parallelism: 1, CheckpointFile: checkpoint}
+
+	_, err := Download(context.Background(), store, "a", f, o)
+	assert.Error(t, err)
+
+	// Checkpoint will be ignored after the object changed.
+	store.failed = 0
+	store.reads = nil
+	store.version++
+	rand.Read(store.data)
+	_, err = Download(context.Background(), store, "a", f, o)
+	assert.Nil(t, err)
+	assert.Equal(t, store.data, readFile(t, f))
+	assert.Len(t, store.reads, 10)
+}
+
+func TestDownloadChanged(t *testing.T) {
+	store := newTestObject(1000)
+	store.onRead = func(offset int64) {
+		if offset == 500 {
+			store.mu.Lock()
+			store.version++
+			store.mu.Unlock()
+		}
+	}
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+
+	_, err := Download(context.Background(), store, "a", newTestFile(t), &Options{ChunkSize: 100, CheckpointFile: checkpoint})
+	assert.ErrorIs(t, err, ErrObjectChanged)
+
+	_, err = os.Stat(checkpoint)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -1,20 +1,20 @@
 package fswrap
 
 import (
-	"bytes"
+	"context"
 	"errors"
-	"io"
 	"net/http"
 	"os"
 
 	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/pkg/objectio"
 	"go.beyondstorage.io/v5/types"
 )
 
 var (
 	_ http.FileSystem = httpFsWrapper{}
 
-	_ http.File = httpFileWrapper{}
+	_ http.File = &httpFileWrapper{}
 )
 
 // HttpFs convert a Storager to http.FileSystem
@@ -31,51 +31,48 @@ func (h httpFsWrapper) Open(name string) (http.File, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &httpFileWrapper{store: h.store, object: o}, nil
+
+	f := &httpFileWrapper{store: h.store, object: o}
+	if o.Mode.IsDir() {
+		return f, nil
+	}
+	f.r, err = objectio.OpenObject(context.Background(), h.store, o, nil)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
 }
 
 type httpFileWrapper struct {
 	store  types.Storager
 	object *types.Object
 
-	offset int64
-	buf    bytes.Buffer
+	// r will be nil for dirs.
+	r *objectio.Reader
 }
 
-func (h httpFileWrapper) Close() error {
-	return nil
+func (h *httpFileWrapper) Close() error {
+	if h.r == nil {
+		return nil
+	}
+	return h.r.Close()
 }
 
-func (h httpFileWrapper) Read(bs []byte) (int, error) {
-	size := int64(len(bs))
-
-	n, err := h.store.Read(h.object.Path, &h.buf, pairs.WithSize(size), pairs.WithOffset(h.offset))
-	if err != nil {
-		return int(n), err
+func (h *httpFileWrapper) Read(bs []byte) (int, error) {
+	if h.r == nil {
+		return 0, os.ErrInvalid
 	}
-	h.offset += n
-
-	nn := copy(bs, h.buf.Bytes())
-	h.buf.Reset() // Reset internal buffer after copy
-	return nn, nil
+	return h.r.Read(bs)
 }
 
-func (h httpFileWrapper) Seek(offset int64, whence int) (int64, error) {
-	size := h.object.MustGetContentLength()
-
-	switch whence {
-	case io.SeekStart:
-		h.offset = offset
-	case io.SeekCurrent:
-		h.offset += offset
-	case io.SeekEnd:
-		// TODO: Do we need to check value here?
-		h.offset = size - offset
+func (h *httpFileWrapper) Seek(offset int64, whence int) (int64, error) {
+	if h.r == nil {
+		return 0, os.ErrInvalid
 	}
-	return h.offset, nil
+	return h.r.Seek(offset, whence)
 }
 
-func (h httpFileWrapper) Readdir(count int) ([]os.FileInfo, error) {
+func (h *httpFileWrapper) Readdir(count int) ([]os.FileInfo, error) {
 	if !h.object.Mode.IsDir() {
 		return nil, os.ErrInvalid
 	}
@@ -117,6 +114,6 @@ func (h httpFileWrapper) Readdir(count int) ([]os.FileInfo, error) {
 	return fi, nil
 }
 
-func (h httpFileWrapper) Stat() (os.FileInfo, error) {
+func (h *httpFileWrapper) Stat() (os.FileInfo, error) {
 	return &fileInfoWrapper{object: h.object}, nil
 }
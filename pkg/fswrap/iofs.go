@@ -5,11 +5,13 @@ package fswrap
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io/fs"
-	"path"
 
 	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/pkg/objectio"
+	"go.beyondstorage.io/v5/pkg/walk"
 	"go.beyondstorage.io/v5/types"
 )
 
@@ -42,34 +44,20 @@ func (w fsWrapper) Open(name string) (fs.File, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &fileWrapper{store: w.store, object: o}, nil
-}
 
-func (w fsWrapper) Glob(name string) ([]string, error) {
-	it, err := w.store.List("", pairs.WithListMode(types.ListModePrefix))
+	f := &fileWrapper{store: w.store, object: o}
+	if o.Mode.IsDir() {
+		return f, nil
+	}
+	f.r, err = objectio.OpenObject(context.Background(), w.store, o, nil)
 	if err != nil {
 		return nil, err
 	}
+	return f, nil
+}
 
-	s := make([]string, 0)
-	for {
-		o, err := it.Next()
-		if err != nil && errors.Is(err, types.IterateDone) {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-
-		ok, err := path.Match(name, o.Path)
-		if err != nil {
-			return nil, err
-		}
-		if ok {
-			s = append(s, o.Path)
-		}
-	}
-	return s, nil
+func (w fsWrapper) Glob(name string) ([]string, error) {
+	return walk.Glob(w.store, name)
 }
 
 func (w fsWrapper) ReadDir(name string) ([]fs.DirEntry, error) {
@@ -119,30 +107,26 @@ type fileWrapper struct {
 	store  types.Storager
 	object *types.Object
 
-	offset int64
-	buf    bytes.Buffer
+	// r will be nil for dirs.
+	r *objectio.Reader
 }
 
-func (o fileWrapper) Stat() (fs.FileInfo, error) {
+func (o *fileWrapper) Stat() (fs.FileInfo, error) {
 	return &fileInfoWrapper{o.object}, nil
 }
 
-func (o fileWrapper) Read(bs []byte) (int, error) {
-	size := int64(len(bs))
-
-	n, err := o.store.Read(o.object.Path, &o.buf, pairs.WithSize(size), pairs.WithOffset(o.offset))
-	if err != nil {
-		return int(n), err
+func (o *fileWrapper) Read(bs []byte) (int, error) {
+	if o.r == nil {
+		return 0, &fs.PathError{Op: "read", Path: o.object.Path, Err: fs.ErrInvalid}
 	}
-	o.offset += n
-
-	nn := copy(bs, o.buf.Bytes())
-	o.buf.Reset() // Reset internal buffer after copy
-	return nn, nil
+	return o.r.Read(bs)
 }
 
-func (o fileWrapper) Close() error {
-	return nil
+func (o *fileWrapper) Close() error {
+	if o.r == nil {
+		return nil
+	}
+	return o.r.Close()
 }
 
 type dirEntryWrapper struct {
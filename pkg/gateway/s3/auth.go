@@ -0,0 +1,280 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	signV4Algorithm = "AWS4-HMAC-SHA256"
+	iso8601Format   = "20060102T150405Z"
+
+	// maxSkew is the max difference between the request time and the server's time.
+	maxSkew = 15 * time.Minute
+	// maxPresignExpires is the max value of X-Amz-Expires, which is 7 days.
+	maxPresignExpires = 7 * 24 * 60 * 60
+
+	unsignedPayload                 = "UNSIGNED-PAYLOAD"
+	streamingPayload                = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	streamingUnsignedPayloadTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+	emptySHA256                     = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// authorization is the result of an authenticated request.
+type authorization struct {
+	// payloadHash is the value of x-amz-content-sha256.
+	payloadHash string
+
+	// Following fields are used to verify chunk signatures of streaming
+	// payload, signingKey will be nil if authentication is disabled.
+	signature  string
+	amzDate    string
+	scope      string
+	signingKey []byte
+}
+
+// parseCredential parses credential in the format of credential.ProtocolHmac,
+// like `hmac:access_key:secret_key`.
+func parseCredential(s string) (accessKey, secretKey string, ok bool) {
+	v := strings.SplitN(s, ":", 3)
+	if len(v) != 3 || v[0] != "hmac" || v[1] == "" || v[2] == "" {
+		return "", "", false
+	}
+	return v[1], v[2], true
+}
+
+// authenticate verifies the AWS Signature Version 4 of r, both authorization
+// header and presigned url are supported.
+//
+// ref: https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
+func (h *Handler) authenticate(r *http.Request) (*authorization, error) {
+	if h.accessKey == "" {
+		return &authorization{payloadHash: r.Header.Get("X-Amz-Content-Sha256")}, nil
+	}
+
+	query := r.URL.Query()
+	if query.Get("X-Amz-Algorithm") != "" {
+		return h.authenticatePresigned(r, query)
+	}
+	return h.authenticateHeader(r)
+}
+
+func (h *Handler) authenticateHeader(r *http.Request) (*authorization, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return nil, errAccessDenied
+	}
+	if !strings.HasPrefix(header, signV4Algorithm+" ") {
+		return nil, errAccessDenied.withMessage("Only %s is supported.", signV4Algorithm)
+	}
+
+	var credential, signedHeaders, signature string
+	for _, v := range strings.Split(strings.TrimPrefix(header, signV4Algorithm+" "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(v), "=", 2)
+		if len(kv) != 2 {
+			return nil, errAuthorizationHeaderMalformed
+		}
+		switch kv[0] {
+		case "Credential":
+			credential = kv[1]
+		case "SignedHeaders":
+			signedHeaders = kv[1]
+		case "Signature":
+			signature = kv[1]
+		}
+	}
+	if credential == "" || signedHeaders == "" || signature == "" {
+		return nil, errAuthorizationHeaderMalformed
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	if amzDate == "" {
+		t, err := http.ParseTime(r.Header.Get("Date"))
+		if err != nil {
+			return nil, errAccessDenied.withMessage("AWS authentication requires a valid Date or x-amz-date header.")
+		}
+		amzDate = t.UTC().Format(iso8601Format)
+	}
+	t, err := time.Parse(iso8601Format, amzDate)
+	if err != nil {
+		return nil, errAccessDenied.withMessage("AWS authentication requires a valid Date or x-amz-date header.")
+	}
+	if d := h.now().Sub(t); d > maxSkew || d < -maxSkew {
+		return nil, errRequestTimeTooSkewed
+	}
+
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		return nil, errInvalidArgument.withMessage("Missing required header for this request: x-amz-content-sha256.")
+	}
+
+	return h.verify(r, credential, amzDate, signedHeaders, signature, payloadHash, false)
+}
+
+func (h *Handler) authenticatePresigned(r *http.Request, query url.Values) (*authorization, error) {
+	if query.Get("X-Amz-Algorithm") != signV4Algorithm {
+		return nil, errAccessDenied.withMessage("Only %s is supported.", signV4Algorithm)
+	}
+
+	amzDate := query.Get("X-Amz-Date")
+	t, err := time.Parse(iso8601Format, amzDate)
+	if err != nil {
+		return nil, errAuthorizationHeaderMalformed.withMessage("X-Amz-Date must be in the ISO8601 format.")
+	}
+	expires, err := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
+	if err != nil || expires < 0 || expires > maxPresignExpires {
+		return nil, errAuthorizationHeaderMalformed.withMessage("X-Amz-Expires must be between 0 and %d.", maxPresignExpires)
+	}
+	now := h.now()
+	if t.Sub(now) > maxSkew {
+		return nil, errRequestTimeTooSkewed
+	}
+	if now.After(t.Add(time.Duration(expires) * time.Second)) {
+		return nil, errAccessDenied.withMessage("Request has expired.")
+	}
+
+	payloadHash := query.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		payloadHash = unsignedPayload
+	}
+
+	return h.verify(r, query.Get("X-Amz-Credential"), amzDate,
+		query.Get("X-Amz-SignedHeaders"), query.Get("X-Amz-Signature"), payloadHash, true)
+}
+
+// verify checks signature of r against the credential.
+func (h *Handler) verify(r *http.Request, credential, amzDate, signedHeaders, signature, payloadHash string, presigned bool) (*authorization, error) {
+	// Credential is in the format of `<access_key>/<date>/<region>/s3/aws4_request`.
+	cred := strings.Split(credential, "/")
+	if len(cred) != 5 || cred[3] != "s3" || cred[4] != "aws4_request" {
+		return nil, errAuthorizationHeaderMalformed.withMessage("The credential %q is malformed.", credential)
+	}
+	if cred[0] != h.accessKey {
+		return nil, errInvalidAccessKeyID
+	}
+	if !strings.HasPrefix(amzDate, cred[1]) {
+		return nil, errAuthorizationHeaderMalformed.withMessage("The credential date %q doesn't match the request date.", cred[1])
+	}
+	if signature == "" || signedHeaders == "" {
+		return nil, errAuthorizationHeaderMalformed
+	}
+
+	scope := strings.Join(cred[1:], "/")
+	key := signingKey(h.secretKey, cred[1], cred[2])
+	cr := canonicalRequest(r, strings.Split(signedHeaders, ";"), payloadHash, presigned)
+	expected := hex.EncodeToString(hmacSHA256(key, stringToSign(amzDate, scope, cr)))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, errSignatureDoesNotMatch
+	}
+
+	return &authorization{
+		payloadHash: payloadHash,
+		signature:   signature,
+		amzDate:     amzDate,
+		scope:       scope,
+		signingKey:  key,
+	}, nil
+}
+
+// canonicalRequest builds the canonical request of r.
+//
+// X-Amz-Signature will be excluded from query if presigned is true.
+func canonicalRequest(r *http.Request, signedHeaders []string, payloadHash string, presigned bool) string {
+	uri := uriEncode(r.URL.Path, false)
+	if uri == "" {
+		uri = "/"
+	}
+
+	query := make([]string, 0)
+	for k, vs := range r.URL.Query() {
+		if presigned && k == "X-Amz-Signature" {
+			continue
+		}
+		for _, v := range vs {
+			query = append(query, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	sort.Strings(query)
+
+	var headers strings.Builder
+	for _, k := range signedHeaders {
+		var v string
+		switch k {
+		case "host":
+			v = r.Host
+		case "content-length":
+			v = r.Header.Get("Content-Length")
+			if v == "" {
+				v = strconv.FormatInt(r.ContentLength, 10)
+			}
+		default:
+			values := r.Header.Values(k)
+			for i := range values {
+				values[i] = strings.Join(strings.Fields(values[i]), " ")
+			}
+			v = strings.Join(values, ",")
+		}
+		headers.WriteString(k + ":" + v + "\n")
+	}
+
+	return strings.Join([]string{
+		r.Method,
+		uri,
+		strings.Join(query, "&"),
+		headers.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+func stringToSign(amzDate, scope, canonicalRequest string) string {
+	return signV4Algorithm + "\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+}
+
+func signingKey(secretKey, date, region string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	v := sha256.Sum256(data)
+	return hex.EncodeToString(v[:])
+}
+
+// uriEncode encodes s as the URI encoding of AWS, which keeps unreserved
+// characters only.
+func uriEncode(s string, encodeSlash bool) string {
+	const upperhex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(upperhex[c>>4])
+			b.WriteByte(upperhex[c&15])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,245 @@
+package s3
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxChunkSize is the max size of a chunk in aws-chunked payload.
+const maxChunkSize = 16 * 1024 * 1024
+
+// body reads the payload of a request and verifies it against the checksums
+// provided by the client.
+//
+// The first error will be kept in err, so that it could be reported to the
+// client even if the Storager doesn't wrap it.
+type body struct {
+	r    io.Reader
+	size int64
+	n    int64
+
+	md5         hash.Hash
+	expectedMD5 []byte
+	sha256      hash.Hash
+	expectedSHA string
+	// signed means signatures of chunks will be verified.
+	signed bool
+
+	err error
+}
+
+// newBody creates a body for r with the authorization of it.
+func newBody(r *http.Request, auth *authorization) (*body, error) {
+	b := &body{md5: md5.New()}
+
+	switch auth.payloadHash {
+	case streamingPayload, streamingUnsignedPayloadTrailer:
+		size, err := strconv.ParseInt(r.Header.Get("X-Amz-Decoded-Content-Length"), 10, 64)
+		if err != nil || size < 0 {
+			return nil, errMissingContentLength
+		}
+		b.size = size
+		b.r = newChunkedReader(r.Body, auth)
+		b.signed = auth.payloadHash == streamingPayload && auth.signingKey != nil
+	case "", unsignedPayload:
+		if r.ContentLength < 0 {
+			return nil, errMissingContentLength
+		}
+		b.size = r.ContentLength
+		b.r = r.Body
+	default:
+		if r.ContentLength < 0 {
+			return nil, errMissingContentLength
+		}
+		b.size = r.ContentLength
+		b.r = r.Body
+		b.sha256 = sha256.New()
+		b.expectedSHA = auth.payloadHash
+	}
+
+	if v := r.Header.Get("Content-MD5"); v != "" {
+		expected, err := base64.StdEncoding.DecodeString(v)
+		if err != nil || len(expected) != md5.Size {
+			return nil, errInvalidDigest
+		}
+		b.expectedMD5 = expected
+	}
+	// Empty payload could be verified directly, Storagers may not read it.
+	if b.size == 0 {
+		if err := b.verify(); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Read implements io.Reader, checksums will be verified once size bytes
+// have been read.
+func (b *body) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.n >= b.size {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.size-b.n {
+		p = p[:b.size-b.n]
+	}
+
+	n, err := b.r.Read(p)
+	b.n += int64(n)
+	b.md5.Write(p[:n])
+	if b.sha256 != nil {
+		b.sha256.Write(p[:n])
+	}
+
+	if errors.Is(err, io.EOF) && b.n < b.size {
+		err = errIncompleteBody
+	}
+	// The last read may return data with io.EOF together.
+	if (err == nil || errors.Is(err, io.EOF)) && b.n == b.size {
+		if verr := b.verify(); verr != nil {
+			err = verr
+		}
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		b.err = err
+	}
+	return n, err
+}
+
+// verifiable returns whether the payload could be rejected after it has been
+// read, in which case it should not be written into the target object directly.
+func (b *body) verifiable() bool {
+	return b.expectedMD5 != nil || b.sha256 != nil || b.signed
+}
+
+func (b *body) verify() error {
+	if b.expectedMD5 != nil && !bytes.Equal(b.expectedMD5, b.md5.Sum(nil)) {
+		return errBadDigest
+	}
+	if b.sha256 != nil && hex.EncodeToString(b.sha256.Sum(nil)) != b.expectedSHA {
+		return errContentSHA256Mismatch
+	}
+	return nil
+}
+
+// etag returns the etag of the payload, which is the hex encoded md5.
+func (b *body) etag() string {
+	return `"` + hex.EncodeToString(b.md5.Sum(nil)) + `"`
+}
+
+// chunkedReader decodes payload in aws-chunked encoding.
+//
+// Chunk signatures will be verified if the signing key is available, and
+// trailing headers will be ignored.
+//
+// ref: https://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-streaming.html
+type chunkedReader struct {
+	r    *bufio.Reader
+	auth *authorization
+
+	// prevSignature is the signature of previous chunk, or the signature
+	// of the request for the first chunk.
+	prevSignature string
+	chunk         []byte
+	done          bool
+}
+
+func newChunkedReader(r io.Reader, auth *authorization) *chunkedReader {
+	return &chunkedReader{
+		r:             bufio.NewReader(r),
+		auth:          auth,
+		prevSignature: auth.signature,
+	}
+}
+
+// Read implements io.Reader
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	for len(c.chunk) == 0 {
+		if c.done {
+			return 0, io.EOF
+		}
+		if err := c.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, c.chunk)
+	c.chunk = c.chunk[n:]
+	return n, nil
+}
+
+func (c *chunkedReader) readChunk() error {
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+
+	// Chunk header is in the format of `<hex size>[;chunk-signature=<signature>]`.
+	var signature string
+	if idx := strings.IndexByte(line, ';'); idx != -1 {
+		signature = strings.TrimPrefix(line[idx+1:], "chunk-signature=")
+		line = line[:idx]
+	}
+	size, err := strconv.ParseInt(line, 16, 64)
+	if err != nil || size < 0 || size > maxChunkSize {
+		return errIncompleteBody.withMessage("The chunk header %q is malformed.", line)
+	}
+
+	chunk := make([]byte, size)
+	if _, err = io.ReadFull(c.r, chunk); err != nil {
+		return errIncompleteBody
+	}
+
+	if c.auth.payloadHash == streamingUnsignedPayloadTrailer && size == 0 {
+		// Skip trailing headers until the empty line.
+		for {
+			line, err = c.readLine()
+			if err != nil {
+				return err
+			}
+			if line == "" {
+				break
+			}
+		}
+		c.done = true
+		return nil
+	}
+	if line, err = c.readLine(); err != nil || line != "" {
+		return errIncompleteBody
+	}
+
+	if c.auth.payloadHash == streamingPayload && c.auth.signingKey != nil {
+		sts := "AWS4-HMAC-SHA256-PAYLOAD\n" + c.auth.amzDate + "\n" + c.auth.scope + "\n" +
+			c.prevSignature + "\n" + emptySHA256 + "\n" + sha256Hex(chunk)
+		expected := hex.EncodeToString(hmacSHA256(c.auth.signingKey, sts))
+		if !hmac.Equal([]byte(expected), []byte(signature)) {
+			return errSignatureDoesNotMatch
+		}
+		c.prevSignature = signature
+	}
+
+	c.chunk = chunk
+	c.done = size == 0
+	return nil
+}
+
+func (c *chunkedReader) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", errIncompleteBody
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
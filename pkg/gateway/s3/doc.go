@@ -0,0 +1,35 @@
+/*
+Package s3 provides an http.Handler which serves any Storager as a bucket via
+the S3 REST API, so that S3 tools and SDKs could access services like ftp,
+hdfs and webdav, and tests could run against an offline endpoint backed by
+memory or fs.
+
+	store, _ := memory.NewStorager()
+	h, _ := s3.NewHandler(store, &s3.Options{
+		Bucket:     "test",
+		Credential: "hmac:access_key:secret_key",
+	})
+	http.ListenAndServe("127.0.0.1:9000", h)
+
+Following operations are supported:
+
+  - ListBuckets, HeadBucket, GetBucketLocation
+  - ListObjects, ListObjectsV2, DeleteObjects
+  - GetObject (with Range), HeadObject, PutObject, DeleteObject
+  - CreateMultipartUpload, UploadPart, CompleteMultipartUpload,
+    AbortMultipartUpload, ListParts, ListMultipartUploads
+
+Requests are authenticated with AWS Signature Version 4, both authorization
+header and presigned url are supported. Only path style requests are
+supported, clients should be configured with path style addressing. Keys
+which start with a slash, contain . or .. segments or NUL characters are
+rejected, so that requests could not escape from the work dir of Storagers.
+
+Storagers don't guarantee the order of listing, so listing walks and sorts
+all objects under the dir of prefix for every page. Multipart uploads are
+emulated by writing parts under Options.MultipartDir and concatenating them
+while completing, the state of uploads is kept in memory. PutObject with a
+signed payload or Content-MD5 is staged under Options.MultipartDir as well,
+and moved to the key only after the payload has been verified.
+*/
+package s3
@@ -0,0 +1,151 @@
+package s3
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.beyondstorage.io/v5/services"
+)
+
+// apiError is an error which will be returned to clients as an S3 error response.
+//
+// ref: https://docs.aws.amazon.com/AmazonS3/latest/API/ErrorResponses.html
+type apiError struct {
+	Code       string
+	StatusCode int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// withMessage returns a copy of e with message.
+func (e *apiError) withMessage(format string, args ...interface{}) *apiError {
+	ne := *e
+	ne.Message = fmt.Sprintf(format, args...)
+	return &ne
+}
+
+// All S3 errors returned by Handler.
+var (
+	errAccessDenied = &apiError{"AccessDenied", http.StatusForbidden,
+		"Access Denied."}
+	errAuthorizationHeaderMalformed = &apiError{"AuthorizationHeaderMalformed", http.StatusBadRequest,
+		"The authorization header is malformed."}
+	errBadDigest = &apiError{"BadDigest", http.StatusBadRequest,
+		"The Content-MD5 you specified did not match what we received."}
+	errBucketAlreadyOwnedByYou = &apiError{"BucketAlreadyOwnedByYou", http.StatusConflict,
+		"Your previous request to create the named bucket succeeded and you already own it."}
+	errEntityTooSmall = &apiError{"EntityTooSmall", http.StatusBadRequest,
+		"Your proposed upload is smaller than the minimum allowed object size."}
+	errIncompleteBody = &apiError{"IncompleteBody", http.StatusBadRequest,
+		"You did not provide the number of bytes specified by the Content-Length HTTP header."}
+	errInternalError = &apiError{"InternalError", http.StatusInternalServerError,
+		"We encountered an internal error. Please try again."}
+	errInvalidAccessKeyID = &apiError{"InvalidAccessKeyId", http.StatusForbidden,
+		"The AWS access key ID you provided does not exist in our records."}
+	errInvalidArgument = &apiError{"InvalidArgument", http.StatusBadRequest,
+		"Invalid Argument."}
+	errInvalidDigest = &apiError{"InvalidDigest", http.StatusBadRequest,
+		"The Content-MD5 you specified is not valid."}
+	errInvalidObjectState = &apiError{"InvalidObjectState", http.StatusForbidden,
+		"The operation is not valid for the current state of the object."}
+	errInvalidPart = &apiError{"InvalidPart", http.StatusBadRequest,
+		"One or more of the specified parts could not be found."}
+	errInvalidPartOrder = &apiError{"InvalidPartOrder", http.StatusBadRequest,
+		"The list of parts was not in ascending order."}
+	errInvalidRange = &apiError{"InvalidRange", http.StatusRequestedRangeNotSatisfiable,
+		"The requested range is not satisfiable."}
+	errKeyTooLong = &apiError{"KeyTooLongError", http.StatusBadRequest,
+		"Your key is too long."}
+	errMalformedXML = &apiError{"MalformedXML", http.StatusBadRequest,
+		"The XML you provided was not well-formed or did not validate against our published schema."}
+	errMethodNotAllowed = &apiError{"MethodNotAllowed", http.StatusMethodNotAllowed,
+		"The specified method is not allowed against this resource."}
+	errMissingContentLength = &apiError{"MissingContentLength", http.StatusLengthRequired,
+		"You must provide the Content-Length HTTP header."}
+	errNoSuchBucket = &apiError{"NoSuchBucket", http.StatusNotFound,
+		"The specified bucket does not exist."}
+	errNoSuchKey = &apiError{"NoSuchKey", http.StatusNotFound,
+		"The specified key does not exist."}
+	errNoSuchUpload = &apiError{"NoSuchUpload", http.StatusNotFound,
+		"The specified multipart upload does not exist."}
+	errNotImplemented = &apiError{"NotImplemented", http.StatusNotImplemented,
+		"A header or query you provided implies functionality that is not implemented."}
+	errNotModified = &apiError{"NotModified", http.StatusNotModified,
+		"Not Modified."}
+	errPreconditionFailed = &apiError{"PreconditionFailed", http.StatusPreconditionFailed,
+		"At least one of the preconditions you specified did not hold."}
+	errRequestTimeTooSkewed = &apiError{"RequestTimeTooSkewed", http.StatusForbidden,
+		"The difference between the request time and the server's time is too large."}
+	errSignatureDoesNotMatch = &apiError{"SignatureDoesNotMatch", http.StatusForbidden,
+		"The request signature we calculated does not match the signature you provided."}
+	errContentSHA256Mismatch = &apiError{"XAmzContentSHA256Mismatch", http.StatusBadRequest,
+		"The provided 'x-amz-content-sha256' header does not match what was computed."}
+)
+
+// errorResponse is the body of S3 error responses.
+type errorResponse struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	Resource  string   `xml:"Resource"`
+	RequestID string   `xml:"RequestId"`
+}
+
+// formatError converts errors returned by Storager into apiError.
+func formatError(err error) *apiError {
+	var ae *apiError
+	if errors.As(err, &ae) {
+		return ae
+	}
+
+	switch {
+	case errors.Is(err, services.ErrObjectNotExist):
+		return errNoSuchKey
+	case errors.Is(err, services.ErrPermissionDenied):
+		return errAccessDenied
+	case errors.Is(err, services.ErrPreconditionFailed):
+		return errPreconditionFailed
+	case errors.Is(err, services.ErrObjectArchived):
+		return errInvalidObjectState
+	case errors.Is(err, services.ErrCapabilityInsufficient):
+		return errNotImplemented.withMessage("%v", err)
+	default:
+		return errInternalError.withMessage("%v", err)
+	}
+}
+
+// writeError writes err as an S3 error response.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	e := formatError(err)
+
+	// Responses of HEAD requests don't have body.
+	if r.Method == http.MethodHead || e.StatusCode == http.StatusNotModified {
+		w.WriteHeader(e.StatusCode)
+		return
+	}
+	writeXML(w, e.StatusCode, errorResponse{
+		Code:      e.Code,
+		Message:   e.Message,
+		Resource:  r.URL.Path,
+		RequestID: w.Header().Get(headerRequestID),
+	})
+}
+
+// writeXML writes v as an XML response body with status code.
+func writeXML(w http.ResponseWriter, code int, v interface{}) {
+	content, err := xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(code)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(content)
+}
@@ -0,0 +1,251 @@
+package s3
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.beyondstorage.io/v5/types"
+)
+
+const (
+	headerRequestID = "X-Amz-Request-Id"
+
+	// defaultRegion is the region returned by GetBucketLocation by default.
+	defaultRegion = "us-east-1"
+	// defaultMultipartDir is the dir that parts are stored in by default.
+	defaultMultipartDir = ".s3-gateway-multipart"
+	// maxKeyLength is the max length of object keys in bytes.
+	maxKeyLength = 1024
+)
+
+// Options is the gateway supported options.
+type Options struct {
+	// Bucket is the name of the only bucket served by Handler, required.
+	Bucket string
+	// Region is the region returned by GetBucketLocation, "us-east-1" by default.
+	//
+	// Requests signed with any region are accepted.
+	Region string
+	// Credential is the credential of clients in the format of
+	// credential.ProtocolHmac, like `hmac:access_key:secret_key`.
+	//
+	// Authentication is disabled while Credential is empty.
+	Credential string
+	// MultipartDir is the dir in the Storager that parts of multipart uploads
+	// are stored in, ".s3-gateway-multipart" by default.
+	//
+	// Objects under MultipartDir are hidden from listing.
+	MultipartDir string
+}
+
+// Handler serves a Storager as a bucket via S3 REST API.
+type Handler struct {
+	store  types.Storager
+	bucket string
+	region string
+
+	accessKey string
+	secretKey string
+
+	multipartDir string
+	uploads      map[string]*upload
+	mu           sync.Mutex
+
+	// now is used to check request time, overwritten in tests.
+	now func() time.Time
+}
+
+// NewHandler will create a new Handler which serves store.
+func NewHandler(store types.Storager, o *Options) (*Handler, error) {
+	if o == nil || o.Bucket == "" {
+		return nil, errors.New("s3 gateway: bucket is required")
+	}
+
+	h := &Handler{
+		store:        store,
+		bucket:       o.Bucket,
+		region:       o.Region,
+		multipartDir: strings.Trim(o.MultipartDir, "/"),
+		uploads:      make(map[string]*upload),
+		now:          time.Now,
+	}
+	if h.region == "" {
+		h.region = defaultRegion
+	}
+	if h.multipartDir == "" {
+		h.multipartDir = defaultMultipartDir
+	}
+	if o.Credential != "" {
+		var ok bool
+		h.accessKey, h.secretKey, ok = parseCredential(o.Credential)
+		if !ok {
+			return nil, errors.New("s3 gateway: credential must be in the format of hmac:access_key:secret_key")
+		}
+	}
+	return h, nil
+}
+
+// ServeHTTP implements http.Handler
+//
+// Only path style requests are supported, which means the bucket name should
+// be the first segment of the url path.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(headerRequestID, newRequestID())
+
+	auth, err := h.authenticate(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	bucket, key := splitPath(r.URL.Path)
+	if bucket == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, r, errMethodNotAllowed)
+			return
+		}
+		h.listBuckets(w, r)
+		return
+	}
+	if bucket != h.bucket {
+		writeError(w, r, errNoSuchBucket)
+		return
+	}
+
+	if key == "" {
+		err = h.serveBucket(w, r)
+	} else {
+		err = h.serveObject(w, r, key, auth)
+	}
+	if err != nil {
+		writeError(w, r, err)
+	}
+}
+
+func (h *Handler) serveBucket(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+
+	switch r.Method {
+	case http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+		return nil
+	case http.MethodGet:
+		switch {
+		case has(query, "location"):
+			return h.getBucketLocation(w, r)
+		case has(query, "uploads"):
+			return h.listMultipartUploads(w, r)
+		case query.Get("list-type") == "2":
+			return h.listObjectsV2(w, r)
+		case len(query) == 0 || has(query, "prefix", "delimiter", "marker", "max-keys", "encoding-type"):
+			return h.listObjects(w, r)
+		}
+	case http.MethodPost:
+		if has(query, "delete") {
+			return h.deleteObjects(w, r)
+		}
+	case http.MethodPut:
+		if len(query) == 0 {
+			return errBucketAlreadyOwnedByYou
+		}
+	case http.MethodDelete:
+		return errMethodNotAllowed.withMessage("The bucket could not be deleted via gateway.")
+	}
+	return errNotImplemented
+}
+
+func (h *Handler) serveObject(w http.ResponseWriter, r *http.Request, key string, auth *authorization) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if h.isMultipartPath(key) {
+		return errAccessDenied.withMessage("The key is reserved for multipart uploads.")
+	}
+
+	query := r.URL.Query()
+
+	switch r.Method {
+	case http.MethodGet:
+		if has(query, "uploadId") {
+			return h.listParts(w, r, key)
+		}
+		return h.getObject(w, r, key)
+	case http.MethodHead:
+		return h.headObject(w, r, key)
+	case http.MethodPut:
+		if r.Header.Get("X-Amz-Copy-Source") != "" {
+			return errNotImplemented.withMessage("CopyObject and UploadPartCopy are not supported.")
+		}
+		if has(query, "uploadId") {
+			return h.uploadPart(w, r, key, auth)
+		}
+		if len(query) == 0 {
+			return h.putObject(w, r, key, auth)
+		}
+	case http.MethodPost:
+		if has(query, "uploads") {
+			return h.createMultipartUpload(w, r, key)
+		}
+		if has(query, "uploadId") {
+			return h.completeMultipartUpload(w, r, key)
+		}
+	case http.MethodDelete:
+		if has(query, "uploadId") {
+			return h.abortMultipartUpload(w, r, key)
+		}
+		return h.deleteObject(w, r, key)
+	}
+	return errNotImplemented
+}
+
+// splitPath splits url path into bucket and key.
+func splitPath(p string) (bucket, key string) {
+	p = strings.TrimPrefix(p, "/")
+	if idx := strings.IndexByte(p, '/'); idx != -1 {
+		return p[:idx], p[idx+1:]
+	}
+	return p, ""
+}
+
+// validateKey checks whether key is safe to be passed to Storagers.
+//
+// Storagers like fs resolve paths against the work dir, so absolute paths
+// and dot segments could escape from it.
+func validateKey(key string) error {
+	if len(key) > maxKeyLength {
+		return errKeyTooLong
+	}
+	if strings.HasPrefix(key, "/") {
+		return errInvalidArgument.withMessage("The key must not start with a slash.")
+	}
+	if strings.IndexByte(key, 0) != -1 {
+		return errInvalidArgument.withMessage("The key must not contain NUL characters.")
+	}
+	for _, v := range strings.Split(key, "/") {
+		if v == "." || v == ".." {
+			return errInvalidArgument.withMessage("The key must not contain . or .. segments.")
+		}
+	}
+	return nil
+}
+
+// has checks whether query contains any of keys.
+func has(query map[string][]string, keys ...string) bool {
+	for _, k := range keys {
+		if _, ok := query[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return strings.ToUpper(hex.EncodeToString(b))
+}
@@ -0,0 +1,338 @@
+package s3
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.beyondstorage.io/v5/pkg/walk"
+	"go.beyondstorage.io/v5/types"
+)
+
+const (
+	// timeFormat is the format of timestamps in S3 XML responses.
+	timeFormat = "2006-01-02T15:04:05.000Z"
+	// maxKeys is the default and max value of max-keys.
+	maxKeys = 1000
+)
+
+type owner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+type listBucketsResult struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBucketsResult"`
+	Owner   owner    `xml:"Owner"`
+	Buckets []bucket `xml:"Buckets>Bucket"`
+}
+
+type bucket struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+func (h *Handler) listBuckets(w http.ResponseWriter, r *http.Request) {
+	writeXML(w, http.StatusOK, listBucketsResult{
+		Buckets: []bucket{{
+			Name:         h.bucket,
+			CreationDate: time.Unix(0, 0).UTC().Format(timeFormat),
+		}},
+	})
+}
+
+type locationConstraint struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
+	Location string   `xml:",chardata"`
+}
+
+func (h *Handler) getBucketLocation(w http.ResponseWriter, r *http.Request) error {
+	// Buckets in us-east-1 have a LocationConstraint of null.
+	location := h.region
+	if location == defaultRegion {
+		location = ""
+	}
+	writeXML(w, http.StatusOK, locationConstraint{Location: location})
+	return nil
+}
+
+type listObjectsResult struct {
+	XMLName        xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name           string         `xml:"Name"`
+	Prefix         string         `xml:"Prefix"`
+	Marker         string         `xml:"Marker"`
+	NextMarker     string         `xml:"NextMarker,omitempty"`
+	MaxKeys        int            `xml:"MaxKeys"`
+	Delimiter      string         `xml:"Delimiter,omitempty"`
+	IsTruncated    bool           `xml:"IsTruncated"`
+	EncodingType   string         `xml:"EncodingType,omitempty"`
+	Contents       []objectEntry  `xml:"Contents"`
+	CommonPrefixes []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type listObjectsV2Result struct {
+	XMLName               xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	StartAfter            string         `xml:"StartAfter,omitempty"`
+	ContinuationToken     string         `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	KeyCount              int            `xml:"KeyCount"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	Delimiter             string         `xml:"Delimiter,omitempty"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	EncodingType          string         `xml:"EncodingType,omitempty"`
+	Contents              []objectEntry  `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type objectEntry struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag,omitempty"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// listInput is the parsed list request.
+type listInput struct {
+	prefix     string
+	delimiter  string
+	startAfter string
+	maxKeys    int
+	urlEncode  bool
+}
+
+// listOutput is a page of listing.
+type listOutput struct {
+	contents       []objectEntry
+	commonPrefixes []commonPrefix
+	isTruncated    bool
+	// lastKey is the last key or common prefix in this page.
+	lastKey string
+}
+
+func parseListInput(r *http.Request) (listInput, error) {
+	query := r.URL.Query()
+	in := listInput{
+		prefix:    query.Get("prefix"),
+		delimiter: query.Get("delimiter"),
+		maxKeys:   maxKeys,
+	}
+	// The dir of prefix will be walked, so it must be a valid key.
+	if idx := strings.LastIndex(in.prefix, "/"); idx != -1 {
+		if err := validateKey(in.prefix[:idx]); err != nil {
+			return in, err
+		}
+	}
+	if v := query.Get("max-keys"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return in, errInvalidArgument.withMessage("Provided max-keys not an integer or within integer range.")
+		}
+		if n < in.maxKeys {
+			in.maxKeys = n
+		}
+	}
+	switch v := query.Get("encoding-type"); v {
+	case "":
+	case "url":
+		in.urlEncode = true
+	default:
+		return in, errInvalidArgument.withMessage("Invalid Encoding Method specified in Request.")
+	}
+	return in, nil
+}
+
+func (h *Handler) listObjects(w http.ResponseWriter, r *http.Request) error {
+	in, err := parseListInput(r)
+	if err != nil {
+		return err
+	}
+	in.startAfter = r.URL.Query().Get("marker")
+
+	out, err := h.list(r.Context(), in)
+	if err != nil {
+		return err
+	}
+
+	result := listObjectsResult{
+		Name:           h.bucket,
+		Prefix:         in.encode(in.prefix),
+		Marker:         in.encode(in.startAfter),
+		MaxKeys:        in.maxKeys,
+		Delimiter:      in.encode(in.delimiter),
+		IsTruncated:    out.isTruncated,
+		Contents:       out.contents,
+		CommonPrefixes: out.commonPrefixes,
+	}
+	if in.urlEncode {
+		result.EncodingType = "url"
+	}
+	// NextMarker is only returned while delimiter is specified.
+	if out.isTruncated && in.delimiter != "" {
+		result.NextMarker = in.encode(out.lastKey)
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+func (h *Handler) listObjectsV2(w http.ResponseWriter, r *http.Request) error {
+	in, err := parseListInput(r)
+	if err != nil {
+		return err
+	}
+
+	query := r.URL.Query()
+	token := query.Get("continuation-token")
+	in.startAfter = query.Get("start-after")
+	if token != "" {
+		v, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return errInvalidArgument.withMessage("The continuation token provided is incorrect.")
+		}
+		in.startAfter = string(v)
+	}
+
+	out, err := h.list(r.Context(), in)
+	if err != nil {
+		return err
+	}
+
+	result := listObjectsV2Result{
+		Name:              h.bucket,
+		Prefix:            in.encode(in.prefix),
+		StartAfter:        in.encode(query.Get("start-after")),
+		ContinuationToken: token,
+		KeyCount:          len(out.contents) + len(out.commonPrefixes),
+		MaxKeys:           in.maxKeys,
+		Delimiter:         in.encode(in.delimiter),
+		IsTruncated:       out.isTruncated,
+		Contents:          out.contents,
+		CommonPrefixes:    out.commonPrefixes,
+	}
+	if in.urlEncode {
+		result.EncodingType = "url"
+	}
+	if out.isTruncated {
+		result.NextContinuationToken = base64.StdEncoding.EncodeToString([]byte(out.lastKey))
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+// list walks the Storager and returns the page of in.
+//
+// Storagers don't guarantee the order of listing, so all objects under the
+// dir of prefix will be walked and sorted for every page.
+func (h *Handler) list(ctx context.Context, in listInput) (listOutput, error) {
+	type entry struct {
+		key    string
+		object *types.Object
+	}
+
+	root := ""
+	if idx := strings.LastIndex(in.prefix, "/"); idx != -1 {
+		root = in.prefix[:idx]
+	}
+
+	entries := make([]entry, 0)
+	prefixes := make(map[string]bool)
+	err := walk.Walk(ctx, h.store, root, func(o *types.Object) error {
+		if h.isMultipartPath(o.Path) {
+			if o.Mode.IsDir() {
+				return walk.SkipDir
+			}
+			return nil
+		}
+
+		if o.Mode.IsDir() {
+			dir := o.Path + "/"
+			// Skip dirs which could not contain any key with prefix.
+			if !strings.HasPrefix(dir, in.prefix) && !strings.HasPrefix(in.prefix, dir) {
+				return walk.SkipDir
+			}
+			// The whole dir will be rolled up into one common prefix.
+			if in.delimiter == "/" && strings.HasPrefix(dir, in.prefix) && dir != in.prefix {
+				rest := dir[len(in.prefix):]
+				prefixes[in.prefix+rest[:strings.Index(rest, "/")+1]] = true
+				return walk.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasPrefix(o.Path, in.prefix) {
+			return nil
+		}
+		if in.delimiter != "" {
+			rest := o.Path[len(in.prefix):]
+			if idx := strings.Index(rest, in.delimiter); idx != -1 {
+				prefixes[in.prefix+rest[:idx+len(in.delimiter)]] = true
+				return nil
+			}
+		}
+		entries = append(entries, entry{key: o.Path, object: o})
+		return nil
+	})
+	if err != nil {
+		return listOutput{}, err
+	}
+
+	for p := range prefixes {
+		entries = append(entries, entry{key: p})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	out := listOutput{}
+	for _, e := range entries {
+		if e.key <= in.startAfter {
+			continue
+		}
+		if len(out.contents)+len(out.commonPrefixes) >= in.maxKeys {
+			out.isTruncated = true
+			break
+		}
+		out.lastKey = e.key
+
+		if e.object == nil {
+			out.commonPrefixes = append(out.commonPrefixes, commonPrefix{Prefix: in.encode(e.key)})
+			continue
+		}
+		oe := objectEntry{
+			Key:          in.encode(e.key),
+			ETag:         formatEtag(e.object),
+			StorageClass: "STANDARD",
+		}
+		if v, ok := e.object.GetContentLength(); ok {
+			oe.Size = v
+		}
+		if v, ok := e.object.GetLastModified(); ok {
+			oe.LastModified = v.UTC().Format(timeFormat)
+		}
+		out.contents = append(out.contents, oe)
+	}
+	return out, nil
+}
+
+// encode encodes v while encoding-type is url.
+func (in listInput) encode(v string) string {
+	if !in.urlEncode {
+		return v
+	}
+	return uriEncode(v, false)
+}
+
+// isMultipartPath checks whether p is under the multipart dir.
+func (h *Handler) isMultipartPath(p string) bool {
+	p = strings.TrimSuffix(p, "/")
+	return p == h.multipartDir || strings.HasPrefix(p, h.multipartDir+"/")
+}
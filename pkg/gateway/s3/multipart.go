@@ -0,0 +1,438 @@
+package s3
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/types"
+)
+
+const (
+	// minPartSize is the min size of all parts except the last one.
+	minPartSize = 5 * 1024 * 1024
+	// maxPartNumber is the max part number of a multipart upload.
+	maxPartNumber = 10000
+	// maxParts is the default and max value of max-parts and max-uploads.
+	maxParts = 1000
+)
+
+// upload is an in-progress multipart upload.
+//
+// Multipart uploads are emulated for all Storagers: parts are written as
+// objects under the multipart dir, and will be concatenated into the target
+// object while completing. The state of uploads is kept in memory, so they
+// will be lost after the Handler is gone.
+type upload struct {
+	id        string
+	key       string
+	initiated time.Time
+	// pairs are used to write the target object.
+	pairs []types.Pair
+	parts map[int]*part
+}
+
+type part struct {
+	number       int
+	etag         string
+	size         int64
+	lastModified time.Time
+}
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ InitiateMultipartUploadResult"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+func (h *Handler) createMultipartUpload(w http.ResponseWriter, r *http.Request, key string) error {
+	u := &upload{
+		id:        uuid.New().String(),
+		key:       key,
+		initiated: h.now(),
+		pairs:     make([]types.Pair, 0),
+		parts:     make(map[int]*part),
+	}
+	if v := r.Header.Get("Content-Type"); v != "" {
+		u.pairs = append(u.pairs, pairs.WithContentType(v))
+	}
+	if v := parseUserMetadata(r.Header); len(v) > 0 {
+		u.pairs = append(u.pairs, pairs.WithUserMetadata(v))
+	}
+
+	h.mu.Lock()
+	h.uploads[u.id] = u
+	h.mu.Unlock()
+
+	writeXML(w, http.StatusOK, initiateMultipartUploadResult{
+		Bucket:   h.bucket,
+		Key:      key,
+		UploadID: u.id,
+	})
+	return nil
+}
+
+func (h *Handler) uploadPart(w http.ResponseWriter, r *http.Request, key string, auth *authorization) error {
+	query := r.URL.Query()
+	number, err := strconv.Atoi(query.Get("partNumber"))
+	if err != nil || number < 1 || number > maxPartNumber {
+		return errInvalidArgument.withMessage("Part number must be an integer between 1 and %d, inclusive.", maxPartNumber)
+	}
+	id := query.Get("uploadId")
+	if _, err = h.getUpload(id, key); err != nil {
+		return err
+	}
+
+	b, err := newBody(r, auth)
+	if err != nil {
+		return err
+	}
+	path := h.partPath(id, number)
+	_, err = h.store.WriteWithContext(r.Context(), path, b, b.size)
+	if b.err != nil {
+		return b.err
+	}
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	u, ok := h.uploads[id]
+	if ok {
+		u.parts[number] = &part{
+			number:       number,
+			etag:         b.etag(),
+			size:         b.size,
+			lastModified: h.now(),
+		}
+	}
+	h.mu.Unlock()
+	if !ok {
+		// The upload has been aborted or completed while uploading.
+		_ = h.store.DeleteWithContext(r.Context(), path)
+		return errNoSuchUpload
+	}
+
+	w.Header().Set("ETag", b.etag())
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+type completeMultipartUploadRequest struct {
+	XMLName xml.Name `xml:"CompleteMultipartUpload"`
+	Parts   []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CompleteMultipartUploadResult"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+func (h *Handler) completeMultipartUpload(w http.ResponseWriter, r *http.Request, key string) error {
+	var req completeMultipartUploadRequest
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Parts) == 0 {
+		return errMalformedXML
+	}
+
+	// Take the upload away so that it could not be used by other requests
+	// while completing, it will be put back if failed.
+	id := r.URL.Query().Get("uploadId")
+	h.mu.Lock()
+	u, ok := h.uploads[id]
+	if !ok || u.key != key {
+		h.mu.Unlock()
+		return errNoSuchUpload
+	}
+	delete(h.uploads, id)
+	h.mu.Unlock()
+
+	parts, err := u.selectParts(req)
+	if err == nil {
+		err = h.concat(r.Context(), u, parts)
+	}
+	if err != nil {
+		h.mu.Lock()
+		h.uploads[id] = u
+		h.mu.Unlock()
+		return err
+	}
+	h.removeParts(r.Context(), u)
+
+	// The etag of a multipart object is the md5 of all parts' md5 with the
+	// count of parts.
+	sum := md5.New()
+	for _, p := range parts {
+		v, _ := hex.DecodeString(strings.Trim(p.etag, `"`))
+		sum.Write(v)
+	}
+	etag := fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(sum.Sum(nil)), len(parts))
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	writeXML(w, http.StatusOK, completeMultipartUploadResult{
+		Location: fmt.Sprintf("%s://%s/%s/%s", scheme, r.Host, h.bucket, uriEncode(key, false)),
+		Bucket:   h.bucket,
+		Key:      key,
+		ETag:     etag,
+	})
+	return nil
+}
+
+// selectParts returns the parts listed in req.
+func (u *upload) selectParts(req completeMultipartUploadRequest) ([]*part, error) {
+	parts := make([]*part, 0, len(req.Parts))
+	for i, v := range req.Parts {
+		if i > 0 && v.PartNumber <= req.Parts[i-1].PartNumber {
+			return nil, errInvalidPartOrder
+		}
+		p, ok := u.parts[v.PartNumber]
+		if !ok || strings.Trim(v.ETag, `"`) != strings.Trim(p.etag, `"`) {
+			return nil, errInvalidPart
+		}
+		parts = append(parts, p)
+	}
+	for _, p := range parts[:len(parts)-1] {
+		if p.size < minPartSize {
+			return nil, errEntityTooSmall
+		}
+	}
+	return parts, nil
+}
+
+// concat writes all parts into the target object of u.
+func (h *Handler) concat(ctx context.Context, u *upload, parts []*part) error {
+	var size int64
+	srcs := make([]string, 0, len(parts))
+	for _, p := range parts {
+		size += p.size
+		srcs = append(srcs, h.partPath(u.id, p.number))
+	}
+	return h.writeFrom(ctx, u.key, srcs, size, u.pairs)
+}
+
+func (h *Handler) abortMultipartUpload(w http.ResponseWriter, r *http.Request, key string) error {
+	id := r.URL.Query().Get("uploadId")
+	u, err := h.getUpload(id, key)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	delete(h.uploads, id)
+	h.mu.Unlock()
+
+	h.removeParts(r.Context(), u)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// removeParts removes all uploaded parts of u, errors will be ignored.
+func (h *Handler) removeParts(ctx context.Context, u *upload) {
+	for number := range u.parts {
+		_ = h.store.DeleteWithContext(ctx, h.partPath(u.id, number))
+	}
+	if !h.store.Features().VirtualDir {
+		_ = h.store.DeleteWithContext(ctx, h.multipartDir+"/"+u.id, pairs.WithObjectMode(types.ModeDir))
+	}
+}
+
+type listPartsResult struct {
+	XMLName              xml.Name    `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListPartsResult"`
+	Bucket               string      `xml:"Bucket"`
+	Key                  string      `xml:"Key"`
+	UploadID             string      `xml:"UploadId"`
+	StorageClass         string      `xml:"StorageClass"`
+	PartNumberMarker     int         `xml:"PartNumberMarker"`
+	NextPartNumberMarker int         `xml:"NextPartNumberMarker"`
+	MaxParts             int         `xml:"MaxParts"`
+	IsTruncated          bool        `xml:"IsTruncated"`
+	Parts                []partEntry `xml:"Part"`
+}
+
+type partEntry struct {
+	PartNumber   int    `xml:"PartNumber"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+}
+
+func (h *Handler) listParts(w http.ResponseWriter, r *http.Request, key string) error {
+	query := r.URL.Query()
+	marker, limit, err := parseMarkerAndLimit(query.Get("part-number-marker"), query.Get("max-parts"))
+	if err != nil {
+		return err
+	}
+
+	id := query.Get("uploadId")
+	u, err := h.getUpload(id, key)
+	if err != nil {
+		return err
+	}
+
+	result := listPartsResult{
+		Bucket:           h.bucket,
+		Key:              key,
+		UploadID:         id,
+		StorageClass:     "STANDARD",
+		PartNumberMarker: marker,
+		MaxParts:         limit,
+	}
+
+	h.mu.Lock()
+	numbers := make([]int, 0, len(u.parts))
+	for number := range u.parts {
+		if number > marker {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Ints(numbers)
+	for _, number := range numbers {
+		if len(result.Parts) >= limit {
+			result.IsTruncated = true
+			break
+		}
+		p := u.parts[number]
+		result.Parts = append(result.Parts, partEntry{
+			PartNumber:   p.number,
+			LastModified: p.lastModified.UTC().Format(timeFormat),
+			ETag:         p.etag,
+			Size:         p.size,
+		})
+		result.NextPartNumberMarker = p.number
+	}
+	h.mu.Unlock()
+
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+type listMultipartUploadsResult struct {
+	XMLName            xml.Name      `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListMultipartUploadsResult"`
+	Bucket             string        `xml:"Bucket"`
+	KeyMarker          string        `xml:"KeyMarker"`
+	UploadIDMarker     string        `xml:"UploadIdMarker"`
+	NextKeyMarker      string        `xml:"NextKeyMarker"`
+	NextUploadIDMarker string        `xml:"NextUploadIdMarker"`
+	Prefix             string        `xml:"Prefix"`
+	MaxUploads         int           `xml:"MaxUploads"`
+	IsTruncated        bool          `xml:"IsTruncated"`
+	Uploads            []uploadEntry `xml:"Upload"`
+}
+
+type uploadEntry struct {
+	Key          string `xml:"Key"`
+	UploadID     string `xml:"UploadId"`
+	Initiated    string `xml:"Initiated"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+func (h *Handler) listMultipartUploads(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	_, limit, err := parseMarkerAndLimit("", query.Get("max-uploads"))
+	if err != nil {
+		return err
+	}
+
+	result := listMultipartUploadsResult{
+		Bucket:         h.bucket,
+		KeyMarker:      query.Get("key-marker"),
+		UploadIDMarker: query.Get("upload-id-marker"),
+		Prefix:         query.Get("prefix"),
+		MaxUploads:     limit,
+	}
+
+	h.mu.Lock()
+	uploads := make([]*upload, 0, len(h.uploads))
+	for _, u := range h.uploads {
+		if strings.HasPrefix(u.key, result.Prefix) {
+			uploads = append(uploads, u)
+		}
+	}
+	h.mu.Unlock()
+	sort.Slice(uploads, func(i, j int) bool {
+		if uploads[i].key != uploads[j].key {
+			return uploads[i].key < uploads[j].key
+		}
+		return uploads[i].id < uploads[j].id
+	})
+
+	for _, u := range uploads {
+		// Uploads after (key-marker, upload-id-marker) will be returned.
+		if result.KeyMarker != "" && (u.key < result.KeyMarker ||
+			(u.key == result.KeyMarker && (result.UploadIDMarker == "" || u.id <= result.UploadIDMarker))) {
+			continue
+		}
+		if len(result.Uploads) >= limit {
+			result.IsTruncated = true
+			break
+		}
+		result.Uploads = append(result.Uploads, uploadEntry{
+			Key:          u.key,
+			UploadID:     u.id,
+			Initiated:    u.initiated.UTC().Format(timeFormat),
+			StorageClass: "STANDARD",
+		})
+		result.NextKeyMarker = u.key
+		result.NextUploadIDMarker = u.id
+	}
+
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+// getUpload returns the upload of id, the key of it must be key.
+func (h *Handler) getUpload(id, key string) (*upload, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	u, ok := h.uploads[id]
+	if !ok || u.key != key {
+		return nil, errNoSuchUpload
+	}
+	return u, nil
+}
+
+func (h *Handler) partPath(id string, number int) string {
+	return h.multipartDir + "/" + id + "/" + strconv.Itoa(number)
+}
+
+// parseMarkerAndLimit parses the number marker and max value of list requests.
+func parseMarkerAndLimit(marker, limit string) (int, int, error) {
+	m, n := 0, maxParts
+	if marker != "" {
+		v, err := strconv.Atoi(marker)
+		if err != nil || v < 0 {
+			return 0, 0, errInvalidArgument.withMessage("Provided marker not an integer or within integer range.")
+		}
+		m = v
+	}
+	if limit != "" {
+		v, err := strconv.Atoi(limit)
+		if err != nil || v < 0 {
+			return 0, 0, errInvalidArgument.withMessage("Provided max value not an integer or within integer range.")
+		}
+		if v < n {
+			n = v
+		}
+	}
+	return m, n, nil
+}
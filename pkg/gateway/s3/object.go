@@ -0,0 +1,467 @@
+package s3
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+const (
+	userMetadataPrefix = "X-Amz-Meta-"
+	// maxDeleteObjects is the max number of keys in a DeleteObjects request.
+	maxDeleteObjects = 1000
+)
+
+func (h *Handler) getObject(w http.ResponseWriter, r *http.Request, key string) error {
+	o, err := h.stat(r, key)
+	if err != nil {
+		return err
+	}
+
+	size, hasSize := o.GetContentLength()
+	offset, length, partial := int64(0), size, false
+	if hasSize {
+		offset, length, partial, err = parseRange(r.Header.Get("Range"), size)
+		if err != nil {
+			return err
+		}
+	}
+
+	header := w.Header()
+	setObjectHeaders(header, o)
+	status := http.StatusOK
+	if hasSize {
+		header.Set("Content-Length", strconv.FormatInt(length, 10))
+	}
+	if partial {
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, size))
+		status = http.StatusPartialContent
+	}
+
+	lw := &lazyWriter{w: w, status: status}
+	err = h.read(r.Context(), key, lw, offset, length, partial)
+	if err != nil {
+		if lw.written {
+			// The status code has been sent, we can only abort the response
+			// by dropping the connection.
+			panic(http.ErrAbortHandler)
+		}
+		header.Del("Content-Length")
+		header.Del("Content-Range")
+		return err
+	}
+	if !lw.written {
+		w.WriteHeader(status)
+	}
+	return nil
+}
+
+// read reads the object at path into w, the whole object will be read and
+// skipped if the Storager doesn't support offset and size.
+func (h *Handler) read(ctx context.Context, path string, w io.Writer, offset, length int64, partial bool) error {
+	if !partial {
+		_, err := h.store.ReadWithContext(ctx, path, w)
+		return err
+	}
+
+	_, err := h.store.ReadWithContext(ctx, path, w, pairs.WithOffset(offset), pairs.WithSize(length))
+	if err == nil || !errors.As(err, &services.PairUnsupportedError{}) {
+		return err
+	}
+
+	rw := &rangeWriter{w: w, skip: offset, remain: length}
+	_, err = h.store.ReadWithContext(ctx, path, rw)
+	if err != nil && rw.remain == 0 {
+		// Reading is stopped by rangeWriter, the error could be wrapped by
+		// Storager in any way.
+		err = nil
+	}
+	return err
+}
+
+func (h *Handler) headObject(w http.ResponseWriter, r *http.Request, key string) error {
+	o, err := h.stat(r, key)
+	if err != nil {
+		return err
+	}
+
+	setObjectHeaders(w.Header(), o)
+	if v, ok := o.GetContentLength(); ok {
+		w.Header().Set("Content-Length", strconv.FormatInt(v, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// stat returns the object at key, and checks conditional headers of r against it.
+func (h *Handler) stat(r *http.Request, key string) (*types.Object, error) {
+	o, err := h.store.StatWithContext(r.Context(), key)
+	if err != nil {
+		return nil, err
+	}
+	if o.Mode.IsDir() {
+		return nil, errNoSuchKey
+	}
+	return o, checkConditions(r, o)
+}
+
+func (h *Handler) putObject(w http.ResponseWriter, r *http.Request, key string, auth *authorization) error {
+	b, err := newBody(r, auth)
+	if err != nil {
+		return err
+	}
+
+	ps := make([]types.Pair, 0)
+	if v := r.Header.Get("Content-Type"); v != "" {
+		ps = append(ps, pairs.WithContentType(v))
+	}
+	if v := parseUserMetadata(r.Header); len(v) > 0 {
+		ps = append(ps, pairs.WithUserMetadata(v))
+	}
+	if v := r.Header.Get("Content-MD5"); v != "" {
+		ps = append(ps, pairs.WithContentMd5(v))
+	}
+
+	if b.size > 0 && b.verifiable() {
+		err = h.stage(r.Context(), key, b, ps)
+	} else {
+		err = h.write(r.Context(), key, b, b.size, ps)
+	}
+	if b.err != nil {
+		return b.err
+	}
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("ETag", b.etag())
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// write writes r into the object at path with ps.
+//
+// Storagers which don't support content type or user metadata reject pairs
+// before reading any data, so the write will be retried without them.
+func (h *Handler) write(ctx context.Context, path string, r io.Reader, size int64, ps []types.Pair) error {
+	_, err := h.store.WriteWithContext(ctx, path, r, size, ps...)
+	if err != nil && len(ps) > 0 && errors.As(err, &services.PairUnsupportedError{}) {
+		_, err = h.store.WriteWithContext(ctx, path, r, size)
+	}
+	return err
+}
+
+// stage writes b into a temp object under the multipart dir, and moves it to
+// path only after b has been verified.
+//
+// Storagers like fs write into the target file directly, so the existing
+// object would be corrupted if a mismatch is only detected by the last Read.
+func (h *Handler) stage(ctx context.Context, path string, b *body, ps []types.Pair) error {
+	tmp := h.multipartDir + "/" + uuid.New().String()
+	// The temp object has been moved if succeeded, errors will be ignored.
+	defer func() { _ = h.store.DeleteWithContext(ctx, tmp) }()
+
+	err := h.write(ctx, tmp, b, b.size, ps)
+	if b.err != nil {
+		return b.err
+	}
+	if err != nil {
+		return err
+	}
+
+	if h.store.Features().Move {
+		return h.store.MoveWithContext(ctx, tmp, path)
+	}
+	return h.writeFrom(ctx, path, []string{tmp}, b.size, ps)
+}
+
+// writeFrom writes the objects at srcs into path in order, size is the sum
+// of their sizes.
+func (h *Handler) writeFrom(ctx context.Context, path string, srcs []string, size int64, ps []types.Pair) error {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, src := range srcs {
+			if _, err := h.store.ReadWithContext(ctx, src, pw); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.Close()
+	}()
+
+	err := h.write(ctx, path, pr, size, ps)
+	// Unblock the reading goroutine if the write is returned early.
+	pr.CloseWithError(io.ErrClosedPipe)
+	<-done
+	return err
+}
+
+func (h *Handler) deleteObject(w http.ResponseWriter, r *http.Request, key string) error {
+	err := h.store.DeleteWithContext(r.Context(), key)
+	if err != nil && !errors.Is(err, services.ErrObjectNotExist) {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+type deleteObjectsRequest struct {
+	XMLName xml.Name `xml:"Delete"`
+	Quiet   bool     `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+type deleteObjectsResult struct {
+	XMLName xml.Name        `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DeleteResult"`
+	Deleted []deletedObject `xml:"Deleted"`
+	Errors  []deleteError   `xml:"Error"`
+}
+
+type deletedObject struct {
+	Key string `xml:"Key"`
+}
+
+type deleteError struct {
+	Key     string `xml:"Key"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (h *Handler) deleteObjects(w http.ResponseWriter, r *http.Request) error {
+	var req deleteObjectsRequest
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errMalformedXML
+	}
+	if len(req.Objects) == 0 || len(req.Objects) > maxDeleteObjects {
+		return errMalformedXML
+	}
+
+	result := deleteObjectsResult{}
+	for _, v := range req.Objects {
+		err := validateKey(v.Key)
+		if err == nil && h.isMultipartPath(v.Key) {
+			err = errAccessDenied
+		}
+		if err == nil {
+			err = h.store.DeleteWithContext(r.Context(), v.Key)
+		}
+		if err != nil && !errors.Is(err, services.ErrObjectNotExist) {
+			e := formatError(err)
+			result.Errors = append(result.Errors, deleteError{Key: v.Key, Code: e.Code, Message: e.Message})
+			continue
+		}
+		if !req.Quiet {
+			result.Deleted = append(result.Deleted, deletedObject{Key: v.Key})
+		}
+	}
+
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+// setObjectHeaders sets headers of object o except Content-Length.
+func setObjectHeaders(header http.Header, o *types.Object) {
+	header.Set("Accept-Ranges", "bytes")
+	if v := formatEtag(o); v != "" {
+		header.Set("ETag", v)
+	}
+	if v, ok := o.GetContentType(); ok {
+		header.Set("Content-Type", v)
+	} else {
+		header.Set("Content-Type", "binary/octet-stream")
+	}
+	if v, ok := o.GetLastModified(); ok {
+		header.Set("Last-Modified", v.UTC().Format(http.TimeFormat))
+	}
+	if v, ok := o.GetUserMetadata(); ok {
+		for k, mv := range v {
+			header.Set(userMetadataPrefix+k, mv)
+		}
+	}
+}
+
+// formatEtag returns the quoted etag of o, content md5 will be used if etag
+// is missing.
+func formatEtag(o *types.Object) string {
+	if v, ok := o.GetEtag(); ok && v != "" {
+		if !strings.HasPrefix(v, `"`) {
+			v = `"` + v + `"`
+		}
+		return v
+	}
+	if v, ok := o.GetContentMd5(); ok {
+		if b, err := base64.StdEncoding.DecodeString(v); err == nil {
+			return `"` + hex.EncodeToString(b) + `"`
+		}
+	}
+	return ""
+}
+
+// parseUserMetadata parses user metadata from x-amz-meta-* headers.
+func parseUserMetadata(header http.Header) map[string]string {
+	um := make(map[string]string)
+	for k, v := range header {
+		if strings.HasPrefix(k, userMetadataPrefix) && len(v) > 0 {
+			um[strings.ToLower(strings.TrimPrefix(k, userMetadataPrefix))] = v[0]
+		}
+	}
+	return um
+}
+
+// checkConditions checks conditional headers of r against o.
+//
+// ref: https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetObject.html
+func checkConditions(r *http.Request, o *types.Object) error {
+	etag := formatEtag(o)
+	lastModified, hasLastModified := o.GetLastModified()
+	lastModified = lastModified.Truncate(time.Second)
+
+	if v := r.Header.Get("If-Match"); v != "" {
+		if !etagMatch(v, etag) {
+			return errPreconditionFailed
+		}
+	} else if t, err := http.ParseTime(r.Header.Get("If-Unmodified-Since")); err == nil && hasLastModified {
+		if lastModified.After(t) {
+			return errPreconditionFailed
+		}
+	}
+
+	if v := r.Header.Get("If-None-Match"); v != "" {
+		if etagMatch(v, etag) {
+			return errNotModified
+		}
+	} else if t, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && hasLastModified {
+		if !lastModified.After(t) {
+			return errNotModified
+		}
+	}
+	return nil
+}
+
+// etagMatch checks whether etag matches any of the comma separated etags in v.
+func etagMatch(v, etag string) bool {
+	for _, e := range strings.Split(v, ",") {
+		e = strings.TrimSpace(e)
+		if e == "*" || (etag != "" && strings.Trim(e, `"`) == strings.Trim(etag, `"`)) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRange parses the Range header of an object with size.
+//
+// Only single byte range is supported, invalid or multiple ranges will be
+// ignored like S3 does.
+func parseRange(v string, size int64) (offset, length int64, partial bool, err error) {
+	if !strings.HasPrefix(v, "bytes=") || strings.Contains(v, ",") {
+		return 0, size, false, nil
+	}
+	spec := strings.SplitN(strings.TrimPrefix(v, "bytes="), "-", 2)
+	if len(spec) != 2 {
+		return 0, size, false, nil
+	}
+
+	var start, end int64
+	switch {
+	case spec[0] == "":
+		// Suffix range like `bytes=-500`.
+		n, perr := strconv.ParseInt(spec[1], 10, 64)
+		if perr != nil || n < 0 {
+			return 0, size, false, nil
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, false, errInvalidRange
+		}
+		if n > size {
+			n = size
+		}
+		start, end = size-n, size-1
+	default:
+		start, err = strconv.ParseInt(spec[0], 10, 64)
+		if err != nil || start < 0 {
+			return 0, size, false, nil
+		}
+		end = size - 1
+		if spec[1] != "" {
+			end, err = strconv.ParseInt(spec[1], 10, 64)
+			if err != nil || end < start {
+				return 0, size, false, nil
+			}
+			if end > size-1 {
+				end = size - 1
+			}
+		}
+		if start >= size {
+			return 0, 0, false, errInvalidRange
+		}
+	}
+	return start, end - start + 1, true, nil
+}
+
+// lazyWriter writes the status code before the first write, so that errors
+// could still be returned before any data is written.
+type lazyWriter struct {
+	w       http.ResponseWriter
+	status  int
+	written bool
+}
+
+func (l *lazyWriter) Write(p []byte) (int, error) {
+	if !l.written {
+		l.w.WriteHeader(l.status)
+		l.written = true
+	}
+	return l.w.Write(p)
+}
+
+// errRangeDone is returned by rangeWriter to stop reading.
+var errRangeDone = errors.New("range done")
+
+// rangeWriter skips the first skip bytes and writes at most remain bytes into w.
+type rangeWriter struct {
+	w      io.Writer
+	skip   int64
+	remain int64
+}
+
+func (rw *rangeWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if rw.skip > 0 {
+		if int64(len(p)) <= rw.skip {
+			rw.skip -= int64(len(p))
+			return n, nil
+		}
+		p = p[rw.skip:]
+		rw.skip = 0
+	}
+	if int64(len(p)) > rw.remain {
+		p = p[:rw.remain]
+	}
+	if _, err := rw.w.Write(p); err != nil {
+		return 0, err
+	}
+	rw.remain -= int64(len(p))
+	if rw.remain == 0 {
+		return n, errRangeDone
+	}
+	return n, nil
+}
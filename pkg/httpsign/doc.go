@@ -0,0 +1,16 @@
+/*
+Package httpsign provides query signed HTTP access to Storagers which don't
+have their own HTTP endpoint, like fs and memory.
+
+Signer builds requests for read, write and delete with an expiration time
+and an HMAC-SHA256 signature in query parameters, and Handler serves a
+Storager by validating these requests. Signer and Handler must share the
+same key.
+
+	store, _ := memory.NewStorager()
+	http.ListenAndServe("127.0.0.1:8080", httpsign.NewHandler(store, key))
+
+Services could implement query_sign_http_* operations via Signer, and
+callers hand out the signed requests as presigned urls.
+*/
+package httpsign
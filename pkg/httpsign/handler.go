@@ -0,0 +1,157 @@
+package httpsign
+
+import (
+	"crypto/hmac"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+var (
+	// ErrSignatureMismatch means the signature of a request is missing or invalid.
+	ErrSignatureMismatch = errors.New("signature mismatch")
+	// ErrRequestExpired means the request has been expired.
+	ErrRequestExpired = errors.New("request expired")
+)
+
+// Handler serves a Storager via requests signed by Signer.
+//
+// Only GET, PUT and DELETE requests which match their signed method are
+// accepted, all other requests will be rejected.
+type Handler struct {
+	store types.Storager
+	key   []byte
+
+	// now is used to check expiration, overwritten in tests.
+	now func() time.Time
+}
+
+// NewHandler will create a new Handler which serves store.
+func NewHandler(store types.Storager, key []byte) *Handler {
+	return &Handler{
+		store: store,
+		key:   key,
+		now:   time.Now,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+
+	query := r.URL.Query()
+	if err := h.verify(r.Method, path, query); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.read(w, r, path)
+	case http.MethodPut:
+		h.write(w, r, path, query)
+	case http.MethodDelete:
+		h.delete(w, r, path)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) verify(method, path string, query url.Values) error {
+	sig := query.Get(QuerySignature)
+	if sig == "" || !hmac.Equal([]byte(sig), []byte(signature(h.key, method, path, query))) {
+		return ErrSignatureMismatch
+	}
+
+	expires, err := strconv.ParseInt(query.Get(QueryExpires), 10, 64)
+	if err != nil {
+		return ErrSignatureMismatch
+	}
+	if h.now().Unix() > expires {
+		return ErrRequestExpired
+	}
+	return nil
+}
+
+func (h *Handler) read(w http.ResponseWriter, r *http.Request, path string) {
+	o, err := h.store.StatWithContext(r.Context(), path)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if o.Mode.IsDir() {
+		http.Error(w, "object is a dir", http.StatusNotFound)
+		return
+	}
+
+	header := w.Header()
+	if v, ok := o.GetContentLength(); ok {
+		header.Set("Content-Length", strconv.FormatInt(v, 10))
+	}
+	if v, ok := o.GetContentType(); ok {
+		header.Set("Content-Type", v)
+	}
+	if v, ok := o.GetEtag(); ok {
+		header.Set("ETag", v)
+	}
+	if v, ok := o.GetLastModified(); ok {
+		header.Set("Last-Modified", v.UTC().Format(http.TimeFormat))
+	}
+
+	// The status code has been sent while data is being read, so we can only
+	// abort the response by dropping the connection.
+	_, err = h.store.ReadWithContext(r.Context(), path, w)
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func (h *Handler) write(w http.ResponseWriter, r *http.Request, path string, query url.Values) {
+	size, err := strconv.ParseInt(query.Get(QuerySize), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid size", http.StatusBadRequest)
+		return
+	}
+	if r.ContentLength != size {
+		http.Error(w, "content length mismatch", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.store.WriteWithContext(r.Context(), path, r.Body, size)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, path string) {
+	err := h.store.DeleteWithContext(r.Context(), path)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeError converts errors returned by Storager into http responses.
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrObjectNotExist):
+		code = http.StatusNotFound
+	case errors.Is(err, services.ErrPermissionDenied):
+		code = http.StatusForbidden
+	case errors.Is(err, services.ErrPreconditionFailed):
+		code = http.StatusPreconditionFailed
+	case errors.Is(err, services.ErrCapabilityInsufficient):
+		code = http.StatusNotImplemented
+	}
+	http.Error(w, err.Error(), code)
+}
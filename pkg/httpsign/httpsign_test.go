@@ -0,0 +1,128 @@
+package httpsign
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/internal/storagetest"
+)
+
+func setup(t *testing.T, prefix string) (*storagetest.Storager, *Signer, *Handler) {
+	key := []byte("test-key")
+	store := storagetest.New()
+	h := NewHandler(store, key)
+
+	mux := http.NewServeMux()
+	mux.Handle(prefix+"/", http.StripPrefix(prefix, h))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	signer, err := NewSigner(srv.URL+prefix, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, signer, h
+}
+
+func TestSignerAndHandler(t *testing.T) {
+	for _, prefix := range []string{"", "/storage"} {
+		_, signer, _ := setup(t, prefix)
+		content := []byte("hello, world")
+		path := "dir/hello world.txt"
+
+		req, err := signer.SignWrite(path, int64(len(content)), time.Hour)
+		assert.NoError(t, err)
+		req.Body = io.NopCloser(bytes.NewReader(content))
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		req, err = signer.SignRead(path, time.Hour)
+		assert.NoError(t, err)
+		resp, err = http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int64(len(content)), resp.ContentLength)
+		assert.Equal(t, content, body)
+
+		req, err = signer.SignDelete(path, time.Hour)
+		assert.NoError(t, err)
+		resp, err = http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		req, err = signer.SignRead(path, time.Hour)
+		assert.NoError(t, err)
+		resp, err = http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestHandler_Reject(t *testing.T) {
+	store, signer, h := setup(t, "")
+	store.Put("a", []byte("content"))
+
+	cases := []struct {
+		name   string
+		modify func(req *http.Request)
+	}{
+		{"tampered path", func(req *http.Request) {
+			req.URL.Path = "/b"
+		}},
+		{"tampered method", func(req *http.Request) {
+			req.Method = http.MethodDelete
+		}},
+		{"tampered expires", func(req *http.Request) {
+			q := req.URL.Query()
+			q.Set(QueryExpires, "9999999999")
+			req.URL.RawQuery = q.Encode()
+		}},
+		{"missing signature", func(req *http.Request) {
+			q := req.URL.Query()
+			q.Del(QuerySignature)
+			req.URL.RawQuery = q.Encode()
+		}},
+		{"expired", func(req *http.Request) {
+			h.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			h.now = time.Now
+
+			req, err := signer.SignRead("a", time.Hour)
+			assert.NoError(t, err)
+			tt.modify(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		})
+	}
+	assert.Equal(t, []byte("content"), store.Data("a"))
+}
+
+func TestNewSigner(t *testing.T) {
+	_, err := NewSigner("http://127.0.0.1:8080", nil)
+	assert.Error(t, err)
+
+	_, err = NewSigner("ftp://127.0.0.1:8080", []byte("key"))
+	assert.Error(t, err)
+
+	_, err = NewSigner("http://127.0.0.1:8080/", []byte("key"))
+	assert.NoError(t, err)
+}
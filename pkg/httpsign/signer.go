@@ -0,0 +1,101 @@
+package httpsign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// All query parameters used by signed requests.
+const (
+	// QueryExpires is the unix timestamp in seconds that the request expires at.
+	QueryExpires = "X-Expires"
+	// QuerySize is the content length of a signed write request.
+	QuerySize = "X-Size"
+	// QuerySignature is the hex encoded HMAC-SHA256 signature of the request.
+	QuerySignature = "X-Signature"
+)
+
+// Signer signs requests which could be served by Handler.
+type Signer struct {
+	endpoint *url.URL
+	key      []byte
+}
+
+// NewSigner will create a new Signer.
+//
+// endpoint is the url that Handler is served at, like "http://127.0.0.1:8080".
+// If endpoint has a path, Handler should be mounted via http.StripPrefix.
+func NewSigner(endpoint string, key []byte) (*Signer, error) {
+	if len(key) == 0 {
+		return nil, errors.New("httpsign: key is empty")
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("httpsign: parse endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("httpsign: endpoint scheme %q is not supported", u.Scheme)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+
+	return &Signer{endpoint: u, key: key}, nil
+}
+
+// SignRead returns a request which will read the object at path.
+func (s *Signer) SignRead(path string, expire time.Duration) (*http.Request, error) {
+	return s.sign(http.MethodGet, path, expire, url.Values{})
+}
+
+// SignWrite returns a request which will write size bytes into the object at
+// path. The request body should be set by caller.
+func (s *Signer) SignWrite(path string, size int64, expire time.Duration) (*http.Request, error) {
+	req, err := s.sign(http.MethodPut, path, expire, url.Values{
+		QuerySize: []string{strconv.FormatInt(size, 10)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	return req, nil
+}
+
+// SignDelete returns a request which will delete the object at path.
+func (s *Signer) SignDelete(path string, expire time.Duration) (*http.Request, error) {
+	return s.sign(http.MethodDelete, path, expire, url.Values{})
+}
+
+func (s *Signer) sign(method, path string, expire time.Duration, query url.Values) (*http.Request, error) {
+	query.Set(QueryExpires, strconv.FormatInt(time.Now().Add(expire).Unix(), 10))
+	query.Set(QuerySignature, signature(s.key, method, path, query))
+
+	u := *s.endpoint
+	u.Path += "/" + strings.TrimPrefix(path, "/")
+	u.RawQuery = query.Encode()
+
+	return http.NewRequest(method, u.String(), nil)
+}
+
+// signature calculates the signature of a request, QuerySignature in query
+// will be ignored.
+func signature(key []byte, method, path string, query url.Values) string {
+	q := make(url.Values, len(query))
+	for k, v := range query {
+		if k != QuerySignature {
+			q[k] = v
+		}
+	}
+
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(method + "\n" + strings.TrimPrefix(path, "/") + "\n" + q.Encode()))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -189,3 +189,33 @@ func (r *CallbackifyReadCloser) Read(p []byte) (int, error) {
 func (r *CallbackifyReadCloser) Close() error {
 	return r.r.Close()
 }
+
+// ThrottleReader will create a new ThrottledReader.
+func ThrottleReader(r io.Reader, fn func(n int) error) *ThrottledReader {
+	return &ThrottledReader{
+		r:  r,
+		fn: fn,
+	}
+}
+
+// ThrottledReader will call fn with the read bytes after every Read, and
+// fn could block to throttle the reading speed.
+type ThrottledReader struct {
+	r  io.Reader
+	fn func(n int) error
+}
+
+var (
+	_ io.Reader = &ThrottledReader{}
+)
+
+// Read will read from underlying Reader.
+func (r *ThrottledReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if ferr := r.fn(n); ferr != nil {
+			return n, ferr
+		}
+	}
+	return n, err
+}
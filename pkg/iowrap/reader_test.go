@@ -367,3 +367,24 @@ func TestCallbackifyReadCloser_Close(t *testing.T) {
 	x := CallbackReadCloser(r, func(bytes []byte) {})
 	_ = x.Close()
 }
+
+func TestThrottledReader_Read(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reader := NewMockReader(ctrl)
+
+	reader.EXPECT().Read(gomock.Any()).DoAndReturn(func(p []byte) (int, error) {
+		return 10, io.EOF
+	}).AnyTimes()
+
+	throttled := 0
+	x := ThrottleReader(reader, func(n int) error {
+		throttled += n
+		return nil
+	})
+
+	_, _ = io.ReadAll(x)
+
+	assert.Equal(t, 10, throttled)
+}
@@ -24,3 +24,26 @@ func (w *CallbackifyWriter) Write(p []byte) (int, error) {
 	w.fn(p[:n])
 	return n, err
 }
+
+// ThrottleWriter will create a new ThrottledWriter.
+func ThrottleWriter(w io.Writer, fn func(n int) error) *ThrottledWriter {
+	return &ThrottledWriter{
+		w:  w,
+		fn: fn,
+	}
+}
+
+// ThrottledWriter will call fn with the bytes to write before every Write,
+// and fn could block to throttle the writing speed.
+type ThrottledWriter struct {
+	w  io.Writer
+	fn func(n int) error
+}
+
+// Write will write into underlying Writer.
+func (w *ThrottledWriter) Write(p []byte) (int, error) {
+	if err := w.fn(len(p)); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
@@ -31,3 +31,31 @@ func TestCallbackifyWriter_Write(t *testing.T) {
 
 	assert.True(t, called)
 }
+
+func TestThrottledWriter_Write(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockWriter(ctrl)
+
+	writer.EXPECT().Write(gomock.Any()).DoAndReturn(func(p []byte) (int, error) {
+		return len(p), nil
+	}).Times(1)
+
+	throttled := 0
+	x := ThrottleWriter(writer, func(n int) error {
+		throttled += n
+		return nil
+	})
+
+	n, err := x.Write(make([]byte, 10))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, n)
+	assert.Equal(t, 10, throttled)
+
+	x = ThrottleWriter(writer, func(n int) error {
+		return io.ErrShortWrite
+	})
+	_, err = x.Write(make([]byte, 10))
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+}
@@ -0,0 +1,11 @@
+/*
+Package lifecycle provides an in-process evaluator of lifecycle rules for
+Storagers without native lifecycle support, like fs, memory and ftp.
+
+Rules are the same types.LifecycleRule used by get_lifecycle and
+set_lifecycle. Evaluation is done in two steps like pkg/mirror: Evaluate
+walks the storage and builds a Plan which could be inspected before
+execution, and Plan.Execute takes the due actions. Nothing runs in the
+background, callers decide when to evaluate.
+*/
+package lifecycle
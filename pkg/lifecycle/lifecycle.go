@@ -0,0 +1,265 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/pkg/walk"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// Action is the action taken on an object.
+type Action int
+
+// All available actions.
+const (
+	// ActionExpire means the object will be deleted.
+	ActionExpire Action = iota + 1
+	// ActionTransit means the object will be transited into another storage class.
+	ActionTransit
+	// ActionAbortMultipart means the incomplete multipart upload will be aborted.
+	ActionAbortMultipart
+)
+
+// String implements fmt.Stringer
+func (a Action) String() string {
+	switch a {
+	case ActionExpire:
+		return "expire"
+	case ActionTransit:
+		return "transit"
+	case ActionAbortMultipart:
+		return "abort_multipart"
+	default:
+		return fmt.Sprintf("Action(%d)", int(a))
+	}
+}
+
+// TransitFunc transits the object into storageClass.
+type TransitFunc func(ctx context.Context, store types.Storager, o *types.Object, storageClass string) error
+
+// Options is the lifecycle supported options.
+type Options struct {
+	// Now is the time that rules are evaluated at, time.Now() by default.
+	Now time.Time
+	// Transit is used to take ActionTransit.
+	//
+	// Backends without native lifecycle support don't have storage classes
+	// in most cases, so transitions will be skipped while Transit is nil.
+	Transit TransitFunc
+}
+
+// Entry is an action to be taken on an object.
+type Entry struct {
+	Action Action
+	// Object is the object to take action on, it will carry multipart_id for
+	// ActionAbortMultipart.
+	Object *types.Object
+	// Rule is the rule which this action comes from.
+	Rule *types.LifecycleRule
+	// StorageClass is the storage class to transit into, only valid for ActionTransit.
+	StorageClass string
+}
+
+// Plan is the result of Evaluate.
+type Plan struct {
+	// Entries is sorted by object path.
+	Entries []Entry
+
+	store types.Storager
+	opt   Options
+}
+
+// Count returns the count of entries with action a.
+func (p *Plan) Count(a Action) int {
+	n := 0
+	for _, e := range p.Entries {
+		if e.Action == a {
+			n++
+		}
+	}
+	return n
+}
+
+// Evaluate walks store and returns the Plan of all due actions of rules.
+//
+// At most one action will be taken on an object: expiration wins over
+// transitions, and the transition with the most days wins if multiple
+// transitions are due.
+func Evaluate(ctx context.Context, store types.Storager, rules []*types.LifecycleRule, o *Options) (*Plan, error) {
+	p := &Plan{store: store}
+	if o != nil {
+		p.opt = *o
+	}
+	if p.opt.Now.IsZero() {
+		p.opt.Now = time.Now()
+	}
+
+	active := make([]*types.LifecycleRule, 0, len(rules))
+	for _, v := range rules {
+		if v != nil && !v.Disabled {
+			active = append(active, v)
+		}
+	}
+	if len(active) == 0 {
+		return p, nil
+	}
+
+	err := walk.Walk(ctx, store, "", func(o *types.Object) error {
+		if o.Mode.IsDir() {
+			if !reachable(active, o.Path+"/") {
+				return walk.SkipDir
+			}
+			return nil
+		}
+
+		lastModified, ok := o.GetLastModified()
+		if !ok {
+			so, err := store.StatWithContext(ctx, o.Path)
+			if errors.Is(err, services.ErrObjectNotExist) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("stat %s: %w", o.Path, err)
+			}
+			if lastModified, ok = so.GetLastModified(); !ok {
+				// Objects without last modified time could never be due.
+				return nil
+			}
+		}
+
+		if e, ok := p.evaluate(active, o, lastModified); ok {
+			p.Entries = append(p.Entries, e)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if store.Features().CreateMultipart {
+		entries, err := p.evaluateMultipart(ctx, active)
+		if err != nil {
+			return nil, err
+		}
+		p.Entries = append(p.Entries, entries...)
+	}
+
+	sort.SliceStable(p.Entries, func(i, j int) bool {
+		return p.Entries[i].Object.Path < p.Entries[j].Object.Path
+	})
+	return p, nil
+}
+
+// evaluate returns the action of object o which is due at now.
+func (p *Plan) evaluate(rules []*types.LifecycleRule, o *types.Object, lastModified time.Time) (Entry, bool) {
+	var (
+		e    Entry
+		days int
+	)
+	for _, r := range rules {
+		if !r.Match(o.Path) {
+			continue
+		}
+		if r.ExpireDays > 0 && p.due(lastModified, r.ExpireDays) {
+			return Entry{Action: ActionExpire, Object: o, Rule: r}, true
+		}
+		for _, t := range r.Transitions {
+			if t.Days < days || !p.due(lastModified, t.Days) {
+				continue
+			}
+			days = t.Days
+			e = Entry{Action: ActionTransit, Object: o, Rule: r, StorageClass: t.StorageClass}
+		}
+	}
+	return e, e.Action != 0
+}
+
+// evaluateMultipart returns all incomplete multipart uploads which should be aborted.
+func (p *Plan) evaluateMultipart(ctx context.Context, rules []*types.LifecycleRule) ([]Entry, error) {
+	entries := make([]Entry, 0)
+	for _, r := range rules {
+		if r.AbortMultipartDays <= 0 {
+			continue
+		}
+
+		it, err := p.store.ListWithContext(ctx, r.Prefix, pairs.WithListMode(types.ListModePart))
+		if err != nil {
+			return nil, fmt.Errorf("list multipart %s: %w", r.Prefix, err)
+		}
+		for {
+			o, err := it.Next()
+			if errors.Is(err, types.IterateDone) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("list multipart %s: %w", r.Prefix, err)
+			}
+
+			// The initiated time of multipart uploads are returned as last
+			// modified, uploads without it will be skipped.
+			initiated, ok := o.GetLastModified()
+			if !ok || !p.due(initiated, r.AbortMultipartDays) {
+				continue
+			}
+			entries = append(entries, Entry{Action: ActionAbortMultipart, Object: o, Rule: r})
+		}
+	}
+	return entries, nil
+}
+
+// due returns whether the action after days of t is due.
+func (p *Plan) due(t time.Time, days int) bool {
+	return !p.opt.Now.Before(t.Add(time.Duration(days) * 24 * time.Hour))
+}
+
+// Execute takes all actions in the Plan in order.
+//
+// ActionTransit will be skipped if Options.Transit is nil. Execute returns
+// the first error while taking actions.
+func (p *Plan) Execute(ctx context.Context) error {
+	for _, e := range p.Entries {
+		var err error
+		switch e.Action {
+		case ActionExpire:
+			err = p.store.DeleteWithContext(ctx, e.Object.Path)
+		case ActionTransit:
+			if p.opt.Transit == nil {
+				continue
+			}
+			err = p.opt.Transit(ctx, p.store, e.Object, e.StorageClass)
+		case ActionAbortMultipart:
+			err = p.store.DeleteWithContext(ctx, e.Object.Path,
+				pairs.WithMultipartID(e.Object.MustGetMultipartID()))
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", e.Action, e.Object.Path, err)
+		}
+	}
+	return nil
+}
+
+// Apply evaluates rules against store and takes all due actions.
+func Apply(ctx context.Context, store types.Storager, rules []*types.LifecycleRule, o *Options) (*Plan, error) {
+	p, err := Evaluate(ctx, store, rules, o)
+	if err != nil {
+		return nil, err
+	}
+	return p, p.Execute(ctx)
+}
+
+// reachable returns whether any rule could match objects under dir.
+func reachable(rules []*types.LifecycleRule, dir string) bool {
+	for _, r := range rules {
+		if strings.HasPrefix(dir, r.Prefix) || strings.HasPrefix(r.Prefix, dir) {
+			return true
+		}
+	}
+	return false
+}
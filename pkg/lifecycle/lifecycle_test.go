@@ -0,0 +1,150 @@
+package lifecycle
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// prefixStorager is a minimal Storager which supports list in prefix mode.
+type prefixStorager struct {
+	types.UnimplementedStorager
+
+	files map[string]time.Time
+	mu    sync.Mutex
+}
+
+func (s *prefixStorager) Features() types.StorageFeatures {
+	return types.StorageFeatures{VirtualDir: true, List: true, Stat: true, Delete: true}
+}
+
+func (s *prefixStorager) ListWithContext(ctx context.Context, prefix string, ps ...types.Pair) (*types.ObjectIterator, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objects := make([]*types.Object, 0)
+	for k, v := range s.files {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		o := types.NewObject(s, true)
+		o.Path = k
+		o.Mode = types.ModeRead
+		o.SetLastModified(v)
+		objects = append(objects, o)
+	}
+
+	fn := types.NextObjectFunc(func(ctx context.Context, page *types.ObjectPage) error {
+		page.Data = append(page.Data, objects...)
+		return types.IterateDone
+	})
+	return types.NewObjectIterator(ctx, fn, nil), nil
+}
+
+func (s *prefixStorager) StatWithContext(ctx context.Context, path string, ps ...types.Pair) (*types.Object, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.files[path]
+	if !ok {
+		return nil, services.ErrObjectNotExist
+	}
+	o := types.NewObject(s, true)
+	o.Path = path
+	o.SetLastModified(v)
+	return o, nil
+}
+
+func (s *prefixStorager) DeleteWithContext(ctx context.Context, path string, ps ...types.Pair) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, path)
+	return nil
+}
+
+func (s *prefixStorager) paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths := make([]string, 0, len(s.files))
+	for k := range s.files {
+		paths = append(paths, k)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	store := &prefixStorager{files: map[string]time.Time{
+		"logs/a":     now.Add(-40 * day),
+		"logs/b":     now.Add(-10 * day),
+		"logs/c":     now.Add(-1 * day),
+		"data/a":     now.Add(-100 * day),
+		"tmp/a":      now.Add(-2 * day),
+		"tmp/nested": now.Add(-3 * day),
+	}}
+	rules := []*types.LifecycleRule{
+		{
+			Prefix:     "logs/",
+			ExpireDays: 30,
+			Transitions: []types.LifecycleTransition{
+				{Days: 7, StorageClass: "cold"},
+				{Days: 3, StorageClass: "warm"},
+			},
+		},
+		{Prefix: "tmp/", ExpireDays: 1},
+		{Prefix: "data/", ExpireDays: 1, Disabled: true},
+	}
+
+	p, err := Evaluate(context.Background(), store, rules, &Options{Now: now})
+	assert.NoError(t, err)
+
+	got := make([]string, 0, len(p.Entries))
+	for _, e := range p.Entries {
+		got = append(got, e.Action.String()+" "+e.Object.Path+" "+e.StorageClass)
+	}
+	assert.Equal(t, []string{
+		"expire logs/a ",
+		"transit logs/b cold",
+		"expire tmp/a ",
+		"expire tmp/nested ",
+	}, got)
+	assert.Equal(t, 3, p.Count(ActionExpire))
+	assert.Equal(t, 1, p.Count(ActionTransit))
+
+	// Transitions are skipped without Transit.
+	err = p.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"data/a", "logs/b", "logs/c"}, store.paths())
+}
+
+func TestApply_Transit(t *testing.T) {
+	now := time.Now()
+	store := &prefixStorager{files: map[string]time.Time{
+		"a": now.Add(-48 * time.Hour),
+	}}
+
+	transited := make(map[string]string)
+	_, err := Apply(context.Background(), store, []*types.LifecycleRule{
+		{Transitions: []types.LifecycleTransition{{Days: 1, StorageClass: "cold"}}},
+	}, &Options{
+		Transit: func(ctx context.Context, store types.Storager, o *types.Object, storageClass string) error {
+			transited[o.Path] = storageClass
+			return nil
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "cold"}, transited)
+	assert.Equal(t, []string{"a"}, store.paths())
+}
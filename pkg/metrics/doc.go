@@ -0,0 +1,17 @@
+/*
+Package metrics provides a Storager middleware which records operation metrics.
+
+The following metrics will be recorded for every service and operation:
+
+- calls and errors by error code defined in services
+- latency histogram
+- bytes read and written
+
+Collector implements http.Handler, so metrics could be exposed in Prometheus
+text format without any external dependencies:
+
+	c := metrics.NewCollector(nil)
+	store = c.Wrap(store)
+	http.Handle("/metrics", c)
+*/
+package metrics
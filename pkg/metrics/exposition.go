@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ContentType is the content type of Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var _ http.Handler = &Collector{}
+
+// ServeHTTP implements http.Handler and exposes all metrics in Prometheus text format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = c.WriteTo(w)
+}
+
+// WriteTo writes all metrics in Prometheus text format into w.
+func (c *Collector) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &countWriter{w: bufio.NewWriter(w)}
+
+	c.mu.RLock()
+	keys := make([]opKey, 0, len(c.ops))
+	for k := range c.ops {
+		keys = append(keys, k)
+	}
+	c.mu.RUnlock()
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].service != keys[j].service {
+			return keys[i].service < keys[j].service
+		}
+		return keys[i].op < keys[j].op
+	})
+
+	// Take a snapshot so that we will not hold locks while writing.
+	snapshots := make([]snapshot, 0, len(keys))
+	for _, k := range keys {
+		snapshots = append(snapshots, c.get(k.service, k.op).snapshot(k))
+	}
+
+	c.writeHeader(cw, "operations_total", "counter", "Total number of storage operations.")
+	for _, s := range snapshots {
+		cw.printf("%s_operations_total%s %d\n", c.namespace, s.labels(), s.calls)
+	}
+
+	c.writeHeader(cw, "operation_errors_total", "counter", "Total number of failed storage operations by error code.")
+	for _, s := range snapshots {
+		codes := make([]string, 0, len(s.errors))
+		for code := range s.errors {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			cw.printf("%s_operation_errors_total%s %d\n", c.namespace, s.labels("code", code), s.errors[code])
+		}
+	}
+
+	c.writeHeader(cw, "operation_duration_seconds", "histogram", "Latency of storage operations in seconds.")
+	for _, s := range snapshots {
+		for k, v := range s.buckets {
+			cw.printf("%s_operation_duration_seconds_bucket%s %d\n",
+				c.namespace, s.labels("le", formatFloat(v)), s.counts[k])
+		}
+		cw.printf("%s_operation_duration_seconds_bucket%s %d\n", c.namespace, s.labels("le", "+Inf"), s.calls)
+		cw.printf("%s_operation_duration_seconds_sum%s %s\n", c.namespace, s.labels(), formatFloat(s.sum))
+		cw.printf("%s_operation_duration_seconds_count%s %d\n", c.namespace, s.labels(), s.calls)
+	}
+
+	c.writeHeader(cw, "read_bytes_total", "counter", "Total bytes read from storage.")
+	for _, s := range snapshots {
+		if s.bytesRead > 0 {
+			cw.printf("%s_read_bytes_total%s %d\n", c.namespace, s.labels(), s.bytesRead)
+		}
+	}
+
+	c.writeHeader(cw, "written_bytes_total", "counter", "Total bytes written into storage.")
+	for _, s := range snapshots {
+		if s.bytesWritten > 0 {
+			cw.printf("%s_written_bytes_total%s %d\n", c.namespace, s.labels(), s.bytesWritten)
+		}
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, cw.w.(*bufio.Writer).Flush()
+}
+
+func (c *Collector) writeHeader(w *countWriter, name, typ, help string) {
+	w.printf("# HELP %s_%s %s\n", c.namespace, name, help)
+	w.printf("# TYPE %s_%s %s\n", c.namespace, name, typ)
+}
+
+type snapshot struct {
+	opKey
+
+	bytesRead    int64
+	bytesWritten int64
+	buckets      []float64
+	counts       []uint64
+	sum          float64
+	calls        uint64
+	errors       map[string]uint64
+}
+
+func (m *opMetrics) snapshot(k opKey) snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := snapshot{
+		opKey:        k,
+		bytesRead:    atomic.LoadInt64(&m.bytesRead),
+		bytesWritten: atomic.LoadInt64(&m.bytesWritten),
+		buckets:      m.buckets,
+		counts:       append([]uint64(nil), m.counts...),
+		sum:          m.sum,
+		calls:        m.calls,
+		errors:       make(map[string]uint64, len(m.errors)),
+	}
+	for k, v := range m.errors {
+		s.errors[k] = v
+	}
+	return s
+}
+
+// labels formats labels of this snapshot with extra label pairs.
+func (s snapshot) labels(extra ...string) string {
+	var b strings.Builder
+	b.WriteString(`{service="`)
+	b.WriteString(escapeLabel(s.service))
+	b.WriteString(`",operation="`)
+	b.WriteString(escapeLabel(s.op))
+	b.WriteString(`"`)
+	for i := 0; i+1 < len(extra); i += 2 {
+		b.WriteString(`,` + extra[i] + `="`)
+		b.WriteString(escapeLabel(extra[i+1]))
+		b.WriteString(`"`)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelReplacer.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (w *countWriter) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(w.w, format, args...)
+	w.n += int64(n)
+	w.err = err
+}
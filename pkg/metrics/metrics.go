@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.beyondstorage.io/v5/pkg/iowrap"
+	"go.beyondstorage.io/v5/pkg/middleware"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// DefaultBuckets is the default latency histogram buckets in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// Options is the metrics supported options.
+type Options struct {
+	// Namespace will be used as the prefix of all metric names, "storage" by default.
+	Namespace string
+	// Buckets is the upper bounds of latency histogram buckets in seconds.
+	Buckets []float64
+}
+
+// Collector records operation metrics of Storagers.
+//
+// A Collector could be shared by multiple Storagers, their metrics will be
+// distinguished by the service label which comes from Storager.String().
+type Collector struct {
+	namespace string
+	buckets   []float64
+
+	ops map[opKey]*opMetrics
+	mu  sync.RWMutex
+}
+
+// NewCollector will create a new Collector.
+func NewCollector(o *Options) *Collector {
+	c := &Collector{
+		namespace: "storage",
+		buckets:   DefaultBuckets,
+		ops:       make(map[opKey]*opMetrics),
+	}
+	if o == nil {
+		return c
+	}
+	if o.Namespace != "" {
+		c.namespace = o.Namespace
+	}
+	if len(o.Buckets) > 0 {
+		c.buckets = o.Buckets
+	}
+	return c
+}
+
+// Wrap will wrap a Storager so that all its operations will be recorded.
+func (c *Collector) Wrap(store types.Storager) types.Storager {
+	return middleware.Wrap(store, c.Middleware(store.String()))
+}
+
+// Middleware returns a middleware which records operations with service as label.
+func (c *Collector) Middleware(service string) middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, call middleware.Call) {
+			m := c.get(service, call.Op())
+
+			switch v := call.(type) {
+			case *middleware.ReadCall:
+				v.W = iowrap.CallbackWriter(v.W, m.addRead)
+			case *middleware.WriteCall:
+				v.R = callbackReader(v.R, m.addWritten)
+			case *middleware.WriteAppendCall:
+				v.R = callbackReader(v.R, m.addWritten)
+			case *middleware.WriteBlockCall:
+				v.R = callbackReader(v.R, m.addWritten)
+			case *middleware.WriteMultipartCall:
+				v.R = callbackReader(v.R, m.addWritten)
+			case *middleware.WritePageCall:
+				v.R = callbackReader(v.R, m.addWritten)
+			}
+
+			start := time.Now()
+			next(ctx, call)
+			m.observe(time.Since(start), call.Result())
+		}
+	}
+}
+
+func (c *Collector) get(service, op string) *opMetrics {
+	k := opKey{service: service, op: op}
+
+	c.mu.RLock()
+	m, ok := c.ops[k]
+	c.mu.RUnlock()
+	if ok {
+		return m
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if m, ok = c.ops[k]; ok {
+		return m
+	}
+	m = &opMetrics{
+		buckets: c.buckets,
+		counts:  make([]uint64, len(c.buckets)),
+		errors:  make(map[string]uint64),
+	}
+	c.ops[k] = m
+	return m
+}
+
+type opKey struct {
+	service string
+	op      string
+}
+
+type opMetrics struct {
+	// Bytes are updated in io callbacks, use atomic to keep them cheap.
+	bytesRead    int64
+	bytesWritten int64
+
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	calls   uint64
+	errors  map[string]uint64
+	mu      sync.Mutex
+}
+
+func (m *opMetrics) addRead(b []byte) {
+	atomic.AddInt64(&m.bytesRead, int64(len(b)))
+}
+
+func (m *opMetrics) addWritten(b []byte) {
+	atomic.AddInt64(&m.bytesWritten, int64(len(b)))
+}
+
+func (m *opMetrics) observe(d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := d.Seconds()
+	m.calls++
+	m.sum += s
+	for k, v := range m.buckets {
+		if s <= v {
+			m.counts[k]++
+		}
+	}
+	if err != nil {
+		m.errors[ErrorCode(err)]++
+	}
+}
+
+// callbackReader keeps nil reader as nil, so that services could handle it.
+func callbackReader(r io.Reader, fn func([]byte)) io.Reader {
+	if r == nil {
+		return nil
+	}
+	return iowrap.CallbackReader(r, fn)
+}
+
+var errorCodes = []error{
+	services.ErrUnexpected,
+	services.ErrCapabilityInsufficient,
+	services.ErrRestrictionDissatisfied,
+	services.ErrObjectNotExist,
+	services.ErrObjectModeInvalid,
+	services.ErrPermissionDenied,
+	services.ErrListModeInvalid,
+	services.ErrServiceNotRegistered,
+	services.ErrServiceInternal,
+	services.ErrRequestThrottled,
+	services.ErrChecksumMismatch,
+	services.ErrPreconditionFailed,
+	services.ErrObjectArchived,
+	types.ErrNotImplemented,
+}
+
+// ErrorCode returns the label value for err.
+//
+// Errors defined in services will be converted to snake case like "object_not_exist",
+// and all other errors will be "unknown".
+func ErrorCode(err error) string {
+	for _, v := range errorCodes {
+		if errors.Is(err, v) {
+			return strings.ReplaceAll(v.Error(), " ", "_")
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+type fakeStorager struct {
+	types.UnimplementedStorager
+}
+
+func (s *fakeStorager) String() string {
+	return "fake"
+}
+
+func (s *fakeStorager) ReadWithContext(ctx context.Context, path string, w io.Writer, pairs ...types.Pair) (n int64, err error) {
+	written, err := w.Write([]byte("hello"))
+	return int64(written), err
+}
+
+func (s *fakeStorager) WriteWithContext(ctx context.Context, path string, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	return io.Copy(ioutil.Discard, r)
+}
+
+func (s *fakeStorager) StatWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return nil, services.StorageError{Op: "stat", Err: services.ErrObjectNotExist, Storager: s}
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector(&Options{Buckets: []float64{1}})
+	store := c.Wrap(&fakeStorager{})
+
+	var buf bytes.Buffer
+	_, err := store.Read("a", &buf)
+	assert.Nil(t, err)
+	_, err = store.Write("a", strings.NewReader("hello world"), 11)
+	assert.Nil(t, err)
+	_, err = store.Stat("a")
+	assert.NotNil(t, err)
+	_, err = store.Stat("a")
+	assert.NotNil(t, err)
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+
+	body := rec.Body.String()
+	for _, v := range []string{
+		`# TYPE storage_operations_total counter`,
+		`storage_operations_total{service="fake",operation="read"} 1`,
+		`storage_operations_total{service="fake",operation="stat"} 2`,
+		`storage_operation_errors_total{service="fake",operation="stat",code="object_not_exist"} 2`,
+		`# TYPE storage_operation_duration_seconds histogram`,
+		`storage_operation_duration_seconds_bucket{service="fake",operation="read",le="1"} 1`,
+		`storage_operation_duration_seconds_bucket{service="fake",operation="read",le="+Inf"} 1`,
+		`storage_operation_duration_seconds_count{service="fake",operation="write"} 1`,
+		`storage_read_bytes_total{service="fake",operation="read"} 5`,
+		`storage_written_bytes_total{service="fake",operation="write"} 11`,
+	} {
+		assert.Contains(t, body, v+"\n")
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, "request_throttled", ErrorCode(services.ErrRequestThrottled))
+	assert.Equal(t, "not_implemented", ErrorCode(types.NewOperationNotImplementedError("read")))
+	assert.Equal(t, "unknown", ErrorCode(io.EOF))
+}
+
+func TestEscapeLabel(t *testing.T) {
+	assert.Equal(t, `a\"b\\c\nd`, escapeLabel("a\"b\\c\nd"))
+}
@@ -0,0 +1,13 @@
+/*
+Package middleware provides a way to decorate any Storager with composable middlewares.
+
+Every Storager operation will be converted into a Call which carries all
+arguments and results of this operation, and passed through the middleware
+chain before reaching the underlying Storager:
+
+	store = middleware.Wrap(store, logging, metrics)
+
+A middleware could inspect or modify the Call, or decide not to call next
+at all and fill the results by itself.
+*/
+package middleware
@@ -0,0 +1,1351 @@
+// Code generated by go generate cmd/definitions; DO NOT EDIT.
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"go.beyondstorage.io/v5/types"
+)
+
+var (
+	_ io.Reader
+	_ http.Request
+	_ time.Duration
+)
+
+// CombineBlockCall carries the arguments and results of Storager.CombineBlock.
+type CombineBlockCall struct {
+	O     *types.Object
+	Bids  []string
+	Pairs []types.Pair
+
+	Err error
+}
+
+func (c *CombineBlockCall) Op() string {
+	return "combine_block"
+}
+func (c *CombineBlockCall) Paths() []string {
+	ps := make([]string, 0)
+	if c.O != nil {
+		ps = append(ps, c.O.Path)
+	}
+	return ps
+}
+func (c *CombineBlockCall) Result() error {
+	return c.Err
+}
+func (c *CombineBlockCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *CombineBlockCall) invoke(ctx context.Context, store types.Storager) {
+	c.Err = store.CombineBlockWithContext(ctx, c.O, c.Bids, c.Pairs...)
+}
+
+// CommitAppendCall carries the arguments and results of Storager.CommitAppend.
+type CommitAppendCall struct {
+	O     *types.Object
+	Pairs []types.Pair
+
+	Err error
+}
+
+func (c *CommitAppendCall) Op() string {
+	return "commit_append"
+}
+func (c *CommitAppendCall) Paths() []string {
+	ps := make([]string, 0)
+	if c.O != nil {
+		ps = append(ps, c.O.Path)
+	}
+	return ps
+}
+func (c *CommitAppendCall) Result() error {
+	return c.Err
+}
+func (c *CommitAppendCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *CommitAppendCall) invoke(ctx context.Context, store types.Storager) {
+	c.Err = store.CommitAppendWithContext(ctx, c.O, c.Pairs...)
+}
+
+// CompleteMultipartCall carries the arguments and results of Storager.CompleteMultipart.
+type CompleteMultipartCall struct {
+	O     *types.Object
+	Parts []*types.Part
+	Pairs []types.Pair
+
+	Err error
+}
+
+func (c *CompleteMultipartCall) Op() string {
+	return "complete_multipart"
+}
+func (c *CompleteMultipartCall) Paths() []string {
+	ps := make([]string, 0)
+	if c.O != nil {
+		ps = append(ps, c.O.Path)
+	}
+	return ps
+}
+func (c *CompleteMultipartCall) Result() error {
+	return c.Err
+}
+func (c *CompleteMultipartCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *CompleteMultipartCall) invoke(ctx context.Context, store types.Storager) {
+	c.Err = store.CompleteMultipartWithContext(ctx, c.O, c.Parts, c.Pairs...)
+}
+
+// CopyCall carries the arguments and results of Storager.Copy.
+type CopyCall struct {
+	Src   string
+	Dst   string
+	Pairs []types.Pair
+
+	Err error
+}
+
+func (c *CopyCall) Op() string {
+	return "copy"
+}
+func (c *CopyCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Src)
+	ps = append(ps, c.Dst)
+	return ps
+}
+func (c *CopyCall) Result() error {
+	return c.Err
+}
+func (c *CopyCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *CopyCall) invoke(ctx context.Context, store types.Storager) {
+	c.Err = store.CopyWithContext(ctx, c.Src, c.Dst, c.Pairs...)
+}
+
+// CreateCall carries the arguments and results of Storager.Create.
+type CreateCall struct {
+	Path  string
+	Pairs []types.Pair
+
+	O   *types.Object
+	Err error
+}
+
+func (c *CreateCall) Op() string {
+	return "create"
+}
+func (c *CreateCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *CreateCall) Result() error {
+	return c.Err
+}
+func (c *CreateCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *CreateCall) invoke(ctx context.Context, store types.Storager) {
+	c.O = store.Create(c.Path, c.Pairs...)
+}
+
+// CreateAppendCall carries the arguments and results of Storager.CreateAppend.
+type CreateAppendCall struct {
+	Path  string
+	Pairs []types.Pair
+
+	O   *types.Object
+	Err error
+}
+
+func (c *CreateAppendCall) Op() string {
+	return "create_append"
+}
+func (c *CreateAppendCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *CreateAppendCall) Result() error {
+	return c.Err
+}
+func (c *CreateAppendCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *CreateAppendCall) invoke(ctx context.Context, store types.Storager) {
+	c.O, c.Err = store.CreateAppendWithContext(ctx, c.Path, c.Pairs...)
+}
+
+// CreateBlockCall carries the arguments and results of Storager.CreateBlock.
+type CreateBlockCall struct {
+	Path  string
+	Pairs []types.Pair
+
+	O   *types.Object
+	Err error
+}
+
+func (c *CreateBlockCall) Op() string {
+	return "create_block"
+}
+func (c *CreateBlockCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *CreateBlockCall) Result() error {
+	return c.Err
+}
+func (c *CreateBlockCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *CreateBlockCall) invoke(ctx context.Context, store types.Storager) {
+	c.O, c.Err = store.CreateBlockWithContext(ctx, c.Path, c.Pairs...)
+}
+
+// CreateDirCall carries the arguments and results of Storager.CreateDir.
+type CreateDirCall struct {
+	Path  string
+	Pairs []types.Pair
+
+	O   *types.Object
+	Err error
+}
+
+func (c *CreateDirCall) Op() string {
+	return "create_dir"
+}
+func (c *CreateDirCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *CreateDirCall) Result() error {
+	return c.Err
+}
+func (c *CreateDirCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *CreateDirCall) invoke(ctx context.Context, store types.Storager) {
+	c.O, c.Err = store.CreateDirWithContext(ctx, c.Path, c.Pairs...)
+}
+
+// CreateLinkCall carries the arguments and results of Storager.CreateLink.
+type CreateLinkCall struct {
+	Path   string
+	Target string
+	Pairs  []types.Pair
+
+	O   *types.Object
+	Err error
+}
+
+func (c *CreateLinkCall) Op() string {
+	return "create_link"
+}
+func (c *CreateLinkCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	ps = append(ps, c.Target)
+	return ps
+}
+func (c *CreateLinkCall) Result() error {
+	return c.Err
+}
+func (c *CreateLinkCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *CreateLinkCall) invoke(ctx context.Context, store types.Storager) {
+	c.O, c.Err = store.CreateLinkWithContext(ctx, c.Path, c.Target, c.Pairs...)
+}
+
+// CreateMultipartCall carries the arguments and results of Storager.CreateMultipart.
+type CreateMultipartCall struct {
+	Path  string
+	Pairs []types.Pair
+
+	O   *types.Object
+	Err error
+}
+
+func (c *CreateMultipartCall) Op() string {
+	return "create_multipart"
+}
+func (c *CreateMultipartCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *CreateMultipartCall) Result() error {
+	return c.Err
+}
+func (c *CreateMultipartCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *CreateMultipartCall) invoke(ctx context.Context, store types.Storager) {
+	c.O, c.Err = store.CreateMultipartWithContext(ctx, c.Path, c.Pairs...)
+}
+
+// CreatePageCall carries the arguments and results of Storager.CreatePage.
+type CreatePageCall struct {
+	Path  string
+	Pairs []types.Pair
+
+	O   *types.Object
+	Err error
+}
+
+func (c *CreatePageCall) Op() string {
+	return "create_page"
+}
+func (c *CreatePageCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *CreatePageCall) Result() error {
+	return c.Err
+}
+func (c *CreatePageCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *CreatePageCall) invoke(ctx context.Context, store types.Storager) {
+	c.O, c.Err = store.CreatePageWithContext(ctx, c.Path, c.Pairs...)
+}
+
+// DeleteCall carries the arguments and results of Storager.Delete.
+type DeleteCall struct {
+	Path  string
+	Pairs []types.Pair
+
+	Err error
+}
+
+func (c *DeleteCall) Op() string {
+	return "delete"
+}
+func (c *DeleteCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *DeleteCall) Result() error {
+	return c.Err
+}
+func (c *DeleteCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *DeleteCall) invoke(ctx context.Context, store types.Storager) {
+	c.Err = store.DeleteWithContext(ctx, c.Path, c.Pairs...)
+}
+
+// FetchCall carries the arguments and results of Storager.Fetch.
+type FetchCall struct {
+	Path  string
+	URL   string
+	Pairs []types.Pair
+
+	Err error
+}
+
+func (c *FetchCall) Op() string {
+	return "fetch"
+}
+func (c *FetchCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *FetchCall) Result() error {
+	return c.Err
+}
+func (c *FetchCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *FetchCall) invoke(ctx context.Context, store types.Storager) {
+	c.Err = store.FetchWithContext(ctx, c.Path, c.URL, c.Pairs...)
+}
+
+// GetLifecycleCall carries the arguments and results of Storager.GetLifecycle.
+type GetLifecycleCall struct {
+	Pairs []types.Pair
+
+	Rules []*types.LifecycleRule
+	Err   error
+}
+
+func (c *GetLifecycleCall) Op() string {
+	return "get_lifecycle"
+}
+func (c *GetLifecycleCall) Paths() []string {
+	ps := make([]string, 0)
+	return ps
+}
+func (c *GetLifecycleCall) Result() error {
+	return c.Err
+}
+func (c *GetLifecycleCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *GetLifecycleCall) invoke(ctx context.Context, store types.Storager) {
+	c.Rules, c.Err = store.GetLifecycleWithContext(ctx, c.Pairs...)
+}
+
+// ListCall carries the arguments and results of Storager.List.
+type ListCall struct {
+	Path  string
+	Pairs []types.Pair
+
+	Oi  *types.ObjectIterator
+	Err error
+}
+
+func (c *ListCall) Op() string {
+	return "list"
+}
+func (c *ListCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *ListCall) Result() error {
+	return c.Err
+}
+func (c *ListCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *ListCall) invoke(ctx context.Context, store types.Storager) {
+	c.Oi, c.Err = store.ListWithContext(ctx, c.Path, c.Pairs...)
+}
+
+// ListBlockCall carries the arguments and results of Storager.ListBlock.
+type ListBlockCall struct {
+	O     *types.Object
+	Pairs []types.Pair
+
+	Bi  *types.BlockIterator
+	Err error
+}
+
+func (c *ListBlockCall) Op() string {
+	return "list_block"
+}
+func (c *ListBlockCall) Paths() []string {
+	ps := make([]string, 0)
+	if c.O != nil {
+		ps = append(ps, c.O.Path)
+	}
+	return ps
+}
+func (c *ListBlockCall) Result() error {
+	return c.Err
+}
+func (c *ListBlockCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *ListBlockCall) invoke(ctx context.Context, store types.Storager) {
+	c.Bi, c.Err = store.ListBlockWithContext(ctx, c.O, c.Pairs...)
+}
+
+// ListMultipartCall carries the arguments and results of Storager.ListMultipart.
+type ListMultipartCall struct {
+	O     *types.Object
+	Pairs []types.Pair
+
+	Pi  *types.PartIterator
+	Err error
+}
+
+func (c *ListMultipartCall) Op() string {
+	return "list_multipart"
+}
+func (c *ListMultipartCall) Paths() []string {
+	ps := make([]string, 0)
+	if c.O != nil {
+		ps = append(ps, c.O.Path)
+	}
+	return ps
+}
+func (c *ListMultipartCall) Result() error {
+	return c.Err
+}
+func (c *ListMultipartCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *ListMultipartCall) invoke(ctx context.Context, store types.Storager) {
+	c.Pi, c.Err = store.ListMultipartWithContext(ctx, c.O, c.Pairs...)
+}
+
+// ListVersionCall carries the arguments and results of Storager.ListVersion.
+type ListVersionCall struct {
+	Path  string
+	Pairs []types.Pair
+
+	Oi  *types.ObjectIterator
+	Err error
+}
+
+func (c *ListVersionCall) Op() string {
+	return "list_version"
+}
+func (c *ListVersionCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *ListVersionCall) Result() error {
+	return c.Err
+}
+func (c *ListVersionCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *ListVersionCall) invoke(ctx context.Context, store types.Storager) {
+	c.Oi, c.Err = store.ListVersionWithContext(ctx, c.Path, c.Pairs...)
+}
+
+// MetadataCall carries the arguments and results of Storager.Metadata.
+type MetadataCall struct {
+	Pairs []types.Pair
+
+	Meta *types.StorageMeta
+	Err  error
+}
+
+func (c *MetadataCall) Op() string {
+	return "metadata"
+}
+func (c *MetadataCall) Paths() []string {
+	ps := make([]string, 0)
+	return ps
+}
+func (c *MetadataCall) Result() error {
+	return c.Err
+}
+func (c *MetadataCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *MetadataCall) invoke(ctx context.Context, store types.Storager) {
+	c.Meta = store.Metadata(c.Pairs...)
+}
+
+// MoveCall carries the arguments and results of Storager.Move.
+type MoveCall struct {
+	Src   string
+	Dst   string
+	Pairs []types.Pair
+
+	Err error
+}
+
+func (c *MoveCall) Op() string {
+	return "move"
+}
+func (c *MoveCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Src)
+	ps = append(ps, c.Dst)
+	return ps
+}
+func (c *MoveCall) Result() error {
+	return c.Err
+}
+func (c *MoveCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *MoveCall) invoke(ctx context.Context, store types.Storager) {
+	c.Err = store.MoveWithContext(ctx, c.Src, c.Dst, c.Pairs...)
+}
+
+// QuerySignHTTPCompleteMultipartCall carries the arguments and results of Storager.QuerySignHTTPCompleteMultipart.
+type QuerySignHTTPCompleteMultipartCall struct {
+	O      *types.Object
+	Parts  []*types.Part
+	Expire time.Duration
+	Pairs  []types.Pair
+
+	Req *http.Request
+	Err error
+}
+
+func (c *QuerySignHTTPCompleteMultipartCall) Op() string {
+	return "query_sign_http_complete_multipart"
+}
+func (c *QuerySignHTTPCompleteMultipartCall) Paths() []string {
+	ps := make([]string, 0)
+	if c.O != nil {
+		ps = append(ps, c.O.Path)
+	}
+	return ps
+}
+func (c *QuerySignHTTPCompleteMultipartCall) Result() error {
+	return c.Err
+}
+func (c *QuerySignHTTPCompleteMultipartCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *QuerySignHTTPCompleteMultipartCall) invoke(ctx context.Context, store types.Storager) {
+	c.Req, c.Err = store.QuerySignHTTPCompleteMultipartWithContext(ctx, c.O, c.Parts, c.Expire, c.Pairs...)
+}
+
+// QuerySignHTTPCreateMultipartCall carries the arguments and results of Storager.QuerySignHTTPCreateMultipart.
+type QuerySignHTTPCreateMultipartCall struct {
+	Path   string
+	Expire time.Duration
+	Pairs  []types.Pair
+
+	Req *http.Request
+	Err error
+}
+
+func (c *QuerySignHTTPCreateMultipartCall) Op() string {
+	return "query_sign_http_create_multipart"
+}
+func (c *QuerySignHTTPCreateMultipartCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *QuerySignHTTPCreateMultipartCall) Result() error {
+	return c.Err
+}
+func (c *QuerySignHTTPCreateMultipartCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *QuerySignHTTPCreateMultipartCall) invoke(ctx context.Context, store types.Storager) {
+	c.Req, c.Err = store.QuerySignHTTPCreateMultipartWithContext(ctx, c.Path, c.Expire, c.Pairs...)
+}
+
+// QuerySignHTTPDeleteCall carries the arguments and results of Storager.QuerySignHTTPDelete.
+type QuerySignHTTPDeleteCall struct {
+	Path   string
+	Expire time.Duration
+	Pairs  []types.Pair
+
+	Req *http.Request
+	Err error
+}
+
+func (c *QuerySignHTTPDeleteCall) Op() string {
+	return "query_sign_http_delete"
+}
+func (c *QuerySignHTTPDeleteCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *QuerySignHTTPDeleteCall) Result() error {
+	return c.Err
+}
+func (c *QuerySignHTTPDeleteCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *QuerySignHTTPDeleteCall) invoke(ctx context.Context, store types.Storager) {
+	c.Req, c.Err = store.QuerySignHTTPDeleteWithContext(ctx, c.Path, c.Expire, c.Pairs...)
+}
+
+// QuerySignHTTPListMultipartCall carries the arguments and results of Storager.QuerySignHTTPListMultipart.
+type QuerySignHTTPListMultipartCall struct {
+	O      *types.Object
+	Expire time.Duration
+	Pairs  []types.Pair
+
+	Req *http.Request
+	Err error
+}
+
+func (c *QuerySignHTTPListMultipartCall) Op() string {
+	return "query_sign_http_list_multipart"
+}
+func (c *QuerySignHTTPListMultipartCall) Paths() []string {
+	ps := make([]string, 0)
+	if c.O != nil {
+		ps = append(ps, c.O.Path)
+	}
+	return ps
+}
+func (c *QuerySignHTTPListMultipartCall) Result() error {
+	return c.Err
+}
+func (c *QuerySignHTTPListMultipartCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *QuerySignHTTPListMultipartCall) invoke(ctx context.Context, store types.Storager) {
+	c.Req, c.Err = store.QuerySignHTTPListMultipartWithContext(ctx, c.O, c.Expire, c.Pairs...)
+}
+
+// QuerySignHTTPReadCall carries the arguments and results of Storager.QuerySignHTTPRead.
+type QuerySignHTTPReadCall struct {
+	Path   string
+	Expire time.Duration
+	Pairs  []types.Pair
+
+	Req *http.Request
+	Err error
+}
+
+func (c *QuerySignHTTPReadCall) Op() string {
+	return "query_sign_http_read"
+}
+func (c *QuerySignHTTPReadCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *QuerySignHTTPReadCall) Result() error {
+	return c.Err
+}
+func (c *QuerySignHTTPReadCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *QuerySignHTTPReadCall) invoke(ctx context.Context, store types.Storager) {
+	c.Req, c.Err = store.QuerySignHTTPReadWithContext(ctx, c.Path, c.Expire, c.Pairs...)
+}
+
+// QuerySignHTTPWriteCall carries the arguments and results of Storager.QuerySignHTTPWrite.
+type QuerySignHTTPWriteCall struct {
+	Path   string
+	Size   int64
+	Expire time.Duration
+	Pairs  []types.Pair
+
+	Req *http.Request
+	Err error
+}
+
+func (c *QuerySignHTTPWriteCall) Op() string {
+	return "query_sign_http_write"
+}
+func (c *QuerySignHTTPWriteCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *QuerySignHTTPWriteCall) Result() error {
+	return c.Err
+}
+func (c *QuerySignHTTPWriteCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *QuerySignHTTPWriteCall) invoke(ctx context.Context, store types.Storager) {
+	c.Req, c.Err = store.QuerySignHTTPWriteWithContext(ctx, c.Path, c.Size, c.Expire, c.Pairs...)
+}
+
+// QuerySignHTTPWriteMultipartCall carries the arguments and results of Storager.QuerySignHTTPWriteMultipart.
+type QuerySignHTTPWriteMultipartCall struct {
+	O      *types.Object
+	Size   int64
+	Index  int
+	Expire time.Duration
+	Pairs  []types.Pair
+
+	Req *http.Request
+	Err error
+}
+
+func (c *QuerySignHTTPWriteMultipartCall) Op() string {
+	return "query_sign_http_write_multipart"
+}
+func (c *QuerySignHTTPWriteMultipartCall) Paths() []string {
+	ps := make([]string, 0)
+	if c.O != nil {
+		ps = append(ps, c.O.Path)
+	}
+	return ps
+}
+func (c *QuerySignHTTPWriteMultipartCall) Result() error {
+	return c.Err
+}
+func (c *QuerySignHTTPWriteMultipartCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *QuerySignHTTPWriteMultipartCall) invoke(ctx context.Context, store types.Storager) {
+	c.Req, c.Err = store.QuerySignHTTPWriteMultipartWithContext(ctx, c.O, c.Size, c.Index, c.Expire, c.Pairs...)
+}
+
+// ReadCall carries the arguments and results of Storager.Read.
+type ReadCall struct {
+	Path  string
+	W     io.Writer
+	Pairs []types.Pair
+
+	N   int64
+	Err error
+}
+
+func (c *ReadCall) Op() string {
+	return "read"
+}
+func (c *ReadCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *ReadCall) Result() error {
+	return c.Err
+}
+func (c *ReadCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *ReadCall) invoke(ctx context.Context, store types.Storager) {
+	c.N, c.Err = store.ReadWithContext(ctx, c.Path, c.W, c.Pairs...)
+}
+
+// RestoreCall carries the arguments and results of Storager.Restore.
+type RestoreCall struct {
+	Path  string
+	Pairs []types.Pair
+
+	Err error
+}
+
+func (c *RestoreCall) Op() string {
+	return "restore"
+}
+func (c *RestoreCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *RestoreCall) Result() error {
+	return c.Err
+}
+func (c *RestoreCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *RestoreCall) invoke(ctx context.Context, store types.Storager) {
+	c.Err = store.RestoreWithContext(ctx, c.Path, c.Pairs...)
+}
+
+// SetLifecycleCall carries the arguments and results of Storager.SetLifecycle.
+type SetLifecycleCall struct {
+	Rules []*types.LifecycleRule
+	Pairs []types.Pair
+
+	Err error
+}
+
+func (c *SetLifecycleCall) Op() string {
+	return "set_lifecycle"
+}
+func (c *SetLifecycleCall) Paths() []string {
+	ps := make([]string, 0)
+	return ps
+}
+func (c *SetLifecycleCall) Result() error {
+	return c.Err
+}
+func (c *SetLifecycleCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *SetLifecycleCall) invoke(ctx context.Context, store types.Storager) {
+	c.Err = store.SetLifecycleWithContext(ctx, c.Rules, c.Pairs...)
+}
+
+// SetStorageClassCall carries the arguments and results of Storager.SetStorageClass.
+type SetStorageClassCall struct {
+	Path  string
+	Class string
+	Pairs []types.Pair
+
+	Err error
+}
+
+func (c *SetStorageClassCall) Op() string {
+	return "set_storage_class"
+}
+func (c *SetStorageClassCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *SetStorageClassCall) Result() error {
+	return c.Err
+}
+func (c *SetStorageClassCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *SetStorageClassCall) invoke(ctx context.Context, store types.Storager) {
+	c.Err = store.SetStorageClassWithContext(ctx, c.Path, c.Class, c.Pairs...)
+}
+
+// StatCall carries the arguments and results of Storager.Stat.
+type StatCall struct {
+	Path  string
+	Pairs []types.Pair
+
+	O   *types.Object
+	Err error
+}
+
+func (c *StatCall) Op() string {
+	return "stat"
+}
+func (c *StatCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *StatCall) Result() error {
+	return c.Err
+}
+func (c *StatCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *StatCall) invoke(ctx context.Context, store types.Storager) {
+	c.O, c.Err = store.StatWithContext(ctx, c.Path, c.Pairs...)
+}
+
+// WriteCall carries the arguments and results of Storager.Write.
+type WriteCall struct {
+	Path  string
+	R     io.Reader
+	Size  int64
+	Pairs []types.Pair
+
+	N   int64
+	Err error
+}
+
+func (c *WriteCall) Op() string {
+	return "write"
+}
+func (c *WriteCall) Paths() []string {
+	ps := make([]string, 0)
+	ps = append(ps, c.Path)
+	return ps
+}
+func (c *WriteCall) Result() error {
+	return c.Err
+}
+func (c *WriteCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *WriteCall) invoke(ctx context.Context, store types.Storager) {
+	c.N, c.Err = store.WriteWithContext(ctx, c.Path, c.R, c.Size, c.Pairs...)
+}
+
+// WriteAppendCall carries the arguments and results of Storager.WriteAppend.
+type WriteAppendCall struct {
+	O     *types.Object
+	R     io.Reader
+	Size  int64
+	Pairs []types.Pair
+
+	N   int64
+	Err error
+}
+
+func (c *WriteAppendCall) Op() string {
+	return "write_append"
+}
+func (c *WriteAppendCall) Paths() []string {
+	ps := make([]string, 0)
+	if c.O != nil {
+		ps = append(ps, c.O.Path)
+	}
+	return ps
+}
+func (c *WriteAppendCall) Result() error {
+	return c.Err
+}
+func (c *WriteAppendCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *WriteAppendCall) invoke(ctx context.Context, store types.Storager) {
+	c.N, c.Err = store.WriteAppendWithContext(ctx, c.O, c.R, c.Size, c.Pairs...)
+}
+
+// WriteBlockCall carries the arguments and results of Storager.WriteBlock.
+type WriteBlockCall struct {
+	O     *types.Object
+	R     io.Reader
+	Size  int64
+	Bid   string
+	Pairs []types.Pair
+
+	N   int64
+	Err error
+}
+
+func (c *WriteBlockCall) Op() string {
+	return "write_block"
+}
+func (c *WriteBlockCall) Paths() []string {
+	ps := make([]string, 0)
+	if c.O != nil {
+		ps = append(ps, c.O.Path)
+	}
+	return ps
+}
+func (c *WriteBlockCall) Result() error {
+	return c.Err
+}
+func (c *WriteBlockCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *WriteBlockCall) invoke(ctx context.Context, store types.Storager) {
+	c.N, c.Err = store.WriteBlockWithContext(ctx, c.O, c.R, c.Size, c.Bid, c.Pairs...)
+}
+
+// WriteMultipartCall carries the arguments and results of Storager.WriteMultipart.
+type WriteMultipartCall struct {
+	O     *types.Object
+	R     io.Reader
+	Size  int64
+	Index int
+	Pairs []types.Pair
+
+	N    int64
+	Part *types.Part
+	Err  error
+}
+
+func (c *WriteMultipartCall) Op() string {
+	return "write_multipart"
+}
+func (c *WriteMultipartCall) Paths() []string {
+	ps := make([]string, 0)
+	if c.O != nil {
+		ps = append(ps, c.O.Path)
+	}
+	return ps
+}
+func (c *WriteMultipartCall) Result() error {
+	return c.Err
+}
+func (c *WriteMultipartCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *WriteMultipartCall) invoke(ctx context.Context, store types.Storager) {
+	c.N, c.Part, c.Err = store.WriteMultipartWithContext(ctx, c.O, c.R, c.Size, c.Index, c.Pairs...)
+}
+
+// WritePageCall carries the arguments and results of Storager.WritePage.
+type WritePageCall struct {
+	O      *types.Object
+	R      io.Reader
+	Size   int64
+	Offset int64
+	Pairs  []types.Pair
+
+	N   int64
+	Err error
+}
+
+func (c *WritePageCall) Op() string {
+	return "write_page"
+}
+func (c *WritePageCall) Paths() []string {
+	ps := make([]string, 0)
+	if c.O != nil {
+		ps = append(ps, c.O.Path)
+	}
+	return ps
+}
+func (c *WritePageCall) Result() error {
+	return c.Err
+}
+func (c *WritePageCall) SetResult(err error) {
+	c.Err = err
+}
+func (c *WritePageCall) invoke(ctx context.Context, store types.Storager) {
+	c.N, c.Err = store.WritePageWithContext(ctx, c.O, c.R, c.Size, c.Offset, c.Pairs...)
+}
+func (s *storager) CombineBlock(o *types.Object, bids []string, pairs ...types.Pair) (err error) {
+	return s.CombineBlockWithContext(context.Background(), o, bids, pairs...)
+}
+func (s *storager) CombineBlockWithContext(ctx context.Context, o *types.Object, bids []string, pairs ...types.Pair) (err error) {
+	c := &CombineBlockCall{O: o, Bids: bids, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Err
+}
+func (s *storager) CommitAppend(o *types.Object, pairs ...types.Pair) (err error) {
+	return s.CommitAppendWithContext(context.Background(), o, pairs...)
+}
+func (s *storager) CommitAppendWithContext(ctx context.Context, o *types.Object, pairs ...types.Pair) (err error) {
+	c := &CommitAppendCall{O: o, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Err
+}
+func (s *storager) CompleteMultipart(o *types.Object, parts []*types.Part, pairs ...types.Pair) (err error) {
+	return s.CompleteMultipartWithContext(context.Background(), o, parts, pairs...)
+}
+func (s *storager) CompleteMultipartWithContext(ctx context.Context, o *types.Object, parts []*types.Part, pairs ...types.Pair) (err error) {
+	c := &CompleteMultipartCall{O: o, Parts: parts, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Err
+}
+func (s *storager) Copy(src string, dst string, pairs ...types.Pair) (err error) {
+	return s.CopyWithContext(context.Background(), src, dst, pairs...)
+}
+func (s *storager) CopyWithContext(ctx context.Context, src string, dst string, pairs ...types.Pair) (err error) {
+	c := &CopyCall{Src: src, Dst: dst, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Err
+}
+func (s *storager) Create(path string, pairs ...types.Pair) (o *types.Object) {
+	c := &CreateCall{Path: path, Pairs: pairs}
+	s.handler(context.Background(), c)
+	return c.O
+}
+func (s *storager) CreateAppend(path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.CreateAppendWithContext(context.Background(), path, pairs...)
+}
+func (s *storager) CreateAppendWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	c := &CreateAppendCall{Path: path, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.O, c.Err
+}
+func (s *storager) CreateBlock(path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.CreateBlockWithContext(context.Background(), path, pairs...)
+}
+func (s *storager) CreateBlockWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	c := &CreateBlockCall{Path: path, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.O, c.Err
+}
+func (s *storager) CreateDir(path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.CreateDirWithContext(context.Background(), path, pairs...)
+}
+func (s *storager) CreateDirWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	c := &CreateDirCall{Path: path, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.O, c.Err
+}
+func (s *storager) CreateLink(path string, target string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.CreateLinkWithContext(context.Background(), path, target, pairs...)
+}
+func (s *storager) CreateLinkWithContext(ctx context.Context, path string, target string, pairs ...types.Pair) (o *types.Object, err error) {
+	c := &CreateLinkCall{Path: path, Target: target, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.O, c.Err
+}
+func (s *storager) CreateMultipart(path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.CreateMultipartWithContext(context.Background(), path, pairs...)
+}
+func (s *storager) CreateMultipartWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	c := &CreateMultipartCall{Path: path, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.O, c.Err
+}
+func (s *storager) CreatePage(path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.CreatePageWithContext(context.Background(), path, pairs...)
+}
+func (s *storager) CreatePageWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	c := &CreatePageCall{Path: path, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.O, c.Err
+}
+func (s *storager) Delete(path string, pairs ...types.Pair) (err error) {
+	return s.DeleteWithContext(context.Background(), path, pairs...)
+}
+func (s *storager) DeleteWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	c := &DeleteCall{Path: path, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Err
+}
+func (s *storager) Fetch(path string, url string, pairs ...types.Pair) (err error) {
+	return s.FetchWithContext(context.Background(), path, url, pairs...)
+}
+func (s *storager) FetchWithContext(ctx context.Context, path string, url string, pairs ...types.Pair) (err error) {
+	c := &FetchCall{Path: path, URL: url, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Err
+}
+func (s *storager) GetLifecycle(pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	return s.GetLifecycleWithContext(context.Background(), pairs...)
+}
+func (s *storager) GetLifecycleWithContext(ctx context.Context, pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	c := &GetLifecycleCall{Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Rules, c.Err
+}
+func (s *storager) List(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	return s.ListWithContext(context.Background(), path, pairs...)
+}
+func (s *storager) ListWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	c := &ListCall{Path: path, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Oi, c.Err
+}
+func (s *storager) ListBlock(o *types.Object, pairs ...types.Pair) (bi *types.BlockIterator, err error) {
+	return s.ListBlockWithContext(context.Background(), o, pairs...)
+}
+func (s *storager) ListBlockWithContext(ctx context.Context, o *types.Object, pairs ...types.Pair) (bi *types.BlockIterator, err error) {
+	c := &ListBlockCall{O: o, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Bi, c.Err
+}
+func (s *storager) ListMultipart(o *types.Object, pairs ...types.Pair) (pi *types.PartIterator, err error) {
+	return s.ListMultipartWithContext(context.Background(), o, pairs...)
+}
+func (s *storager) ListMultipartWithContext(ctx context.Context, o *types.Object, pairs ...types.Pair) (pi *types.PartIterator, err error) {
+	c := &ListMultipartCall{O: o, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Pi, c.Err
+}
+func (s *storager) ListVersion(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	return s.ListVersionWithContext(context.Background(), path, pairs...)
+}
+func (s *storager) ListVersionWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	c := &ListVersionCall{Path: path, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Oi, c.Err
+}
+func (s *storager) Metadata(pairs ...types.Pair) (meta *types.StorageMeta) {
+	c := &MetadataCall{Pairs: pairs}
+	s.handler(context.Background(), c)
+	return c.Meta
+}
+func (s *storager) Move(src string, dst string, pairs ...types.Pair) (err error) {
+	return s.MoveWithContext(context.Background(), src, dst, pairs...)
+}
+func (s *storager) MoveWithContext(ctx context.Context, src string, dst string, pairs ...types.Pair) (err error) {
+	c := &MoveCall{Src: src, Dst: dst, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Err
+}
+func (s *storager) QuerySignHTTPCompleteMultipart(o *types.Object, parts []*types.Part, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	return s.QuerySignHTTPCompleteMultipartWithContext(context.Background(), o, parts, expire, pairs...)
+}
+func (s *storager) QuerySignHTTPCompleteMultipartWithContext(ctx context.Context, o *types.Object, parts []*types.Part, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	c := &QuerySignHTTPCompleteMultipartCall{O: o, Parts: parts, Expire: expire, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Req, c.Err
+}
+func (s *storager) QuerySignHTTPCreateMultipart(path string, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	return s.QuerySignHTTPCreateMultipartWithContext(context.Background(), path, expire, pairs...)
+}
+func (s *storager) QuerySignHTTPCreateMultipartWithContext(ctx context.Context, path string, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	c := &QuerySignHTTPCreateMultipartCall{Path: path, Expire: expire, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Req, c.Err
+}
+func (s *storager) QuerySignHTTPDelete(path string, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	return s.QuerySignHTTPDeleteWithContext(context.Background(), path, expire, pairs...)
+}
+func (s *storager) QuerySignHTTPDeleteWithContext(ctx context.Context, path string, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	c := &QuerySignHTTPDeleteCall{Path: path, Expire: expire, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Req, c.Err
+}
+func (s *storager) QuerySignHTTPListMultipart(o *types.Object, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	return s.QuerySignHTTPListMultipartWithContext(context.Background(), o, expire, pairs...)
+}
+func (s *storager) QuerySignHTTPListMultipartWithContext(ctx context.Context, o *types.Object, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	c := &QuerySignHTTPListMultipartCall{O: o, Expire: expire, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Req, c.Err
+}
+func (s *storager) QuerySignHTTPRead(path string, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	return s.QuerySignHTTPReadWithContext(context.Background(), path, expire, pairs...)
+}
+func (s *storager) QuerySignHTTPReadWithContext(ctx context.Context, path string, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	c := &QuerySignHTTPReadCall{Path: path, Expire: expire, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Req, c.Err
+}
+func (s *storager) QuerySignHTTPWrite(path string, size int64, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	return s.QuerySignHTTPWriteWithContext(context.Background(), path, size, expire, pairs...)
+}
+func (s *storager) QuerySignHTTPWriteWithContext(ctx context.Context, path string, size int64, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	c := &QuerySignHTTPWriteCall{Path: path, Size: size, Expire: expire, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Req, c.Err
+}
+func (s *storager) QuerySignHTTPWriteMultipart(o *types.Object, size int64, index int, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	return s.QuerySignHTTPWriteMultipartWithContext(context.Background(), o, size, index, expire, pairs...)
+}
+func (s *storager) QuerySignHTTPWriteMultipartWithContext(ctx context.Context, o *types.Object, size int64, index int, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
+	c := &QuerySignHTTPWriteMultipartCall{O: o, Size: size, Index: index, Expire: expire, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Req, c.Err
+}
+func (s *storager) Read(path string, w io.Writer, pairs ...types.Pair) (n int64, err error) {
+	return s.ReadWithContext(context.Background(), path, w, pairs...)
+}
+func (s *storager) ReadWithContext(ctx context.Context, path string, w io.Writer, pairs ...types.Pair) (n int64, err error) {
+	c := &ReadCall{Path: path, W: w, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.N, c.Err
+}
+func (s *storager) Restore(path string, pairs ...types.Pair) (err error) {
+	return s.RestoreWithContext(context.Background(), path, pairs...)
+}
+func (s *storager) RestoreWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	c := &RestoreCall{Path: path, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Err
+}
+func (s *storager) SetLifecycle(rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	return s.SetLifecycleWithContext(context.Background(), rules, pairs...)
+}
+func (s *storager) SetLifecycleWithContext(ctx context.Context, rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	c := &SetLifecycleCall{Rules: rules, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Err
+}
+func (s *storager) SetStorageClass(path string, class string, pairs ...types.Pair) (err error) {
+	return s.SetStorageClassWithContext(context.Background(), path, class, pairs...)
+}
+func (s *storager) SetStorageClassWithContext(ctx context.Context, path string, class string, pairs ...types.Pair) (err error) {
+	c := &SetStorageClassCall{Path: path, Class: class, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.Err
+}
+func (s *storager) Stat(path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.StatWithContext(context.Background(), path, pairs...)
+}
+func (s *storager) StatWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	c := &StatCall{Path: path, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.O, c.Err
+}
+func (s *storager) Write(path string, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	return s.WriteWithContext(context.Background(), path, r, size, pairs...)
+}
+func (s *storager) WriteWithContext(ctx context.Context, path string, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	c := &WriteCall{Path: path, R: r, Size: size, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.N, c.Err
+}
+func (s *storager) WriteAppend(o *types.Object, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	return s.WriteAppendWithContext(context.Background(), o, r, size, pairs...)
+}
+func (s *storager) WriteAppendWithContext(ctx context.Context, o *types.Object, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	c := &WriteAppendCall{O: o, R: r, Size: size, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.N, c.Err
+}
+func (s *storager) WriteBlock(o *types.Object, r io.Reader, size int64, bid string, pairs ...types.Pair) (n int64, err error) {
+	return s.WriteBlockWithContext(context.Background(), o, r, size, bid, pairs...)
+}
+func (s *storager) WriteBlockWithContext(ctx context.Context, o *types.Object, r io.Reader, size int64, bid string, pairs ...types.Pair) (n int64, err error) {
+	c := &WriteBlockCall{O: o, R: r, Size: size, Bid: bid, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.N, c.Err
+}
+func (s *storager) WriteMultipart(o *types.Object, r io.Reader, size int64, index int, pairs ...types.Pair) (n int64, part *types.Part, err error) {
+	return s.WriteMultipartWithContext(context.Background(), o, r, size, index, pairs...)
+}
+func (s *storager) WriteMultipartWithContext(ctx context.Context, o *types.Object, r io.Reader, size int64, index int, pairs ...types.Pair) (n int64, part *types.Part, err error) {
+	c := &WriteMultipartCall{O: o, R: r, Size: size, Index: index, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.N, c.Part, c.Err
+}
+func (s *storager) WritePage(o *types.Object, r io.Reader, size int64, offset int64, pairs ...types.Pair) (n int64, err error) {
+	return s.WritePageWithContext(context.Background(), o, r, size, offset, pairs...)
+}
+func (s *storager) WritePageWithContext(ctx context.Context, o *types.Object, r io.Reader, size int64, offset int64, pairs ...types.Pair) (n int64, err error) {
+	c := &WritePageCall{O: o, R: r, Size: size, Offset: offset, Pairs: pairs}
+	s.handler(ctx, c)
+	return c.N, c.Err
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+
+	"go.beyondstorage.io/v5/types"
+)
+
+// Call is a single Storager operation invocation.
+//
+// The concrete type of a Call is always a pointer to the generated XxxCall
+// struct, for example *ReadCall. Middlewares could use type switch to access
+// the arguments and results of a specific operation.
+type Call interface {
+	// Op returns the operation name in snake case, for example "read".
+	Op() string
+	// Paths returns all paths this operation will touch.
+	Paths() []string
+	// Result returns the error returned by this operation.
+	Result() error
+	// SetResult sets the error returned by this operation.
+	//
+	// Middlewares which don't call next SHOULD set the result by themselves.
+	SetResult(err error)
+
+	invoke(ctx context.Context, store types.Storager)
+}
+
+// Handler handles a Call.
+//
+// Handler SHOULD fill the results of the Call instead of returning them.
+type Handler func(ctx context.Context, c Call)
+
+// Middleware decorates a Handler.
+type Middleware func(next Handler) Handler
+
+// Chain composes middlewares into a single one.
+//
+// The first middleware will be the outermost one.
+func Chain(mws ...Middleware) Middleware {
+	return func(next Handler) Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
+		return next
+	}
+}
+
+// FromInterceptor converts a types.Interceptor into a Middleware.
+func FromInterceptor(i types.Interceptor) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, c Call) {
+			fn := i(ctx, c.Op())
+			next(ctx, c)
+			fn(c.Result())
+		}
+	}
+}
+
+// Wrap will wrap a Storager with middlewares.
+//
+// All operations on the returning Storager will go through mws in order, and
+// then reach store.
+func Wrap(store types.Storager, mws ...Middleware) types.Storager {
+	return &storager{
+		store: store,
+		handler: Chain(mws...)(func(ctx context.Context, c Call) {
+			c.invoke(ctx, store)
+		}),
+	}
+}
+
+// Unwrap returns the underlying Storager if store is created by Wrap.
+func Unwrap(store types.Storager) types.Storager {
+	if s, ok := store.(*storager); ok {
+		return s.store
+	}
+	return store
+}
+
+type storager struct {
+	store   types.Storager
+	handler Handler
+
+	types.UnimplementedStorager
+}
+
+// String implements Storager.String
+func (s *storager) String() string {
+	return s.store.String()
+}
+
+// Features implements Storager.Features
+func (s *storager) Features() types.StorageFeatures {
+	return s.store.Features()
+}
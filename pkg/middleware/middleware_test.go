@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/types"
+)
+
+type fakeStorager struct {
+	types.UnimplementedStorager
+
+	content []byte
+}
+
+func (s *fakeStorager) String() string {
+	return "fake"
+}
+
+func (s *fakeStorager) ReadWithContext(ctx context.Context, path string, w io.Writer, pairs ...types.Pair) (n int64, err error) {
+	written, err := w.Write(s.content)
+	return int64(written), err
+}
+
+func TestWrap(t *testing.T) {
+	store := &fakeStorager{content: []byte("hello")}
+
+	var ops []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, c Call) {
+				ops = append(ops, name+":"+c.Op())
+				next(ctx, c)
+			}
+		}
+	}
+
+	ws := Wrap(store, record("a"), record("b"))
+	assert.Equal(t, "fake", ws.String())
+	assert.Equal(t, store, Unwrap(ws))
+
+	var buf bytes.Buffer
+	n, err := ws.Read("test", &buf, pairs.WithSize(5))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, "hello", buf.String())
+	assert.Equal(t, []string{"a:read", "b:read"}, ops)
+
+	_, err = ws.Stat("test")
+	assert.True(t, errors.Is(err, types.ErrNotImplemented))
+}
+
+func TestWrap_ShortCircuit(t *testing.T) {
+	store := &fakeStorager{content: []byte("hello")}
+
+	ws := Wrap(store, func(next Handler) Handler {
+		return func(ctx context.Context, c Call) {
+			rc, ok := c.(*ReadCall)
+			if !ok {
+				next(ctx, c)
+				return
+			}
+			assert.Equal(t, []string{"test"}, rc.Paths())
+			assert.Len(t, rc.Pairs, 1)
+
+			written, err := rc.W.Write([]byte("cached"))
+			rc.N, rc.Err = int64(written), err
+		}
+	})
+
+	var buf bytes.Buffer
+	n, err := ws.Read("test", &buf, pairs.WithSize(5))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), n)
+	assert.Equal(t, "cached", buf.String())
+}
+
+func TestFromInterceptor(t *testing.T) {
+	store := &fakeStorager{}
+
+	var method string
+	var result error
+	ws := Wrap(store, FromInterceptor(func(ctx context.Context, m string) func(error) {
+		method = m
+		return func(err error) {
+			result = err
+		}
+	}))
+
+	_, err := ws.Stat("test")
+	assert.Equal(t, "stat", method)
+	assert.Equal(t, err, result)
+}
@@ -0,0 +1,9 @@
+/*
+Package mirror provides rsync-like sync between directories of two Storagers.
+
+Sync is done in two steps: Diff lists both trees and builds a Plan which
+could be inspected before execution, and Plan.Execute applies it by
+transfer. Objects are compared by size, content_md5, etag and last_modified,
+see Options for details.
+*/
+package mirror
@@ -0,0 +1,297 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.beyondstorage.io/v5/pkg/transfer"
+	"go.beyondstorage.io/v5/pkg/walk"
+	"go.beyondstorage.io/v5/types"
+)
+
+// Action is the action to sync an object.
+type Action int
+
+// All available actions.
+const (
+	// ActionCreate means the object doesn't exist in dst.
+	ActionCreate Action = iota + 1
+	// ActionUpdate means the object in dst is different from src.
+	ActionUpdate
+	// ActionDelete means the object only exists in dst.
+	ActionDelete
+)
+
+// String implements fmt.Stringer
+func (a Action) String() string {
+	switch a {
+	case ActionCreate:
+		return "create"
+	case ActionUpdate:
+		return "update"
+	case ActionDelete:
+		return "delete"
+	default:
+		return fmt.Sprintf("Action(%d)", int(a))
+	}
+}
+
+// Options is the mirror supported options.
+type Options struct {
+	// DeleteExtraneous means objects only exist in dst will be deleted.
+	DeleteExtraneous bool
+	// ChecksumOnly means objects will be compared by content_md5 (or etag if
+	// src and dst are the same Storager) only, objects without checksum will
+	// always be updated.
+	//
+	// By default, objects with different size will be updated, then checksums
+	// will be compared if available, and objects will be updated if src is
+	// newer than dst at last.
+	ChecksumOnly bool
+	// Include is the glob patterns of paths relative to the dir, only paths
+	// match any pattern will be synced. Empty means all paths are included.
+	//
+	// Patterns are matched by path.Match.
+	Include []string
+	// Exclude is the glob patterns of paths relative to the dir, paths match
+	// any pattern will be ignored, even they are extraneous.
+	Exclude []string
+	// Parallelism is the max number of concurrent transfers and deletes, 4 by default.
+	Parallelism int
+	// Transfer is the options used by transfer, Parallelism will be overwritten.
+	Transfer *transfer.Options
+}
+
+// Entry is an object to be synced.
+type Entry struct {
+	Action Action
+	// Path is the path relative to the dir.
+	Path string
+	// Src is the src object, nil for ActionDelete.
+	Src *types.Object
+	// Dst is the dst object, nil for ActionCreate.
+	Dst *types.Object
+}
+
+// Plan is the result of Diff.
+type Plan struct {
+	// Entries is sorted by Path.
+	Entries []Entry
+
+	src    types.Storager
+	dst    types.Storager
+	srcDir string
+	dstDir string
+	opt    Options
+}
+
+// Count returns the count of entries with action a.
+func (p *Plan) Count(a Action) int {
+	n := 0
+	for _, e := range p.Entries {
+		if e.Action == a {
+			n++
+		}
+	}
+	return n
+}
+
+// Diff compares objects under srcDir of src and dstDir of dst, and returns the Plan to sync them.
+func Diff(ctx context.Context, src, dst types.Storager, srcDir, dstDir string, o *Options) (*Plan, error) {
+	p := &Plan{
+		src:    src,
+		dst:    dst,
+		srcDir: cleanDir(srcDir),
+		dstDir: cleanDir(dstDir),
+	}
+	if o != nil {
+		p.opt = *o
+	}
+	for _, v := range append(p.opt.Include, p.opt.Exclude...) {
+		if _, err := path.Match(v, ""); err != nil {
+			return nil, fmt.Errorf("pattern %s: %w", v, err)
+		}
+	}
+
+	srcObjects := make(map[string]*types.Object)
+	err := walkTree(ctx, src, p.srcDir, func(rel string, o *types.Object) {
+		if p.match(rel) {
+			srcObjects[rel] = o
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	dstObjects := make(map[string]*types.Object)
+	err = walkTree(ctx, dst, p.dstDir, func(rel string, o *types.Object) {
+		if p.match(rel) {
+			dstObjects[rel] = o
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for rel, so := range srcObjects {
+		do, ok := dstObjects[rel]
+		switch {
+		case !ok:
+			p.Entries = append(p.Entries, Entry{Action: ActionCreate, Path: rel, Src: so})
+		case p.changed(so, do):
+			p.Entries = append(p.Entries, Entry{Action: ActionUpdate, Path: rel, Src: so, Dst: do})
+		}
+	}
+	if p.opt.DeleteExtraneous {
+		for rel, do := range dstObjects {
+			if _, ok := srcObjects[rel]; !ok {
+				p.Entries = append(p.Entries, Entry{Action: ActionDelete, Path: rel, Dst: do})
+			}
+		}
+	}
+	sort.Slice(p.Entries, func(i, j int) bool {
+		return p.Entries[i].Path < p.Entries[j].Path
+	})
+	return p, nil
+}
+
+// Execute will apply the plan, objects will be created and updated before deletion.
+//
+// All entries will be tried, and the first error will be returned.
+func (p *Plan) Execute(ctx context.Context) error {
+	parallelism := p.opt.Parallelism
+	if parallelism <= 0 {
+		parallelism = 4
+	}
+	var to transfer.Options
+	if p.opt.Transfer != nil {
+		to = *p.opt.Transfer
+	}
+	to.Parallelism = parallelism
+
+	jobs := make([]transfer.Job, 0, len(p.Entries))
+	deletes := make([]string, 0)
+	for _, e := range p.Entries {
+		if e.Action == ActionDelete {
+			deletes = append(deletes, p.dstPath(e.Path))
+			continue
+		}
+		jobs = append(jobs, transfer.Job{Src: p.srcPath(e.Path), Dst: p.dstPath(e.Path)})
+	}
+
+	err := transfer.New(p.src, p.dst, &to).CopyAll(ctx, jobs)
+
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+		sem  = make(chan struct{}, parallelism)
+	)
+	for _, v := range deletes {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(v string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if derr := p.dst.DeleteWithContext(ctx, v); derr != nil {
+				once.Do(func() {
+					if err == nil {
+						err = fmt.Errorf("delete %s: %w", v, derr)
+					}
+				})
+			}
+		}(v)
+	}
+	wg.Wait()
+	return err
+}
+
+func (p *Plan) srcPath(rel string) string {
+	return joinPath(p.srcDir, rel)
+}
+
+func (p *Plan) dstPath(rel string) string {
+	return joinPath(p.dstDir, rel)
+}
+
+// match returns whether rel should be synced.
+func (p *Plan) match(rel string) bool {
+	for _, v := range p.opt.Exclude {
+		if ok, _ := path.Match(v, rel); ok {
+			return false
+		}
+	}
+	if len(p.opt.Include) == 0 {
+		return true
+	}
+	for _, v := range p.opt.Include {
+		if ok, _ := path.Match(v, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// changed returns whether dst object do should be updated by src object so.
+func (p *Plan) changed(so, do *types.Object) bool {
+	if !p.opt.ChecksumOnly {
+		ss, sok := so.GetContentLength()
+		ds, dok := do.GetContentLength()
+		if sok && dok && ss != ds {
+			return true
+		}
+	}
+
+	// Follow RFC-14, content_md5 could be compared across services, but etag
+	// could only be compared in the same service.
+	if sv, ok := so.GetContentMd5(); ok && sv != "" {
+		if dv, ok := do.GetContentMd5(); ok && dv != "" {
+			return sv != dv
+		}
+	}
+	if p.src == p.dst {
+		if sv, ok := so.GetEtag(); ok && sv != "" {
+			if dv, ok := do.GetEtag(); ok && dv != "" {
+				return sv != dv
+			}
+		}
+	}
+	if p.opt.ChecksumOnly {
+		return true
+	}
+
+	st, sok := so.GetLastModified()
+	dt, dok := do.GetLastModified()
+	if sok && dok {
+		return st.After(dt)
+	}
+	// We can't tell whether they are the same, update it to be safe.
+	return true
+}
+
+// walkTree will call fn for every object under dir recursively with the path relative to dir.
+func walkTree(ctx context.Context, store types.Storager, dir string, fn func(rel string, o *types.Object)) error {
+	return walk.Walk(ctx, store, dir, func(o *types.Object) error {
+		if o.Mode.IsDir() || !o.Mode.IsRead() {
+			return nil
+		}
+		rel := strings.TrimPrefix(o.Path, dir)
+		fn(strings.TrimPrefix(rel, "/"), o)
+		return nil
+	})
+}
+
+func cleanDir(dir string) string {
+	return strings.TrimRight(dir, "/")
+}
+
+func joinPath(dir, rel string) string {
+	if dir == "" {
+		return rel
+	}
+	return dir + "/" + rel
+}
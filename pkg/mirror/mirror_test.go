@@ -0,0 +1,109 @@
+package mirror
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/internal/storagetest"
+	"go.beyondstorage.io/v5/types"
+)
+
+func newStorager(files map[string]string, modTime time.Time) *storagetest.Storager {
+	s := storagetest.New()
+	s.StorageFeatures = types.StorageFeatures{List: true, Read: true, Write: true, Stat: true, Delete: true, Copy: true}
+	for k, v := range files {
+		s.Put(k, []byte(v)).LastModified = modTime
+	}
+	return s
+}
+
+func paths(s *storagetest.Storager, prefix string) []string {
+	ps := make([]string, 0)
+	for _, k := range s.Paths() {
+		if strings.HasPrefix(k, prefix) {
+			ps = append(ps, k)
+		}
+	}
+	return ps
+}
+
+func TestDiffAndExecute(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+	src := newStorager(map[string]string{
+		"src/a":       "a",
+		"src/b/c":     "c",
+		"src/b/d/e":   "e",
+		"src/changed": "new content",
+		"src/newer":   "xx",
+		"src/skip.go": "go",
+	}, time.Now())
+	dst := newStorager(map[string]string{
+		"dst/changed": "old",
+		"dst/newer":   "yy",
+		"dst/extra":   "extra",
+		"dst/keep.go": "go",
+	}, old)
+
+	plan, err := Diff(context.Background(), src, dst, "src", "dst/", &Options{
+		DeleteExtraneous: true,
+		Exclude:          []string{"*.go"},
+	})
+	assert.NoError(t, err)
+
+	actions := make(map[string]Action)
+	for _, e := range plan.Entries {
+		actions[e.Path] = e.Action
+	}
+	assert.Equal(t, map[string]Action{
+		"a":       ActionCreate,
+		"b/c":     ActionCreate,
+		"b/d/e":   ActionCreate,
+		"changed": ActionUpdate,
+		"newer":   ActionUpdate,
+		"extra":   ActionDelete,
+	}, actions)
+	assert.Equal(t, 3, plan.Count(ActionCreate))
+
+	assert.NoError(t, plan.Execute(context.Background()))
+	assert.Equal(t, []string{"dst/a", "dst/b/c", "dst/b/d/e", "dst/changed", "dst/keep.go", "dst/newer"}, paths(dst, "dst/"))
+	assert.Equal(t, "new content", string(dst.Data("dst/changed")))
+
+	// Nothing to do after sync.
+	plan, err = Diff(context.Background(), src, dst, "src", "dst", &Options{Exclude: []string{"*.go"}})
+	assert.NoError(t, err)
+	assert.Len(t, plan.Entries, 0)
+}
+
+func TestSameStorager(t *testing.T) {
+	store := newStorager(map[string]string{
+		"src/a":   "a",
+		"src/b/c": "c",
+	}, time.Now())
+
+	plan, err := Diff(context.Background(), store, store, "src", "dst", &Options{Include: []string{"b/*"}})
+	assert.NoError(t, err)
+	assert.Len(t, plan.Entries, 1)
+
+	assert.NoError(t, plan.Execute(context.Background()))
+	assert.Equal(t, 1, store.Count("copy"))
+	assert.Equal(t, []string{"dst/b/c"}, paths(store, "dst/"))
+}
+
+func TestChecksumOnly(t *testing.T) {
+	p := &Plan{opt: Options{ChecksumOnly: true}}
+
+	so := types.NewObject(nil, true)
+	so.SetContentLength(1)
+	so.SetContentMd5("x")
+	do := types.NewObject(nil, true)
+	do.SetContentLength(2)
+	do.SetContentMd5("x")
+	assert.False(t, p.changed(so, do))
+
+	do.SetContentMd5("y")
+	assert.True(t, p.changed(so, do))
+}
@@ -0,0 +1,11 @@
+/*
+Package objectio provides io interfaces over objects in any Storager.
+
+Reader implements io.ReadSeekCloser and io.ReaderAt by issuing ranged reads
+with offset and size pairs, so remote objects could be fed to archive/zip,
+http.ServeContent and other consumers that need random access.
+
+Writer implements io.WriteCloser for data of unknown length, it switches to
+multipart, block or append operations automatically for large objects.
+*/
+package objectio
@@ -0,0 +1,288 @@
+package objectio
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/types"
+)
+
+// DefaultBlockSize is the default size of ranged reads.
+const DefaultBlockSize = 1024 * 1024
+
+var (
+	_ io.ReadSeekCloser = &Reader{}
+	_ io.ReaderAt       = &Reader{}
+)
+
+// ReaderOptions is the options for Reader.
+type ReaderOptions struct {
+	// BlockSize is the size of every ranged read, DefaultBlockSize by default.
+	BlockSize int64
+	// CacheBlocks is the max number of blocks cached, 4 by default.
+	//
+	// CacheBlocks will be increased to ReadAhead+1 if it's smaller.
+	CacheBlocks int
+	// ReadAhead is the number of blocks fetched in background while reading
+	// sequentially via Read, 0 means read ahead is disabled.
+	ReadAhead int
+}
+
+// Reader is an object opened for reading.
+//
+// ReadAt is safe for concurrent use, but Read and Seek are not.
+type Reader struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	store  types.Storager
+	path   string
+	size   int64
+
+	blockSize int64
+	maxBlocks int
+	readAhead int
+
+	mu     sync.Mutex
+	blocks map[int64]*list.Element
+	lru    *list.List
+	closed bool
+
+	offset int64
+}
+
+// block is a cached block, data and err could only be read after done closed.
+type block struct {
+	index int64
+	data  []byte
+	err   error
+	done  chan struct{}
+}
+
+// Open will open the object at path for reading.
+func Open(store types.Storager, path string, o *ReaderOptions) (*Reader, error) {
+	return OpenWithContext(context.Background(), store, path, o)
+}
+
+// OpenWithContext will open the object at path for reading.
+//
+// ctx will be used by all reads, and Close will cancel it.
+func OpenWithContext(ctx context.Context, store types.Storager, path string, o *ReaderOptions) (*Reader, error) {
+	object, err := store.StatWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return OpenObject(ctx, store, object, o)
+}
+
+// OpenObject will open an object returned by Stat or List for reading.
+//
+// The object will be stated again if its content length is missing.
+func OpenObject(ctx context.Context, store types.Storager, object *types.Object, o *ReaderOptions) (*Reader, error) {
+	if object.Mode.IsDir() {
+		return nil, fmt.Errorf("open %s: %w", object.Path, os.ErrInvalid)
+	}
+
+	size, ok := object.GetContentLength()
+	if !ok {
+		x, err := store.StatWithContext(ctx, object.Path)
+		if err != nil {
+			return nil, err
+		}
+		if size, ok = x.GetContentLength(); !ok {
+			return nil, fmt.Errorf("open %s: content length is missing", object.Path)
+		}
+	}
+
+	r := &Reader{
+		store:     store,
+		path:      object.Path,
+		size:      size,
+		blockSize: DefaultBlockSize,
+		maxBlocks: 4,
+		blocks:    make(map[int64]*list.Element),
+		lru:       list.New(),
+	}
+	if o != nil {
+		if o.BlockSize > 0 {
+			r.blockSize = o.BlockSize
+		}
+		if o.CacheBlocks > 0 {
+			r.maxBlocks = o.CacheBlocks
+		}
+		if o.ReadAhead > 0 {
+			r.readAhead = o.ReadAhead
+		}
+	}
+	if r.maxBlocks < r.readAhead+1 {
+		r.maxBlocks = r.readAhead + 1
+	}
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	return r, nil
+}
+
+// Size returns the size of the object.
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	n, err := r.ReadAt(p, r.offset)
+	r.offset += int64(n)
+	if n > 0 && errors.Is(err, io.EOF) {
+		err = nil
+	}
+	if err == nil && r.readAhead > 0 {
+		r.prefetch(r.offset / r.blockSize)
+	}
+	return n, err
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *Reader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("read %s: negative offset: %w", r.path, os.ErrInvalid)
+	}
+
+	n := 0
+	for n < len(p) && off < r.size {
+		b, err := r.block(off / r.blockSize)
+		if err != nil {
+			return n, err
+		}
+		nn := copy(p[n:], b[off%r.blockSize:])
+		n += nn
+		off += int64(nn)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Seek implements io.Seeker.
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, fmt.Errorf("seek %s: invalid whence: %w", r.path, os.ErrInvalid)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("seek %s: negative position: %w", r.path, os.ErrInvalid)
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+// Close will cancel all reads and drop cached blocks.
+func (r *Reader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return os.ErrClosed
+	}
+	r.closed = true
+	r.cancel()
+	r.blocks = nil
+	r.lru.Init()
+	return nil
+}
+
+// block returns the data of block idx, blocks which are not cached will be
+// fetched and concurrent callers of the same block will share one read.
+func (r *Reader) block(idx int64) ([]byte, error) {
+	b, fetch, err := r.acquire(idx)
+	if err != nil {
+		return nil, err
+	}
+	if fetch {
+		r.fetch(b)
+	}
+	<-b.done
+	return b.data, b.err
+}
+
+// prefetch fetches blocks after idx in background.
+func (r *Reader) prefetch(idx int64) {
+	for i := idx; i < idx+int64(r.readAhead)+1; i++ {
+		if i*r.blockSize >= r.size {
+			return
+		}
+		b, fetch, err := r.acquire(i)
+		if err != nil {
+			return
+		}
+		if fetch {
+			go r.fetch(b)
+		}
+	}
+}
+
+// acquire gets block idx from cache, fetch will be true if the caller should fetch it.
+func (r *Reader) acquire(idx int64) (b *block, fetch bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil, false, os.ErrClosed
+	}
+	if e, ok := r.blocks[idx]; ok {
+		r.lru.MoveToFront(e)
+		return e.Value.(*block), false, nil
+	}
+
+	b = &block{index: idx, done: make(chan struct{})}
+	r.blocks[idx] = r.lru.PushFront(b)
+	for r.lru.Len() > r.maxBlocks {
+		e := r.lru.Back()
+		r.lru.Remove(e)
+		delete(r.blocks, e.Value.(*block).index)
+	}
+	return b, true, nil
+}
+
+func (r *Reader) fetch(b *block) {
+	defer close(b.done)
+
+	offset := b.index * r.blockSize
+	size := r.blockSize
+	if offset+size > r.size {
+		size = r.size - offset
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, size))
+	n, err := r.store.ReadWithContext(r.ctx, r.path, buf,
+		pairs.WithOffset(offset), pairs.WithSize(size))
+	if err == nil && n != size {
+		err = fmt.Errorf("read %s: %w", r.path, io.ErrUnexpectedEOF)
+	}
+	if err == nil {
+		b.data = buf.Bytes()
+		return
+	}
+
+	b.err = err
+	// Remove failed block so that it could be fetched again.
+	r.mu.Lock()
+	if e, ok := r.blocks[b.index]; ok && e.Value == b {
+		r.lru.Remove(e)
+		delete(r.blocks, b.index)
+	}
+	r.mu.Unlock()
+}
@@ -0,0 +1,214 @@
+package objectio
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/internal/storagetest"
+	"go.beyondstorage.io/v5/types"
+)
+
+// reads returns the ranges of reads recorded by s.
+func reads(s *storagetest.Storager) [][2]int64 {
+	rs := make([][2]int64, 0)
+	for _, c := range s.Calls("read") {
+		rs = append(rs, [2]int64{c.Offset, c.Size})
+	}
+	return rs
+}
+
+func newTestReader(t *testing.T, size int, o *ReaderOptions) (*storagetest.Storager, *Reader, []byte) {
+	content := make([]byte, size)
+	rand.Read(content)
+
+	store := storagetest.New()
+	store.Put("test", content)
+
+	r, err := Open(store, "test", o)
+	assert.Nil(t, err)
+	return store, r, content
+}
+
+func TestReaderReadAt(t *testing.T) {
+	store, r, content := newTestReader(t, 1000, &ReaderOptions{BlockSize: 100, CacheBlocks: 2})
+	assert.Equal(t, int64(1000), r.Size())
+
+	p := make([]byte, 150)
+	n, err := r.ReadAt(p, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, 150, n)
+	assert.Equal(t, content[50:200], p)
+	assert.Equal(t, [][2]int64{{0, 100}, {100, 100}}, reads(store))
+
+	// Cached blocks will not be read again.
+	_, err = r.ReadAt(p[:10], 120)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, store.Count("read"))
+
+	n, err = r.ReadAt(p, 900)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 100, n)
+	assert.Equal(t, content[900:], p[:n])
+
+	n, err = r.ReadAt(p, 1000)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 0, n)
+
+	assert.Nil(t, r.Close())
+	_, err = r.ReadAt(p, 0)
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestReaderReadSeek(t *testing.T) {
+	_, r, content := newTestReader(t, 1000, &ReaderOptions{BlockSize: 64})
+
+	data, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, content, data)
+
+	pos, err := r.Seek(-100, io.SeekEnd)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(900), pos)
+	pos, err = r.Seek(-50, io.SeekCurrent)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(850), pos)
+
+	data, err = ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, content[850:], data)
+
+	_, err = r.Seek(-1, io.SeekStart)
+	assert.Error(t, err)
+}
+
+func TestReaderReadAhead(t *testing.T) {
+	store, r, content := newTestReader(t, 1000, &ReaderOptions{BlockSize: 100, ReadAhead: 2})
+
+	p := make([]byte, 10)
+	_, err := io.ReadFull(r, p)
+	assert.Nil(t, err)
+	assert.Equal(t, content[:10], p)
+
+	// Wait for blocks read ahead.
+	assert.Eventually(t, func() bool { return store.Count("read") == 3 }, time.Second, time.Millisecond)
+
+	// Blocks read ahead will be used.
+	_, err = r.Seek(250, io.SeekStart)
+	assert.Nil(t, err)
+	_, err = io.ReadFull(r, p)
+	assert.Nil(t, err)
+	assert.Equal(t, content[250:260], p)
+	assert.Eventually(t, func() bool { return store.Count("read") == 5 }, time.Second, time.Millisecond)
+	assert.Nil(t, r.Close())
+}
+
+func TestReaderNoReadAhead(t *testing.T) {
+	store, r, content := newTestReader(t, 1000, &ReaderOptions{BlockSize: 100})
+
+	p := make([]byte, 100)
+	_, err := io.ReadFull(r, p)
+	assert.Nil(t, err)
+	assert.Equal(t, content[:100], p)
+
+	// The next block will not be read before requested.
+	assert.Never(t, func() bool { return store.Count("read") > 1 }, 50*time.Millisecond, time.Millisecond)
+	assert.Nil(t, r.Close())
+}
+
+func TestReaderConcurrentReadAt(t *testing.T) {
+	store, r, content := newTestReader(t, 4096, &ReaderOptions{BlockSize: 512, CacheBlocks: 8})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(off int64) {
+			defer wg.Done()
+
+			p := make([]byte, 1000)
+			n, err := r.ReadAt(p, off)
+			assert.Nil(t, err)
+			assert.Equal(t, content[off:off+int64(n)], p)
+		}(int64(i * 100))
+	}
+	wg.Wait()
+
+	// Every block should be read only once.
+	assert.Equal(t, 5, store.Count("read"))
+}
+
+func TestReaderRetry(t *testing.T) {
+	store, r, content := newTestReader(t, 100, nil)
+
+	// Fail the first read.
+	failed := false
+	store.Fail = func(c storagetest.Call) error {
+		if failed {
+			return nil
+		}
+		failed = true
+		return errors.New("read failed")
+	}
+	p := make([]byte, 10)
+	_, err := r.ReadAt(p, 0)
+	assert.Error(t, err)
+
+	_, err = r.ReadAt(p, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, content[:10], p)
+}
+
+func TestReaderZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("hello.txt")
+	assert.Nil(t, err)
+	_, err = w.Write([]byte("hello, world"))
+	assert.Nil(t, err)
+	assert.Nil(t, zw.Close())
+
+	store := storagetest.New()
+	store.Put("test.zip", buf.Bytes())
+
+	r, err := Open(store, "test.zip", &ReaderOptions{BlockSize: 32})
+	assert.Nil(t, err)
+	defer r.Close()
+
+	zr, err := zip.NewReader(r, r.Size())
+	assert.Nil(t, err)
+	f, err := zr.Open("hello.txt")
+	assert.Nil(t, err)
+	data, err := ioutil.ReadAll(f)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello, world", string(data))
+}
+
+// noLengthStorager returns objects without content length.
+type noLengthStorager struct {
+	*storagetest.Storager
+}
+
+func (s noLengthStorager) StatWithContext(ctx context.Context, path string, ps ...types.Pair) (*types.Object, error) {
+	o := types.NewObject(s, true)
+	o.Path = path
+	o.Mode = types.ModeRead
+	return o, nil
+}
+
+func TestOpenWithoutContentLength(t *testing.T) {
+	store := noLengthStorager{storagetest.New()}
+	store.Put("a", []byte("hello"))
+
+	_, err := Open(store, "a", nil)
+	assert.Error(t, err)
+}
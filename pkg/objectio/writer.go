@@ -0,0 +1,272 @@
+package objectio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/types"
+)
+
+// DefaultPartSize is the default size of parts while multipart_size_minimum is not set.
+const DefaultPartSize = 8 * 1024 * 1024
+
+var _ io.WriteCloser = &Writer{}
+
+// WriterOptions is the options for Writer.
+type WriterOptions struct {
+	// PartSize is the size of buffered data before uploading a part, multipart_size_minimum
+	// from StorageMeta by default, or DefaultPartSize if it's not set.
+	//
+	// PartSize will be adjusted to satisfy the multipart size restrictions.
+	PartSize int64
+	// Pairs will be passed to Write, CreateMultipart, CreateBlock and CreateAppend.
+	Pairs []types.Pair
+}
+
+// writeMode is the mode used to upload data.
+type writeMode int
+
+const (
+	writeModeWrite writeMode = iota
+	writeModeMultipart
+	writeModeBlock
+	writeModeAppend
+)
+
+// Writer is an object opened for writing data of unknown length.
+//
+// Data smaller than part size will be written by a single Write. Otherwise,
+// multipart, block or append operations will be used depending on Features.
+// Services that support none of them will have the whole object buffered
+// in memory.
+//
+// The object will be visible only after Close returned without error.
+type Writer struct {
+	ctx   context.Context
+	store types.Storager
+	path  string
+	pairs []types.Pair
+
+	mode     writeMode
+	partSize int64
+	buf      []byte
+
+	// o will be set after upload started.
+	o      *types.Object
+	parts  []*types.Part
+	blocks []string
+
+	err    error
+	closed bool
+}
+
+// Create will create a Writer for the object at path.
+func Create(store types.Storager, path string, o *WriterOptions) *Writer {
+	return CreateWithContext(context.Background(), store, path, o)
+}
+
+// CreateWithContext will create a Writer for the object at path.
+func CreateWithContext(ctx context.Context, store types.Storager, path string, o *WriterOptions) *Writer {
+	w := &Writer{
+		ctx:   ctx,
+		store: store,
+		path:  path,
+	}
+	if o != nil {
+		w.pairs = o.Pairs
+		w.partSize = o.PartSize
+	}
+
+	f := store.Features()
+	switch {
+	case f.CreateMultipart && f.WriteMultipart && f.CompleteMultipart:
+		w.mode = writeModeMultipart
+	case f.CreateBlock && f.WriteBlock && f.CombineBlock:
+		w.mode = writeModeBlock
+	case f.CreateAppend && f.WriteAppend && f.CommitAppend:
+		w.mode = writeModeAppend
+	}
+
+	meta := store.Metadata()
+	if w.partSize <= 0 {
+		w.partSize = DefaultPartSize
+		if v, ok := meta.GetMultipartSizeMinimum(); ok && v > 0 && w.mode == writeModeMultipart {
+			w.partSize = v
+		}
+	}
+	if w.mode == writeModeMultipart {
+		if v, ok := meta.GetMultipartSizeMinimum(); ok && w.partSize < v {
+			w.partSize = v
+		}
+		if v, ok := meta.GetMultipartSizeMaximum(); ok && v > 0 && w.partSize > v {
+			w.partSize = v
+		}
+	}
+	return w
+}
+
+// Write implements io.Writer.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n := 0
+	for len(p) > 0 {
+		// Only flush while there is more data, so that data of exactly
+		// part size could still be written by a single Write.
+		if int64(len(w.buf)) >= w.partSize && w.mode != writeModeWrite {
+			if err := w.flush(); err != nil {
+				return n, err
+			}
+		}
+
+		size := len(p)
+		if w.mode != writeModeWrite {
+			if x := int(w.partSize) - len(w.buf); size > x {
+				size = x
+			}
+		}
+		w.buf = append(w.buf, p[:size]...)
+		p = p[size:]
+		n += size
+	}
+	return n, nil
+}
+
+// Close will upload buffered data and complete the upload.
+func (w *Writer) Close() error {
+	if w.closed {
+		return os.ErrClosed
+	}
+	w.closed = true
+	if w.err != nil {
+		return w.err
+	}
+
+	if w.o == nil {
+		_, err := w.store.WriteWithContext(w.ctx, w.path, bytes.NewReader(w.buf), int64(len(w.buf)), w.pairs...)
+		w.buf = nil
+		return w.formatError(err)
+	}
+
+	if len(w.buf) > 0 {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+	w.buf = nil
+
+	var err error
+	switch w.mode {
+	case writeModeMultipart:
+		err = w.store.CompleteMultipartWithContext(w.ctx, w.o, w.parts)
+	case writeModeBlock:
+		err = w.store.CombineBlockWithContext(w.ctx, w.o, w.blocks)
+	case writeModeAppend:
+		err = w.store.CommitAppendWithContext(w.ctx, w.o)
+	}
+	if err != nil {
+		return w.fail(err)
+	}
+	return nil
+}
+
+// Abort will abort the upload and clean up uploaded data.
+//
+// Abort is a no-op after Close succeeded.
+func (w *Writer) Abort() error {
+	if w.closed && w.err == nil {
+		return nil
+	}
+	w.closed = true
+	w.buf = nil
+	if w.err == nil {
+		w.err = fmt.Errorf("write %s: %w", w.path, context.Canceled)
+	}
+	return w.cleanup()
+}
+
+// flush uploads buffered data as a part, the upload will be started if not yet.
+func (w *Writer) flush() (err error) {
+	if w.o == nil {
+		switch w.mode {
+		case writeModeMultipart:
+			w.o, err = w.store.CreateMultipartWithContext(w.ctx, w.path, w.pairs...)
+		case writeModeBlock:
+			w.o, err = w.store.CreateBlockWithContext(w.ctx, w.path, w.pairs...)
+		case writeModeAppend:
+			w.o, err = w.store.CreateAppendWithContext(w.ctx, w.path, w.pairs...)
+		}
+		if err != nil {
+			return w.fail(err)
+		}
+	}
+
+	r, size := bytes.NewReader(w.buf), int64(len(w.buf))
+	switch w.mode {
+	case writeModeMultipart:
+		var part *types.Part
+		_, part, err = w.store.WriteMultipartWithContext(w.ctx, w.o, r, size, len(w.parts))
+		if err == nil {
+			w.parts = append(w.parts, part)
+		}
+	case writeModeBlock:
+		bid := fmt.Sprintf("%016d", len(w.blocks))
+		_, err = w.store.WriteBlockWithContext(w.ctx, w.o, r, size, bid)
+		if err == nil {
+			w.blocks = append(w.blocks, bid)
+		}
+	case writeModeAppend:
+		_, err = w.store.WriteAppendWithContext(w.ctx, w.o, r, size)
+	}
+	if err != nil {
+		return w.fail(err)
+	}
+
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// fail marks the writer as failed and cleans up the upload.
+func (w *Writer) fail(err error) error {
+	w.err = w.formatError(err)
+	w.buf = nil
+	// Cleanup error is ignored, the original error is more useful.
+	_ = w.cleanup()
+	return w.err
+}
+
+// cleanup removes the uploaded data.
+func (w *Writer) cleanup() error {
+	if w.o == nil {
+		return nil
+	}
+	o := w.o
+	w.o = nil
+
+	// ctx could have been canceled, use a new context for cleanup.
+	ctx := context.Background()
+	switch w.mode {
+	case writeModeMultipart:
+		return w.store.DeleteWithContext(ctx, w.path, pairs.WithMultipartID(o.MustGetMultipartID()))
+	case writeModeAppend:
+		return w.store.DeleteWithContext(ctx, w.path)
+	}
+	// Uncommitted blocks will be garbage collected by services.
+	return nil
+}
+
+func (w *Writer) formatError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("write %s: %w", w.path, err)
+}
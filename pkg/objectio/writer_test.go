@@ -0,0 +1,170 @@
+package objectio
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/internal/storagetest"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+func newStorager() *storagetest.Storager {
+	s := storagetest.New()
+	s.StorageFeatures = types.StorageFeatures{Read: true, Write: true, Stat: true}
+	return s
+}
+
+func newMultipartStorager() *storagetest.Storager {
+	s := storagetest.New()
+	s.StorageFeatures = types.StorageFeatures{
+		Read: true, Write: true, Stat: true, Delete: true,
+		CreateMultipart: true, WriteMultipart: true, CompleteMultipart: true,
+	}
+	s.StorageMeta.SetMultipartSizeMinimum(1024)
+	return s
+}
+
+type appendStorager struct {
+	*storagetest.Storager
+
+	appending map[string][]byte
+}
+
+func newAppendStorager() *appendStorager {
+	return &appendStorager{Storager: newStorager(), appending: make(map[string][]byte)}
+}
+
+func (s *appendStorager) Features() types.StorageFeatures {
+	return types.StorageFeatures{
+		Read: true, Write: true, Stat: true,
+		CreateAppend: true, WriteAppend: true, CommitAppend: true,
+	}
+}
+
+func (s *appendStorager) CreateAppendWithContext(ctx context.Context, path string, ps ...types.Pair) (*types.Object, error) {
+	s.appending[path] = []byte{}
+	o := types.NewObject(s, true)
+	o.Path = path
+	o.Mode = types.ModeAppend
+	return o, nil
+}
+
+func (s *appendStorager) WriteAppendWithContext(ctx context.Context, o *types.Object, r io.Reader, size int64, ps ...types.Pair) (int64, error) {
+	d, err := ioutil.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	s.appending[o.Path] = append(s.appending[o.Path], d...)
+	return int64(len(d)), nil
+}
+
+func (s *appendStorager) CommitAppendWithContext(ctx context.Context, o *types.Object, ps ...types.Pair) error {
+	s.Put(o.Path, s.appending[o.Path])
+	delete(s.appending, o.Path)
+	return nil
+}
+
+// writeChunks writes content to w in random sized chunks.
+func writeChunks(t *testing.T, w io.Writer, content []byte) {
+	for len(content) > 0 {
+		n := rand.Intn(300) + 1
+		if n > len(content) {
+			n = len(content)
+		}
+		nn, err := w.Write(content[:n])
+		assert.Nil(t, err)
+		assert.Equal(t, n, nn)
+		content = content[n:]
+	}
+}
+
+func TestWriterMultipart(t *testing.T) {
+	store := newMultipartStorager()
+	content := make([]byte, 10*1024+1)
+	rand.Read(content)
+
+	// PartSize will be adjusted to multipart_size_minimum.
+	w := Create(store, "a", &WriterOptions{PartSize: 100})
+	writeChunks(t, w, content)
+	assert.Nil(t, w.Close())
+	assert.Equal(t, content, store.Data("a"))
+	assert.Equal(t, 11, store.Count("write_multipart"))
+	assert.Equal(t, 0, store.Count("write"))
+
+	// Data not larger than part size will be written by a single Write.
+	w = Create(store, "b", nil)
+	writeChunks(t, w, content[:1024])
+	assert.Nil(t, w.Close())
+	assert.Equal(t, content[:1024], store.Data("b"))
+	assert.Equal(t, 1, store.Count("write"))
+
+	// Empty object.
+	w = Create(store, "empty", nil)
+	assert.Nil(t, w.Close())
+	assert.Len(t, store.Data("empty"), 0)
+	assert.ErrorIs(t, w.Close(), os.ErrClosed)
+}
+
+func TestWriterMultipartFailed(t *testing.T) {
+	store := newMultipartStorager()
+	store.Fail = func(c storagetest.Call) error {
+		if c.Op == "write_multipart" && c.Index == 1 {
+			return services.ErrServiceInternal
+		}
+		return nil
+	}
+	content := make([]byte, 4096)
+
+	w := Create(store, "a", nil)
+	_, err := w.Write(content)
+	assert.ErrorIs(t, err, services.ErrServiceInternal)
+	assert.Equal(t, 1, store.Count("delete"))
+
+	_, err = w.Write(content)
+	assert.ErrorIs(t, err, services.ErrServiceInternal)
+	assert.ErrorIs(t, w.Close(), services.ErrServiceInternal)
+	assert.Nil(t, store.Object("a"))
+}
+
+func TestWriterAbort(t *testing.T) {
+	store := newMultipartStorager()
+
+	w := Create(store, "a", nil)
+	writeChunks(t, w, make([]byte, 2048))
+	assert.Nil(t, w.Abort())
+	assert.Equal(t, 1, store.Count("delete"))
+	assert.Error(t, w.Close())
+	assert.Nil(t, store.Object("a"))
+}
+
+func TestWriterAppend(t *testing.T) {
+	store := newAppendStorager()
+	content := make([]byte, 1000)
+	rand.Read(content)
+
+	w := Create(store, "a", &WriterOptions{PartSize: 128})
+	writeChunks(t, w, content)
+	assert.Contains(t, store.appending, "a")
+	assert.Nil(t, w.Close())
+	assert.Equal(t, content, store.Data("a"))
+}
+
+func TestWriterWrite(t *testing.T) {
+	store := newStorager()
+	content := make([]byte, 1000)
+	rand.Read(content)
+
+	// Whole object will be buffered for services which only support Write.
+	w := Create(store, "a", &WriterOptions{PartSize: 128})
+	writeChunks(t, w, content)
+	assert.Nil(t, store.Object("a"))
+	assert.Nil(t, w.Close())
+	assert.Equal(t, content, store.Data("a"))
+}
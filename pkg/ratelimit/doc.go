@@ -0,0 +1,14 @@
+/*
+Package ratelimit provides a Storager middleware which limits bandwidth and request rate.
+
+Limiters are safe for concurrent use, so a Limiter could be shared across
+several Storagers to apply a global limit:
+
+	l, err := ratelimit.NewLimiter(10*1024*1024, 1024*1024)
+	if err != nil {
+		return err
+	}
+	a = ratelimit.New(a, ratelimit.Options{WriteLimiter: l})
+	b = ratelimit.New(b, ratelimit.Options{WriteLimiter: l})
+*/
+package ratelimit
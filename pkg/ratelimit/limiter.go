@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket rate limiter.
+//
+// Tokens are added at rate per second up to burst. WaitN could take more
+// tokens than burst, and the following calls will wait until the debt has
+// been paid.
+type Limiter struct {
+	rate  float64
+	burst float64
+
+	tokens float64
+	last   time.Time
+	mu     sync.Mutex
+}
+
+// NewLimiter will create a new Limiter which allows rate tokens per second with burst.
+//
+// rate must be positive and burst must not be negative.
+func NewLimiter(rate float64, burst int) (*Limiter, error) {
+	if !(rate > 0) {
+		return nil, fmt.Errorf("ratelimit: rate %v is not positive", rate)
+	}
+	if burst < 0 {
+		return nil, fmt.Errorf("ratelimit: burst %d is negative", burst)
+	}
+	return &Limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}, nil
+}
+
+// Wait is the same as WaitN(ctx, 1).
+func (l *Limiter) Wait(ctx context.Context) error {
+	return l.WaitN(ctx, 1)
+}
+
+// WaitN blocks until n tokens are available or ctx is done.
+//
+// Tokens will be returned if ctx is done before they are available.
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+
+	d := l.reserve(float64(n))
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel(float64(n))
+		return ctx.Err()
+	}
+}
+
+// reserve takes n tokens and returns the duration to wait before they are available.
+func (l *Limiter) reserve(n float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.advance(now)
+
+	l.tokens -= n
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+func (l *Limiter) cancel(n float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(time.Now())
+	l.tokens += n
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+func (l *Limiter) advance(now time.Time) {
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
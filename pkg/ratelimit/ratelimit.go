@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+
+	"go.beyondstorage.io/v5/pkg/iowrap"
+	"go.beyondstorage.io/v5/pkg/middleware"
+	"go.beyondstorage.io/v5/types"
+)
+
+// Options is the ratelimit supported options.
+//
+// A nil Limiter means no limit.
+type Options struct {
+	// ReadLimiter limits bytes per second read from storage.
+	ReadLimiter *Limiter
+	// WriteLimiter limits bytes per second written into storage.
+	WriteLimiter *Limiter
+	// OpLimiter limits operations per second sent to storage.
+	OpLimiter *Limiter
+}
+
+// New will create a Storager which limits bandwidth and request rate of store.
+func New(store types.Storager, o Options) types.Storager {
+	return middleware.Wrap(store, Middleware(o))
+}
+
+// Middleware will create a ratelimit middleware.
+func Middleware(o Options) middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, c middleware.Call) {
+			switch c.(type) {
+			case *middleware.CreateCall, *middleware.MetadataCall:
+				// Local operations don't send any request.
+				next(ctx, c)
+				return
+			}
+
+			if err := o.OpLimiter.Wait(ctx); err != nil {
+				c.SetResult(err)
+				return
+			}
+
+			switch v := c.(type) {
+			case *middleware.ReadCall:
+				v.W = throttleWriter(ctx, v.W, o.ReadLimiter)
+			case *middleware.WriteCall:
+				v.R = throttleReader(ctx, v.R, o.WriteLimiter)
+			case *middleware.WriteAppendCall:
+				v.R = throttleReader(ctx, v.R, o.WriteLimiter)
+			case *middleware.WriteBlockCall:
+				v.R = throttleReader(ctx, v.R, o.WriteLimiter)
+			case *middleware.WriteMultipartCall:
+				v.R = throttleReader(ctx, v.R, o.WriteLimiter)
+			case *middleware.WritePageCall:
+				v.R = throttleReader(ctx, v.R, o.WriteLimiter)
+			}
+			next(ctx, c)
+		}
+	}
+}
+
+func throttleReader(ctx context.Context, r io.Reader, l *Limiter) io.Reader {
+	if r == nil || l == nil {
+		return r
+	}
+	return iowrap.ThrottleReader(r, func(n int) error {
+		return l.WaitN(ctx, n)
+	})
+}
+
+func throttleWriter(ctx context.Context, w io.Writer, l *Limiter) io.Writer {
+	if l == nil {
+		return w
+	}
+	return iowrap.ThrottleWriter(w, func(n int) error {
+		return l.WaitN(ctx, n)
+	})
+}
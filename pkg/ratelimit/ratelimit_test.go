@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/types"
+)
+
+type fakeStorager struct {
+	types.UnimplementedStorager
+}
+
+func (s *fakeStorager) ReadWithContext(ctx context.Context, path string, w io.Writer, pairs ...types.Pair) (n int64, err error) {
+	return io.Copy(w, bytes.NewReader(make([]byte, 100)))
+}
+
+func (s *fakeStorager) WriteWithContext(ctx context.Context, path string, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	return io.Copy(ioutil.Discard, r)
+}
+
+func TestLimiter_WaitN(t *testing.T) {
+	l, err := NewLimiter(1000, 100)
+	assert.NoError(t, err)
+
+	start := time.Now()
+	assert.NoError(t, l.WaitN(context.Background(), 100))
+	assert.Less(t, int64(time.Since(start)), int64(10*time.Millisecond))
+
+	// 100 more tokens need 100ms.
+	assert.NoError(t, l.WaitN(context.Background(), 100))
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(90*time.Millisecond))
+}
+
+func TestNewLimiter(t *testing.T) {
+	for _, rate := range []float64{0, -1, math.NaN()} {
+		_, err := NewLimiter(rate, 1)
+		assert.Error(t, err, "rate %v", rate)
+	}
+	_, err := NewLimiter(1, -1)
+	assert.Error(t, err)
+}
+
+func TestLimiter_Cancel(t *testing.T) {
+	l, err := NewLimiter(1, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, l.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestMiddleware(t *testing.T) {
+	rl, err := NewLimiter(1000, 100)
+	assert.NoError(t, err)
+	wl, err := NewLimiter(1000, 100)
+	assert.NoError(t, err)
+	store := New(&fakeStorager{}, Options{ReadLimiter: rl, WriteLimiter: wl})
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		n, err := store.Read("a", ioutil.Discard)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), n)
+	}
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(90*time.Millisecond))
+
+	start = time.Now()
+	for i := 0; i < 2; i++ {
+		n, err := store.Write("a", bytes.NewReader(make([]byte, 100)), 100)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), n)
+	}
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(90*time.Millisecond))
+}
+
+func TestMiddleware_OpLimiter(t *testing.T) {
+	ol, err := NewLimiter(1, 1)
+	assert.NoError(t, err)
+	store := New(&fakeStorager{}, Options{OpLimiter: ol})
+
+	_, err = store.Read("a", ioutil.Discard)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = store.ReadWithContext(ctx, "a", ioutil.Discard)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
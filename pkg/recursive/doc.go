@@ -0,0 +1,16 @@
+/*
+Package recursive provides helpers to remove, copy and move directories recursively.
+
+Operations like Delete, Copy and Move only handle one and only one object,
+users need to do them recursively by themselves. This package walks all
+objects under the directory with package walk, by ListModePrefix for services
+which have virtual dir, and by ListModeDir recursively for others. Then
+operations are applied to every object with bounded concurrency.
+
+Errors will not stop the whole operation, all of them will be aggregated
+into an *Error.
+
+Storagers could implement RemoveAller, CopyAller or MoveAller to provide
+native fast paths.
+*/
+package recursive
@@ -0,0 +1,260 @@
+package recursive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/pkg/transfer"
+	"go.beyondstorage.io/v5/pkg/walk"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// RemoveAller could be implemented by Storager to remove a dir natively.
+type RemoveAller interface {
+	RemoveAllWithContext(ctx context.Context, path string) error
+}
+
+// CopyAller could be implemented by Storager to copy a dir natively.
+type CopyAller interface {
+	CopyAllWithContext(ctx context.Context, src, dst string) error
+}
+
+// MoveAller could be implemented by Storager to move a dir natively.
+type MoveAller interface {
+	MoveAllWithContext(ctx context.Context, src, dst string) error
+}
+
+// Options is the recursive supported options.
+type Options struct {
+	// Parallelism is the max number of concurrent operations, 4 by default.
+	Parallelism int
+	// DisableNative means RemoveAller, CopyAller and MoveAller will not be used.
+	DisableNative bool
+}
+
+// Error aggregates errors of all failed objects.
+type Error struct {
+	Errs []error
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	if len(e.Errs) == 1 {
+		return e.Errs[0].Error()
+	}
+	return fmt.Sprintf("%d objects failed, first error: %s", len(e.Errs), e.Errs[0])
+}
+
+// Unwrap returns the first error, so that errors.Is and errors.As could be used.
+func (e *Error) Unwrap() error {
+	return e.Errs[0]
+}
+
+// RemoveAll will remove path and all objects under it.
+//
+// Removing a not exist path will return nil.
+func RemoveAll(ctx context.Context, store types.Storager, path string, o *Options) error {
+	r := newRunner(store, o)
+	if ra, ok := store.(RemoveAller); ok && !r.disableNative {
+		return ra.RemoveAllWithContext(ctx, path)
+	}
+
+	dir := strings.TrimRight(path, "/")
+	dirs := make([]string, 0)
+	err := walk.Walk(ctx, store, dir, func(o *types.Object) error {
+		if o.Mode.IsDir() {
+			dirs = append(dirs, o.Path)
+			return nil
+		}
+		r.do(ctx, o.Path, func(ctx context.Context) error {
+			return store.DeleteWithContext(ctx, o.Path)
+		})
+		return nil
+	})
+	r.wait()
+	if err != nil {
+		r.fail(dir, err)
+	}
+	if r.err() != nil {
+		return r.err()
+	}
+
+	// Dirs could only be removed after all objects under them have been removed.
+	dirs = append(dirs, dir)
+	removeDirs(ctx, r, dirs)
+	return r.err()
+}
+
+// CopyAll will copy all objects under src into dst.
+//
+// Objects will be copied by Copy if the Storager supports it, or read and
+// written otherwise. Empty dirs will be created if the Storager supports
+// create_dir.
+func CopyAll(ctx context.Context, store types.Storager, src, dst string, o *Options) error {
+	r := newRunner(store, o)
+	if ca, ok := store.(CopyAller); ok && !r.disableNative {
+		return ca.CopyAllWithContext(ctx, src, dst)
+	}
+
+	t := transfer.New(store, store, &transfer.Options{Parallelism: 1})
+	return r.each(ctx, src, dst, func(ctx context.Context, src, dst string) error {
+		return t.Copy(ctx, src, dst)
+	})
+}
+
+// MoveAll will move all objects under src into dst, and remove src at last.
+//
+// Objects will be moved by Move if the Storager supports it, or copied and
+// removed otherwise.
+func MoveAll(ctx context.Context, store types.Storager, src, dst string, o *Options) error {
+	r := newRunner(store, o)
+	if ma, ok := store.(MoveAller); ok && !r.disableNative {
+		return ma.MoveAllWithContext(ctx, src, dst)
+	}
+
+	move := store.Features().Move
+	t := transfer.New(store, store, &transfer.Options{Parallelism: 1})
+	err := r.each(ctx, src, dst, func(ctx context.Context, src, dst string) error {
+		if move {
+			return store.MoveWithContext(ctx, src, dst)
+		}
+		if err := t.Copy(ctx, src, dst); err != nil {
+			return err
+		}
+		return store.DeleteWithContext(ctx, src)
+	})
+	if err != nil {
+		return err
+	}
+	// Remove the dirs left in src.
+	return RemoveAll(ctx, store, src, o)
+}
+
+// runner runs operations with bounded concurrency and collects errors.
+type runner struct {
+	store         types.Storager
+	disableNative bool
+
+	sem  chan struct{}
+	wg   sync.WaitGroup
+	errs []error
+	mu   sync.Mutex
+}
+
+func newRunner(store types.Storager, o *Options) *runner {
+	r := &runner{store: store}
+	parallelism := 4
+	if o != nil {
+		if o.Parallelism > 0 {
+			parallelism = o.Parallelism
+		}
+		r.disableNative = o.DisableNative
+	}
+	r.sem = make(chan struct{}, parallelism)
+	return r
+}
+
+// do runs fn in background, it will block if there are too many running operations.
+func (r *runner) do(ctx context.Context, path string, fn func(ctx context.Context) error) {
+	r.sem <- struct{}{}
+	r.wg.Add(1)
+	go func() {
+		defer func() {
+			<-r.sem
+			r.wg.Done()
+		}()
+		if err := fn(ctx); err != nil {
+			r.fail(path, err)
+		}
+	}()
+}
+
+func (r *runner) wait() {
+	r.wg.Wait()
+}
+
+func (r *runner) fail(path string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Storage errors already contain the path.
+	var se services.StorageError
+	if !errors.As(err, &se) {
+		err = fmt.Errorf("%s: %w", path, err)
+	}
+	r.errs = append(r.errs, err)
+}
+
+func (r *runner) err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.errs) == 0 {
+		return nil
+	}
+	return &Error{Errs: append([]error(nil), r.errs...)}
+}
+
+// each calls fn for every object under src with the corresponding path under dst.
+func (r *runner) each(ctx context.Context, src, dst string, fn func(ctx context.Context, src, dst string) error) error {
+	src, dst = strings.TrimRight(src, "/"), strings.TrimRight(dst, "/")
+	createDir := r.store.Features().CreateDir
+
+	// Dirs will be visited before objects under them by default.
+	err := walk.Walk(ctx, r.store, src, func(o *types.Object) error {
+		p := o.Path
+		target := strings.TrimPrefix(strings.TrimPrefix(p, src), "/")
+		if dst != "" {
+			target = dst + "/" + target
+		}
+		if o.Mode.IsDir() {
+			if createDir {
+				// Create dirs in order, so that empty dirs will be kept.
+				if _, err := r.store.CreateDirWithContext(ctx, target); err != nil {
+					r.fail(target, err)
+				}
+			}
+			return nil
+		}
+		r.do(ctx, p, func(ctx context.Context) error {
+			return fn(ctx, p, target)
+		})
+		return nil
+	})
+	r.wait()
+	if err != nil {
+		r.fail(src, err)
+	}
+	return r.err()
+}
+
+// removeDirs removes dirs from the deepest one.
+func removeDirs(ctx context.Context, r *runner, dirs []string) {
+	sort.Slice(dirs, func(i, j int) bool {
+		di, dj := strings.Count(dirs[i], "/"), strings.Count(dirs[j], "/")
+		if di != dj {
+			return di > dj
+		}
+		return dirs[i] > dirs[j]
+	})
+	for _, v := range dirs {
+		if v == "" {
+			// Never remove the work dir.
+			continue
+		}
+		err := r.store.DeleteWithContext(ctx, v, pairs.WithObjectMode(types.ModeDir))
+		var e services.PairUnsupportedError
+		if errors.As(err, &e) {
+			err = r.store.DeleteWithContext(ctx, v)
+		}
+		if err != nil {
+			r.fail(v, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package recursive
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/internal/storagetest"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+func newStorager(features types.StorageFeatures, files ...string) *storagetest.Storager {
+	s := storagetest.New()
+	s.StorageFeatures = features
+	for _, v := range files {
+		s.Put(v, []byte(v))
+	}
+	return s
+}
+
+// failPaths makes delete, copy and move of paths fail.
+func failPaths(paths ...string) func(c storagetest.Call) error {
+	return func(c storagetest.Call) error {
+		if c.Op != "delete" && c.Op != "copy" && c.Op != "move" {
+			return nil
+		}
+		for _, p := range paths {
+			if c.Path == p {
+				return services.ErrPermissionDenied
+			}
+		}
+		return nil
+	}
+}
+
+// paths returns paths of all objects and dirs, dirs are ended with "/".
+func paths(s *storagetest.Storager) []string {
+	ps := s.Paths()
+	for _, v := range s.Dirs() {
+		ps = append(ps, v+"/")
+	}
+	sort.Strings(ps)
+	return ps
+}
+
+var features = types.StorageFeatures{
+	List: true, Read: true, Write: true, Stat: true, Delete: true,
+	Copy: true, Move: true, CreateDir: true,
+}
+
+func TestRemoveAll(t *testing.T) {
+	s := newStorager(features, "a/b/c", "a/b/d", "a/e", "a/f/g/h", "x")
+	assert.NoError(t, RemoveAll(context.Background(), s, "a", nil))
+	assert.Equal(t, []string{"x"}, paths(s))
+
+	// Remove not exist dir.
+	assert.NoError(t, RemoveAll(context.Background(), s, "a", nil))
+}
+
+func TestRemoveAllPrefix(t *testing.T) {
+	vf := features
+	vf.VirtualDir = true
+	s := newStorager(vf, "a/b/c", "a/e", "ab")
+	assert.NoError(t, RemoveAll(context.Background(), s, "a/", nil))
+	assert.Equal(t, []string{"ab"}, paths(s))
+}
+
+func TestRemoveAllError(t *testing.T) {
+	s := newStorager(features, "a/b", "a/c", "a/d")
+	s.Fail = failPaths("a/b", "a/c")
+
+	err := RemoveAll(context.Background(), s, "a", &Options{Parallelism: 1})
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Len(t, e.Errs, 2)
+	assert.ErrorIs(t, err, services.ErrPermissionDenied)
+	// Other objects will still be removed.
+	assert.Equal(t, []string{"a/", "a/b", "a/c"}, paths(s))
+}
+
+func TestCopyAll(t *testing.T) {
+	s := newStorager(features, "a/b/c", "a/e")
+	_, err := s.CreateDirWithContext(context.Background(), "a/empty")
+	assert.NoError(t, err)
+
+	assert.NoError(t, CopyAll(context.Background(), s, "a", "x/y", nil))
+	assert.Equal(t, []string{
+		"a/", "a/b/", "a/b/c", "a/e", "a/empty/",
+		"x/", "x/y/", "x/y/b/", "x/y/b/c", "x/y/e", "x/y/empty/",
+	}, paths(s))
+	assert.Equal(t, "a/b/c", string(s.Data("x/y/b/c")))
+}
+
+func TestMoveAll(t *testing.T) {
+	for _, move := range []bool{true, false} {
+		f := features
+		f.Move = move
+		s := newStorager(f, "a/b/c", "a/e", "z")
+
+		assert.NoError(t, MoveAll(context.Background(), s, "a", "x", nil))
+		assert.Equal(t, []string{"x/", "x/b/", "x/b/c", "x/e", "z"}, paths(s))
+	}
+}
+
+type nativeStorager struct {
+	*storagetest.Storager
+	called bool
+}
+
+func (s *nativeStorager) RemoveAllWithContext(ctx context.Context, path string) error {
+	s.called = true
+	return nil
+}
+
+func TestNative(t *testing.T) {
+	s := &nativeStorager{Storager: newStorager(features, "a/b")}
+
+	assert.NoError(t, RemoveAll(context.Background(), s, "a", &Options{DisableNative: true}))
+	assert.False(t, s.called)
+	assert.NoError(t, RemoveAll(context.Background(), s, "a", nil))
+	assert.True(t, s.called)
+}
@@ -0,0 +1,12 @@
+/*
+Package retry provides a Storager middleware which retries idempotent operations.
+
+Only errors classified as retryable will be retried, by default they are:
+
+- services.ErrRequestThrottled
+- services.ErrServiceInternal
+- network timeout errors, like the ones returned by connections from pkg/httpclient
+
+Non-idempotent operations like WriteAppend and CompleteMultipart will never be retried.
+*/
+package retry
@@ -0,0 +1,346 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/pkg/middleware"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// Options is the retry supported options.
+type Options struct {
+	// MaxAttempts is the max attempts of an operation, including the first one.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry.
+	BaseDelay time.Duration
+	// MaxDelay is the upper bound of the delay between retries.
+	MaxDelay time.Duration
+	// Multiplier is the factor the delay will be multiplied by after every retry.
+	Multiplier float64
+	// Jitter is the randomization factor in (0, 1] applied to every delay, 0.2 by default.
+	//
+	// Set it to NoJitter, or any negative value, to disable jitter.
+	Jitter float64
+
+	// Budget limits retries for every operation, nil means no limit.
+	Budget *Budget
+	// Retryable decides whether an error could be retried, IsRetryable will be used if nil.
+	Retryable func(err error) bool
+}
+
+// NoJitter could be used as Options.Jitter to disable jitter, so that delays
+// are exactly computed from BaseDelay and Multiplier.
+const NoJitter = -1
+
+// Budget is a token bucket based retry budget which will be applied to every operation separately.
+//
+// Every retry costs one token and every successful call returns TokenRatio tokens.
+// Retry is only allowed while the remaining tokens is more than half of MaxTokens,
+// so that we will stop retrying while an operation keeps failing.
+type Budget struct {
+	MaxTokens  float64
+	TokenRatio float64
+}
+
+// IsRetryable returns true if err is a throttled, service internal or network timeout error.
+func IsRetryable(err error) bool {
+	if errors.Is(err, services.ErrRequestThrottled) ||
+		errors.Is(err, services.ErrServiceInternal) {
+		return true
+	}
+
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	return false
+}
+
+// New will create a Storager which retries idempotent operations on store.
+func New(store types.Storager, o *Options) types.Storager {
+	return middleware.Wrap(store, Middleware(o))
+}
+
+// Middleware will create a retry middleware.
+func Middleware(o *Options) middleware.Middleware {
+	r := newRetrier(o)
+
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, c middleware.Call) {
+			r.handle(ctx, next, c)
+		}
+	}
+}
+
+type retrier struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	multiplier  float64
+	jitter      float64
+	retryable   func(err error) bool
+
+	budget  *Budget
+	buckets map[string]*bucket
+	mu      sync.Mutex
+}
+
+func newRetrier(o *Options) *retrier {
+	r := &retrier{
+		maxAttempts: 3,
+		baseDelay:   100 * time.Millisecond,
+		maxDelay:    10 * time.Second,
+		multiplier:  2,
+		jitter:      0.2,
+		retryable:   IsRetryable,
+		buckets:     make(map[string]*bucket),
+	}
+	if o == nil {
+		return r
+	}
+
+	if o.MaxAttempts > 0 {
+		r.maxAttempts = o.MaxAttempts
+	}
+	if o.BaseDelay > 0 {
+		r.baseDelay = o.BaseDelay
+	}
+	if o.MaxDelay > 0 {
+		r.maxDelay = o.MaxDelay
+	}
+	if o.Multiplier >= 1 {
+		r.multiplier = o.Multiplier
+	}
+	if o.Jitter < 0 {
+		r.jitter = 0
+	} else if o.Jitter > 0 && o.Jitter <= 1 {
+		r.jitter = o.Jitter
+	}
+	if o.Retryable != nil {
+		r.retryable = o.Retryable
+	}
+	r.budget = o.Budget
+	return r
+}
+
+func (r *retrier) handle(ctx context.Context, next middleware.Handler, c middleware.Call) {
+	call := func() error {
+		next(ctx, c)
+		return c.Result()
+	}
+
+	switch v := c.(type) {
+	case *middleware.StatCall:
+		v.Err = r.do(ctx, c.Op(), call)
+	case *middleware.DeleteCall:
+		v.Err = r.do(ctx, c.Op(), call)
+	case *middleware.CreateDirCall:
+		v.Err = r.do(ctx, c.Op(), call)
+	case *middleware.ListCall:
+		v.Err = r.do(ctx, c.Op(), call)
+		if v.Err == nil {
+			v.Oi = r.wrapObjectIterator(ctx, c.Op(), v.Oi)
+		}
+	case *middleware.ReadCall:
+		r.handleRead(ctx, call, v)
+	case *middleware.WriteCall:
+		v.Err = r.doWithReader(ctx, c.Op(), v.R, call)
+	case *middleware.WriteMultipartCall:
+		v.Err = r.doWithReader(ctx, c.Op(), v.R, call)
+	default:
+		// All other operations are not idempotent, call them directly.
+		next(ctx, c)
+	}
+}
+
+// handleRead will resume from the written offset so that data which has
+// been written into w will not be written again.
+func (r *retrier) handleRead(ctx context.Context, call func() error, c *middleware.ReadCall) {
+	w, ps := c.W, c.Pairs
+	defer func() {
+		c.W, c.Pairs = w, ps
+	}()
+
+	var offset, size int64
+	hasSize := false
+	// Services will take the first pair if there are duplicate pairs.
+	for i := len(ps) - 1; i >= 0; i-- {
+		switch ps[i].Key {
+		case "offset":
+			offset = ps[i].Value.(int64)
+		case "size":
+			size, hasSize = ps[i].Value.(int64), true
+		}
+	}
+
+	cw := &countWriter{w: w}
+	c.W = cw
+
+	err := r.do(ctx, c.Op(), func() error {
+		if cw.n > 0 {
+			resume := []types.Pair{pairs.WithOffset(offset + cw.n)}
+			if hasSize {
+				resume = append(resume, pairs.WithSize(size-cw.n))
+			}
+			c.Pairs = append(resume, ps...)
+		}
+		return call()
+	})
+	c.N, c.Err = cw.n, err
+}
+
+// doWithReader will only retry while r could be seeked back.
+func (r *retrier) doWithReader(ctx context.Context, op string, rd io.Reader, call func() error) error {
+	if rd == nil {
+		return r.do(ctx, op, call)
+	}
+	s, ok := rd.(io.Seeker)
+	if !ok {
+		return call()
+	}
+	start, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return call()
+	}
+
+	first := true
+	return r.do(ctx, op, func() error {
+		if !first {
+			if _, err := s.Seek(start, io.SeekStart); err != nil {
+				return err
+			}
+		}
+		first = false
+		return call()
+	})
+}
+
+func (r *retrier) wrapObjectIterator(ctx context.Context, op string, it *types.ObjectIterator) *types.ObjectIterator {
+	fn := types.NextObjectFunc(func(ctx context.Context, page *types.ObjectPage) error {
+		var o *types.Object
+		err := r.do(ctx, op, func() (err error) {
+			o, err = it.Next()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		page.Data = append(page.Data, o)
+		return nil
+	})
+	return types.NewObjectIterator(ctx, fn, it)
+}
+
+func (r *retrier) do(ctx context.Context, op string, fn func() error) error {
+	b := r.bucket(op)
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			b.succeed()
+			return nil
+		}
+		if attempt >= r.maxAttempts || !r.retryable(err) {
+			return err
+		}
+		if !b.withdraw() {
+			return err
+		}
+
+		t := time.NewTimer(r.delay(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// delay returns the delay before the next attempt.
+func (r *retrier) delay(attempt int) time.Duration {
+	d := float64(r.baseDelay) * math.Pow(r.multiplier, float64(attempt-1))
+	if d > float64(r.maxDelay) {
+		d = float64(r.maxDelay)
+	}
+	d -= d * r.jitter * rand.Float64()
+	return time.Duration(d)
+}
+
+func (r *retrier) bucket(op string) *bucket {
+	if r.budget == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.buckets[op]
+	if !ok {
+		b = &bucket{
+			max:    r.budget.MaxTokens,
+			ratio:  r.budget.TokenRatio,
+			tokens: r.budget.MaxTokens,
+		}
+		r.buckets[op] = b
+	}
+	return b
+}
+
+type bucket struct {
+	max    float64
+	ratio  float64
+	tokens float64
+	mu     sync.Mutex
+}
+
+// withdraw will take a token from bucket and report whether retry is allowed.
+//
+// A nil bucket means there is no budget.
+func (b *bucket) withdraw() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens > 0 {
+		b.tokens--
+	}
+	return b.tokens > b.max/2
+}
+
+func (b *bucket) succeed() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens += b.ratio
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
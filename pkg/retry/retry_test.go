@@ -0,0 +1,179 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+type fakeStorager struct {
+	types.UnimplementedStorager
+
+	// failures is the number of calls which will fail before success.
+	failures int
+	calls    int
+	content  []byte
+	written  []byte
+}
+
+func (s *fakeStorager) fail() error {
+	s.calls++
+	if s.calls <= s.failures {
+		return services.StorageError{
+			Op:       "test",
+			Err:      fmt.Errorf("%w: %d", services.ErrRequestThrottled, s.calls),
+			Storager: s,
+		}
+	}
+	return nil
+}
+
+func (s *fakeStorager) StatWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	if err = s.fail(); err != nil {
+		return nil, err
+	}
+	return types.NewObject(s, true), nil
+}
+
+func (s *fakeStorager) ReadWithContext(ctx context.Context, path string, w io.Writer, ps ...types.Pair) (n int64, err error) {
+	var offset int64
+	for i := len(ps) - 1; i >= 0; i-- {
+		if ps[i].Key == "offset" {
+			offset = ps[i].Value.(int64)
+		}
+	}
+	content := s.content[offset:]
+
+	// Write half of the content before failing.
+	if err = s.fail(); err != nil {
+		written, _ := w.Write(content[:len(content)/2])
+		return int64(written), err
+	}
+	written, err := w.Write(content)
+	return int64(written), err
+}
+
+func (s *fakeStorager) WriteWithContext(ctx context.Context, path string, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	s.written, err = ioutil.ReadAll(r)
+	if err != nil {
+		return
+	}
+	if err = s.fail(); err != nil {
+		return 0, err
+	}
+	return int64(len(s.written)), nil
+}
+
+func (s *fakeStorager) WriteAppendWithContext(ctx context.Context, o *types.Object, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	return 0, s.fail()
+}
+
+var fastOptions = &Options{
+	MaxAttempts: 3,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    time.Millisecond,
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, IsRetryable(fmt.Errorf("%w: test", services.ErrServiceInternal)))
+	assert.True(t, IsRetryable(services.StorageError{Err: services.ErrRequestThrottled}))
+	assert.False(t, IsRetryable(services.ErrObjectNotExist))
+	assert.False(t, IsRetryable(errors.New("test")))
+}
+
+func TestNewRetrier(t *testing.T) {
+	assert.Equal(t, 0.2, newRetrier(nil).jitter)
+	// Zero and invalid jitter fall back to the default one.
+	assert.Equal(t, 0.2, newRetrier(fastOptions).jitter)
+	assert.Equal(t, 0.2, newRetrier(&Options{Jitter: 2}).jitter)
+	assert.Equal(t, 0.5, newRetrier(&Options{Jitter: 0.5}).jitter)
+	assert.Equal(t, float64(0), newRetrier(&Options{Jitter: NoJitter}).jitter)
+
+	r := newRetrier(&Options{BaseDelay: time.Second, Jitter: NoJitter})
+	assert.Equal(t, time.Second, r.delay(1))
+	assert.Equal(t, 2*time.Second, r.delay(2))
+}
+
+func TestRetry_Stat(t *testing.T) {
+	s := &fakeStorager{failures: 2}
+
+	_, err := New(s, fastOptions).Stat("test")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, s.calls)
+
+	s = &fakeStorager{failures: 3}
+	_, err = New(s, fastOptions).Stat("test")
+	assert.True(t, errors.Is(err, services.ErrRequestThrottled))
+	assert.Equal(t, 3, s.calls)
+}
+
+func TestRetry_Read(t *testing.T) {
+	s := &fakeStorager{failures: 1, content: []byte("0123456789")}
+
+	var buf bytes.Buffer
+	n, err := New(s, fastOptions).Read("test", &buf, pairs.WithOffset(2))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(8), n)
+	assert.Equal(t, "23456789", buf.String())
+}
+
+func TestRetry_Write(t *testing.T) {
+	s := &fakeStorager{failures: 2}
+
+	n, err := New(s, fastOptions).Write("test", bytes.NewReader([]byte("hello")), 5)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, "hello", string(s.written))
+
+	// Non-seekable reader will not be retried.
+	s = &fakeStorager{failures: 1}
+	_, err = New(s, fastOptions).Write("test", io.LimitReader(bytes.NewReader([]byte("hello")), 5), 5)
+	assert.True(t, errors.Is(err, services.ErrRequestThrottled))
+	assert.Equal(t, 1, s.calls)
+}
+
+func TestRetry_NotIdempotent(t *testing.T) {
+	s := &fakeStorager{failures: 1}
+
+	_, err := New(s, fastOptions).WriteAppend(types.NewObject(s, true), nil, 0)
+	assert.True(t, errors.Is(err, services.ErrRequestThrottled))
+	assert.Equal(t, 1, s.calls)
+}
+
+func TestRetry_Budget(t *testing.T) {
+	s := &fakeStorager{failures: 100}
+
+	store := New(s, &Options{
+		MaxAttempts: 10,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+		Budget:      &Budget{MaxTokens: 4, TokenRatio: 0.1},
+	})
+
+	_, err := store.Stat("test")
+	assert.NotNil(t, err)
+	// Tokens: 4 -> 3 (retry) -> 2 (stop)
+	assert.Equal(t, 2, s.calls)
+}
+
+func TestRetry_Context(t *testing.T) {
+	s := &fakeStorager{failures: 100}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := New(s, &Options{MaxAttempts: 10, BaseDelay: time.Hour}).StatWithContext(ctx, "test")
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 1, s.calls)
+}
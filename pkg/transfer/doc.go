@@ -0,0 +1,20 @@
+/*
+Package transfer provides a Transferer which copies objects between two Storagers.
+
+The strategy of every object is chosen by the Features of both sides:
+
+  - Copy if src and dst are the same Storager and dst supports copy.
+  - Multipart upload if dst supports create_multipart, write_multipart and complete_multipart.
+  - Block upload if dst supports create_block, write_block and combine_block.
+  - Append upload if dst supports create_append, write_append and commit_append.
+  - Write otherwise.
+
+Objects not larger than Options.PartSize will always be written directly if
+dst supports write.
+
+Data will be streamed from src into dst without buffering whole objects, and
+parts of multipart and block uploads will be transferred in parallel.
+
+content_type, content_md5 and user_metadata of src objects will be preserved if dst supports them.
+*/
+package transfer
@@ -0,0 +1,514 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/pkg/iowrap"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// DefaultPartSize is the default size of parts in multipart, block and append uploads.
+const DefaultPartSize = 16 * 1024 * 1024
+
+// Strategy is the way to transfer an object.
+type Strategy int
+
+// All available strategies.
+const (
+	StrategyCopy Strategy = iota + 1
+	StrategyMultipart
+	StrategyBlock
+	StrategyAppend
+	StrategyWrite
+)
+
+// String implements fmt.Stringer
+func (s Strategy) String() string {
+	switch s {
+	case StrategyCopy:
+		return "copy"
+	case StrategyMultipart:
+		return "multipart"
+	case StrategyBlock:
+		return "block"
+	case StrategyAppend:
+		return "append"
+	case StrategyWrite:
+		return "write"
+	default:
+		return fmt.Sprintf("Strategy(%d)", int(s))
+	}
+}
+
+// Progress is the progress of an object transfer.
+type Progress struct {
+	Src string
+	Dst string
+
+	// Bytes is the bytes that have been transferred.
+	Bytes int64
+	// Total is the size of the object.
+	Total int64
+}
+
+// Options is the transfer supported options.
+type Options struct {
+	// PartSize is the size of parts, DefaultPartSize by default.
+	//
+	// PartSize will be adjusted to satisfy the multipart restrictions of dst.
+	PartSize int64
+	// Parallelism is the max number of concurrent reads and writes, 4 by default.
+	Parallelism int
+	// Progress will be called while objects are transferring, calls are serialized.
+	Progress func(p Progress)
+}
+
+// Job is an object to be transferred.
+type Job struct {
+	Src string
+	Dst string
+}
+
+// Transferer copies objects from src to dst.
+type Transferer struct {
+	src types.Storager
+	dst types.Storager
+
+	partSize int64
+	progress func(p Progress)
+	// sem limits the concurrent reads and writes.
+	sem chan struct{}
+	// mu serializes progress calls.
+	mu sync.Mutex
+}
+
+// New will create a Transferer from src to dst.
+func New(src, dst types.Storager, o *Options) *Transferer {
+	t := &Transferer{
+		src:      src,
+		dst:      dst,
+		partSize: DefaultPartSize,
+	}
+	parallelism := 4
+	if o != nil {
+		if o.PartSize > 0 {
+			t.partSize = o.PartSize
+		}
+		if o.Parallelism > 0 {
+			parallelism = o.Parallelism
+		}
+		t.progress = o.Progress
+	}
+	t.sem = make(chan struct{}, parallelism)
+	return t
+}
+
+// Strategy returns the strategy which will be used to transfer objects.
+//
+// Objects not larger than PartSize will be transferred via StrategyWrite
+// instead of multipart, block or append uploads if dst supports write.
+func (t *Transferer) Strategy() Strategy {
+	f := t.dst.Features()
+	switch {
+	case t.src == t.dst && f.Copy:
+		return StrategyCopy
+	case f.CreateMultipart && f.WriteMultipart && f.CompleteMultipart:
+		return StrategyMultipart
+	case f.CreateBlock && f.WriteBlock && f.CombineBlock:
+		return StrategyBlock
+	case f.CreateAppend && f.WriteAppend && f.CommitAppend:
+		return StrategyAppend
+	default:
+		return StrategyWrite
+	}
+}
+
+// Copy will copy object src into dst.
+func (t *Transferer) Copy(ctx context.Context, src, dst string) (err error) {
+	strategy := t.Strategy()
+	if strategy == StrategyCopy {
+		t.acquire()
+		defer t.release()
+		return t.dst.CopyWithContext(ctx, src, dst)
+	}
+
+	o, err := t.src.StatWithContext(ctx, src)
+	if err != nil {
+		return err
+	}
+	if !o.Mode.IsRead() {
+		return services.StorageError{
+			Op:       "transfer",
+			Err:      services.ErrObjectModeInvalid,
+			Storager: t.src,
+			Path:     []string{src},
+		}
+	}
+	size, ok := o.GetContentLength()
+	if !ok {
+		return fmt.Errorf("transfer %s: content length of src is unknown", src)
+	}
+	// A single write saves the round trips of creating and completing uploads.
+	if size <= t.partSize && t.dst.Features().Write {
+		strategy = StrategyWrite
+	}
+
+	tr := &transfer{
+		Transferer: t,
+		srcPath:    src,
+		dstPath:    dst,
+		size:       size,
+		meta:       metadataPairs(o),
+	}
+	switch strategy {
+	case StrategyMultipart:
+		return tr.multipart(ctx)
+	case StrategyBlock:
+		return tr.block(ctx)
+	case StrategyAppend:
+		return tr.append(ctx)
+	default:
+		return tr.write(ctx)
+	}
+}
+
+// CopyAll will copy all jobs with limited parallelism.
+//
+// All jobs will be tried, and the first error will be returned.
+func (t *Transferer) CopyAll(ctx context.Context, jobs []Job) error {
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	// Limit the pending objects so that we will not stat too many objects ahead.
+	pending := make(chan struct{}, cap(t.sem))
+	for _, j := range jobs {
+		pending <- struct{}{}
+		wg.Add(1)
+		go func(j Job) {
+			defer func() {
+				<-pending
+				wg.Done()
+			}()
+			if err := t.Copy(ctx, j.Src, j.Dst); err != nil {
+				once.Do(func() { firstErr = fmt.Errorf("transfer %s to %s: %w", j.Src, j.Dst, err) })
+			}
+		}(j)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func (t *Transferer) acquire() {
+	t.sem <- struct{}{}
+}
+
+func (t *Transferer) release() {
+	<-t.sem
+}
+
+// metadataPairs returns pairs to preserve metadata of o.
+func metadataPairs(o *types.Object) []types.Pair {
+	ps := make([]types.Pair, 0, 3)
+	if v, ok := o.GetContentType(); ok && v != "" {
+		ps = append(ps, pairs.WithContentType(v))
+	}
+	if v, ok := o.GetContentMd5(); ok && v != "" {
+		ps = append(ps, pairs.WithContentMd5(v))
+	}
+	if v, ok := o.GetUserMetadata(); ok && len(v) > 0 {
+		ps = append(ps, pairs.WithUserMetadata(v))
+	}
+	return ps
+}
+
+// transfer is the state of a single object transfer.
+type transfer struct {
+	*Transferer
+
+	srcPath string
+	dstPath string
+	size    int64
+	meta    []types.Pair
+
+	bytes int64
+}
+
+// partSizeFor returns the part size satisfied dst restrictions.
+func (t *transfer) partSizeFor(multipart bool) int64 {
+	partSize := t.partSize
+	if !multipart {
+		return partSize
+	}
+
+	meta := t.dst.Metadata()
+	if v, ok := meta.GetMultipartSizeMinimum(); ok && partSize < v {
+		partSize = v
+	}
+	if v, ok := meta.GetMultipartNumberMaximum(); ok && v > 0 {
+		if x := (t.size + int64(v) - 1) / int64(v); partSize < x {
+			partSize = x
+		}
+	}
+	if v, ok := meta.GetMultipartSizeMaximum(); ok && v > 0 && partSize > v {
+		partSize = v
+	}
+	return partSize
+}
+
+// parts splits the object into parts, there is always one part for empty object.
+func (t *transfer) parts(partSize int64) [][2]int64 {
+	if t.size == 0 {
+		return [][2]int64{{0, 0}}
+	}
+	ps := make([][2]int64, 0, (t.size+partSize-1)/partSize)
+	for offset := int64(0); offset < t.size; offset += partSize {
+		size := partSize
+		if offset+size > t.size {
+			size = t.size - offset
+		}
+		ps = append(ps, [2]int64{offset, size})
+	}
+	return ps
+}
+
+// pipe will stream size bytes at offset of src into fn.
+func (t *transfer) pipe(ctx context.Context, offset, size int64, fn func(r io.Reader) error) error {
+	if size == 0 {
+		return fn(iowrap.CallbackReader(eofReader{}, t.addBytes))
+	}
+
+	pr, pw := io.Pipe()
+	errc := make(chan error, 1)
+	go func() {
+		ps := make([]types.Pair, 0, 2)
+		if offset != 0 || size != t.size {
+			ps = append(ps, pairs.WithOffset(offset), pairs.WithSize(size))
+		}
+		_, err := t.src.ReadWithContext(ctx, t.srcPath, pw, ps...)
+		_ = pw.CloseWithError(err)
+		errc <- err
+	}()
+
+	err := fn(iowrap.CallbackReader(pr, t.addBytes))
+	if err != nil {
+		_ = pr.CloseWithError(err)
+	} else {
+		// Read will fail with io.ErrClosedPipe if fn doesn't read all data.
+		_ = pr.Close()
+	}
+	if rerr := <-errc; err == nil {
+		err = rerr
+	}
+	return err
+}
+
+func (t *transfer) addBytes(b []byte) {
+	if t.progress == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.bytes += int64(len(b))
+	t.progress(Progress{
+		Src:   t.srcPath,
+		Dst:   t.dstPath,
+		Bytes: t.bytes,
+		Total: t.size,
+	})
+}
+
+// withPairs will call fn with ps, and drop pairs that are not supported by dst.
+//
+// Pairs are parsed before any data read, so it's safe to call fn again with the same reader.
+func withPairs(ps []types.Pair, fn func(ps []types.Pair) error) error {
+	for {
+		err := fn(ps)
+		var e services.PairUnsupportedError
+		if !errors.As(err, &e) {
+			return err
+		}
+
+		x := ps[:0:0]
+		for _, p := range ps {
+			if p.Key != e.Pair.Key {
+				x = append(x, p)
+			}
+		}
+		if len(x) == len(ps) {
+			return err
+		}
+		ps = x
+	}
+}
+
+func (t *transfer) write(ctx context.Context) error {
+	t.acquire()
+	defer t.release()
+
+	return t.pipe(ctx, 0, t.size, func(r io.Reader) error {
+		return withPairs(t.meta, func(ps []types.Pair) error {
+			_, err := t.dst.WriteWithContext(ctx, t.dstPath, r, t.size, ps...)
+			return err
+		})
+	})
+}
+
+func (t *transfer) multipart(ctx context.Context) (err error) {
+	var o *types.Object
+	err = withPairs(contentTypePairs(t.meta), func(ps []types.Pair) (err error) {
+		o, err = t.dst.CreateMultipartWithContext(ctx, t.dstPath, ps...)
+		return
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			// Abort the multipart upload, ctx could have been canceled.
+			_ = t.dst.DeleteWithContext(context.Background(), t.dstPath, pairs.WithMultipartID(o.MustGetMultipartID()))
+		}
+	}()
+
+	chunks := t.parts(t.partSizeFor(true))
+	parts := make([]*types.Part, len(chunks))
+	err = t.parallel(ctx, chunks, func(ctx context.Context, idx int, offset, size int64) error {
+		return t.pipe(ctx, offset, size, func(r io.Reader) error {
+			_, part, err := t.dst.WriteMultipartWithContext(ctx, o, r, size, idx)
+			parts[idx] = part
+			return err
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Index < parts[j].Index })
+	return t.dst.CompleteMultipartWithContext(ctx, o, parts)
+}
+
+func (t *transfer) block(ctx context.Context) error {
+	var o *types.Object
+	err := withPairs(contentTypePairs(t.meta), func(ps []types.Pair) (err error) {
+		o, err = t.dst.CreateBlockWithContext(ctx, t.dstPath, ps...)
+		return
+	})
+	if err != nil {
+		return err
+	}
+
+	chunks := t.parts(t.partSize)
+	bids := make([]string, len(chunks))
+	for k := range bids {
+		bids[k] = blockID(k)
+	}
+	err = t.parallel(ctx, chunks, func(ctx context.Context, idx int, offset, size int64) error {
+		return t.pipe(ctx, offset, size, func(r io.Reader) error {
+			_, err := t.dst.WriteBlockWithContext(ctx, o, r, size, bids[idx])
+			return err
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return t.dst.CombineBlockWithContext(ctx, o, bids)
+}
+
+func (t *transfer) append(ctx context.Context) error {
+	var o *types.Object
+	err := withPairs(contentTypePairs(t.meta), func(ps []types.Pair) (err error) {
+		o, err = t.dst.CreateAppendWithContext(ctx, t.dstPath, ps...)
+		return
+	})
+	if err != nil {
+		return err
+	}
+
+	t.acquire()
+	defer t.release()
+
+	// Appends must be sequential.
+	for _, c := range t.parts(t.partSize) {
+		size := c[1]
+		err = t.pipe(ctx, c[0], size, func(r io.Reader) error {
+			_, err := t.dst.WriteAppendWithContext(ctx, o, r, size)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return t.dst.CommitAppendWithContext(ctx, o)
+}
+
+// parallel calls fn for every chunk with limited parallelism, and returns the first error.
+func (t *transfer) parallel(ctx context.Context, chunks [][2]int64, fn func(ctx context.Context, idx int, offset, size int64) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for k, c := range chunks {
+		t.acquire()
+		if ctx.Err() != nil {
+			t.release()
+			break
+		}
+
+		wg.Add(1)
+		go func(idx int, offset, size int64) {
+			defer func() {
+				t.release()
+				wg.Done()
+			}()
+			if err := fn(ctx, idx, offset, size); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(k, c[0], c[1])
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// contentTypePairs returns pairs that are valid for creating multipart, block or append objects.
+func contentTypePairs(ps []types.Pair) []types.Pair {
+	x := make([]types.Pair, 0, len(ps))
+	for _, p := range ps {
+		// content_md5 is the md5 of whole object, which can't be used for parts.
+		if p.Key != "content_md5" {
+			x = append(x, p)
+		}
+	}
+	return x
+}
+
+// blockID returns the id of block idx, all ids have the same length.
+func blockID(idx int) string {
+	return fmt.Sprintf("%016d", idx)
+}
+
+type eofReader struct{}
+
+func (eofReader) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
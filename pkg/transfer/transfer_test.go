@@ -0,0 +1,152 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/internal/storagetest"
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+func newStorager() *storagetest.Storager {
+	s := storagetest.New()
+	s.StorageFeatures = types.StorageFeatures{Read: true, Write: true, Stat: true, Copy: true}
+	s.Pairs = map[string][]string{"write": {"content_type"}}
+	return s
+}
+
+func newMultipartStorager() *storagetest.Storager {
+	s := newStorager()
+	s.StorageFeatures = types.StorageFeatures{
+		Read: true, Write: true, Stat: true, Delete: true,
+		CreateMultipart: true, WriteMultipart: true, CompleteMultipart: true,
+	}
+	s.StorageMeta.SetMultipartSizeMinimum(1024)
+	return s
+}
+
+func randBytes(size int) []byte {
+	b := make([]byte, size)
+	rand.Read(b)
+	return b
+}
+
+func TestWrite(t *testing.T) {
+	src, dst := newStorager(), newStorager()
+	content := randBytes(4096)
+	src.Put("a", content).ContentType = "text/plain"
+
+	var last Progress
+	tr := New(src, dst, &Options{Progress: func(p Progress) { last = p }})
+	assert.Equal(t, StrategyWrite, tr.Strategy())
+
+	assert.NoError(t, tr.Copy(context.Background(), "a", "b"))
+	assert.Equal(t, content, dst.Data("b"))
+	assert.Equal(t, "text/plain", dst.Object("b").ContentType)
+	assert.Equal(t, Progress{Src: "a", Dst: "b", Bytes: 4096, Total: 4096}, last)
+
+	// Empty object.
+	src.Put("empty", []byte{})
+	assert.NoError(t, tr.Copy(context.Background(), "empty", "empty"))
+	assert.Len(t, dst.Data("empty"), 0)
+}
+
+func TestCopy(t *testing.T) {
+	store := newStorager()
+	store.Put("a", []byte("hello"))
+
+	tr := New(store, store, nil)
+	assert.Equal(t, StrategyCopy, tr.Strategy())
+	assert.NoError(t, tr.Copy(context.Background(), "a", "b"))
+	assert.Equal(t, 1, store.Count("copy"))
+	assert.Equal(t, []byte("hello"), store.Data("b"))
+}
+
+func TestMultipart(t *testing.T) {
+	src, dst := newStorager(), newMultipartStorager()
+	content := randBytes(10*1024 + 1)
+	src.Put("a", content)
+
+	// PartSize will be adjusted to multipart_size_minimum.
+	tr := New(src, dst, &Options{PartSize: 100, Parallelism: 3})
+	assert.Equal(t, StrategyMultipart, tr.Strategy())
+	assert.NoError(t, tr.Copy(context.Background(), "a", "b"))
+	assert.Equal(t, content, dst.Data("b"))
+	assert.Equal(t, 11, dst.Count("write_multipart"))
+
+	// Objects not larger than PartSize will be written directly.
+	dst.ResetCalls()
+	small := New(src, dst, &Options{PartSize: int64(len(content))})
+	assert.NoError(t, small.Copy(context.Background(), "a", "small"))
+	assert.Equal(t, content, dst.Data("small"))
+	assert.Equal(t, 0, dst.Count("write_multipart"))
+
+	dst.Fail = func(c storagetest.Call) error {
+		if c.Op == "write_multipart" && c.Index == 1 {
+			return services.ErrServiceInternal
+		}
+		return nil
+	}
+	assert.ErrorIs(t, tr.Copy(context.Background(), "a", "c"), services.ErrServiceInternal)
+	assert.Equal(t, 1, dst.Count("delete"))
+	assert.Empty(t, dst.Uploads())
+}
+
+func TestCopyAll(t *testing.T) {
+	src, dst := newStorager(), newStorager()
+
+	jobs := make([]Job, 0)
+	for i := 0; i < 20; i++ {
+		p := fmt.Sprintf("%d", i)
+		src.Put(p, randBytes(100))
+		jobs = append(jobs, Job{Src: p, Dst: p})
+	}
+
+	tr := New(src, dst, &Options{Parallelism: 4})
+	assert.NoError(t, tr.CopyAll(context.Background(), jobs))
+
+	assert.Len(t, dst.Paths(), 20)
+	for _, k := range dst.Paths() {
+		assert.Equal(t, src.Data(k), dst.Data(k))
+	}
+
+	jobs = append(jobs, Job{Src: "not-exist", Dst: "x"})
+	assert.ErrorIs(t, tr.CopyAll(context.Background(), jobs), services.ErrObjectNotExist)
+}
+
+func TestPairUnsupported(t *testing.T) {
+	src, dst := newStorager(), newStorager()
+	src.Put("a", []byte("hello"))
+
+	o, err := src.StatWithContext(context.Background(), "a")
+	assert.NoError(t, err)
+	o.SetContentMd5("XUFAKrxLKna5cZ2REBfFkg==")
+
+	// content_md5 is not supported by dst and will be dropped.
+	var got []types.Pair
+	err = withPairs(metadataPairs(o), func(ps []types.Pair) error {
+		got = ps
+		_, err := dst.WriteWithContext(context.Background(), "b", bytes.NewReader([]byte("hello")), 5, ps...)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestMetadataPairs(t *testing.T) {
+	o := types.NewObject(nil, true)
+	o.SetContentType("text/plain")
+	o.SetUserMetadata(map[string]string{"stage": "2"})
+
+	assert.Equal(t, []types.Pair{
+		pairs.WithContentType("text/plain"),
+		pairs.WithUserMetadata(map[string]string{"stage": "2"}),
+	}, metadataPairs(o))
+}
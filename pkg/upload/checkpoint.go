@@ -0,0 +1,164 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// Checkpoint is the persisted state of an upload.
+type Checkpoint struct {
+	// Path is the path of the object to upload.
+	Path string `json:"path"`
+	// MultipartID is the id of the multipart upload.
+	MultipartID string `json:"multipart_id"`
+	// Size is the size of the source.
+	Size int64 `json:"size"`
+	// PartSize is the size of every part except the last one.
+	PartSize int64 `json:"part_size"`
+	// Offset is the offset of the source that all data before it has been uploaded.
+	Offset int64 `json:"offset"`
+	// Parts are the completed parts.
+	Parts []*types.Part `json:"parts"`
+}
+
+// CheckpointStore persists checkpoints by key.
+type CheckpointStore interface {
+	// Load returns the checkpoint of key, nil will be returned if not exist.
+	Load(ctx context.Context, key string) (*Checkpoint, error)
+	// Save will save the checkpoint of key.
+	Save(ctx context.Context, key string, c *Checkpoint) error
+	// Delete will delete the checkpoint of key, it's ok to delete not exist checkpoint.
+	Delete(ctx context.Context, key string) error
+}
+
+// checkpointName returns the file name of the checkpoint of key.
+func checkpointName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+type fileCheckpointStore struct {
+	dir string
+}
+
+// NewFileCheckpointStore will create a CheckpointStore which stores checkpoints in local dir.
+func NewFileCheckpointStore(dir string) CheckpointStore {
+	return &fileCheckpointStore{dir: dir}
+}
+
+func (s *fileCheckpointStore) Load(ctx context.Context, key string) (*Checkpoint, error) {
+	content, err := ioutil.ReadFile(filepath.Join(s.dir, checkpointName(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Checkpoint{}
+	if err = json.Unmarshal(content, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *fileCheckpointStore) Save(ctx context.Context, key string, c *Checkpoint) error {
+	content, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	// Write to a temp file and rename, so that the checkpoint will not be broken by crash.
+	f, err := ioutil.TempFile(s.dir, ".checkpoint-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Remove the temp file if rename failed.
+		_ = os.Remove(f.Name())
+	}()
+
+	_, err = f.Write(content)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(s.dir, checkpointName(key)))
+}
+
+func (s *fileCheckpointStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(filepath.Join(s.dir, checkpointName(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+type storagerCheckpointStore struct {
+	store types.Storager
+	dir   string
+}
+
+// NewStoragerCheckpointStore will create a CheckpointStore which stores checkpoints in dir of store.
+func NewStoragerCheckpointStore(store types.Storager, dir string) CheckpointStore {
+	return &storagerCheckpointStore{store: store, dir: dir}
+}
+
+func (s *storagerCheckpointStore) path(key string) string {
+	if s.dir == "" {
+		return checkpointName(key)
+	}
+	return s.dir + "/" + checkpointName(key)
+}
+
+func (s *storagerCheckpointStore) Load(ctx context.Context, key string) (*Checkpoint, error) {
+	var buf bytes.Buffer
+	_, err := s.store.ReadWithContext(ctx, s.path(key), &buf)
+	if errors.Is(err, services.ErrObjectNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Checkpoint{}
+	if err = json.Unmarshal(buf.Bytes(), c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *storagerCheckpointStore) Save(ctx context.Context, key string, c *Checkpoint) error {
+	content, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = s.store.WriteWithContext(ctx, s.path(key), bytes.NewReader(content), int64(len(content)))
+	return err
+}
+
+func (s *storagerCheckpointStore) Delete(ctx context.Context, key string) error {
+	err := s.store.DeleteWithContext(ctx, s.path(key))
+	if errors.Is(err, services.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
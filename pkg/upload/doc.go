@@ -0,0 +1,12 @@
+/*
+Package upload provides resumable multipart uploads.
+
+Upload persists a Checkpoint after every part uploaded. If the upload is
+interrupted, calling Upload again with the same CheckpointStore will reconcile
+the checkpoint with parts listed by ListMultipart, and only upload the missing
+parts.
+
+Checkpoints could be stored in a local dir via NewFileCheckpointStore, or in
+any Storager via NewStoragerCheckpointStore.
+*/
+package upload
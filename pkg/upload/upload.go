@@ -0,0 +1,284 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// DefaultPartSize is the default size of parts.
+const DefaultPartSize = 16 * 1024 * 1024
+
+// Options is the options for Upload.
+type Options struct {
+	// PartSize is the size of every part, DefaultPartSize by default.
+	//
+	// PartSize will be adjusted to satisfy the multipart restrictions of the service.
+	PartSize int64
+	// Checkpoints is where checkpoints will be persisted, the upload could not
+	// be resumed if it's nil.
+	Checkpoints CheckpointStore
+	// Key is the key of the checkpoint, the path of the object by default.
+	Key string
+	// Pairs will be passed to CreateMultipart.
+	Pairs []types.Pair
+}
+
+// Upload will upload size bytes from r to path with multipart operations.
+//
+// If a checkpoint of the same object, size and part size exists, parts
+// that have been uploaded will be reconciled via ListMultipart and skipped.
+// The checkpoint will be kept on error so that the upload could be resumed,
+// and will be deleted after the upload completed.
+func Upload(ctx context.Context, store types.Storager, path string, r io.ReaderAt, size int64, o *Options) (err error) {
+	f := store.Features()
+	if !(f.CreateMultipart && f.WriteMultipart && f.CompleteMultipart && f.ListMultipart) {
+		return formatError(path, services.ErrCapabilityInsufficient)
+	}
+
+	u := &uploader{
+		store:    store,
+		path:     path,
+		key:      path,
+		size:     size,
+		partSize: DefaultPartSize,
+	}
+	if o != nil {
+		if o.PartSize > 0 {
+			u.partSize = o.PartSize
+		}
+		if o.Key != "" {
+			u.key = o.Key
+		}
+		u.checkpoints = o.Checkpoints
+		u.pairs = o.Pairs
+	}
+	u.adjustPartSize()
+
+	defer func() {
+		err = formatError(path, err)
+	}()
+
+	if err = u.resume(ctx); err != nil {
+		return err
+	}
+	if u.o == nil {
+		if err = u.start(ctx); err != nil {
+			return err
+		}
+	}
+
+	for idx := range u.parts {
+		if u.parts[idx] != nil {
+			continue
+		}
+		if err = u.upload(ctx, r, idx); err != nil {
+			return err
+		}
+	}
+
+	if err = store.CompleteMultipartWithContext(ctx, u.o, u.parts); err != nil {
+		return err
+	}
+	if u.checkpoints != nil {
+		return u.checkpoints.Delete(ctx, u.key)
+	}
+	return nil
+}
+
+// Abort will abort the upload recorded by the checkpoint of key and delete the checkpoint.
+func Abort(ctx context.Context, store types.Storager, checkpoints CheckpointStore, key string) error {
+	c, err := checkpoints.Load(ctx, key)
+	if err != nil || c == nil {
+		return err
+	}
+
+	err = store.DeleteWithContext(ctx, c.Path, pairs.WithMultipartID(c.MultipartID))
+	if err != nil && !errors.Is(err, services.ErrObjectNotExist) {
+		return formatError(c.Path, err)
+	}
+	return checkpoints.Delete(ctx, key)
+}
+
+type uploader struct {
+	store       types.Storager
+	checkpoints CheckpointStore
+	pairs       []types.Pair
+
+	path     string
+	key      string
+	size     int64
+	partSize int64
+
+	o *types.Object
+	// parts will be nil if the part has not been uploaded.
+	parts []*types.Part
+}
+
+// adjustPartSize adjusts part size to satisfy the restrictions of store.
+func (u *uploader) adjustPartSize() {
+	meta := u.store.Metadata()
+	if v, ok := meta.GetMultipartSizeMinimum(); ok && u.partSize < v {
+		u.partSize = v
+	}
+	if v, ok := meta.GetMultipartNumberMaximum(); ok && v > 0 {
+		if x := (u.size + int64(v) - 1) / int64(v); u.partSize < x {
+			u.partSize = x
+		}
+	}
+	if v, ok := meta.GetMultipartSizeMaximum(); ok && v > 0 && u.partSize > v {
+		u.partSize = v
+	}
+}
+
+// partRange returns the offset and size of part idx.
+func (u *uploader) partRange(idx int) (offset, size int64) {
+	offset = int64(idx) * u.partSize
+	size = u.partSize
+	if offset+size > u.size {
+		size = u.size - offset
+	}
+	return
+}
+
+// partCount returns the count of parts, there is always one part for empty object.
+func (u *uploader) partCount() int {
+	if u.size == 0 {
+		return 1
+	}
+	return int((u.size + u.partSize - 1) / u.partSize)
+}
+
+// resume loads the checkpoint and reconciles it with the parts listed from store.
+//
+// The upload will be started from scratch if the checkpoint doesn't match or
+// the multipart is not available anymore, and the stale multipart will be
+// aborted first. Other errors will be returned as is.
+func (u *uploader) resume(ctx context.Context) error {
+	if u.checkpoints == nil {
+		return nil
+	}
+	c, err := u.checkpoints.Load(ctx, u.key)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return nil
+	}
+	if c.Path != u.path || c.Size != u.size || c.PartSize != u.partSize {
+		// The stale upload will never be completed, abort it.
+		return u.abort(ctx, c)
+	}
+
+	listed, o, err := u.list(ctx, c)
+	if errors.Is(err, services.ErrObjectNotExist) {
+		// The multipart is not available anymore, abort it in case it's
+		// only invisible to stat or list.
+		return u.abort(ctx, c)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Parts listed from store are the truth, parts uploaded but not saved
+	// in checkpoint will also be picked up.
+	parts := make([]*types.Part, u.partCount())
+	for _, p := range listed {
+		if p.Index < 0 || p.Index >= len(parts) {
+			continue
+		}
+		if _, size := u.partRange(p.Index); p.Size != size {
+			continue
+		}
+		parts[p.Index] = p
+	}
+	u.o, u.parts = o, parts
+	return nil
+}
+
+// list returns the multipart recorded by c and its uploaded parts.
+func (u *uploader) list(ctx context.Context, c *Checkpoint) ([]*types.Part, *types.Object, error) {
+	o, err := u.store.StatWithContext(ctx, u.path, pairs.WithMultipartID(c.MultipartID))
+	if err != nil {
+		return nil, nil, err
+	}
+	it, err := u.store.ListMultipartWithContext(ctx, o)
+	if err != nil {
+		return nil, nil, err
+	}
+	parts, err := it.Collect()
+	if err != nil {
+		return nil, nil, err
+	}
+	return parts, o, nil
+}
+
+// abort aborts the multipart recorded by c, so that the upload could be
+// started from scratch. The checkpoint will be overwritten by start.
+func (u *uploader) abort(ctx context.Context, c *Checkpoint) error {
+	err := u.store.DeleteWithContext(ctx, c.Path, pairs.WithMultipartID(c.MultipartID))
+	if err != nil && !errors.Is(err, services.ErrObjectNotExist) {
+		return err
+	}
+	return nil
+}
+
+// start creates a new multipart.
+func (u *uploader) start(ctx context.Context) (err error) {
+	u.o, err = u.store.CreateMultipartWithContext(ctx, u.path, u.pairs...)
+	if err != nil {
+		return err
+	}
+	u.parts = make([]*types.Part, u.partCount())
+	return u.save(ctx)
+}
+
+func (u *uploader) upload(ctx context.Context, r io.ReaderAt, idx int) error {
+	offset, size := u.partRange(idx)
+	_, part, err := u.store.WriteMultipartWithContext(ctx, u.o, io.NewSectionReader(r, offset, size), size, idx)
+	if err != nil {
+		return err
+	}
+	u.parts[idx] = part
+	return u.save(ctx)
+}
+
+// save persists the checkpoint.
+func (u *uploader) save(ctx context.Context) error {
+	if u.checkpoints == nil {
+		return nil
+	}
+
+	c := &Checkpoint{
+		Path:        u.path,
+		MultipartID: u.o.MustGetMultipartID(),
+		Size:        u.size,
+		PartSize:    u.partSize,
+		Parts:       make([]*types.Part, 0, len(u.parts)),
+	}
+	contiguous := true
+	for idx, p := range u.parts {
+		if p == nil {
+			contiguous = false
+			continue
+		}
+		c.Parts = append(c.Parts, p)
+		if contiguous {
+			_, size := u.partRange(idx)
+			c.Offset += size
+		}
+	}
+	return u.checkpoints.Save(ctx, u.key, c)
+}
+
+func formatError(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("upload %s: %w", path, err)
+}
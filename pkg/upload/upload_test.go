@@ -0,0 +1,209 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/internal/storagetest"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+func newStorager() *storagetest.Storager {
+	s := storagetest.New()
+	s.StorageFeatures = types.StorageFeatures{
+		Read: true, Write: true, Stat: true, Delete: true,
+		CreateMultipart: true, WriteMultipart: true, CompleteMultipart: true, ListMultipart: true,
+	}
+	s.StorageMeta.SetMultipartSizeMinimum(100)
+	return s
+}
+
+// failPart makes WriteMultipart fail at part index.
+func failPart(index int) func(c storagetest.Call) error {
+	return func(c storagetest.Call) error {
+		if c.Op == "write_multipart" && c.Index == index {
+			return services.ErrServiceInternal
+		}
+		return nil
+	}
+}
+
+// failStat makes Stat return err.
+func failStat(err error) func(c storagetest.Call) error {
+	return func(c storagetest.Call) error {
+		if c.Op == "stat" {
+			return err
+		}
+		return nil
+	}
+}
+
+// written returns indexes of parts written.
+func written(s *storagetest.Storager) []int {
+	indexes := make([]int, 0)
+	for _, c := range s.Calls("write_multipart") {
+		indexes = append(indexes, c.Index)
+	}
+	return indexes
+}
+
+// failedCheckpointStore fails Save after n saves.
+type failedCheckpointStore struct {
+	CheckpointStore
+	n int
+}
+
+func (s *failedCheckpointStore) Save(ctx context.Context, key string, c *Checkpoint) error {
+	if s.n == 0 {
+		return errors.New("save failed")
+	}
+	s.n--
+	return s.CheckpointStore.Save(ctx, key, c)
+}
+
+func randBytes(size int) []byte {
+	b := make([]byte, size)
+	rand.Read(b)
+	return b
+}
+
+func TestUploadResume(t *testing.T) {
+	ctx := context.Background()
+	content := randBytes(1050)
+
+	for name, checkpoints := range map[string]CheckpointStore{
+		"file":     NewFileCheckpointStore(t.TempDir()),
+		"storager": NewStoragerCheckpointStore(newStorager(), "checkpoints"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := newStorager()
+			store.Fail = failPart(4)
+			o := &Options{PartSize: 100, Checkpoints: checkpoints}
+
+			err := Upload(ctx, store, "a", bytes.NewReader(content), int64(len(content)), o)
+			assert.ErrorIs(t, err, services.ErrServiceInternal)
+
+			c, err := checkpoints.Load(ctx, "a")
+			assert.Nil(t, err)
+			assert.Equal(t, int64(100), c.PartSize)
+			assert.Equal(t, int64(400), c.Offset)
+			assert.Len(t, c.Parts, 4)
+
+			// Only the missing parts will be uploaded after resumed.
+			store.Fail = nil
+			store.ResetCalls()
+			err = Upload(ctx, store, "a", bytes.NewReader(content), int64(len(content)), o)
+			assert.Nil(t, err)
+			assert.Equal(t, content, store.Data("a"))
+			assert.Equal(t, []int{4, 5, 6, 7, 8, 9, 10}, written(store))
+
+			c, err = checkpoints.Load(ctx, "a")
+			assert.Nil(t, err)
+			assert.Nil(t, c)
+		})
+	}
+}
+
+func TestUploadReconcile(t *testing.T) {
+	ctx := context.Background()
+	content := randBytes(500)
+	store := newStorager()
+
+	// Checkpoint failed to save after part 1 uploaded.
+	checkpoints := NewFileCheckpointStore(t.TempDir())
+	o := &Options{PartSize: 100, Checkpoints: &failedCheckpointStore{CheckpointStore: checkpoints, n: 2}}
+	err := Upload(ctx, store, "a", bytes.NewReader(content), int64(len(content)), o)
+	assert.Error(t, err)
+
+	c, err := checkpoints.Load(ctx, "a")
+	assert.Nil(t, err)
+	assert.Len(t, c.Parts, 1)
+
+	// Parts listed from store will be picked up.
+	store.ResetCalls()
+	err = Upload(ctx, store, "a", bytes.NewReader(content), int64(len(content)), &Options{PartSize: 100, Checkpoints: checkpoints})
+	assert.Nil(t, err)
+	assert.Equal(t, content, store.Data("a"))
+	assert.Equal(t, []int{2, 3, 4}, written(store))
+}
+
+func TestUploadMismatch(t *testing.T) {
+	ctx := context.Background()
+	store := newStorager()
+	checkpoints := NewFileCheckpointStore(t.TempDir())
+	store.Fail = failPart(1)
+
+	err := Upload(ctx, store, "a", bytes.NewReader(randBytes(300)), 300, &Options{PartSize: 100, Checkpoints: checkpoints})
+	assert.Error(t, err)
+	assert.Len(t, store.Uploads(), 1)
+
+	// Source changed, the stale upload will be aborted.
+	store.Fail = nil
+	store.ResetCalls()
+	content := randBytes(200)
+	err = Upload(ctx, store, "a", bytes.NewReader(content), 200, &Options{PartSize: 100, Checkpoints: checkpoints})
+	assert.Nil(t, err)
+	assert.Equal(t, content, store.Data("a"))
+	assert.Equal(t, []int{0, 1}, written(store))
+	assert.Len(t, store.Uploads(), 0)
+}
+
+func TestUploadResumeError(t *testing.T) {
+	ctx := context.Background()
+	store := newStorager()
+	checkpoints := NewFileCheckpointStore(t.TempDir())
+	store.Fail = failPart(1)
+
+	o := &Options{PartSize: 100, Checkpoints: checkpoints}
+	err := Upload(ctx, store, "a", bytes.NewReader(randBytes(300)), 300, o)
+	assert.Error(t, err)
+	c, err := checkpoints.Load(ctx, "a")
+	assert.Nil(t, err)
+
+	// Transient errors will be returned without starting a new multipart.
+	store.Fail = failStat(services.ErrServiceInternal)
+	err = Upload(ctx, store, "a", bytes.NewReader(randBytes(300)), 300, o)
+	assert.ErrorIs(t, err, services.ErrServiceInternal)
+	assert.Len(t, store.Uploads(), 1)
+	nc, err := checkpoints.Load(ctx, "a")
+	assert.Nil(t, err)
+	assert.Equal(t, c.MultipartID, nc.MultipartID)
+
+	// The upload will be started from scratch if the multipart has gone.
+	store.Fail = failStat(services.ErrObjectNotExist)
+	content := randBytes(300)
+	err = Upload(ctx, store, "a", bytes.NewReader(content), 300, o)
+	assert.Nil(t, err)
+	assert.Equal(t, content, store.Data("a"))
+	assert.Len(t, store.Uploads(), 0)
+}
+
+func TestAbort(t *testing.T) {
+	ctx := context.Background()
+	store := newStorager()
+	checkpoints := NewFileCheckpointStore(t.TempDir())
+	store.Fail = failPart(1)
+
+	err := Upload(ctx, store, "a", bytes.NewReader(randBytes(300)), 300, &Options{PartSize: 100, Checkpoints: checkpoints, Key: "key"})
+	assert.Error(t, err)
+
+	assert.Nil(t, Abort(ctx, store, checkpoints, "key"))
+	assert.Len(t, store.Uploads(), 0)
+	c, err := checkpoints.Load(ctx, "key")
+	assert.Nil(t, err)
+	assert.Nil(t, c)
+}
+
+func TestUploadUnsupported(t *testing.T) {
+	store := struct {
+		types.UnimplementedStorager
+	}{}
+	err := Upload(context.Background(), store, "a", bytes.NewReader(nil), 0, nil)
+	assert.ErrorIs(t, err, services.ErrCapabilityInsufficient)
+}
@@ -0,0 +1,12 @@
+/*
+Package walk provides Walk and Glob over any Storager.
+
+For services which have virtual dir, the whole tree will be listed by a
+single ListModePrefix scan, and dirs will be synthesized from object paths.
+For other services, dirs will be listed by ListModeDir recursively, and
+listings of sub dirs could be fetched concurrently.
+
+Glob supports "**" which matches zero or more path segments, and the static
+part of the pattern will be pushed down as the list prefix.
+*/
+package walk
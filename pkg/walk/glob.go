@@ -0,0 +1,120 @@
+package walk
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"go.beyondstorage.io/v5/types"
+)
+
+// Glob returns the paths of all objects matching pattern with default options.
+func Glob(store types.Storager, pattern string) ([]string, error) {
+	return GlobWithContext(context.Background(), store, pattern)
+}
+
+// GlobWithContext returns the paths of all objects matching pattern with default options.
+func GlobWithContext(ctx context.Context, store types.Storager, pattern string) ([]string, error) {
+	return NewWalker(nil).Glob(ctx, store, pattern)
+}
+
+// Glob returns the sorted paths of all objects matching pattern.
+//
+// The syntax of pattern is the same as in path.Match, besides a "**" segment
+// matches zero or more path segments. Both objects and dirs could be matched.
+func (w *Walker) Glob(ctx context.Context, store types.Storager, pattern string) ([]string, error) {
+	segs := strings.Split(strings.Trim(pattern, "/"), "/")
+	for _, v := range segs {
+		if _, err := path.Match(v, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	// Static segments could be used as the root directly.
+	n := 0
+	for n < len(segs)-1 && !hasMeta(segs[n]) {
+		n++
+	}
+	root := strings.Join(segs[:n], "/")
+
+	matches := make([]string, 0)
+	fn := func(o *types.Object) error {
+		rel := strings.Split(o.Path, "/")
+		if matchSegments(segs, rel) {
+			matches = append(matches, o.Path)
+		}
+		if o.Mode.IsDir() && !matchPrefix(segs, rel) {
+			return SkipDir
+		}
+		return nil
+	}
+
+	var err error
+	if store.Features().VirtualDir {
+		// Push down all the literal part of pattern as list prefix.
+		err = w.walkPrefix(ctx, store, root, literalPrefix(pattern), fn)
+	} else {
+		err = w.walkDir(ctx, store, root, fn)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// hasMeta reports whether s contains any of the magic characters recognized by path.Match.
+func hasMeta(s string) bool {
+	return strings.ContainsAny(s, `*?[\`)
+}
+
+// literalPrefix returns the part of pattern before the first magic character.
+func literalPrefix(pattern string) string {
+	pattern = strings.TrimLeft(pattern, "/")
+	if idx := strings.IndexAny(pattern, `*?[\`); idx >= 0 {
+		return pattern[:idx]
+	}
+	return pattern
+}
+
+// matchSegments reports whether segs of a path matches all segs of pattern.
+func matchSegments(pattern, segs []string) bool {
+	if len(pattern) == 0 {
+		return len(segs) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segs); i++ {
+			if matchSegments(pattern[1:], segs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segs) == 0 {
+		return false
+	}
+	// Pattern has been checked, it's safe to ignore the error here.
+	if ok, _ := path.Match(pattern[0], segs[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segs[1:])
+}
+
+// matchPrefix reports whether objects under the dir with segs could match pattern.
+func matchPrefix(pattern, segs []string) bool {
+	if len(segs) == 0 {
+		return len(pattern) > 0
+	}
+	if len(pattern) == 0 {
+		return false
+	}
+	if pattern[0] == "**" {
+		return true
+	}
+	if ok, _ := path.Match(pattern[0], segs[0]); !ok {
+		return false
+	}
+	return matchPrefix(pattern[1:], segs[1:])
+}
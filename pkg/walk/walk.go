@@ -0,0 +1,308 @@
+package walk
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+// SkipDir is used as a return value from Func to indicate that the dir named
+// in the call is to be skipped. If it's returned for an object which is not
+// a dir, the remaining objects in the same dir will be skipped, and Walk
+// will stop if the object is directly under root.
+var SkipDir = errors.New("skip this directory")
+
+// Func is called for every object visited by Walk.
+//
+// Paths of dirs will not contain the trailing slash.
+type Func func(o *types.Object) error
+
+// Options is the walk supported options.
+type Options struct {
+	// Parallelism is the max number of concurrent dir listings, 1 by default.
+	//
+	// Func will always be called sequentially.
+	Parallelism int
+	// Sorted means objects in the same dir will be visited in lexical order,
+	// and dirs will be visited before objects under them.
+	Sorted bool
+}
+
+// Walker walks Storagers with options.
+type Walker struct {
+	parallelism int
+	sorted      bool
+}
+
+// NewWalker will create a Walker.
+func NewWalker(o *Options) *Walker {
+	w := &Walker{parallelism: 1}
+	if o != nil {
+		if o.Parallelism > 0 {
+			w.parallelism = o.Parallelism
+		}
+		w.sorted = o.Sorted
+	}
+	return w
+}
+
+// Walk will walk the tree under root with default options.
+func Walk(ctx context.Context, store types.Storager, root string, fn Func) error {
+	return NewWalker(nil).Walk(ctx, store, root, fn)
+}
+
+// Walk calls fn for every object under root, root itself will not be visited.
+//
+// Not exist root will be treated as an empty dir.
+func (w *Walker) Walk(ctx context.Context, store types.Storager, root string, fn Func) error {
+	root = strings.TrimRight(root, "/")
+	if store.Features().VirtualDir {
+		prefix := root
+		if prefix != "" {
+			prefix += "/"
+		}
+		return w.walkPrefix(ctx, store, root, prefix, fn)
+	}
+	return w.walkDir(ctx, store, root, fn)
+}
+
+// walkPrefix lists all objects starts with prefix, and calls fn for objects under root.
+func (w *Walker) walkPrefix(ctx context.Context, store types.Storager, root, prefix string, fn Func) error {
+	it, err := store.ListWithContext(ctx, prefix, pairs.WithListMode(types.ListModePrefix))
+	if err != nil {
+		return ignoreNotExist(err)
+	}
+
+	var objects []*types.Object
+	if w.sorted {
+		// Read all objects so that they could be sorted.
+		if objects, err = it.Collect(); err != nil {
+			return err
+		}
+		sort.Slice(objects, func(i, j int) bool { return objects[i].Path < objects[j].Path })
+	}
+
+	next := func() (*types.Object, error) {
+		if !w.sorted {
+			return it.Next()
+		}
+		if len(objects) == 0 {
+			return nil, types.IterateDone
+		}
+		o := objects[0]
+		objects = objects[1:]
+		return o, nil
+	}
+
+	visited := make(map[string]bool)
+	skipped := make([]string, 0)
+	isSkipped := func(p string) bool {
+		for _, v := range skipped {
+			if strings.HasPrefix(p, v+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	for {
+		o, err := next()
+		if errors.Is(err, types.IterateDone) {
+			return nil
+		}
+		if err != nil {
+			return ignoreNotExist(err)
+		}
+
+		p := strings.TrimSuffix(o.Path, "/")
+		if p == root || isSkipped(p) {
+			continue
+		}
+		if p != o.Path {
+			// Dir markers will be visited as dirs.
+			o.Path = p
+			o.Mode |= types.ModeDir
+		}
+
+		// Synthesize dirs between root and this object.
+		rel := p
+		if root != "" {
+			rel = strings.TrimPrefix(p, root+"/")
+		}
+		segs := strings.Split(rel, "/")
+		dir := root
+		skip := false
+		for _, v := range segs[:len(segs)-1] {
+			dir = joinPath(dir, v)
+			if visited[dir] {
+				continue
+			}
+			visited[dir] = true
+
+			d := types.NewObject(store, true)
+			d.ID = dir
+			d.Path = dir
+			d.Mode = types.ModeDir
+			err = fn(d)
+			if errors.Is(err, SkipDir) {
+				skipped = append(skipped, dir)
+				skip = true
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if skip || (o.Mode.IsDir() && visited[p]) {
+			continue
+		}
+		if o.Mode.IsDir() {
+			visited[p] = true
+		}
+
+		err = fn(o)
+		if errors.Is(err, SkipDir) {
+			if o.Mode.IsDir() {
+				skipped = append(skipped, p)
+				continue
+			}
+			dir := parentDir(p)
+			if dir == root {
+				// All remaining objects are under root.
+				return nil
+			}
+			skipped = append(skipped, dir)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// listing is the result of a dir listing.
+type listing struct {
+	objects []*types.Object
+	err     error
+}
+
+// walkDir lists dirs recursively, listings of sub dirs will be prefetched concurrently.
+func (w *Walker) walkDir(ctx context.Context, store types.Storager, root string, fn Func) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, w.parallelism)
+	list := func(dir string) <-chan listing {
+		ch := make(chan listing, 1)
+		go func() {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				ch <- listing{err: ctx.Err()}
+				return
+			}
+			defer func() { <-sem }()
+
+			objects, err := w.list(ctx, store, dir)
+			ch <- listing{objects: objects, err: err}
+		}()
+		return ch
+	}
+
+	var visit func(dir string, ch <-chan listing) error
+	visit = func(dir string, ch <-chan listing) error {
+		l := <-ch
+		if l.err != nil {
+			return l.err
+		}
+
+		// Start listing sub dirs before visiting, so that they could be fetched concurrently.
+		subs := make(map[string]<-chan listing)
+		if w.parallelism > 1 {
+			for _, o := range l.objects {
+				if o.Mode.IsDir() {
+					subs[o.Path] = list(o.Path)
+				}
+			}
+		}
+
+		for _, o := range l.objects {
+			err := fn(o)
+			if errors.Is(err, SkipDir) {
+				if o.Mode.IsDir() {
+					continue
+				}
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !o.Mode.IsDir() {
+				continue
+			}
+
+			sub, ok := subs[o.Path]
+			if !ok {
+				sub = list(o.Path)
+			}
+			if err = visit(o.Path, sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(root, list(root))
+}
+
+// list lists objects in dir.
+func (w *Walker) list(ctx context.Context, store types.Storager, dir string) ([]*types.Object, error) {
+	it, err := store.ListWithContext(ctx, dir, pairs.WithListMode(types.ListModeDir))
+	if err != nil {
+		return nil, ignoreNotExist(err)
+	}
+	objects, err := it.Collect()
+	if err != nil {
+		// Services like fs will return error while the dir is not exist.
+		return nil, ignoreNotExist(err)
+	}
+
+	x := objects[:0]
+	for _, o := range objects {
+		o.Path = strings.TrimSuffix(o.Path, "/")
+		if o.Path == dir {
+			continue
+		}
+		x = append(x, o)
+	}
+	if w.sorted {
+		sort.Slice(x, func(i, j int) bool { return x[i].Path < x[j].Path })
+	}
+	return x, nil
+}
+
+func ignoreNotExist(err error) error {
+	if errors.Is(err, services.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
+
+func joinPath(dir, name string) string {
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
+
+func parentDir(p string) string {
+	idx := strings.LastIndex(p, "/")
+	if idx < 0 {
+		return ""
+	}
+	return p[:idx]
+}
@@ -0,0 +1,171 @@
+package walk
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/internal/storagetest"
+	"go.beyondstorage.io/v5/types"
+)
+
+func newStorager(virtualDir bool, files ...string) *storagetest.Storager {
+	s := storagetest.New()
+	s.StorageFeatures = types.StorageFeatures{VirtualDir: virtualDir}
+	for _, v := range files {
+		s.Put(v, []byte(v))
+	}
+	return s
+}
+
+// listed returns the listed dirs of s.
+func listed(s *storagetest.Storager) []string {
+	dirs := make([]string, 0)
+	for _, c := range s.Calls("list") {
+		dirs = append(dirs, c.Path)
+	}
+	return dirs
+}
+
+var testFiles = []string{
+	"a/b/c.txt",
+	"a/b/d.log",
+	"a/e.txt",
+	"f.txt",
+	"g/h/i/j.txt",
+}
+
+func walkPaths(t *testing.T, w *Walker, store types.Storager, root string, fn Func) []string {
+	paths := make([]string, 0)
+	err := w.Walk(context.Background(), store, root, func(o *types.Object) error {
+		p := o.Path
+		if o.Mode.IsDir() {
+			p += "/"
+		}
+		paths = append(paths, p)
+		if fn != nil {
+			return fn(o)
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	return paths
+}
+
+func TestWalk(t *testing.T) {
+	expected := []string{
+		"a/", "a/b/", "a/b/c.txt", "a/b/d.log", "a/e.txt",
+		"f.txt",
+		"g/", "g/h/", "g/h/i/", "g/h/i/j.txt",
+	}
+
+	for _, virtualDir := range []bool{true, false} {
+		for _, parallelism := range []int{1, 4} {
+			store := newStorager(virtualDir, testFiles...)
+			w := NewWalker(&Options{Parallelism: parallelism, Sorted: true})
+
+			assert.Equal(t, expected, walkPaths(t, w, store, "", nil), "virtual dir %v", virtualDir)
+			assert.Equal(t, []string{"a/b/", "a/b/c.txt", "a/b/d.log", "a/e.txt"}, walkPaths(t, w, store, "a", nil))
+			assert.Empty(t, walkPaths(t, w, store, "not-exist", nil))
+		}
+	}
+}
+
+func TestWalkPrefixScan(t *testing.T) {
+	store := newStorager(true, testFiles...)
+
+	walkPaths(t, NewWalker(nil), store, "a/", nil)
+	assert.Equal(t, []string{"a/"}, listed(store))
+}
+
+func TestWalkSkipDir(t *testing.T) {
+	for _, virtualDir := range []bool{true, false} {
+		store := newStorager(virtualDir, testFiles...)
+		w := NewWalker(&Options{Sorted: true})
+
+		paths := walkPaths(t, w, store, "", func(o *types.Object) error {
+			if o.Path == "a/b" || o.Path == "g/h/i/j.txt" {
+				return SkipDir
+			}
+			return nil
+		})
+		assert.Equal(t, []string{"a/", "a/b/", "a/e.txt", "f.txt", "g/", "g/h/", "g/h/i/", "g/h/i/j.txt"}, paths, "virtual dir %v", virtualDir)
+
+		// Skip the remaining objects in the same dir.
+		paths = walkPaths(t, w, store, "a/b", func(o *types.Object) error {
+			return SkipDir
+		})
+		assert.Equal(t, []string{"a/b/c.txt"}, paths, "virtual dir %v", virtualDir)
+
+		// Skipping an object directly under root stops the walk.
+		paths = walkPaths(t, w, store, "", func(o *types.Object) error {
+			if o.Path == "f.txt" {
+				return SkipDir
+			}
+			return nil
+		})
+		assert.Equal(t, []string{"a/", "a/b/", "a/b/c.txt", "a/b/d.log", "a/e.txt", "f.txt"}, paths, "virtual dir %v", virtualDir)
+	}
+}
+
+func TestWalkError(t *testing.T) {
+	expected := errors.New("walk error")
+
+	for _, virtualDir := range []bool{true, false} {
+		store := newStorager(virtualDir, testFiles...)
+
+		n := 0
+		err := NewWalker(&Options{Parallelism: 4}).Walk(context.Background(), store, "", func(o *types.Object) error {
+			n++
+			return expected
+		})
+		assert.ErrorIs(t, err, expected)
+		assert.Equal(t, 1, n)
+	}
+}
+
+func TestGlob(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"*.txt", []string{"f.txt"}},
+		{"a/*/*.txt", []string{"a/b/c.txt"}},
+		{"a/*", []string{"a/b", "a/e.txt"}},
+		{"**/*.txt", []string{"a/b/c.txt", "a/e.txt", "f.txt", "g/h/i/j.txt"}},
+		{"a/**", []string{"a/b", "a/b/c.txt", "a/b/d.log", "a/e.txt"}},
+		{"g/**/j.txt", []string{"g/h/i/j.txt"}},
+		{"a/b/d.log", []string{"a/b/d.log"}},
+		{"x/*", []string{}},
+	}
+
+	for _, virtualDir := range []bool{true, false} {
+		for _, tt := range cases {
+			store := newStorager(virtualDir, testFiles...)
+
+			matches, err := Glob(store, tt.pattern)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, matches, "pattern %s, virtual dir %v", tt.pattern, virtualDir)
+		}
+	}
+
+	_, err := Glob(newStorager(true), "a/[")
+	assert.Error(t, err)
+}
+
+func TestGlobPushdown(t *testing.T) {
+	store := newStorager(true, testFiles...)
+	_, err := Glob(store, "a/b/c*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a/b/c"}, listed(store))
+
+	store = newStorager(false, testFiles...)
+	_, err = Glob(store, "a/*/c.txt")
+	assert.Nil(t, err)
+	dirs := listed(store)
+	sort.Strings(dirs)
+	assert.Equal(t, []string{"a", "a/b"}, dirs)
+}
@@ -1,5 +1,10 @@
 /*
 Package azblob provided support for Azure Storage containers and blobs objects (https://docs.microsoft.com/en-us/azure/storage/blobs/storage-blobs-introduction)
+
+Lifecycle management policies are not supported: they are only available via
+the Azure Resource Manager, which can't be reached with the data plane client
+and credentials used by this service. GetLifecycle and SetLifecycle return
+NotImplemented error, please manage policies via Azure Portal or Azure CLI.
 */
 package azblob
 
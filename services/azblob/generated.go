@@ -208,8 +208,11 @@ func (f *Factory) storageFeatures() (s types.StorageFeatures) {
 	s.CreateDir = true
 	s.Delete = true
 	s.List = true
+	s.ListVersion = true
 	s.Metadata = true
 	s.Read = true
+	s.Restore = true
+	s.SetStorageClass = true
 	s.Stat = true
 	s.Write = true
 	s.WriteAppend = true
@@ -535,6 +538,8 @@ type pairStorageCreateAppend struct {
 	EncryptionKey      []byte
 	HasEncryptionScope bool
 	EncryptionScope    string
+	HasUserMetadata    bool
+	UserMetadata       map[string]string
 }
 
 func (s *Storage) parsePairStorageCreateAppend(opts []types.Pair) (pairStorageCreateAppend, error) {
@@ -561,6 +566,12 @@ func (s *Storage) parsePairStorageCreateAppend(opts []types.Pair) (pairStorageCr
 			}
 			result.HasEncryptionScope = true
 			result.EncryptionScope = v.Value.(string)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageCreateAppend{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -730,9 +741,17 @@ func (s *Storage) CreatePageWithContext(ctx context.Context, path string, pairs
 }
 
 type pairStorageDelete struct {
-	pairs         []types.Pair
-	HasObjectMode bool
-	ObjectMode    types.ObjectMode
+	pairs              []types.Pair
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasObjectMode      bool
+	ObjectMode         types.ObjectMode
+	HasVersionID       bool
+	VersionID          string
 }
 
 func (s *Storage) parsePairStorageDelete(opts []types.Pair) (pairStorageDelete, error) {
@@ -741,12 +760,36 @@ func (s *Storage) parsePairStorageDelete(opts []types.Pair) (pairStorageDelete,
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "object_mode":
 			if result.HasObjectMode {
 				continue
 			}
 			result.HasObjectMode = true
 			result.ObjectMode = v.Value.(types.ObjectMode)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageDelete{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -797,6 +840,31 @@ func (s *Storage) FetchWithContext(ctx context.Context, path string, url string,
 	return
 }
 
+type pairStorageGetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageGetLifecycle(opts []types.Pair) (pairStorageGetLifecycle, error) {
+	result :=
+		pairStorageGetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageGetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) GetLifecycle(pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	err = types.NewOperationNotImplementedError("get_lifecycle")
+	return
+}
+func (s *Storage) GetLifecycleWithContext(ctx context.Context, pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	err = types.NewOperationNotImplementedError("get_lifecycle")
+	return
+}
+
 type pairStorageList struct {
 	pairs       []types.Pair
 	HasListMode bool
@@ -890,6 +958,41 @@ func (s *Storage) ListMultipartWithContext(ctx context.Context, o *types.Object,
 	return
 }
 
+type pairStorageListVersion struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageListVersion(opts []types.Pair) (pairStorageListVersion, error) {
+	result :=
+		pairStorageListVersion{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageListVersion{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) ListVersion(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	ctx := context.Background()
+	return s.ListVersionWithContext(ctx, path, pairs...)
+}
+func (s *Storage) ListVersionWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	defer func() {
+		err =
+			s.formatError("list_version", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.ListVersion...)
+	var opt pairStorageListVersion
+
+	opt, err = s.parsePairStorageListVersion(pairs)
+	if err != nil {
+		return
+	}
+	return s.listVersion(ctx, strings.ReplaceAll(path, "\\", "/"), opt)
+}
+
 type pairStorageMetadata struct {
 	pairs []types.Pair
 }
@@ -1121,12 +1224,20 @@ type pairStorageRead struct {
 	EncryptionKey      []byte
 	HasEncryptionScope bool
 	EncryptionScope    string
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
 	HasIoCallback      bool
 	IoCallback         func([]byte)
 	HasOffset          bool
 	Offset             int64
 	HasSize            bool
 	Size               int64
+	HasVersionID       bool
+	VersionID          string
 }
 
 func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, error) {
@@ -1147,6 +1258,24 @@ func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, erro
 			}
 			result.HasEncryptionScope = true
 			result.EncryptionScope = v.Value.(string)
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
@@ -1165,6 +1294,12 @@ func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, erro
 			}
 			result.HasSize = true
 			result.Size = v.Value.(int64)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageRead{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -1190,14 +1325,117 @@ func (s *Storage) ReadWithContext(ctx context.Context, path string, w io.Writer,
 	return s.read(ctx, strings.ReplaceAll(path, "\\", "/"), w, opt)
 }
 
+type pairStorageRestore struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageRestore(opts []types.Pair) (pairStorageRestore, error) {
+	result :=
+		pairStorageRestore{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageRestore{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) Restore(path string, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.RestoreWithContext(ctx, path, pairs...)
+}
+func (s *Storage) RestoreWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("restore", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.Restore...)
+	var opt pairStorageRestore
+
+	opt, err = s.parsePairStorageRestore(pairs)
+	if err != nil {
+		return
+	}
+	return s.restore(ctx, strings.ReplaceAll(path, "\\", "/"), opt)
+}
+
+type pairStorageSetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetLifecycle(opts []types.Pair) (pairStorageSetLifecycle, error) {
+	result :=
+		pairStorageSetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetLifecycle(rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_lifecycle")
+	return
+}
+func (s *Storage) SetLifecycleWithContext(ctx context.Context, rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_lifecycle")
+	return
+}
+
+type pairStorageSetStorageClass struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetStorageClass(opts []types.Pair) (pairStorageSetStorageClass, error) {
+	result :=
+		pairStorageSetStorageClass{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetStorageClass{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetStorageClass(path string, class string, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.SetStorageClassWithContext(ctx, path, class, pairs...)
+}
+func (s *Storage) SetStorageClassWithContext(ctx context.Context, path string, class string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("set_storage_class", err, path, class)
+	}()
+	pairs = append(pairs, s.defaultPairs.SetStorageClass...)
+	var opt pairStorageSetStorageClass
+
+	opt, err = s.parsePairStorageSetStorageClass(pairs)
+	if err != nil {
+		return
+	}
+	return s.setStorageClass(ctx, strings.ReplaceAll(path, "\\", "/"), class, opt)
+}
+
 type pairStorageStat struct {
 	pairs              []types.Pair
 	HasEncryptionKey   bool
 	EncryptionKey      []byte
 	HasEncryptionScope bool
 	EncryptionScope    string
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
 	HasObjectMode      bool
 	ObjectMode         types.ObjectMode
+	HasVersionID       bool
+	VersionID          string
 }
 
 func (s *Storage) parsePairStorageStat(opts []types.Pair) (pairStorageStat, error) {
@@ -1218,12 +1456,36 @@ func (s *Storage) parsePairStorageStat(opts []types.Pair) (pairStorageStat, erro
 			}
 			result.HasEncryptionScope = true
 			result.EncryptionScope = v.Value.(string)
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "object_mode":
 			if result.HasObjectMode {
 				continue
 			}
 			result.HasObjectMode = true
 			result.ObjectMode = v.Value.(types.ObjectMode)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageStat{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -1261,8 +1523,16 @@ type pairStorageWrite struct {
 	EncryptionKey      []byte
 	HasEncryptionScope bool
 	EncryptionScope    string
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
 	HasIoCallback      bool
 	IoCallback         func([]byte)
+	HasUserMetadata    bool
+	UserMetadata       map[string]string
 }
 
 func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, error) {
@@ -1301,12 +1571,36 @@ func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, er
 			}
 			result.HasEncryptionScope = true
 			result.EncryptionScope = v.Value.(string)
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
 			}
 			result.HasIoCallback = true
 			result.IoCallback = v.Value.(func([]byte))
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageWrite{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -37,17 +37,20 @@ var Metadata = def.Metadata{
 			VirtualDir:       true,
 			WriteEmptyObject: true,
 
-			Create:       true,
-			CreateDir:    true,
-			CreateAppend: true,
-			Delete:       true,
-			List:         true,
-			Metadata:     true,
-			Read:         true,
-			Stat:         true,
-			Write:        true,
-			WriteAppend:  true,
-			CommitAppend: true,
+			Create:          true,
+			CreateDir:       true,
+			CreateAppend:    true,
+			Delete:          true,
+			List:            true,
+			ListVersion:     true,
+			Metadata:        true,
+			Read:            true,
+			Restore:         true,
+			SetStorageClass: true,
+			Stat:            true,
+			Write:           true,
+			WriteAppend:     true,
+			CommitAppend:    true,
 		},
 
 		Create: []def.Pair{
@@ -55,6 +58,10 @@ var Metadata = def.Metadata{
 		},
 		Delete: []def.Pair{
 			def.PairObjectMode,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
+			def.PairVersionID,
 		},
 		List: []def.Pair{
 			def.PairListMode,
@@ -63,26 +70,39 @@ var Metadata = def.Metadata{
 			def.PairOffset,
 			def.PairIoCallback,
 			def.PairSize,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
 			pairEncryptionKey,
 			pairEncryptionScope,
+			def.PairVersionID,
 		},
 		Write: []def.Pair{
 			def.PairContentMD5,
 			def.PairContentType,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
 			def.PairIoCallback,
 			pairAccessTier,
 			pairEncryptionKey,
 			pairEncryptionScope,
+			def.PairUserMetadata,
 		},
 		Stat: []def.Pair{
 			def.PairObjectMode,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
 			pairEncryptionKey,
 			pairEncryptionScope,
+			def.PairVersionID,
 		},
 		CreateAppend: []def.Pair{
 			def.PairContentType,
 			pairEncryptionKey,
 			pairEncryptionScope,
+			def.PairUserMetadata,
 		},
 		WriteAppend: []def.Pair{
 			def.PairContentMD5,
@@ -57,7 +57,12 @@ func (s *Storage) createAppend(ctx context.Context, path string, opt pairStorage
 		}
 	}
 
-	_, err = s.bucket.NewAppendBlobURL(rp).Create(ctx, headers, nil,
+	var metadata azblob.Metadata
+	if opt.HasUserMetadata {
+		metadata = formatUserMetadata(opt.UserMetadata)
+	}
+
+	_, err = s.bucket.NewAppendBlobURL(rp).Create(ctx, headers, metadata,
 		azblob.BlobAccessConditions{}, nil, cpk)
 	if err != nil {
 		return
@@ -115,8 +120,20 @@ func (s *Storage) delete(ctx context.Context, path string, opt pairStorageDelete
 		rp += "/"
 	}
 
-	_, err = s.bucket.NewBlockBlobURL(rp).Delete(ctx,
-		azblob.DeleteSnapshotsOptionNone, azblob.BlobAccessConditions{})
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	blob := s.bucket.NewBlockBlobURL(rp)
+	if opt.HasVersionID {
+		blob = blob.WithVersionID(opt.VersionID)
+	}
+	_, err = blob.Delete(ctx,
+		azblob.DeleteSnapshotsOptionNone, cond.accessConditions())
 	if err != nil && checkError(err, azblob.ServiceCodeBlobNotFound) {
 		// Omit `BlobNotFound` error here
 		// ref: [GSP-46](https://github.com/beyondstorage/specs/blob/master/rfcs/46-idempotent-delete.md)
@@ -161,6 +178,14 @@ func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (o
 	return types.NewObjectIterator(ctx, nextFn, input), nil
 }
 
+func (s *Storage) listVersion(ctx context.Context, path string, opt pairStorageListVersion) (oi *types.ObjectIterator, err error) {
+	input := &objectPageStatus{
+		maxResults: 200,
+		prefix:     s.getAbsPath(path),
+	}
+	return types.NewObjectIterator(ctx, s.nextVersionObjectPage, input), nil
+}
+
 func (s *Storage) metadata(opt pairStorageMetadata) (meta *types.StorageMeta) {
 	meta = types.NewStorageMeta()
 	meta.Name = s.name
@@ -176,6 +201,9 @@ func (s *Storage) nextObjectPageByDir(ctx context.Context, page *types.ObjectPag
 	input := page.Status.(*objectPageStatus)
 
 	output, err := s.bucket.ListBlobsHierarchySegment(ctx, input.marker, input.delimiter, azblob.ListBlobsSegmentOptions{
+		Details: azblob.BlobListingDetails{
+			Metadata: true,
+		},
 		Prefix:     input.prefix,
 		MaxResults: input.maxResults,
 	})
@@ -213,6 +241,9 @@ func (s *Storage) nextObjectPageByPrefix(ctx context.Context, page *types.Object
 	input := page.Status.(*objectPageStatus)
 
 	output, err := s.bucket.ListBlobsFlatSegment(ctx, input.marker, azblob.ListBlobsSegmentOptions{
+		Details: azblob.BlobListingDetails{
+			Metadata: true,
+		},
 		Prefix:     input.prefix,
 		MaxResults: input.maxResults,
 	})
@@ -237,6 +268,50 @@ func (s *Storage) nextObjectPageByPrefix(ctx context.Context, page *types.Object
 	return nil
 }
 
+// nextVersionObjectPage will return all versions of the object in one page.
+//
+// azblob lists versions from the oldest to the newest, so we need to collect
+// them all before reversing.
+func (s *Storage) nextVersionObjectPage(ctx context.Context, page *types.ObjectPage) error {
+	input := page.Status.(*objectPageStatus)
+
+	for {
+		output, err := s.bucket.ListBlobsFlatSegment(ctx, input.marker, azblob.ListBlobsSegmentOptions{
+			Details: azblob.BlobListingDetails{
+				Metadata: true,
+				Versions: true,
+			},
+			Prefix:     input.prefix,
+			MaxResults: input.maxResults,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, v := range output.Segment.BlobItems {
+			// Prefix could match other objects, only versions of this path are needed.
+			if v.Name != input.prefix {
+				continue
+			}
+			o, err := s.formatFileObject(v)
+			if err != nil {
+				return err
+			}
+			page.Data = append(page.Data, o)
+		}
+
+		input.marker = output.NextMarker
+		if !output.NextMarker.NotDone() {
+			break
+		}
+	}
+
+	for i, j := 0, len(page.Data)-1; i < j; i, j = i+1, j-1 {
+		page.Data[i], page.Data[j] = page.Data[j], page.Data[i]
+	}
+	return types.IterateDone
+}
+
 func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairStorageRead) (n int64, err error) {
 	rp := s.getAbsPath(path)
 
@@ -257,9 +332,21 @@ func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairSt
 			return 0, err
 		}
 	}
-	output, err := s.bucket.NewBlockBlobURL(rp).Download(
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	blob := s.bucket.NewBlockBlobURL(rp)
+	if opt.HasVersionID {
+		blob = blob.WithVersionID(opt.VersionID)
+	}
+	output, err := blob.Download(
 		ctx, offset, count,
-		azblob.BlobAccessConditions{}, false, cpk)
+		cond.accessConditions(), false, cpk)
 	if err != nil {
 		return 0, err
 	}
@@ -278,6 +365,41 @@ func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairSt
 	return io.Copy(w, rc)
 }
 
+func (s *Storage) restore(ctx context.Context, path string, opt pairStorageRestore) (err error) {
+	blob := s.bucket.NewBlockBlobURL(s.getAbsPath(path))
+
+	output, err := blob.GetProperties(ctx, azblob.BlobAccessConditions{}, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return err
+	}
+	// Rehydration in azblob changes the access tier of a blob permanently,
+	// so we should only rehydrate blobs in the archive tier.
+	if azblob.AccessTierType(output.AccessTier()) != azblob.AccessTierArchive {
+		return nil
+	}
+
+	_, err = blob.SetTier(ctx, azblob.AccessTierHot, azblob.LeaseAccessConditions{})
+	if err != nil && checkError(err, azblob.ServiceCodeBlobBeingRehydrated) {
+		// Restore should be idempotent, so we need to omit the error while
+		// the blob is being rehydrated.
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) setStorageClass(ctx context.Context, path string, class string, opt pairStorageSetStorageClass) (err error) {
+	blob := s.bucket.NewBlockBlobURL(s.getAbsPath(path))
+
+	_, err = blob.SetTier(ctx, azblob.AccessTierType(class), azblob.LeaseAccessConditions{})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o *types.Object, err error) {
 	rp := s.getAbsPath(path)
 
@@ -298,7 +420,19 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 		}
 	}
 
-	output, err := s.bucket.NewBlockBlobURL(rp).GetProperties(ctx, azblob.BlobAccessConditions{}, cpk)
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	blob := s.bucket.NewBlockBlobURL(rp)
+	if opt.HasVersionID {
+		blob = blob.WithVersionID(opt.VersionID)
+	}
+	output, err := blob.GetProperties(ctx, cond.accessConditions(), cpk)
 	if err != nil {
 		return nil, err
 	}
@@ -319,6 +453,12 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 	if v := string(output.ETag()); v != "" {
 		o.SetEtag(v)
 	}
+	if v := output.VersionID(); v != "" {
+		o.SetVersionID(v)
+	}
+	if v := output.NewMetadata(); len(v) > 0 {
+		o.SetUserMetadata(formatUserMetadata(v))
+	}
 	if v := output.ContentType(); v != "" {
 		o.SetContentType(v)
 	}
@@ -326,6 +466,10 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 		o.SetContentMd5(base64.StdEncoding.EncodeToString(v))
 	}
 
+	if v := formatRestoreStatus(output.AccessTier(), output.ArchiveStatus()); v != "" {
+		o.SetRestoreStatus(v)
+	}
+
 	var sm ObjectSystemMetadata
 	if v := output.AccessTier(); v != "" {
 		sm.AccessTier = v
@@ -387,9 +531,22 @@ func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int6
 			return 0, err
 		}
 	}
+	var metadata azblob.Metadata
+	if opt.HasUserMetadata {
+		metadata = formatUserMetadata(opt.UserMetadata)
+	}
+
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
 	_, err = s.bucket.NewBlockBlobURL(rp).Upload(
 		ctx, iowrap.SizedReadSeekCloser(r, size),
-		headers, azblob.Metadata{}, azblob.BlobAccessConditions{},
+		headers, metadata, cond.accessConditions(),
 		accessTier, azblob.BlobTagsMap{}, cpk)
 	if err != nil {
 		return 0, err
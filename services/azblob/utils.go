@@ -3,6 +3,7 @@ package azblob
 import (
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 
 	"net/url"
@@ -176,7 +177,10 @@ const (
 
 // ref: https://docs.microsoft.com/en-us/rest/api/storageservices/status-and-error-codes2
 func formatError(err error) error {
-	if _, ok := err.(services.InternalError); ok {
+	// Errors wrapping our error codes, like ErrCapabilityInsufficient, are
+	// returned by ourselves and should not be formatted again.
+	var ie services.InternalError
+	if errors.As(err, &ie) {
 		return err
 	}
 
@@ -191,6 +195,8 @@ func formatError(err error) error {
 		switch e.Response().StatusCode {
 		case 404:
 			return fmt.Errorf("%w: %v", services.ErrObjectNotExist, err)
+		case 304, 412:
+			return fmt.Errorf("%w: %v", services.ErrPreconditionFailed, err)
 		default:
 			return fmt.Errorf("%w, %v", services.ErrUnexpected, err)
 		}
@@ -198,11 +204,64 @@ func formatError(err error) error {
 		return fmt.Errorf("%w: %v", services.ErrObjectNotExist, err)
 	case azblob.StorageErrorCodeInsufficientAccountPermissions:
 		return fmt.Errorf("%w: %v", services.ErrPermissionDenied, err)
+	case azblob.StorageErrorCodeConditionNotMet:
+		return fmt.Errorf("%w: %v", services.ErrPreconditionFailed, err)
+	case azblob.StorageErrorCodeBlobArchived:
+		return fmt.Errorf("%w: %v", services.ErrObjectArchived, err)
 	default:
 		return fmt.Errorf("%w, %v", services.ErrUnexpected, err)
 	}
 }
 
+// formatRestoreStatus converts the access tier and archive status into restore status.
+//
+// ref: https://docs.microsoft.com/en-us/azure/storage/blobs/archive-rehydrate-overview
+func formatRestoreStatus(tier, archiveStatus string) string {
+	switch {
+	case strings.HasPrefix(archiveStatus, "rehydrate-pending"):
+		return typ.RestoreStatusOngoing
+	case azblob.AccessTierType(tier) == azblob.AccessTierArchive:
+		return typ.RestoreStatusArchived
+	default:
+		return ""
+	}
+}
+
+// formatUserMetadata normalizes keys of user metadata into lower case.
+//
+// azblob requires metadata names to be valid C# identifiers, and treats them case-insensitively.
+func formatUserMetadata(m map[string]string) map[string]string {
+	um := make(map[string]string, len(m))
+	for k, v := range m {
+		um[strings.ToLower(k)] = v
+	}
+	return um
+}
+
+// conditions are the precondition pairs of an operation.
+type conditions struct {
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+}
+
+// accessConditions converts conditions into azblob's access conditions.
+func (c conditions) accessConditions() (ac azblob.BlobAccessConditions) {
+	if c.HasIfMatch {
+		ac.ModifiedAccessConditions.IfMatch = azblob.ETag(c.IfMatch)
+	}
+	if c.HasIfModifiedSince {
+		ac.ModifiedAccessConditions.IfModifiedSince = c.IfModifiedSince
+	}
+	if c.HasIfNoneMatch {
+		ac.ModifiedAccessConditions.IfNoneMatch = azblob.ETag(c.IfNoneMatch)
+	}
+	return ac
+}
+
 // newStorage will create a new client.
 func (f *Factory) newStorage(pairs ...typ.Pair) (st *Storage, err error) {
 	s, err := f.newService()
@@ -282,6 +341,12 @@ func (s *Storage) formatFileObject(v azblob.BlobItemInternal) (o *typ.Object, er
 	if len(v.Properties.ContentMD5) > 0 {
 		o.SetContentMd5(base64.StdEncoding.EncodeToString(v.Properties.ContentMD5))
 	}
+	if v.VersionID != nil {
+		o.SetVersionID(*v.VersionID)
+	}
+	if len(v.Metadata) > 0 {
+		o.SetUserMetadata(formatUserMetadata(v.Metadata))
+	}
 
 	var sm ObjectSystemMetadata
 	if value := v.Properties.AccessTier; value != "" {
@@ -240,10 +240,14 @@ func (f *Factory) storageFeatures() (s types.StorageFeatures) {
 	s.CreateDir = true
 	s.CreateMultipart = true
 	s.Delete = true
+	s.GetLifecycle = true
 	s.List = true
 	s.ListMultipart = true
 	s.Metadata = true
 	s.Read = true
+	s.Restore = true
+	s.SetLifecycle = true
+	s.SetStorageClass = true
 	s.Stat = true
 	s.Write = true
 	s.WriteMultipart = true
@@ -727,6 +731,8 @@ type pairStorageCreateMultipart struct {
 	ServerSideEncryptionCustomerKey          []byte
 	HasStorageClass                          bool
 	StorageClass                             string
+	HasUserMetadata                          bool
+	UserMetadata                             map[string]string
 }
 
 func (s *Storage) parsePairStorageCreateMultipart(opts []types.Pair) (pairStorageCreateMultipart, error) {
@@ -777,6 +783,12 @@ func (s *Storage) parsePairStorageCreateMultipart(opts []types.Pair) (pairStorag
 			}
 			result.HasStorageClass = true
 			result.StorageClass = v.Value.(string)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageCreateMultipart{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -903,6 +915,41 @@ func (s *Storage) FetchWithContext(ctx context.Context, path string, url string,
 	return
 }
 
+type pairStorageGetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageGetLifecycle(opts []types.Pair) (pairStorageGetLifecycle, error) {
+	result :=
+		pairStorageGetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageGetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) GetLifecycle(pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	ctx := context.Background()
+	return s.GetLifecycleWithContext(ctx, pairs...)
+}
+func (s *Storage) GetLifecycleWithContext(ctx context.Context, pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	defer func() {
+		err =
+			s.formatError("get_lifecycle", err)
+	}()
+	pairs = append(pairs, s.defaultPairs.GetLifecycle...)
+	var opt pairStorageGetLifecycle
+
+	opt, err = s.parsePairStorageGetLifecycle(pairs)
+	if err != nil {
+		return
+	}
+	return s.getLifecycle(ctx, opt)
+}
+
 type pairStorageList struct {
 	pairs       []types.Pair
 	HasListMode bool
@@ -1006,6 +1053,31 @@ func (s *Storage) ListMultipartWithContext(ctx context.Context, o *types.Object,
 	return s.listMultipart(ctx, o, opt)
 }
 
+type pairStorageListVersion struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageListVersion(opts []types.Pair) (pairStorageListVersion, error) {
+	result :=
+		pairStorageListVersion{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageListVersion{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) ListVersion(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	err = types.NewOperationNotImplementedError("list_version")
+	return
+}
+func (s *Storage) ListVersionWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	err = types.NewOperationNotImplementedError("list_version")
+	return
+}
+
 type pairStorageMetadata struct {
 	pairs []types.Pair
 }
@@ -1306,6 +1378,119 @@ func (s *Storage) ReadWithContext(ctx context.Context, path string, w io.Writer,
 	return s.read(ctx, strings.ReplaceAll(path, "\\", "/"), w, opt)
 }
 
+type pairStorageRestore struct {
+	pairs          []types.Pair
+	HasRestoreDays bool
+	RestoreDays    int
+}
+
+func (s *Storage) parsePairStorageRestore(opts []types.Pair) (pairStorageRestore, error) {
+	result :=
+		pairStorageRestore{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "restore_days":
+			if result.HasRestoreDays {
+				continue
+			}
+			result.HasRestoreDays = true
+			result.RestoreDays = v.Value.(int)
+		default:
+			return pairStorageRestore{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) Restore(path string, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.RestoreWithContext(ctx, path, pairs...)
+}
+func (s *Storage) RestoreWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("restore", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.Restore...)
+	var opt pairStorageRestore
+
+	opt, err = s.parsePairStorageRestore(pairs)
+	if err != nil {
+		return
+	}
+	return s.restore(ctx, strings.ReplaceAll(path, "\\", "/"), opt)
+}
+
+type pairStorageSetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetLifecycle(opts []types.Pair) (pairStorageSetLifecycle, error) {
+	result :=
+		pairStorageSetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetLifecycle(rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.SetLifecycleWithContext(ctx, rules, pairs...)
+}
+func (s *Storage) SetLifecycleWithContext(ctx context.Context, rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("set_lifecycle", err)
+	}()
+	pairs = append(pairs, s.defaultPairs.SetLifecycle...)
+	var opt pairStorageSetLifecycle
+
+	opt, err = s.parsePairStorageSetLifecycle(pairs)
+	if err != nil {
+		return
+	}
+	return s.setLifecycle(ctx, rules, opt)
+}
+
+type pairStorageSetStorageClass struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetStorageClass(opts []types.Pair) (pairStorageSetStorageClass, error) {
+	result :=
+		pairStorageSetStorageClass{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetStorageClass{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetStorageClass(path string, class string, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.SetStorageClassWithContext(ctx, path, class, pairs...)
+}
+func (s *Storage) SetStorageClassWithContext(ctx context.Context, path string, class string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("set_storage_class", err, path, class)
+	}()
+	pairs = append(pairs, s.defaultPairs.SetStorageClass...)
+	var opt pairStorageSetStorageClass
+
+	opt, err = s.parsePairStorageSetStorageClass(pairs)
+	if err != nil {
+		return
+	}
+	return s.setStorageClass(ctx, strings.ReplaceAll(path, "\\", "/"), class, opt)
+}
+
 type pairStorageStat struct {
 	pairs                                    []types.Pair
 	HasMultipartID                           bool
@@ -1401,6 +1586,8 @@ type pairStorageWrite struct {
 	ServerSideEncryptionCustomerKey          []byte
 	HasStorageClass                          bool
 	StorageClass                             string
+	HasUserMetadata                          bool
+	UserMetadata                             map[string]string
 }
 
 func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, error) {
@@ -1463,6 +1650,12 @@ func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, er
 			}
 			result.HasStorageClass = true
 			result.StorageClass = v.Value.(string)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageWrite{}, services.PairUnsupportedError{Pair: v}
 		}
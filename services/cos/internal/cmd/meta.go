@@ -56,10 +56,14 @@ var Metadata = def.Metadata{
 			CreateDir:         true,
 			CreateMultipart:   true,
 			Delete:            true,
+			GetLifecycle:      true,
 			List:              true,
 			ListMultipart:     true,
 			Metadata:          true,
 			Read:              true,
+			Restore:           true,
+			SetLifecycle:      true,
+			SetStorageClass:   true,
 			Stat:              true,
 			Write:             true,
 			WriteMultipart:    true,
@@ -93,6 +97,10 @@ var Metadata = def.Metadata{
 			pairServerSideEncryptionCustomerKey,
 			pairServerSideEncryptionCosKmsKeyId,
 			pairServerSideEncryptionContext,
+			def.PairUserMetadata,
+		},
+		Restore: []def.Pair{
+			def.PairRestoreDays,
 		},
 		Stat: []def.Pair{
 			def.PairMultipartID,
@@ -109,6 +117,7 @@ var Metadata = def.Metadata{
 			pairServerSideEncryptionCustomerAlgorithm,
 			pairServerSideEncryptionCosKmsKeyId,
 			pairServerSideEncryptionContext,
+			def.PairUserMetadata,
 		},
 		WriteMultipart: []def.Pair{
 			def.PairContentMD5,
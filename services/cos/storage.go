@@ -105,13 +105,18 @@ func (s *Storage) createDir(ctx context.Context, path string, opt pairStorageCre
 func (s *Storage) createMultipart(ctx context.Context, path string, opt pairStorageCreateMultipart) (o *types.Object, err error) {
 	rp := s.getAbsPath(path)
 
-	input := &cos.InitiateMultipartUploadOptions{}
+	input := &cos.InitiateMultipartUploadOptions{
+		ObjectPutHeaderOptions: &cos.ObjectPutHeaderOptions{},
+	}
 	if opt.HasStorageClass {
 		input.XCosStorageClass = opt.StorageClass
 	}
 	if opt.HasContentType {
 		input.ContentType = opt.ContentType
 	}
+	if opt.HasUserMetadata {
+		input.XCosMetaXXX = formatUserMetadataHeader(opt.UserMetadata)
+	}
 	// SSE-C
 	if opt.HasServerSideEncryptionCustomerAlgorithm {
 		input.XCosSSECustomerAglo, input.XCosSSECustomerKey, input.XCosSSECustomerKeyMD5, err = calculateEncryptionHeaders(opt.ServerSideEncryptionCustomerAlgorithm, opt.ServerSideEncryptionCustomerKey)
@@ -185,6 +190,26 @@ func (s *Storage) delete(ctx context.Context, path string, opt pairStorageDelete
 	return nil
 }
 
+func (s *Storage) getLifecycle(ctx context.Context, opt pairStorageGetLifecycle) (rules []*types.LifecycleRule, err error) {
+	output, err := s.getLifecycleRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.parseLifecycleRules(output), nil
+}
+
+// getLifecycleRules returns all native lifecycle rules of the bucket.
+func (s *Storage) getLifecycleRules(ctx context.Context) ([]cos.BucketLifecycleRule, error) {
+	output, _, err := s.bucket.GetLifecycle(ctx)
+	if err != nil {
+		if e, ok := err.(*cos.ErrorResponse); ok && e.Code == "NoSuchLifecycleConfiguration" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return output.Rules, nil
+}
+
 func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (oi *types.ObjectIterator, err error) {
 	if !opt.HasListMode {
 		// Support `ListModePrefix` as the default `ListMode`.
@@ -417,6 +442,71 @@ func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairSt
 	return io.Copy(w, rc)
 }
 
+func (s *Storage) restore(ctx context.Context, path string, opt pairStorageRestore) (err error) {
+	days := defaultRestoreDays
+	if opt.HasRestoreDays {
+		days = opt.RestoreDays
+	}
+
+	_, err = s.object.PostRestore(ctx, s.getAbsPath(path), &cos.ObjectRestoreOptions{
+		Days: days,
+	})
+	if err != nil {
+		// Restore should be idempotent, so we need to omit the error while
+		// the object is being restored.
+		if e, ok := err.(*cos.ErrorResponse); ok && e.Code == "RestoreAlreadyInProgress" {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) setLifecycle(ctx context.Context, rules []*types.LifecycleRule, opt pairStorageSetLifecycle) (err error) {
+	existing, err := s.getLifecycleRules(ctx)
+	if err != nil {
+		return err
+	}
+	// Only rules managed by this storager will be replaced, rules outside
+	// the work dir or not representable are kept untouched.
+	output := make([]cos.BucketLifecycleRule, 0, len(existing)+len(rules))
+	for _, v := range existing {
+		if !s.isManagedLifecycleRule(v) {
+			output = append(output, v)
+		}
+	}
+	output = append(output, s.formatLifecycleRules(rules)...)
+
+	// PutLifecycle doesn't accept empty rules, we need to delete the whole
+	// configuration instead.
+	if len(output) == 0 {
+		_, err = s.bucket.DeleteLifecycle(ctx)
+		return err
+	}
+	_, err = s.bucket.PutLifecycle(ctx, &cos.BucketPutLifecycleOptions{
+		Rules: output,
+	})
+	return err
+}
+
+func (s *Storage) setStorageClass(ctx context.Context, path string, class string, opt pairStorageSetStorageClass) (err error) {
+	rp := s.getAbsPath(path)
+
+	// COS doesn't support changing storage class in place, we need to copy
+	// the object to itself with the new storage class.
+	//
+	// The source url is built from the bucket url of the client, so that
+	// custom endpoints are respected. The key will be escaped by Copy.
+	sourceURL := s.client.BaseURL.BucketURL.Host + "/" + rp
+	_, _, err = s.object.Copy(ctx, rp, sourceURL, &cos.ObjectCopyOptions{
+		ObjectCopyHeaderOptions: &cos.ObjectCopyHeaderOptions{
+			XCosMetadataDirective: "Copy",
+			XCosStorageClass:      class,
+		},
+	})
+	return err
+}
+
 func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o *types.Object, err error) {
 	rp := s.getAbsPath(path)
 
@@ -487,6 +577,13 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 	if v := output.Header.Get(headers.ETag); v != "" {
 		o.SetEtag(v)
 	}
+	if v := formatUserMetadata(output.Header); len(v) > 0 {
+		o.SetUserMetadata(v)
+	}
+
+	if v := formatRestoreStatus(output.Header.Get(storageClassHeader), output.Header.Get(restoreHeader)); v != "" {
+		o.SetRestoreStatus(v)
+	}
 
 	var sm ObjectSystemMetadata
 	if v := output.Header.Get(storageClassHeader); v != "" {
@@ -538,6 +635,9 @@ func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int6
 	if opt.HasStorageClass {
 		putOptions.XCosStorageClass = opt.StorageClass
 	}
+	if opt.HasUserMetadata {
+		putOptions.XCosMetaXXX = formatUserMetadataHeader(opt.UserMetadata)
+	}
 	// SSE-C
 	if opt.HasServerSideEncryptionCustomerAlgorithm {
 		putOptions.XCosSSECustomerAglo, putOptions.XCosSSECustomerKey, putOptions.XCosSSECustomerKeyMD5, err = calculateEncryptionHeaders(opt.ServerSideEncryptionCustomerAlgorithm, opt.ServerSideEncryptionCustomerKey)
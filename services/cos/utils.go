@@ -38,6 +38,7 @@ func (s *Service) String() string {
 type Storage struct {
 	f Factory
 
+	client *cos.Client
 	bucket *cos.BucketService
 	object *cos.ObjectService
 
@@ -141,6 +142,56 @@ const (
 	StorageClassArchive    = "ARCHIVE"
 )
 
+const (
+	// ref: https://cloud.tencent.com/document/product/436/7745
+	restoreHeader = "x-cos-restore"
+
+	// defaultRestoreDays is the days of restored copy that will be kept if
+	// restore_days is not set.
+	defaultRestoreDays = 1
+)
+
+// formatRestoreStatus converts the x-cos-restore header into restore status.
+func formatRestoreStatus(class, restore string) string {
+	switch {
+	case strings.Contains(restore, `ongoing-request="true"`):
+		return typ.RestoreStatusOngoing
+	case restore != "":
+		return typ.RestoreStatusRestored
+	case class == StorageClassArchive || class == "DEEP_ARCHIVE":
+		return typ.RestoreStatusArchived
+	default:
+		return ""
+	}
+}
+
+// userMetadataPrefix is the header prefix of cos user metadata.
+//
+// ref: https://cloud.tencent.com/document/product/436/7729
+const userMetadataPrefix = "X-Cos-Meta-"
+
+// formatUserMetadataHeader converts user metadata into cos meta headers.
+func formatUserMetadataHeader(m map[string]string) *http.Header {
+	h := &http.Header{}
+	for k, v := range m {
+		h.Set(userMetadataPrefix+strings.ToLower(k), v)
+	}
+	return h
+}
+
+// formatUserMetadata extracts user metadata from headers, keys will be
+// normalized into lower case.
+func formatUserMetadata(h http.Header) map[string]string {
+	um := make(map[string]string)
+	for k := range h {
+		if !strings.HasPrefix(k, userMetadataPrefix) {
+			continue
+		}
+		um[strings.ToLower(strings.TrimPrefix(k, userMetadataPrefix))] = h.Get(k)
+	}
+	return um
+}
+
 // ref: https://www.qcloud.com/document/product/436/7730
 func formatError(err error) error {
 	if _, ok := err.(services.InternalError); ok {
@@ -185,6 +236,7 @@ func (f *Factory) newStorage() (st *Storage, err error) {
 	url := cos.NewBucketURL(f.Name, f.Location, true)
 	c := cos.NewClient(&cos.BaseURL{BucketURL: url}, s.client)
 
+	st.client = c
 	st.bucket = c.Bucket
 	st.object = c.Object
 	st.name = f.Name
@@ -228,14 +280,28 @@ func (s *Storage) formatError(op string, err error, path ...string) error {
 		return nil
 	}
 
+	if isObjectArchivedError(op, err) {
+		err = fmt.Errorf("%w: %v", services.ErrObjectArchived, err)
+	} else {
+		err = formatError(err)
+	}
+
 	return services.StorageError{
 		Op:       op,
-		Err:      formatError(err),
+		Err:      err,
 		Storager: s,
 		Path:     path,
 	}
 }
 
+// isObjectArchivedError checks whether err is returned for reading archived
+// objects. InvalidObjectState is also returned by other operations, for
+// example restoring objects which are not archived, so only read is checked.
+func isObjectArchivedError(op string, err error) bool {
+	e, ok := err.(*cos.ErrorResponse)
+	return op == "read" && ok && e.Code == "InvalidObjectState"
+}
+
 func (s *Storage) formatFileObject(v cos.Object) (o *typ.Object, err error) {
 	o = s.newObject(false)
 	o.ID = v.Key
@@ -334,3 +400,100 @@ const (
 	// ref: https://cloud.tencent.com/document/product/436/7749
 	writeSizeMaximum = 5 * 1024 * 1024 * 1024
 )
+
+// formatLifecycleRules converts lifecycle rules into cos lifecycle rules.
+func (s *Storage) formatLifecycleRules(rules []*typ.LifecycleRule) []cos.BucketLifecycleRule {
+	output := make([]cos.BucketLifecycleRule, 0, len(rules))
+	for _, r := range rules {
+		rule := cos.BucketLifecycleRule{
+			ID:     r.ID,
+			Status: "Enabled",
+			Filter: &cos.BucketLifecycleFilter{Prefix: s.getAbsPath(r.Prefix)},
+		}
+		if r.Disabled {
+			rule.Status = "Disabled"
+		}
+		if r.ExpireDays > 0 {
+			rule.Expiration = &cos.BucketLifecycleExpiration{Days: r.ExpireDays}
+		}
+		for _, t := range r.Transitions {
+			rule.Transition = append(rule.Transition, cos.BucketLifecycleTransition{
+				Days:         t.Days,
+				StorageClass: t.StorageClass,
+			})
+		}
+		if r.AbortMultipartDays > 0 {
+			rule.AbortIncompleteMultipartUpload = &cos.BucketLifecycleAbortIncompleteMultipartUpload{
+				DaysAfterInitiation: r.AbortMultipartDays,
+			}
+		}
+		output = append(output, rule)
+	}
+	return output
+}
+
+// parseLifecycleRules converts cos lifecycle rules into lifecycle rules.
+//
+// Only rules managed by this storager will be returned.
+func (s *Storage) parseLifecycleRules(rules []cos.BucketLifecycleRule) []*typ.LifecycleRule {
+	output := make([]*typ.LifecycleRule, 0, len(rules))
+	for _, v := range rules {
+		if !s.isManagedLifecycleRule(v) {
+			continue
+		}
+		var prefix string
+		if v.Filter != nil {
+			prefix = v.Filter.Prefix
+		}
+
+		rule := &typ.LifecycleRule{
+			ID:       v.ID,
+			Prefix:   s.getRelPath(prefix),
+			Disabled: v.Status != "Enabled",
+		}
+		if v.Expiration != nil {
+			rule.ExpireDays = v.Expiration.Days
+		}
+		for _, t := range v.Transition {
+			rule.Transitions = append(rule.Transitions, typ.LifecycleTransition{
+				Days:         t.Days,
+				StorageClass: t.StorageClass,
+			})
+		}
+		if v.AbortIncompleteMultipartUpload != nil {
+			rule.AbortMultipartDays = v.AbortIncompleteMultipartUpload.DaysAfterInitiation
+		}
+		output = append(output, rule)
+	}
+	return output
+}
+
+// isManagedLifecycleRule checks whether rule is under the work dir and could
+// be represented by LifecycleRule.
+//
+// Other rules, like rules filtered by tags or expired at a date, are invisible
+// to get_lifecycle and will be kept untouched by set_lifecycle.
+func (s *Storage) isManagedLifecycleRule(rule cos.BucketLifecycleRule) bool {
+	var prefix string
+	if f := rule.Filter; f != nil {
+		if f.Tag != nil || f.And != nil {
+			return false
+		}
+		prefix = f.Prefix
+	}
+	if !strings.HasPrefix(prefix, strings.TrimPrefix(s.workDir, "/")) {
+		return false
+	}
+	if v := rule.Expiration; v != nil && (v.Date != "" || v.ExpiredObjectDeleteMarker) {
+		return false
+	}
+	if rule.NoncurrentVersionExpiration != nil || len(rule.NoncurrentVersionTransition) > 0 {
+		return false
+	}
+	for _, t := range rule.Transition {
+		if t.Date != "" {
+			return false
+		}
+	}
+	return true
+}
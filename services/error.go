@@ -56,6 +56,12 @@ var (
 	ErrServiceInternal = NewErrorCode("service internal")
 	// ErrRequestThrottled means there are too many requests.
 	ErrRequestThrottled = NewErrorCode("request throttled")
+	// ErrChecksumMismatch means the checksum of the written content doesn't match the provided one.
+	ErrChecksumMismatch = NewErrorCode("checksum mismatch")
+	// ErrPreconditionFailed means the conditions like if_match provided by pairs are not satisfied.
+	ErrPreconditionFailed = NewErrorCode("precondition failed")
+	// ErrObjectArchived means the object is archived, and it needs to be restored before read.
+	ErrObjectArchived = NewErrorCode("object archived")
 )
 
 // InitError means this service init failed.
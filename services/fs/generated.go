@@ -77,20 +77,35 @@ func WithDefaultStoragePairs(v DefaultStoragePairs) Pair {
 	return Pair{Key: "default_storage_pairs", Value: v}
 }
 
+// WithHTTPSignerEndpoint will apply http_signer_endpoint value to Options.
+//
+// is the url that httpsign.Handler serving this storage is listening at, like `http://127.0.0.1:8080`
+func WithHTTPSignerEndpoint(v string) Pair {
+	return Pair{Key: "http_signer_endpoint", Value: v}
+}
+
+// WithHTTPSignerKey will apply http_signer_key value to Options.
+//
+// is the HMAC key shared with httpsign.Handler
+func WithHTTPSignerKey(v string) Pair {
+	return Pair{Key: "http_signer_key", Value: v}
+}
+
 // WithStorageFeatures will apply storage_features value to Options.
 func WithStorageFeatures(v StorageFeatures) Pair {
 	return Pair{Key: "storage_features", Value: v}
 }
 
-var pairMap = map[string]string{"content_md5": "string", "content_type": "string", "context": "context.Context", "continuation_token": "string", "credential": "string", "default_content_type": "string", "default_io_callback": "func([]byte)", "default_storage_pairs": "DefaultStoragePairs", "endpoint": "string", "expire": "time.Duration", "http_client_options": "*httpclient.Options", "interceptor": "Interceptor", "io_callback": "func([]byte)", "list_mode": "ListMode", "location": "string", "multipart_id": "string", "name": "string", "object_mode": "ObjectMode", "offset": "int64", "size": "int64", "storage_features": "StorageFeatures", "work_dir": "string"}
+var pairMap = map[string]string{"content_md5": "string", "content_type": "string", "context": "context.Context", "continuation_token": "string", "credential": "string", "default_content_type": "string", "default_io_callback": "func([]byte)", "default_storage_pairs": "DefaultStoragePairs", "endpoint": "string", "expire": "time.Duration", "if_match": "string", "if_modified_since": "time.Time", "if_none_match": "string", "http_client_options": "*httpclient.Options", "http_signer_endpoint": "string", "http_signer_key": "string", "interceptor": "Interceptor", "io_callback": "func([]byte)", "list_mode": "ListMode", "location": "string", "multipart_id": "string", "name": "string", "object_mode": "ObjectMode", "offset": "int64", "size": "int64", "storage_features": "StorageFeatures", "user_metadata": "map[string]string", "work_dir": "string"}
 var (
-	_ Appender = &Storage{}
-	_ Copier   = &Storage{}
-	_ Direr    = &Storage{}
-	_ Fetcher  = &Storage{}
-	_ Linker   = &Storage{}
-	_ Mover    = &Storage{}
-	_ Storager = &Storage{}
+	_ Appender          = &Storage{}
+	_ Copier            = &Storage{}
+	_ Direr             = &Storage{}
+	_ Fetcher           = &Storage{}
+	_ Linker            = &Storage{}
+	_ Mover             = &Storage{}
+	_ StorageHTTPSigner = &Storage{}
+	_ Storager          = &Storage{}
 )
 
 type StorageFeatures struct {
@@ -108,6 +123,10 @@ type pairStorageNew struct {
 	DefaultIoCallback      func([]byte)
 	HasDefaultStoragePairs bool
 	DefaultStoragePairs    DefaultStoragePairs
+	HasHTTPSignerEndpoint  bool
+	HTTPSignerEndpoint     string
+	HasHTTPSignerKey       bool
+	HTTPSignerKey          string
 	HasStorageFeatures     bool
 	StorageFeatures        StorageFeatures
 	HasWorkDir             bool
@@ -140,6 +159,18 @@ func parsePairStorageNew(opts []Pair) (pairStorageNew, error) {
 			}
 			result.HasDefaultStoragePairs = true
 			result.DefaultStoragePairs = v.Value.(DefaultStoragePairs)
+		case "http_signer_endpoint":
+			if result.HasHTTPSignerEndpoint {
+				continue
+			}
+			result.HasHTTPSignerEndpoint = true
+			result.HTTPSignerEndpoint = v.Value.(string)
+		case "http_signer_key":
+			if result.HasHTTPSignerKey {
+				continue
+			}
+			result.HasHTTPSignerKey = true
+			result.HTTPSignerKey = v.Value.(string)
 		case "storage_features":
 			if result.HasStorageFeatures {
 				continue
@@ -172,21 +203,24 @@ func parsePairStorageNew(opts []Pair) (pairStorageNew, error) {
 
 // DefaultStoragePairs is default pairs for specific action
 type DefaultStoragePairs struct {
-	CommitAppend []Pair
-	Copy         []Pair
-	Create       []Pair
-	CreateAppend []Pair
-	CreateDir    []Pair
-	CreateLink   []Pair
-	Delete       []Pair
-	Fetch        []Pair
-	List         []Pair
-	Metadata     []Pair
-	Move         []Pair
-	Read         []Pair
-	Stat         []Pair
-	Write        []Pair
-	WriteAppend  []Pair
+	CommitAppend        []Pair
+	Copy                []Pair
+	Create              []Pair
+	CreateAppend        []Pair
+	CreateDir           []Pair
+	CreateLink          []Pair
+	Delete              []Pair
+	Fetch               []Pair
+	List                []Pair
+	Metadata            []Pair
+	Move                []Pair
+	QuerySignHTTPDelete []Pair
+	QuerySignHTTPRead   []Pair
+	QuerySignHTTPWrite  []Pair
+	Read                []Pair
+	Stat                []Pair
+	Write               []Pair
+	WriteAppend         []Pair
 }
 type pairStorageCommitAppend struct {
 	pairs []Pair
@@ -212,6 +246,14 @@ type pairStorageCopy struct {
 	pairs []Pair
 	// Required pairs
 	// Optional pairs
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasUserMetadata    bool
+	UserMetadata       map[string]string
 }
 
 func (s *Storage) parsePairStorageCopy(opts []Pair) (pairStorageCopy, error) {
@@ -220,6 +262,30 @@ func (s *Storage) parsePairStorageCopy(opts []Pair) (pairStorageCopy, error) {
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageCopy{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -260,6 +326,8 @@ type pairStorageCreateAppend struct {
 	pairs []Pair
 	// Required pairs
 	// Optional pairs
+	HasUserMetadata bool
+	UserMetadata    map[string]string
 }
 
 func (s *Storage) parsePairStorageCreateAppend(opts []Pair) (pairStorageCreateAppend, error) {
@@ -268,6 +336,12 @@ func (s *Storage) parsePairStorageCreateAppend(opts []Pair) (pairStorageCreateAp
 
 	for _, v := range opts {
 		switch v.Key {
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageCreateAppend{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -320,8 +394,14 @@ type pairStorageDelete struct {
 	pairs []Pair
 	// Required pairs
 	// Optional pairs
-	HasObjectMode bool
-	ObjectMode    ObjectMode
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasObjectMode      bool
+	ObjectMode         ObjectMode
 }
 
 func (s *Storage) parsePairStorageDelete(opts []Pair) (pairStorageDelete, error) {
@@ -330,6 +410,24 @@ func (s *Storage) parsePairStorageDelete(opts []Pair) (pairStorageDelete, error)
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "object_mode":
 			if result.HasObjectMode {
 				continue
@@ -440,16 +538,82 @@ func (s *Storage) parsePairStorageMove(opts []Pair) (pairStorageMove, error) {
 	return result, nil
 }
 
+type pairStorageQuerySignHTTPDelete struct {
+	pairs []Pair
+	// Required pairs
+	// Optional pairs
+}
+
+func (s *Storage) parsePairStorageQuerySignHTTPDelete(opts []Pair) (pairStorageQuerySignHTTPDelete, error) {
+	result :=
+		pairStorageQuerySignHTTPDelete{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageQuerySignHTTPDelete{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	return result, nil
+}
+
+type pairStorageQuerySignHTTPRead struct {
+	pairs []Pair
+	// Required pairs
+	// Optional pairs
+}
+
+func (s *Storage) parsePairStorageQuerySignHTTPRead(opts []Pair) (pairStorageQuerySignHTTPRead, error) {
+	result :=
+		pairStorageQuerySignHTTPRead{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageQuerySignHTTPRead{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	return result, nil
+}
+
+type pairStorageQuerySignHTTPWrite struct {
+	pairs []Pair
+	// Required pairs
+	// Optional pairs
+}
+
+func (s *Storage) parsePairStorageQuerySignHTTPWrite(opts []Pair) (pairStorageQuerySignHTTPWrite, error) {
+	result :=
+		pairStorageQuerySignHTTPWrite{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageQuerySignHTTPWrite{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	return result, nil
+}
+
 type pairStorageRead struct {
 	pairs []Pair
 	// Required pairs
 	// Optional pairs
-	HasIoCallback bool
-	IoCallback    func([]byte)
-	HasOffset     bool
-	Offset        int64
-	HasSize       bool
-	Size          int64
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasIoCallback      bool
+	IoCallback         func([]byte)
+	HasOffset          bool
+	Offset             int64
+	HasSize            bool
+	Size               int64
 }
 
 func (s *Storage) parsePairStorageRead(opts []Pair) (pairStorageRead, error) {
@@ -458,6 +622,24 @@ func (s *Storage) parsePairStorageRead(opts []Pair) (pairStorageRead, error) {
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
@@ -488,8 +670,14 @@ type pairStorageStat struct {
 	pairs []Pair
 	// Required pairs
 	// Optional pairs
-	HasObjectMode bool
-	ObjectMode    ObjectMode
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasObjectMode      bool
+	ObjectMode         ObjectMode
 }
 
 func (s *Storage) parsePairStorageStat(opts []Pair) (pairStorageStat, error) {
@@ -498,6 +686,24 @@ func (s *Storage) parsePairStorageStat(opts []Pair) (pairStorageStat, error) {
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "object_mode":
 			if result.HasObjectMode {
 				continue
@@ -516,14 +722,22 @@ type pairStorageWrite struct {
 	pairs []Pair
 	// Required pairs
 	// Optional pairs
-	HasContentMd5  bool
-	ContentMd5     string
-	HasContentType bool
-	ContentType    string
-	HasIoCallback  bool
-	IoCallback     func([]byte)
-	HasOffset      bool
-	Offset         int64
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasContentMd5      bool
+	ContentMd5         string
+	HasContentType     bool
+	ContentType        string
+	HasIoCallback      bool
+	IoCallback         func([]byte)
+	HasOffset          bool
+	Offset             int64
+	HasUserMetadata    bool
+	UserMetadata       map[string]string
 }
 
 func (s *Storage) parsePairStorageWrite(opts []Pair) (pairStorageWrite, error) {
@@ -532,6 +746,24 @@ func (s *Storage) parsePairStorageWrite(opts []Pair) (pairStorageWrite, error) {
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "content_md5":
 			if result.HasContentMd5 {
 				continue
@@ -556,6 +788,12 @@ func (s *Storage) parsePairStorageWrite(opts []Pair) (pairStorageWrite, error) {
 			}
 			result.HasOffset = true
 			result.Offset = v.Value.(int64)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageWrite{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -773,6 +1011,63 @@ func (s *Storage) MoveWithContext(ctx context.Context, src string, dst string, p
 	}
 	return s.move(ctx, strings.ReplaceAll(src, "\\", "/"), strings.ReplaceAll(dst, "\\", "/"), opt)
 }
+func (s *Storage) QuerySignHTTPDelete(path string, expire time.Duration, pairs ...Pair) (req *http.Request, err error) {
+	ctx := context.Background()
+	return s.QuerySignHTTPDeleteWithContext(ctx, path, expire, pairs...)
+}
+func (s *Storage) QuerySignHTTPDeleteWithContext(ctx context.Context, path string, expire time.Duration, pairs ...Pair) (req *http.Request, err error) {
+	defer func() {
+		err =
+			s.formatError("query_sign_http_delete", err, path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.QuerySignHTTPDelete...)
+	var opt pairStorageQuerySignHTTPDelete
+
+	opt, err = s.parsePairStorageQuerySignHTTPDelete(pairs)
+	if err != nil {
+		return
+	}
+	return s.querySignHTTPDelete(ctx, strings.ReplaceAll(path, "\\", "/"), expire, opt)
+}
+func (s *Storage) QuerySignHTTPRead(path string, expire time.Duration, pairs ...Pair) (req *http.Request, err error) {
+	ctx := context.Background()
+	return s.QuerySignHTTPReadWithContext(ctx, path, expire, pairs...)
+}
+func (s *Storage) QuerySignHTTPReadWithContext(ctx context.Context, path string, expire time.Duration, pairs ...Pair) (req *http.Request, err error) {
+	defer func() {
+		err =
+			s.formatError("query_sign_http_read", err, path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.QuerySignHTTPRead...)
+	var opt pairStorageQuerySignHTTPRead
+
+	opt, err = s.parsePairStorageQuerySignHTTPRead(pairs)
+	if err != nil {
+		return
+	}
+	return s.querySignHTTPRead(ctx, strings.ReplaceAll(path, "\\", "/"), expire, opt)
+}
+func (s *Storage) QuerySignHTTPWrite(path string, size int64, expire time.Duration, pairs ...Pair) (req *http.Request, err error) {
+	ctx := context.Background()
+	return s.QuerySignHTTPWriteWithContext(ctx, path, size, expire, pairs...)
+}
+func (s *Storage) QuerySignHTTPWriteWithContext(ctx context.Context, path string, size int64, expire time.Duration, pairs ...Pair) (req *http.Request, err error) {
+	defer func() {
+		err =
+			s.formatError("query_sign_http_write", err, path)
+	}()
+
+	pairs = append(pairs, s.defaultPairs.QuerySignHTTPWrite...)
+	var opt pairStorageQuerySignHTTPWrite
+
+	opt, err = s.parsePairStorageQuerySignHTTPWrite(pairs)
+	if err != nil {
+		return
+	}
+	return s.querySignHTTPWrite(ctx, strings.ReplaceAll(path, "\\", "/"), size, expire, opt)
+}
 func (s *Storage) Read(path string, w io.Writer, pairs ...Pair) (n int64, err error) {
 	ctx := context.Background()
 	return s.ReadWithContext(ctx, path, w, pairs...)
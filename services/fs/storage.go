@@ -2,12 +2,15 @@ package fs
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/qingstor/go-mime"
 
@@ -19,6 +22,23 @@ import (
 func (s *Storage) delete(ctx context.Context, path string, opt pairStorageDelete) (err error) {
 	rp := s.getAbsPath(path)
 
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	// Only conditional operations need to be serialized.
+	if !cond.isEmpty() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	if err = s.checkConditions(rp, cond); err != nil {
+		return err
+	}
+
 	err = os.Remove(rp)
 	if err != nil && errors.Is(err, os.ErrNotExist) {
 		// Omit `file not exist` error here
@@ -56,6 +76,24 @@ func (s *Storage) copy(ctx context.Context, src string, dst string, opt pairStor
 	rs := s.getAbsPath(src)
 	rd := s.getAbsPath(dst)
 
+	// Conditions of copy are checked against the source file.
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	// Only conditional operations need to be serialized.
+	if !cond.isEmpty() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	if err = s.checkConditions(rs, cond); err != nil {
+		return err
+	}
+
 	srcFile, needClose, err := s.openFile(rs, os.O_RDONLY)
 	if err != nil {
 		return err
@@ -72,11 +110,29 @@ func (s *Storage) copy(ctx context.Context, src string, dst string, opt pairStor
 		defer dstFile.Close()
 	}
 
-	_, err = io.CopyBuffer(dstFile, srcFile, make([]byte, 1024*1024))
+	h := md5.New()
+	_, err = io.CopyBuffer(io.MultiWriter(dstFile, h), srcFile, make([]byte, 1024*1024))
 	if err != nil {
 		return err
 	}
-	return
+
+	if isStdPath(rd) {
+		return
+	}
+	if err = setContentMD5(rd, base64.StdEncoding.EncodeToString(h.Sum(nil))); err != nil {
+		return err
+	}
+	// Keep src file's user metadata unless user_metadata is set.
+	um := opt.UserMetadata
+	if !opt.HasUserMetadata {
+		um, err = getUserMetadata(rs)
+		if err != nil {
+			return err
+		}
+	}
+	// dst file is truncated in place, user metadata of the previous file
+	// must be replaced even if um is empty.
+	return setUserMetadata(rd, um)
 }
 
 func (s *Storage) create(path string, opt pairStorageCreate) (o *types.Object) {
@@ -107,6 +163,15 @@ func (s *Storage) createAppend(ctx context.Context, path string, opt pairStorage
 		}
 	}
 
+	if !isStdPath(rp) {
+		// The file is truncated in place, user metadata of the previous file
+		// will be removed while user_metadata is not set.
+		err = setUserMetadata(rp, opt.UserMetadata)
+		if err != nil {
+			return
+		}
+	}
+
 	o = s.newObject(true)
 	o.ID = rp
 	o.Path = path
@@ -269,12 +334,54 @@ func (s *Storage) move(ctx context.Context, src string, dst string, opt pairStor
 	return
 }
 
+func (s *Storage) querySignHTTPDelete(ctx context.Context, path string, expire time.Duration, opt pairStorageQuerySignHTTPDelete) (req *http.Request, err error) {
+	if s.signer == nil {
+		return nil, errHTTPSignerNotConfigured
+	}
+	return s.signer.SignDelete(path, expire)
+}
+
+func (s *Storage) querySignHTTPRead(ctx context.Context, path string, expire time.Duration, opt pairStorageQuerySignHTTPRead) (req *http.Request, err error) {
+	if s.signer == nil {
+		return nil, errHTTPSignerNotConfigured
+	}
+	return s.signer.SignRead(path, expire)
+}
+
+func (s *Storage) querySignHTTPWrite(ctx context.Context, path string, size int64, expire time.Duration, opt pairStorageQuerySignHTTPWrite) (req *http.Request, err error) {
+	if s.signer == nil {
+		return nil, errHTTPSignerNotConfigured
+	}
+	return s.signer.SignWrite(path, size, expire)
+}
+
 func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairStorageRead) (n int64, err error) {
 	var rc io.ReadCloser
 
 	rp := s.getAbsPath(path)
 
-	f, needClose, err := s.openFile(rp, os.O_RDONLY)
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	// Hold the lock until the file is opened, so that it could not be
+	// replaced by conditional writes after the check.
+	if !cond.isEmpty() {
+		s.mu.Lock()
+	}
+	var f *os.File
+	var needClose bool
+	err = s.checkConditions(rp, cond)
+	if err == nil {
+		f, needClose, err = s.openFile(rp, os.O_RDONLY)
+	}
+	if !cond.isEmpty() {
+		s.mu.Unlock()
+	}
 	if err != nil {
 		return
 	}
@@ -310,6 +417,23 @@ func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairSt
 func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o *types.Object, err error) {
 	rp := s.getAbsPath(path)
 
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	// Only conditional operations need to be serialized.
+	if !cond.isEmpty() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	if err = s.checkConditions(rp, cond); err != nil {
+		return nil, err
+	}
+
 	fi, err := s.statFile(rp)
 	if err != nil {
 		return nil, err
@@ -330,9 +454,23 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 		o.SetContentLength(fi.Size())
 		o.SetLastModified(fi.ModTime())
 
+		// Content md5 is only available for files written by this service,
+		// and will be dropped once the file is changed by others.
+		if !isStdPath(rp) {
+			if v, ok := getContentMD5(rp, fi); ok {
+				setObjectContentMD5(o, v)
+			}
+		}
+
 		if v := mime.DetectFilePath(path); v != "" {
 			o.SetContentType(v)
 		}
+
+		if !isStdPath(rp) {
+			if v, err := getUserMetadata(rp); err == nil && len(v) > 0 {
+				o.SetUserMetadata(v)
+			}
+		}
 	}
 
 	// Check if this file is a link.
@@ -360,19 +498,58 @@ func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int6
 
 	rp := s.getAbsPath(path)
 
-	f, needClose, err := s.createFile(rp)
-	if err != nil {
-		return
+	if opt.HasIoCallback {
+		r = iowrap.CallbackReader(r, opt.IoCallback)
 	}
-	if needClose {
-		defer f.Close()
+
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	// Only conditional operations need to be serialized.
+	if !cond.isEmpty() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	if err = s.checkConditions(rp, cond); err != nil {
+		return 0, err
 	}
 
-	if opt.HasIoCallback {
-		r = iowrap.CallbackReader(r, opt.IoCallback)
+	if opt.HasContentMd5 && !isStdPath(rp) {
+		n, err = s.createFileAtomic(rp, r, size, opt.ContentMd5)
+	} else {
+		var needClose bool
+		f, needClose, err = s.createFile(rp)
+		if err != nil {
+			return 0, err
+		}
+		if needClose {
+			defer f.Close()
+		}
+
+		h := md5.New()
+		n, err = io.CopyN(io.MultiWriter(f, h), r, size)
+		if err == nil && !isStdPath(rp) {
+			err = setContentMD5(rp, base64.StdEncoding.EncodeToString(h.Sum(nil)))
+		}
+	}
+	if err != nil {
+		return n, err
 	}
 
-	return io.CopyN(f, r, size)
+	if !isStdPath(rp) {
+		// Write replaces the whole object, user metadata of the previous file
+		// will be removed while user_metadata is not set.
+		err = setUserMetadata(rp, opt.UserMetadata)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
 }
 
 func (s *Storage) writeAppend(ctx context.Context, o *types.Object, r io.Reader, size int64, opt pairStorageWriteAppend) (n int64, err error) {
@@ -0,0 +1,79 @@
+package fs
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/services"
+)
+
+func TestStorage_WriteContentMd5(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	store, err := newStorager(pairs.WithWorkDir(tmpDir))
+	assert.NoError(t, err)
+
+	content := []byte("hello, world")
+	sum := md5.Sum(content)
+	contentMd5 := base64.StdEncoding.EncodeToString(sum[:])
+
+	_, err = store.Write("a", bytes.NewReader(content), int64(len(content)), pairs.WithContentMd5(contentMd5))
+	assert.NoError(t, err)
+
+	_, err = store.Write("b", bytes.NewReader(content), int64(len(content)), pairs.WithContentMd5("invalid"))
+	assert.ErrorIs(t, err, services.ErrChecksumMismatch)
+
+	// No partial or temp file should be left.
+	entries, err := os.ReadDir(tmpDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestStorage_Conditions(t *testing.T) {
+	store, err := newStorager(pairs.WithWorkDir(t.TempDir()))
+	assert.NoError(t, err)
+
+	content := []byte("hello, world")
+	sum := md5.Sum(content)
+	etag := base64.StdEncoding.EncodeToString(sum[:])
+
+	// if_none_match "*" only allows creating new files.
+	_, err = store.Write("a", bytes.NewReader(content), int64(len(content)), pairs.WithIfNoneMatch("*"))
+	assert.NoError(t, err)
+	_, err = store.Write("a", bytes.NewReader(content), int64(len(content)), pairs.WithIfNoneMatch("*"))
+	assert.ErrorIs(t, err, services.ErrPreconditionFailed)
+
+	// if_match requires the file to exist with the same etag.
+	_, err = store.Stat("a", pairs.WithIfMatch(`"`+etag+`"`))
+	assert.NoError(t, err)
+	_, err = store.Stat("a", pairs.WithIfMatch("invalid"))
+	assert.ErrorIs(t, err, services.ErrPreconditionFailed)
+	_, err = store.Write("b", bytes.NewReader(content), int64(len(content)), pairs.WithIfMatch("*"))
+	assert.ErrorIs(t, err, services.ErrPreconditionFailed)
+
+	// if_modified_since fails while the file has not been modified.
+	var buf bytes.Buffer
+	_, err = store.Read("a", &buf, pairs.WithIfModifiedSince(time.Now().Add(time.Hour)))
+	assert.ErrorIs(t, err, services.ErrPreconditionFailed)
+	_, err = store.Read("a", &buf, pairs.WithIfModifiedSince(time.Now().Add(-time.Hour)))
+	assert.NoError(t, err)
+	assert.Equal(t, content, buf.Bytes())
+
+	// Conditions of copy are checked against the source file.
+	err = store.Copy("a", "c", pairs.WithIfNoneMatch(etag))
+	assert.ErrorIs(t, err, services.ErrPreconditionFailed)
+	err = store.Copy("a", "c", pairs.WithIfMatch(etag))
+	assert.NoError(t, err)
+
+	err = store.Delete("c", pairs.WithIfMatch("invalid"))
+	assert.ErrorIs(t, err, services.ErrPreconditionFailed)
+	err = store.Delete("c", pairs.WithIfMatch(etag))
+	assert.NoError(t, err)
+}
@@ -50,3 +50,12 @@ func TestLinker(t *testing.T) {
 	}
 	tests.TestLinker(t, setupTest(t))
 }
+
+func TestHTTPSigner(t *testing.T) {
+	if os.Getenv("STORAGE_FS_INTEGRATION_TEST") != "on" {
+		t.Skipf("STORAGE_FS_INTEGRATION_TEST is not 'on', skipped")
+	}
+	tests.TestStorageHTTPSignerRead(t, setupHTTPSignerTest(t))
+	tests.TestStorageHTTPSignerWrite(t, setupHTTPSignerTest(t))
+	tests.TestStorageHTTPSignerDelete(t, setupHTTPSignerTest(t))
+}
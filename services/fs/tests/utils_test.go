@@ -1,10 +1,12 @@
 package tests
 
 import (
+	"net/http/httptest"
 	"testing"
 
 	fs "go.beyondstorage.io/services/fs/v4"
 	ps "go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/pkg/httpsign"
 	"go.beyondstorage.io/v5/types"
 )
 
@@ -18,3 +20,26 @@ func setupTest(t *testing.T) types.Storager {
 	}
 	return store
 }
+
+// setupHTTPSignerTest creates a storager which is served by httpsign.Handler.
+func setupHTTPSignerTest(t *testing.T) types.Storager {
+	tmpDir := t.TempDir()
+	t.Logf("Setup http signer test at %s", tmpDir)
+
+	key := "test-key"
+	srv := httptest.NewUnstartedServer(nil)
+	t.Cleanup(srv.Close)
+
+	store, err := fs.NewStorager(
+		ps.WithWorkDir(tmpDir),
+		fs.WithHTTPSignerEndpoint("http://"+srv.Listener.Addr().String()),
+		fs.WithHTTPSignerKey(key),
+	)
+	if err != nil {
+		t.Fatalf("new storager: %v", err)
+	}
+
+	srv.Config.Handler = httpsign.NewHandler(store, []byte(key))
+	srv.Start()
+	return store
+}
@@ -1,15 +1,28 @@
 package fs
 
 import (
+	"crypto/md5"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
+
+	"go.beyondstorage.io/v5/pkg/httpsign"
 	"go.beyondstorage.io/v5/services"
 	typ "go.beyondstorage.io/v5/types"
 )
 
+// errHTTPSignerNotConfigured is returned by query_sign_http_* operations while
+// http_signer_endpoint is not set.
+var errHTTPSignerNotConfigured = fmt.Errorf("http signer is not configured: %w", services.ErrCapabilityInsufficient)
+
 // Std{in/out/err} support
 const (
 	Stdin  = "/dev/stdin"
@@ -25,6 +38,12 @@ type Storage struct {
 	defaultPairs DefaultStoragePairs
 	features     StorageFeatures
 
+	// mu makes conditions checking and conditional operations atomic inside this process.
+	mu sync.Mutex
+	// signer is used by query_sign_http_* operations, nil means the
+	// http signer is not configured.
+	signer *httpsign.Signer
+
 	typ.UnimplementedStorager
 	typ.UnimplementedCopier
 	typ.UnimplementedMover
@@ -32,6 +51,7 @@ type Storage struct {
 	typ.UnimplementedAppender
 	typ.UnimplementedDirer
 	typ.UnimplementedLinker
+	typ.UnimplementedStorageHTTPSigner
 }
 
 // String implements Storager.String
@@ -73,6 +93,12 @@ func newStorager(pairs ...typ.Pair) (store *Storage, err error) {
 		}
 		store.workDir = workDir
 	}
+	if opt.HasHTTPSignerEndpoint {
+		store.signer, err = httpsign.NewSigner(opt.HTTPSignerEndpoint, []byte(opt.HTTPSignerKey))
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Check and create work dir
 	err = os.MkdirAll(store.workDir, 0755)
@@ -132,36 +158,163 @@ func (s *Storage) createFileWithFlag(absPath string, flag int) (f *os.File, need
 		return os.Stderr, false, nil
 	}
 
+	err = s.prepareFile(absPath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// There are two situations we handled here:
+	// - The file is exist and not a dir
+	// - The file is not exist
+	f, err = os.OpenFile(absPath, flag, 0666)
+	if err != nil {
+		return nil, false, err
+	}
+	return f, true, nil
+}
+
+// prepareFile will check whether absPath could be written as a file, and
+// create its parent dirs if the file is not exist.
+func (s *Storage) prepareFile(absPath string) (err error) {
 	fi, err := os.Lstat(absPath)
 	if err == nil {
 		// File is exist, let's check if the file is a dir or a symlink.
 		if fi.IsDir() || fi.Mode()&os.ModeSymlink != 0 {
-			return nil, false, services.ErrObjectModeInvalid
+			return services.ErrObjectModeInvalid
 		}
+		return nil
 	}
-	if err != nil && !errors.Is(err, os.ErrNotExist) {
+	if !errors.Is(err, os.ErrNotExist) {
 		// Something error other than ErrNotExist happened, return directly.
-		return
+		return err
 	}
-	// Set stat error to nil.
-	err = nil
 
-	// The file is not exist, we should create the dir and create the file.
-	if fi == nil {
-		err = os.MkdirAll(filepath.Dir(absPath), 0755)
+	// The file is not exist, we should create the dir.
+	return os.MkdirAll(filepath.Dir(absPath), 0755)
+}
+
+// createFileAtomic will write size bytes from r into absPath via a temp file,
+// the temp file will be renamed to absPath only if its content md5 equals to
+// contentMD5, so that there will be no partial file.
+func (s *Storage) createFileAtomic(absPath string, r io.Reader, size int64, contentMD5 string) (n int64, err error) {
+	err = s.prepareFile(absPath)
+	if err != nil {
+		return 0, err
+	}
+
+	tmpPath := absPath + ".tmp-" + uuid.NewString()
+	f, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
 		if err != nil {
-			return nil, false, err
+			_ = os.Remove(tmpPath)
 		}
+	}()
+
+	h := md5.New()
+	n, err = io.CopyN(io.MultiWriter(f, h), r, size)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return n, err
+	}
+	if base64.StdEncoding.EncodeToString(h.Sum(nil)) != contentMD5 {
+		return 0, services.ErrChecksumMismatch
 	}
+	if err = os.Rename(tmpPath, absPath); err != nil {
+		return 0, err
+	}
+	return n, setContentMD5(absPath, contentMD5)
+}
 
-	// There are two situations we handled here:
-	// - The file is exist and not a dir
-	// - The file is not exist
-	f, err = os.OpenFile(absPath, flag, 0666)
+// fileContentMD5 returns the base64 encoded md5 of the file.
+func (s *Storage) fileContentMD5(absPath string) (string, error) {
+	f, err := os.Open(absPath)
 	if err != nil {
-		return nil, false, err
+		return "", err
 	}
-	return f, true, nil
+	defer f.Close()
+
+	h := md5.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// setObjectContentMD5 sets the content md5 of o as both content_md5 and etag.
+//
+// Local files have no etag, following RFC-14 the content md5 persisted
+// while writing is used instead, so that conditions could be checked.
+func setObjectContentMD5(o *typ.Object, v string) {
+	o.SetContentMd5(v)
+	o.SetEtag(v)
+}
+
+// conditions are the precondition pairs of an operation.
+type conditions struct {
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+}
+
+func (c conditions) isEmpty() bool {
+	return !c.HasIfMatch && !c.HasIfModifiedSince && !c.HasIfNoneMatch
+}
+
+// checkConditions checks conditions against the file at absPath.
+//
+// The content md5 of the file will be used as etag, and it will only be
+// calculated while etag related conditions are set and the persisted one
+// is not available.
+func (s *Storage) checkConditions(absPath string, c conditions) error {
+	if c.isEmpty() {
+		return nil
+	}
+
+	fi, err := os.Stat(absPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	exist := err == nil
+
+	var etag string
+	if exist && ((c.HasIfMatch && c.IfMatch != "*") || (c.HasIfNoneMatch && c.IfNoneMatch != "*")) {
+		var ok bool
+		if etag, ok = getContentMD5(absPath, fi); !ok {
+			etag, err = s.fileContentMD5(absPath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.HasIfMatch {
+		if !exist || (c.IfMatch != "*" && !etagMatch(c.IfMatch, etag)) {
+			return services.ErrPreconditionFailed
+		}
+	}
+	if c.HasIfNoneMatch && exist {
+		if c.IfNoneMatch == "*" || etagMatch(c.IfNoneMatch, etag) {
+			return services.ErrPreconditionFailed
+		}
+	}
+	// if_modified_since will be ignored while file is not exist.
+	if c.HasIfModifiedSince && exist && !fi.ModTime().After(c.IfModifiedSince) {
+		return services.ErrPreconditionFailed
+	}
+	return nil
+}
+
+// etagMatch compares etags without the surrounding quotes.
+func etagMatch(a, b string) bool {
+	return strings.Trim(a, `"`) == strings.Trim(b, `"`)
 }
 
 func (s *Storage) statFile(absPath string) (fi os.FileInfo, err error) {
@@ -181,6 +334,10 @@ func (s *Storage) statFile(absPath string) (fi os.FileInfo, err error) {
 	return
 }
 
+func isStdPath(absPath string) bool {
+	return absPath == Stdin || absPath == Stdout || absPath == Stderr
+}
+
 func (s *Storage) getAbsPath(path string) string {
 	if filepath.IsAbs(path) {
 		return path
@@ -0,0 +1,36 @@
+//go:build !darwin && !freebsd && !linux && !netbsd
+// +build !darwin,!freebsd,!linux,!netbsd
+
+package fs
+
+import (
+	"fmt"
+	"os"
+
+	"go.beyondstorage.io/v5/services"
+)
+
+// setUserMetadata is not supported on this platform, only empty user metadata
+// is accepted as files never have user metadata here.
+func setUserMetadata(absPath string, m map[string]string) error {
+	if len(m) == 0 {
+		return nil
+	}
+	return fmt.Errorf("set user metadata: %w", services.ErrCapabilityInsufficient)
+}
+
+// getUserMetadata always returns no user metadata on this platform.
+func getUserMetadata(absPath string) (map[string]string, error) {
+	return nil, nil
+}
+
+// setContentMD5 does nothing as content md5 could not be persisted on this
+// platform.
+func setContentMD5(absPath string, contentMD5 string) error {
+	return nil
+}
+
+// getContentMD5 always returns no content md5 on this platform.
+func getContentMD5(absPath string, fi os.FileInfo) (contentMD5 string, ok bool) {
+	return "", false
+}
@@ -0,0 +1,134 @@
+//go:build darwin || freebsd || linux || netbsd
+// +build darwin freebsd linux netbsd
+
+package fs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// userMetadataPrefix is the namespace of extended attributes that are used to
+	// store user metadata.
+	userMetadataPrefix = "user."
+	// contentMD5Name is the extended attribute that the content md5 of the file
+	// is stored in, it's reserved and hidden from user metadata.
+	contentMD5Name = userMetadataPrefix + "beyondstorage.content-md5"
+)
+
+// setUserMetadata replaces all user metadata of the file at absPath with m.
+func setUserMetadata(absPath string, m map[string]string) error {
+	names, err := listUserMetadataNames(absPath)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err = unix.Removexattr(absPath, name); err != nil {
+			return err
+		}
+	}
+
+	for k, v := range m {
+		err = unix.Setxattr(absPath, userMetadataPrefix+strings.ToLower(k), []byte(v), 0)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getUserMetadata returns user metadata of the file at absPath, keys will be
+// normalized into lower case.
+//
+// File systems which don't support extended attributes will be treated as
+// having no user metadata.
+func getUserMetadata(absPath string) (map[string]string, error) {
+	names, err := listUserMetadataNames(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]string, len(names))
+	for _, name := range names {
+		size, err := unix.Getxattr(absPath, name, nil)
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size)
+		size, err = unix.Getxattr(absPath, name, buf)
+		if err != nil {
+			return nil, err
+		}
+		m[strings.ToLower(strings.TrimPrefix(name, userMetadataPrefix))] = string(buf[:size])
+	}
+	return m, nil
+}
+
+// listUserMetadataNames returns the names of extended attributes under user
+// namespace.
+func listUserMetadataNames(absPath string) ([]string, error) {
+	size, err := unix.Listxattr(absPath, nil)
+	if errors.Is(err, unix.ENOTSUP) {
+		return nil, nil
+	}
+	if err != nil || size == 0 {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	size, err = unix.Listxattr(absPath, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, name := range bytes.Split(buf[:size], []byte{0}) {
+		if string(name) == contentMD5Name {
+			continue
+		}
+		if bytes.HasPrefix(name, []byte(userMetadataPrefix)) {
+			names = append(names, string(name))
+		}
+	}
+	return names, nil
+}
+
+// setContentMD5 persists the base64 encoded content md5 of the file at
+// absPath along with its size and modified time, so that the content md5
+// will be invalidated once the file is changed by others.
+//
+// File systems which don't support extended attributes will be ignored.
+func setContentMD5(absPath string, contentMD5 string) error {
+	fi, err := os.Stat(absPath)
+	if err != nil {
+		return err
+	}
+	v := fmt.Sprintf("%s %d %d", contentMD5, fi.Size(), fi.ModTime().UnixNano())
+	err = unix.Setxattr(absPath, contentMD5Name, []byte(v), 0)
+	if errors.Is(err, unix.ENOTSUP) {
+		return nil
+	}
+	return err
+}
+
+// getContentMD5 returns the persisted content md5 of the file at absPath,
+// ok will be false if it's missing or the file has been changed since then.
+func getContentMD5(absPath string, fi os.FileInfo) (contentMD5 string, ok bool) {
+	buf := make([]byte, 128)
+	size, err := unix.Getxattr(absPath, contentMD5Name, buf)
+	if err != nil {
+		return "", false
+	}
+
+	var n, mtime int64
+	_, err = fmt.Sscanf(string(buf[:size]), "%s %d %d", &contentMD5, &n, &mtime)
+	if err != nil || n != fi.Size() || mtime != fi.ModTime().UnixNano() {
+		return "", false
+	}
+	return contentMD5, true
+}
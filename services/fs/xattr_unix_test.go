@@ -0,0 +1,124 @@
+//go:build darwin || freebsd || linux || netbsd
+// +build darwin freebsd linux netbsd
+
+package fs
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/types"
+)
+
+func TestStorage_UserMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := setUserMetadata(tmpDir, map[string]string{"probe": "x"})
+	if err != nil {
+		t.Skipf("extended attributes are not supported: %v", err)
+	}
+
+	store, err := newStorager(pairs.WithWorkDir(tmpDir))
+	assert.NoError(t, err)
+
+	content := []byte("hello, world")
+	_, err = store.Write("a", bytes.NewReader(content), int64(len(content)),
+		pairs.WithUserMetadata(map[string]string{"Pipeline": "ingest"}))
+	assert.NoError(t, err)
+
+	o, err := store.Stat("a")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"pipeline": "ingest"}, o.MustGetUserMetadata())
+
+	// Copy keeps src file's user metadata by default.
+	err = store.Copy("a", "b")
+	assert.NoError(t, err)
+	o, err = store.Stat("b")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"pipeline": "ingest"}, o.MustGetUserMetadata())
+
+	// Copy replaces user metadata while user_metadata is set.
+	err = store.Copy("a", "c", pairs.WithUserMetadata(map[string]string{"stage": "2"}))
+	assert.NoError(t, err)
+	o, err = store.Stat("c")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"stage": "2"}, o.MustGetUserMetadata())
+
+	// Write without user_metadata removes user metadata of the previous
+	// file, no matter the file is overwritten in place or atomically.
+	sum := md5.Sum(content)
+	for _, ps := range [][]types.Pair{
+		nil,
+		{pairs.WithContentMd5(base64.StdEncoding.EncodeToString(sum[:]))},
+	} {
+		_, err = store.Write("b", bytes.NewReader(content), int64(len(content)), ps...)
+		assert.NoError(t, err)
+		o, err = store.Stat("b")
+		assert.NoError(t, err)
+		_, ok := o.GetUserMetadata()
+		assert.False(t, ok)
+
+		err = store.Copy("a", "b")
+		assert.NoError(t, err)
+	}
+
+	// Copy from a file without user metadata removes user metadata of dst.
+	_, err = store.Write("d", bytes.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+	err = store.Copy("d", "c")
+	assert.NoError(t, err)
+	o, err = store.Stat("c")
+	assert.NoError(t, err)
+	_, ok := o.GetUserMetadata()
+	assert.False(t, ok)
+}
+
+func TestStorage_ContentMD5(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := setUserMetadata(tmpDir, map[string]string{"probe": "x"})
+	if err != nil {
+		t.Skipf("extended attributes are not supported: %v", err)
+	}
+
+	store, err := newStorager(pairs.WithWorkDir(tmpDir))
+	assert.NoError(t, err)
+
+	content := []byte("hello, world")
+	sum := md5.Sum(content)
+	contentMd5 := base64.StdEncoding.EncodeToString(sum[:])
+
+	// Content md5 is computed while writing with or without content_md5.
+	_, err = store.Write("a", bytes.NewReader(content), int64(len(content)), pairs.WithContentMd5(contentMd5))
+	assert.NoError(t, err)
+	_, err = store.Write("b", bytes.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+	err = store.Copy("b", "c")
+	assert.NoError(t, err)
+
+	for _, path := range []string{"a", "b", "c"} {
+		o, err := store.Stat(path)
+		assert.NoError(t, err)
+		assert.Equal(t, contentMd5, o.MustGetContentMd5(), path)
+		assert.Equal(t, contentMd5, o.MustGetEtag(), path)
+		_, ok := o.GetUserMetadata()
+		assert.False(t, ok, path)
+	}
+
+	// Content md5 is dropped once the file is changed by others.
+	err = os.WriteFile(filepath.Join(tmpDir, "a"), []byte("changed"), 0644)
+	assert.NoError(t, err)
+	o, err := store.Stat("a")
+	assert.NoError(t, err)
+	_, ok := o.GetContentMd5()
+	assert.False(t, ok)
+	_, ok = o.GetEtag()
+	assert.False(t, ok)
+}
@@ -213,9 +213,12 @@ func (f *Factory) storageFeatures() (s types.StorageFeatures) {
 	s.Create = true
 	s.CreateDir = true
 	s.Delete = true
+	s.GetLifecycle = true
 	s.List = true
+	s.ListVersion = true
 	s.Metadata = true
 	s.Read = true
+	s.SetLifecycle = true
 	s.Stat = true
 	s.Write = true
 	s.WriteEmptyObject = true
@@ -691,9 +694,17 @@ func (s *Storage) CreatePageWithContext(ctx context.Context, path string, pairs
 }
 
 type pairStorageDelete struct {
-	pairs         []types.Pair
-	HasObjectMode bool
-	ObjectMode    types.ObjectMode
+	pairs              []types.Pair
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasObjectMode      bool
+	ObjectMode         types.ObjectMode
+	HasVersionID       bool
+	VersionID          string
 }
 
 func (s *Storage) parsePairStorageDelete(opts []types.Pair) (pairStorageDelete, error) {
@@ -702,12 +713,36 @@ func (s *Storage) parsePairStorageDelete(opts []types.Pair) (pairStorageDelete,
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "object_mode":
 			if result.HasObjectMode {
 				continue
 			}
 			result.HasObjectMode = true
 			result.ObjectMode = v.Value.(types.ObjectMode)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageDelete{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -758,6 +793,41 @@ func (s *Storage) FetchWithContext(ctx context.Context, path string, url string,
 	return
 }
 
+type pairStorageGetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageGetLifecycle(opts []types.Pair) (pairStorageGetLifecycle, error) {
+	result :=
+		pairStorageGetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageGetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) GetLifecycle(pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	ctx := context.Background()
+	return s.GetLifecycleWithContext(ctx, pairs...)
+}
+func (s *Storage) GetLifecycleWithContext(ctx context.Context, pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	defer func() {
+		err =
+			s.formatError("get_lifecycle", err)
+	}()
+	pairs = append(pairs, s.defaultPairs.GetLifecycle...)
+	var opt pairStorageGetLifecycle
+
+	opt, err = s.parsePairStorageGetLifecycle(pairs)
+	if err != nil {
+		return
+	}
+	return s.getLifecycle(ctx, opt)
+}
+
 type pairStorageList struct {
 	pairs       []types.Pair
 	HasListMode bool
@@ -851,6 +921,41 @@ func (s *Storage) ListMultipartWithContext(ctx context.Context, o *types.Object,
 	return
 }
 
+type pairStorageListVersion struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageListVersion(opts []types.Pair) (pairStorageListVersion, error) {
+	result :=
+		pairStorageListVersion{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageListVersion{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) ListVersion(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	ctx := context.Background()
+	return s.ListVersionWithContext(ctx, path, pairs...)
+}
+func (s *Storage) ListVersionWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	defer func() {
+		err =
+			s.formatError("list_version", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.ListVersion...)
+	var opt pairStorageListVersion
+
+	opt, err = s.parsePairStorageListVersion(pairs)
+	if err != nil {
+		return
+	}
+	return s.listVersion(ctx, strings.ReplaceAll(path, "\\", "/"), opt)
+}
+
 type pairStorageMetadata struct {
 	pairs []types.Pair
 }
@@ -1077,15 +1182,23 @@ func (s *Storage) QuerySignHTTPWriteMultipartWithContext(ctx context.Context, o
 }
 
 type pairStorageRead struct {
-	pairs            []types.Pair
-	HasEncryptionKey bool
-	EncryptionKey    []byte
-	HasIoCallback    bool
-	IoCallback       func([]byte)
-	HasOffset        bool
-	Offset           int64
-	HasSize          bool
-	Size             int64
+	pairs              []types.Pair
+	HasEncryptionKey   bool
+	EncryptionKey      []byte
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasIoCallback      bool
+	IoCallback         func([]byte)
+	HasOffset          bool
+	Offset             int64
+	HasSize            bool
+	Size               int64
+	HasVersionID       bool
+	VersionID          string
 }
 
 func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, error) {
@@ -1100,6 +1213,24 @@ func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, erro
 			}
 			result.HasEncryptionKey = true
 			result.EncryptionKey = v.Value.([]byte)
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
@@ -1118,6 +1249,12 @@ func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, erro
 			}
 			result.HasSize = true
 			result.Size = v.Value.(int64)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageRead{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -1143,10 +1280,103 @@ func (s *Storage) ReadWithContext(ctx context.Context, path string, w io.Writer,
 	return s.read(ctx, strings.ReplaceAll(path, "\\", "/"), w, opt)
 }
 
+type pairStorageRestore struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageRestore(opts []types.Pair) (pairStorageRestore, error) {
+	result :=
+		pairStorageRestore{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageRestore{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) Restore(path string, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("restore")
+	return
+}
+func (s *Storage) RestoreWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("restore")
+	return
+}
+
+type pairStorageSetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetLifecycle(opts []types.Pair) (pairStorageSetLifecycle, error) {
+	result :=
+		pairStorageSetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetLifecycle(rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.SetLifecycleWithContext(ctx, rules, pairs...)
+}
+func (s *Storage) SetLifecycleWithContext(ctx context.Context, rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("set_lifecycle", err)
+	}()
+	pairs = append(pairs, s.defaultPairs.SetLifecycle...)
+	var opt pairStorageSetLifecycle
+
+	opt, err = s.parsePairStorageSetLifecycle(pairs)
+	if err != nil {
+		return
+	}
+	return s.setLifecycle(ctx, rules, opt)
+}
+
+type pairStorageSetStorageClass struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetStorageClass(opts []types.Pair) (pairStorageSetStorageClass, error) {
+	result :=
+		pairStorageSetStorageClass{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetStorageClass{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetStorageClass(path string, class string, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_storage_class")
+	return
+}
+func (s *Storage) SetStorageClassWithContext(ctx context.Context, path string, class string, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_storage_class")
+	return
+}
+
 type pairStorageStat struct {
-	pairs         []types.Pair
-	HasObjectMode bool
-	ObjectMode    types.ObjectMode
+	pairs              []types.Pair
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasObjectMode      bool
+	ObjectMode         types.ObjectMode
+	HasVersionID       bool
+	VersionID          string
 }
 
 func (s *Storage) parsePairStorageStat(opts []types.Pair) (pairStorageStat, error) {
@@ -1155,12 +1385,36 @@ func (s *Storage) parsePairStorageStat(opts []types.Pair) (pairStorageStat, erro
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "object_mode":
 			if result.HasObjectMode {
 				continue
 			}
 			result.HasObjectMode = true
 			result.ObjectMode = v.Value.(types.ObjectMode)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageStat{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -1187,19 +1441,27 @@ func (s *Storage) StatWithContext(ctx context.Context, path string, pairs ...typ
 }
 
 type pairStorageWrite struct {
-	pairs            []types.Pair
-	HasContentMd5    bool
-	ContentMd5       string
-	HasContentType   bool
-	ContentType      string
-	HasEncryptionKey bool
-	EncryptionKey    []byte
-	HasIoCallback    bool
-	IoCallback       func([]byte)
-	HasKmsKeyName    bool
-	KmsKeyName       string
-	HasStorageClass  bool
-	StorageClass     string
+	pairs              []types.Pair
+	HasContentMd5      bool
+	ContentMd5         string
+	HasContentType     bool
+	ContentType        string
+	HasEncryptionKey   bool
+	EncryptionKey      []byte
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasIoCallback      bool
+	IoCallback         func([]byte)
+	HasKmsKeyName      bool
+	KmsKeyName         string
+	HasStorageClass    bool
+	StorageClass       string
+	HasUserMetadata    bool
+	UserMetadata       map[string]string
 }
 
 func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, error) {
@@ -1226,6 +1488,24 @@ func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, er
 			}
 			result.HasEncryptionKey = true
 			result.EncryptionKey = v.Value.([]byte)
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
@@ -1244,6 +1524,12 @@ func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, er
 			}
 			result.HasStorageClass = true
 			result.StorageClass = v.Value.(string)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageWrite{}, services.PairUnsupportedError{Pair: v}
 		}
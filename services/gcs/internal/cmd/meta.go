@@ -36,14 +36,17 @@ var Metadata = def.Metadata{
 			VirtualDir:       true,
 			WriteEmptyObject: true,
 
-			Create:    true,
-			CreateDir: true,
-			Delete:    true,
-			List:      true,
-			Metadata:  true,
-			Read:      true,
-			Stat:      true,
-			Write:     true,
+			Create:       true,
+			CreateDir:    true,
+			Delete:       true,
+			GetLifecycle: true,
+			List:         true,
+			ListVersion:  true,
+			Metadata:     true,
+			Read:         true,
+			SetLifecycle: true,
+			Stat:         true,
+			Write:        true,
 		},
 
 		Create: []def.Pair{
@@ -54,6 +57,10 @@ var Metadata = def.Metadata{
 		},
 		Delete: []def.Pair{
 			def.PairObjectMode,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
+			def.PairVersionID,
 		},
 		List: []def.Pair{
 			def.PairListMode,
@@ -62,18 +69,30 @@ var Metadata = def.Metadata{
 			def.PairOffset,
 			def.PairIoCallback,
 			def.PairSize,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
 			pairEncryptionKey,
+			def.PairVersionID,
 		},
 		Write: []def.Pair{
 			def.PairContentMD5,
 			def.PairContentType,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
 			def.PairIoCallback,
 			pairStorageClass,
 			pairEncryptionKey,
 			pairKmsKeyName,
+			def.PairUserMetadata,
 		},
 		Stat: []def.Pair{
 			def.PairObjectMode,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
+			def.PairVersionID,
 		},
 	},
 }
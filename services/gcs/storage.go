@@ -71,7 +71,26 @@ func (s *Storage) delete(ctx context.Context, path string, opt pairStorageDelete
 		}
 		rp += "/"
 	}
-	err = s.bucket.Object(rp).Delete(ctx)
+	object := s.bucket.Object(rp)
+	if opt.HasVersionID {
+		generation, err := parseVersionID(opt.VersionID)
+		if err != nil {
+			return err
+		}
+		object = object.Generation(generation)
+	}
+	object, err = formatConditions(ctx, object, conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	})
+	if err != nil {
+		return err
+	}
+	err = object.Delete(ctx)
 	if err != nil && errors.Is(err, gs.ErrObjectNotExist) {
 		// Omit `ErrObjectNotExist` error here.
 		// ref: [GSP-46](https://github.com/beyondstorage/specs/blob/master/rfcs/46-idempotent-delete.md)
@@ -83,6 +102,14 @@ func (s *Storage) delete(ctx context.Context, path string, opt pairStorageDelete
 	return nil
 }
 
+func (s *Storage) getLifecycle(ctx context.Context, opt pairStorageGetLifecycle) (rules []*types.LifecycleRule, err error) {
+	attrs, err := s.bucket.Attrs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.parseLifecycleRules(attrs.Lifecycle.Rules), nil
+}
+
 func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (oi *types.ObjectIterator, err error) {
 	if !opt.HasListMode {
 		// Support `ListModePrefix` as the default `ListMode`.
@@ -113,6 +140,13 @@ func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (o
 	return types.NewObjectIterator(ctx, nextFn, input), nil
 }
 
+func (s *Storage) listVersion(ctx context.Context, path string, opt pairStorageListVersion) (oi *types.ObjectIterator, err error) {
+	input := &objectPageStatus{
+		prefix: s.getAbsPath(path),
+	}
+	return types.NewObjectIterator(ctx, s.nextVersionObjectPage, input), nil
+}
+
 func (s *Storage) metadata(opt pairStorageMetadata) (meta *types.StorageMeta) {
 	meta = types.NewStorageMeta()
 	meta.Name = s.name
@@ -158,6 +192,40 @@ func (s *Storage) nextObjectPageByDir(ctx context.Context, page *types.ObjectPag
 	return nil
 }
 
+// nextVersionObjectPage will return all versions of the object in one page.
+//
+// gcs lists versions from the oldest to the newest, so we need to collect
+// them all before reversing.
+func (s *Storage) nextVersionObjectPage(ctx context.Context, page *types.ObjectPage) error {
+	input := page.Status.(*objectPageStatus)
+	it := s.bucket.Objects(ctx, &gs.Query{
+		Prefix:   input.prefix,
+		Versions: true,
+	})
+	for {
+		object, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		// Prefix could match other objects, only versions of this path are needed.
+		if object.Name != input.prefix {
+			continue
+		}
+		o, err := s.formatFileObject(object)
+		if err != nil {
+			return err
+		}
+		page.Data = append(page.Data, o)
+	}
+	for i, j := 0, len(page.Data)-1; i < j; i, j = i+1, j-1 {
+		page.Data[i], page.Data[j] = page.Data[j], page.Data[i]
+	}
+	return types.IterateDone
+}
+
 func (s *Storage) nextObjectPageByPrefix(ctx context.Context, page *types.ObjectPage) error {
 	input := page.Status.(*objectPageStatus)
 	it := s.bucket.Objects(ctx, &gs.Query{
@@ -189,6 +257,24 @@ func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairSt
 	if opt.HasEncryptionKey {
 		object = object.Key(opt.EncryptionKey)
 	}
+	if opt.HasVersionID {
+		generation, err := parseVersionID(opt.VersionID)
+		if err != nil {
+			return 0, err
+		}
+		object = object.Generation(generation)
+	}
+	object, err = formatConditions(ctx, object, conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	})
+	if err != nil {
+		return 0, err
+	}
 	if opt.HasOffset && !opt.HasSize {
 		rc, err = object.NewRangeReader(ctx, opt.Offset, -1)
 	} else if !opt.HasOffset && opt.HasSize {
@@ -213,6 +299,29 @@ func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairSt
 	return io.Copy(w, rc)
 }
 
+func (s *Storage) setLifecycle(ctx context.Context, rules []*types.LifecycleRule, opt pairStorageSetLifecycle) (err error) {
+	lc, err := s.formatLifecycle(rules)
+	if err != nil {
+		return err
+	}
+
+	attrs, err := s.bucket.Attrs(ctx)
+	if err != nil {
+		return err
+	}
+	// Only rules managed by this storager will be replaced, other rules are
+	// kept untouched.
+	for _, v := range attrs.Lifecycle.Rules {
+		if !s.isManagedLifecycleRule(v) {
+			lc.Rules = append(lc.Rules, v)
+		}
+	}
+	// Make sure rules have not been changed since read.
+	_, err = s.bucket.If(gs.BucketConditions{MetagenerationMatch: attrs.MetaGeneration}).
+		Update(ctx, gs.BucketAttrsToUpdate{Lifecycle: &lc})
+	return err
+}
+
 func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o *types.Object, err error) {
 	rp := s.getAbsPath(path)
 	if opt.HasObjectMode && opt.ObjectMode.IsDir() {
@@ -222,7 +331,26 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 		}
 		rp += "/"
 	}
-	attr, err := s.bucket.Object(rp).Attrs(ctx)
+	object := s.bucket.Object(rp)
+	if opt.HasVersionID {
+		generation, err := parseVersionID(opt.VersionID)
+		if err != nil {
+			return nil, err
+		}
+		object = object.Generation(generation)
+	}
+	object, err = formatConditions(ctx, object, conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	attr, err := object.Attrs(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -253,6 +381,17 @@ func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int6
 	if opt.HasEncryptionKey {
 		object = object.Key(opt.EncryptionKey)
 	}
+	object, err = formatConditions(ctx, object, conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	})
+	if err != nil {
+		return 0, err
+	}
 	w := object.NewWriter(ctx)
 	defer func() {
 		cerr := w.Close()
@@ -271,6 +410,9 @@ func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int6
 	if opt.HasKmsKeyName {
 		w.KMSKeyName = opt.KmsKeyName
 	}
+	if opt.HasUserMetadata {
+		w.Metadata = formatUserMetadata(opt.UserMetadata)
+	}
 	if opt.HasIoCallback {
 		r = iowrap.CallbackReader(r, opt.IoCallback)
 	}
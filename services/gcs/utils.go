@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	gs "cloud.google.com/go/storage"
 	"golang.org/x/oauth2"
@@ -181,7 +183,10 @@ const (
 
 // ref: https://cloud.google.com/storage/docs/json_api/v1/status-codes
 func formatError(err error) error {
-	if _, ok := err.(services.InternalError); ok {
+	// Keep errors which already wrap our error codes, for example the
+	// ErrCapabilityInsufficient returned while converting lifecycle rules.
+	var ie services.InternalError
+	if errors.As(err, &ie) {
 		return err
 	}
 
@@ -200,11 +205,74 @@ func formatError(err error) error {
 		return fmt.Errorf("%w: %v", services.ErrObjectNotExist, err)
 	case http.StatusForbidden:
 		return fmt.Errorf("%w: %v", services.ErrPermissionDenied, err)
+	case http.StatusPreconditionFailed, http.StatusNotModified:
+		return fmt.Errorf("%w: %v", services.ErrPreconditionFailed, err)
 	default:
 		return fmt.Errorf("%w, %v", services.ErrUnexpected, err)
 	}
 }
 
+// conditions are the precondition pairs of an operation.
+type conditions struct {
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+}
+
+// formatConditions applies conditions to the object handle.
+//
+// gcs only supports preconditions on generation, so we will check conditions
+// against object's attrs and use the generation to make sure the object is
+// not changed after checking.
+func formatConditions(ctx context.Context, object *gs.ObjectHandle, c conditions) (*gs.ObjectHandle, error) {
+	if !c.HasIfMatch && !c.HasIfModifiedSince && !c.HasIfNoneMatch {
+		return object, nil
+	}
+	// if_none_match "*" could be applied directly.
+	if !c.HasIfMatch && !c.HasIfModifiedSince && c.IfNoneMatch == "*" {
+		return object.If(gs.Conditions{DoesNotExist: true}), nil
+	}
+
+	attr, err := object.Attrs(ctx)
+	if err != nil && !errors.Is(err, gs.ErrObjectNotExist) {
+		return nil, err
+	}
+	if err != nil {
+		if c.HasIfMatch {
+			return nil, services.ErrPreconditionFailed
+		}
+		return object.If(gs.Conditions{DoesNotExist: true}), nil
+	}
+
+	if c.HasIfMatch && c.IfMatch != "*" && !etagMatch(c.IfMatch, attr.Etag) {
+		return nil, services.ErrPreconditionFailed
+	}
+	if c.HasIfNoneMatch && (c.IfNoneMatch == "*" || etagMatch(c.IfNoneMatch, attr.Etag)) {
+		return nil, services.ErrPreconditionFailed
+	}
+	if c.HasIfModifiedSince && !attr.Updated.After(c.IfModifiedSince) {
+		return nil, services.ErrPreconditionFailed
+	}
+	return object.If(gs.Conditions{GenerationMatch: attr.Generation}), nil
+}
+
+// parseVersionID converts version id into gcs object's generation.
+func parseVersionID(id string) (int64, error) {
+	generation, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("version id %s is invalid: %w", id, err)
+	}
+	return generation, nil
+}
+
+// etagMatch compares etags without the surrounding quotes.
+func etagMatch(a, b string) bool {
+	return strings.Trim(a, `"`) == strings.Trim(b, `"`)
+}
+
 // newStorage will create a new client.
 func (f *Factory) newStorage() (st *Storage, err error) {
 	s, err := f.newService()
@@ -285,6 +353,12 @@ func (s *Storage) formatFileObject(v *gs.ObjectAttrs) (o *typ.Object, err error)
 	if len(v.MD5) > 0 {
 		o.SetContentMd5(base64.StdEncoding.EncodeToString(v.MD5))
 	}
+	if v.Generation != 0 {
+		o.SetVersionID(strconv.FormatInt(v.Generation, 10))
+	}
+	if len(v.Metadata) > 0 {
+		o.SetUserMetadata(formatUserMetadata(v.Metadata))
+	}
 
 	var sm ObjectSystemMetadata
 	if value := v.StorageClass; value != "" {
@@ -298,6 +372,96 @@ func (s *Storage) formatFileObject(v *gs.ObjectAttrs) (o *typ.Object, err error)
 	return
 }
 
+// formatUserMetadata normalizes keys of user metadata into lower case.
+func formatUserMetadata(m map[string]string) map[string]string {
+	um := make(map[string]string, len(m))
+	for k, v := range m {
+		um[strings.ToLower(k)] = v
+	}
+	return um
+}
+
 func (s *Storage) newObject(done bool) *typ.Object {
 	return typ.NewObject(s, done)
 }
+
+// formatLifecycle converts lifecycle rules into gcs lifecycle.
+//
+// gcs rules can't be filtered by prefix and only contain one action, so a
+// rule will be split into multiple gcs rules, and only rules for the whole
+// bucket are supported. Disabled rules will be dropped as gcs doesn't have
+// rule status.
+func (s *Storage) formatLifecycle(rules []*typ.LifecycleRule) (gs.Lifecycle, error) {
+	lc := gs.Lifecycle{Rules: make([]gs.LifecycleRule, 0, len(rules))}
+	for _, r := range rules {
+		if r.Disabled {
+			continue
+		}
+		if s.getAbsPath(r.Prefix) != "" {
+			return gs.Lifecycle{}, fmt.Errorf("lifecycle rule with prefix %q: %w", r.Prefix, services.ErrCapabilityInsufficient)
+		}
+		if r.AbortMultipartDays > 0 {
+			return gs.Lifecycle{}, fmt.Errorf("lifecycle rule with abort multipart: %w", services.ErrCapabilityInsufficient)
+		}
+
+		if r.ExpireDays > 0 {
+			lc.Rules = append(lc.Rules, gs.LifecycleRule{
+				Action:    gs.LifecycleAction{Type: gs.DeleteAction},
+				Condition: gs.LifecycleCondition{AgeInDays: int64(r.ExpireDays)},
+			})
+		}
+		for _, t := range r.Transitions {
+			lc.Rules = append(lc.Rules, gs.LifecycleRule{
+				Action:    gs.LifecycleAction{Type: gs.SetStorageClassAction, StorageClass: t.StorageClass},
+				Condition: gs.LifecycleCondition{AgeInDays: int64(t.Days)},
+			})
+		}
+	}
+	return lc, nil
+}
+
+// parseLifecycleRules converts gcs lifecycle rules into lifecycle rules.
+//
+// Every gcs rule will be returned as a separate rule, and only rules managed
+// by this storager will be returned.
+func (s *Storage) parseLifecycleRules(rules []gs.LifecycleRule) []*typ.LifecycleRule {
+	output := make([]*typ.LifecycleRule, 0, len(rules))
+	for _, v := range rules {
+		if !s.isManagedLifecycleRule(v) {
+			continue
+		}
+
+		days := int(v.Condition.AgeInDays)
+		switch v.Action.Type {
+		case gs.DeleteAction:
+			output = append(output, &typ.LifecycleRule{ExpireDays: days})
+		case gs.SetStorageClassAction:
+			output = append(output, &typ.LifecycleRule{
+				Transitions: []typ.LifecycleTransition{
+					{Days: days, StorageClass: v.Action.StorageClass},
+				},
+			})
+		}
+	}
+	return output
+}
+
+// isManagedLifecycleRule checks whether rule could be represented by
+// LifecycleRule.
+//
+// gcs rules apply to the whole bucket, so no rule is managed by storagers
+// with a work dir. Rules with conditions other than age or other actions are
+// invisible to get_lifecycle and will be kept untouched by set_lifecycle.
+func (s *Storage) isManagedLifecycleRule(rule gs.LifecycleRule) bool {
+	if s.getAbsPath("") != "" {
+		return false
+	}
+	if rule.Action.Type != gs.DeleteAction && rule.Action.Type != gs.SetStorageClassAction {
+		return false
+	}
+	c := rule.Condition
+	return c.AgeInDays > 0 && c.CreatedBefore.IsZero() && c.CustomTimeBefore.IsZero() &&
+		c.DaysSinceCustomTime == 0 && c.DaysSinceNoncurrentTime == 0 &&
+		c.NoncurrentTimeBefore.IsZero() && c.Liveness == gs.LiveAndArchived &&
+		len(c.MatchesStorageClasses) == 0 && c.NumNewerVersions == 0
+}
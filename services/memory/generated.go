@@ -70,8 +70,32 @@ func setStorageSystemMetadata(s *types.StorageMeta, sm StorageSystemMetadata) {
 	s.SetSystemMetadata(sm)
 }
 
+// WithEnableVersioning will apply enable_versioning value to Options.
+//
+// set this to `true` to keep all versions of objects, they will be kept in memory until deleted via version_id
+func WithEnableVersioning() types.Pair {
+	return types.Pair{Key: "enable_versioning", Value: true}
+}
+
+// WithHTTPSignerEndpoint will apply http_signer_endpoint value to Options.
+//
+// is the url that httpsign.Handler serving this storage is listening at, like `http://127.0.0.1:8080`
+func WithHTTPSignerEndpoint(v string) types.Pair {
+	return types.Pair{Key: "http_signer_endpoint", Value: v}
+}
+
+// WithHTTPSignerKey will apply http_signer_key value to Options.
+//
+// is the HMAC key shared with httpsign.Handler
+func WithHTTPSignerKey(v string) types.Pair {
+	return types.Pair{Key: "http_signer_key", Value: v}
+}
+
 type Factory struct {
-	WorkDir string
+	EnableVersioning   bool
+	HTTPSignerEndpoint string
+	HTTPSignerKey      string
+	WorkDir            string
 }
 
 func (f *Factory) FromString(conn string) (err error) {
@@ -110,6 +134,12 @@ func (f *Factory) FromString(conn string) (err error) {
 				value = vs[1]
 			}
 			switch key {
+			case "enable_versioning":
+				f.EnableVersioning = true
+			case "http_signer_endpoint":
+				f.HTTPSignerEndpoint = value
+			case "http_signer_key":
+				f.HTTPSignerKey = value
 			case "work_dir":
 				f.WorkDir = value
 			}
@@ -120,6 +150,12 @@ func (f *Factory) FromString(conn string) (err error) {
 func (f *Factory) WithPairs(ps ...types.Pair) (err error) {
 	for _, v := range ps {
 		switch v.Key {
+		case "enable_versioning":
+			f.EnableVersioning = v.Value.(bool)
+		case "http_signer_endpoint":
+			f.HTTPSignerEndpoint = v.Value.(string)
+		case "http_signer_key":
+			f.HTTPSignerKey = v.Value.(string)
 		case "work_dir":
 			f.WorkDir = v.Value.(string)
 		}
@@ -146,8 +182,12 @@ func (f *Factory) storageFeatures() (s types.StorageFeatures) {
 	s.CreateDir = true
 	s.Delete = true
 	s.List = true
+	s.ListVersion = true
 	s.Metadata = true
 	s.Move = true
+	s.QuerySignHTTPDelete = true
+	s.QuerySignHTTPRead = true
+	s.QuerySignHTTPWrite = true
 	s.Read = true
 	s.Stat = true
 	s.Write = true
@@ -366,7 +406,15 @@ func (s *Storage) CompleteMultipartWithContext(ctx context.Context, o *types.Obj
 }
 
 type pairStorageCopy struct {
-	pairs []types.Pair
+	pairs              []types.Pair
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasUserMetadata    bool
+	UserMetadata       map[string]string
 }
 
 func (s *Storage) parsePairStorageCopy(opts []types.Pair) (pairStorageCopy, error) {
@@ -375,6 +423,30 @@ func (s *Storage) parsePairStorageCopy(opts []types.Pair) (pairStorageCopy, erro
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageCopy{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -434,7 +506,9 @@ func (s *Storage) Create(path string, pairs ...types.Pair) (o *types.Object) {
 }
 
 type pairStorageCreateAppend struct {
-	pairs []types.Pair
+	pairs           []types.Pair
+	HasUserMetadata bool
+	UserMetadata    map[string]string
 }
 
 func (s *Storage) parsePairStorageCreateAppend(opts []types.Pair) (pairStorageCreateAppend, error) {
@@ -443,6 +517,12 @@ func (s *Storage) parsePairStorageCreateAppend(opts []types.Pair) (pairStorageCr
 
 	for _, v := range opts {
 		switch v.Key {
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageCreateAppend{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -604,9 +684,17 @@ func (s *Storage) CreatePageWithContext(ctx context.Context, path string, pairs
 }
 
 type pairStorageDelete struct {
-	pairs         []types.Pair
-	HasObjectMode bool
-	ObjectMode    types.ObjectMode
+	pairs              []types.Pair
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasObjectMode      bool
+	ObjectMode         types.ObjectMode
+	HasVersionID       bool
+	VersionID          string
 }
 
 func (s *Storage) parsePairStorageDelete(opts []types.Pair) (pairStorageDelete, error) {
@@ -615,12 +703,36 @@ func (s *Storage) parsePairStorageDelete(opts []types.Pair) (pairStorageDelete,
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "object_mode":
 			if result.HasObjectMode {
 				continue
 			}
 			result.HasObjectMode = true
 			result.ObjectMode = v.Value.(types.ObjectMode)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageDelete{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -671,6 +783,31 @@ func (s *Storage) FetchWithContext(ctx context.Context, path string, url string,
 	return
 }
 
+type pairStorageGetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageGetLifecycle(opts []types.Pair) (pairStorageGetLifecycle, error) {
+	result :=
+		pairStorageGetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageGetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) GetLifecycle(pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	err = types.NewOperationNotImplementedError("get_lifecycle")
+	return
+}
+func (s *Storage) GetLifecycleWithContext(ctx context.Context, pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	err = types.NewOperationNotImplementedError("get_lifecycle")
+	return
+}
+
 type pairStorageList struct {
 	pairs       []types.Pair
 	HasListMode bool
@@ -764,6 +901,41 @@ func (s *Storage) ListMultipartWithContext(ctx context.Context, o *types.Object,
 	return
 }
 
+type pairStorageListVersion struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageListVersion(opts []types.Pair) (pairStorageListVersion, error) {
+	result :=
+		pairStorageListVersion{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageListVersion{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) ListVersion(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	ctx := context.Background()
+	return s.ListVersionWithContext(ctx, path, pairs...)
+}
+func (s *Storage) ListVersionWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	defer func() {
+		err =
+			s.formatError("list_version", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.ListVersion...)
+	var opt pairStorageListVersion
+
+	opt, err = s.parsePairStorageListVersion(pairs)
+	if err != nil {
+		return
+	}
+	return s.listVersion(ctx, strings.ReplaceAll(path, "\\", "/"), opt)
+}
+
 type pairStorageMetadata struct {
 	pairs []types.Pair
 }
@@ -891,12 +1063,22 @@ func (s *Storage) parsePairStorageQuerySignHTTPDelete(opts []types.Pair) (pairSt
 	return result, nil
 }
 func (s *Storage) QuerySignHTTPDelete(path string, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
-	err = types.NewOperationNotImplementedError("query_sign_http_delete")
-	return
+	ctx := context.Background()
+	return s.QuerySignHTTPDeleteWithContext(ctx, path, expire, pairs...)
 }
 func (s *Storage) QuerySignHTTPDeleteWithContext(ctx context.Context, path string, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
-	err = types.NewOperationNotImplementedError("query_sign_http_delete")
-	return
+	defer func() {
+		err =
+			s.formatError("query_sign_http_delete", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.QuerySignHTTPDelete...)
+	var opt pairStorageQuerySignHTTPDelete
+
+	opt, err = s.parsePairStorageQuerySignHTTPDelete(pairs)
+	if err != nil {
+		return
+	}
+	return s.querySignHTTPDelete(ctx, strings.ReplaceAll(path, "\\", "/"), expire, opt)
 }
 
 type pairStorageQuerySignHTTPListMultipart struct {
@@ -941,12 +1123,22 @@ func (s *Storage) parsePairStorageQuerySignHTTPRead(opts []types.Pair) (pairStor
 	return result, nil
 }
 func (s *Storage) QuerySignHTTPRead(path string, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
-	err = types.NewOperationNotImplementedError("query_sign_http_read")
-	return
+	ctx := context.Background()
+	return s.QuerySignHTTPReadWithContext(ctx, path, expire, pairs...)
 }
 func (s *Storage) QuerySignHTTPReadWithContext(ctx context.Context, path string, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
-	err = types.NewOperationNotImplementedError("query_sign_http_read")
-	return
+	defer func() {
+		err =
+			s.formatError("query_sign_http_read", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.QuerySignHTTPRead...)
+	var opt pairStorageQuerySignHTTPRead
+
+	opt, err = s.parsePairStorageQuerySignHTTPRead(pairs)
+	if err != nil {
+		return
+	}
+	return s.querySignHTTPRead(ctx, strings.ReplaceAll(path, "\\", "/"), expire, opt)
 }
 
 type pairStorageQuerySignHTTPWrite struct {
@@ -966,12 +1158,22 @@ func (s *Storage) parsePairStorageQuerySignHTTPWrite(opts []types.Pair) (pairSto
 	return result, nil
 }
 func (s *Storage) QuerySignHTTPWrite(path string, size int64, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
-	err = types.NewOperationNotImplementedError("query_sign_http_write")
-	return
+	ctx := context.Background()
+	return s.QuerySignHTTPWriteWithContext(ctx, path, size, expire, pairs...)
 }
 func (s *Storage) QuerySignHTTPWriteWithContext(ctx context.Context, path string, size int64, expire time.Duration, pairs ...types.Pair) (req *http.Request, err error) {
-	err = types.NewOperationNotImplementedError("query_sign_http_write")
-	return
+	defer func() {
+		err =
+			s.formatError("query_sign_http_write", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.QuerySignHTTPWrite...)
+	var opt pairStorageQuerySignHTTPWrite
+
+	opt, err = s.parsePairStorageQuerySignHTTPWrite(pairs)
+	if err != nil {
+		return
+	}
+	return s.querySignHTTPWrite(ctx, strings.ReplaceAll(path, "\\", "/"), size, expire, opt)
 }
 
 type pairStorageQuerySignHTTPWriteMultipart struct {
@@ -1000,13 +1202,21 @@ func (s *Storage) QuerySignHTTPWriteMultipartWithContext(ctx context.Context, o
 }
 
 type pairStorageRead struct {
-	pairs         []types.Pair
-	HasIoCallback bool
-	IoCallback    func([]byte)
-	HasOffset     bool
-	Offset        int64
-	HasSize       bool
-	Size          int64
+	pairs              []types.Pair
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasIoCallback      bool
+	IoCallback         func([]byte)
+	HasOffset          bool
+	Offset             int64
+	HasSize            bool
+	Size               int64
+	HasVersionID       bool
+	VersionID          string
 }
 
 func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, error) {
@@ -1015,6 +1225,24 @@ func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, erro
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
@@ -1033,6 +1261,12 @@ func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, erro
 			}
 			result.HasSize = true
 			result.Size = v.Value.(int64)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageRead{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -1058,10 +1292,93 @@ func (s *Storage) ReadWithContext(ctx context.Context, path string, w io.Writer,
 	return s.read(ctx, strings.ReplaceAll(path, "\\", "/"), w, opt)
 }
 
+type pairStorageRestore struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageRestore(opts []types.Pair) (pairStorageRestore, error) {
+	result :=
+		pairStorageRestore{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageRestore{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) Restore(path string, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("restore")
+	return
+}
+func (s *Storage) RestoreWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("restore")
+	return
+}
+
+type pairStorageSetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetLifecycle(opts []types.Pair) (pairStorageSetLifecycle, error) {
+	result :=
+		pairStorageSetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetLifecycle(rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_lifecycle")
+	return
+}
+func (s *Storage) SetLifecycleWithContext(ctx context.Context, rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_lifecycle")
+	return
+}
+
+type pairStorageSetStorageClass struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetStorageClass(opts []types.Pair) (pairStorageSetStorageClass, error) {
+	result :=
+		pairStorageSetStorageClass{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetStorageClass{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetStorageClass(path string, class string, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_storage_class")
+	return
+}
+func (s *Storage) SetStorageClassWithContext(ctx context.Context, path string, class string, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_storage_class")
+	return
+}
+
 type pairStorageStat struct {
-	pairs         []types.Pair
-	HasObjectMode bool
-	ObjectMode    types.ObjectMode
+	pairs              []types.Pair
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasObjectMode      bool
+	ObjectMode         types.ObjectMode
+	HasVersionID       bool
+	VersionID          string
 }
 
 func (s *Storage) parsePairStorageStat(opts []types.Pair) (pairStorageStat, error) {
@@ -1070,12 +1387,36 @@ func (s *Storage) parsePairStorageStat(opts []types.Pair) (pairStorageStat, erro
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "object_mode":
 			if result.HasObjectMode {
 				continue
 			}
 			result.HasObjectMode = true
 			result.ObjectMode = v.Value.(types.ObjectMode)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageStat{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -1102,13 +1443,21 @@ func (s *Storage) StatWithContext(ctx context.Context, path string, pairs ...typ
 }
 
 type pairStorageWrite struct {
-	pairs          []types.Pair
-	HasContentMd5  bool
-	ContentMd5     string
-	HasContentType bool
-	ContentType    string
-	HasIoCallback  bool
-	IoCallback     func([]byte)
+	pairs              []types.Pair
+	HasContentMd5      bool
+	ContentMd5         string
+	HasContentType     bool
+	ContentType        string
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasIoCallback      bool
+	IoCallback         func([]byte)
+	HasUserMetadata    bool
+	UserMetadata       map[string]string
 }
 
 func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, error) {
@@ -1129,12 +1478,36 @@ func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, er
 			}
 			result.HasContentType = true
 			result.ContentType = v.Value.(string)
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
 			}
 			result.HasIoCallback = true
 			result.IoCallback = v.Value.(func([]byte))
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageWrite{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -6,10 +6,17 @@ import (
 )
 
 var Metadata = def.Metadata{
-	Name:  "memory",
-	Pairs: []def.Pair{},
+	Name: "memory",
+	Pairs: []def.Pair{
+		pairEnableVersioning,
+		pairHTTPSignerEndpoint,
+		pairHTTPSignerKey,
+	},
 	Infos: []def.Info{},
 	Factory: []def.Pair{
+		pairEnableVersioning,
+		pairHTTPSignerEndpoint,
+		pairHTTPSignerKey,
 		def.PairWorkDir,
 	},
 	Service: def.Service{},
@@ -17,42 +24,87 @@ var Metadata = def.Metadata{
 		Features: types.StorageFeatures{
 			WriteEmptyObject: true,
 
-			Create:       true,
-			CreateAppend: true,
-			CreateDir:    true,
-			CommitAppend: true,
-			Copy:         true,
-			Delete:       true,
-			List:         true,
-			Metadata:     true,
-			Move:         true,
-			Read:         true,
-			Stat:         true,
-			Write:        true,
-			WriteAppend:  true,
+			Create:              true,
+			CreateAppend:        true,
+			CreateDir:           true,
+			CommitAppend:        true,
+			Copy:                true,
+			Delete:              true,
+			List:                true,
+			ListVersion:         true,
+			Metadata:            true,
+			Move:                true,
+			QuerySignHTTPDelete: true,
+			QuerySignHTTPRead:   true,
+			QuerySignHTTPWrite:  true,
+			Read:                true,
+			Stat:                true,
+			Write:               true,
+			WriteAppend:         true,
 		},
 
+		Copy: []def.Pair{
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
+			def.PairUserMetadata,
+		},
 		Create: []def.Pair{
 			def.PairObjectMode,
 		},
+		CreateAppend: []def.Pair{
+			def.PairUserMetadata,
+		},
 		Delete: []def.Pair{
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
 			def.PairObjectMode,
+			def.PairVersionID,
 		},
 		List: []def.Pair{
 			def.PairListMode,
 		},
 		Read: []def.Pair{
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
 			def.PairOffset,
 			def.PairIoCallback,
 			def.PairSize,
+			def.PairVersionID,
 		},
 		Write: []def.Pair{
 			def.PairContentMD5,
 			def.PairContentType,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
 			def.PairIoCallback,
+			def.PairUserMetadata,
 		},
 		Stat: []def.Pair{
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
 			def.PairObjectMode,
+			def.PairVersionID,
 		},
 	},
 }
+
+var pairEnableVersioning = def.Pair{
+	Name:        "enable_versioning",
+	Type:        def.Type{Name: "bool"},
+	Description: "set this to `true` to keep all versions of objects, they will be kept in memory until deleted via version_id",
+}
+var pairHTTPSignerEndpoint = def.Pair{
+	Name:        "http_signer_endpoint",
+	Type:        def.Type{Name: "string"},
+	Description: "is the url that httpsign.Handler serving this storage is listening at, like `http://127.0.0.1:8080`",
+}
+var pairHTTPSignerKey = def.Pair{
+	Name:        "http_signer_key",
+	Type:        def.Type{Name: "string"},
+	Description: "is the HMAC key shared with httpsign.Handler",
+}
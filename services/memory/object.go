@@ -1,8 +1,12 @@
 package memory
 
 import (
+	"crypto/md5"
+	"encoding/base64"
+	"hash"
 	"strings"
 	"sync"
+	"time"
 
 	"go.beyondstorage.io/v5/types"
 )
@@ -10,6 +14,14 @@ import (
 type object struct {
 	mode   types.ObjectMode
 	length int64
+	// md5 is the base64 encoded md5 of data, it's updated along with data.
+	md5 string
+	// appendHash is the running md5 of data for appendable objects.
+	appendHash   hash.Hash
+	lastModified time.Time
+	// versionID is the version of current data, empty means not versioned.
+	versionID    string
+	userMetadata map[string]string
 
 	name   string
 	parent *object
@@ -18,6 +30,29 @@ type object struct {
 	data   []byte
 }
 
+// version is a snapshot of an object's data.
+type version struct {
+	id           string
+	data         []byte
+	length       int64
+	md5          string
+	lastModified time.Time
+	userMetadata map[string]string
+}
+
+// object converts the version into a detached read object.
+func (v *version) object() *object {
+	return &object{
+		mode:         types.ModeRead,
+		length:       v.length,
+		md5:          v.md5,
+		lastModified: v.lastModified,
+		versionID:    v.id,
+		userMetadata: v.userMetadata,
+		data:         v.data,
+	}
+}
+
 func newObject(name string, parent *object, mode types.ObjectMode) *object {
 	return &object{
 		mode:   mode,
@@ -27,6 +62,28 @@ func newObject(name string, parent *object, mode types.ObjectMode) *object {
 	}
 }
 
+// contentMD5 returns the base64 encoded md5 of data.
+func (o *object) contentMD5() string {
+	if o.md5 == "" {
+		// Objects without data, like the ones created by dir operations.
+		sum := md5.Sum(o.data)
+		return base64.StdEncoding.EncodeToString(sum[:])
+	}
+	return o.md5
+}
+
+// appendData appends p to data and updates md5 incrementally.
+func (o *object) appendData(p []byte) {
+	if o.appendHash == nil {
+		o.appendHash = md5.New()
+		o.appendHash.Write(o.data)
+	}
+	o.appendHash.Write(p)
+	o.data = append(o.data, p...)
+	o.length += int64(len(p))
+	o.md5 = base64.StdEncoding.EncodeToString(o.appendHash.Sum(nil))
+}
+
 func (o *object) getChild(name string) *object {
 	o.mu.Lock()
 	defer o.mu.Unlock()
@@ -2,9 +2,13 @@ package memory
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/base64"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
+	"time"
 
 	"go.beyondstorage.io/v5/pkg/iowrap"
 	"go.beyondstorage.io/v5/services"
@@ -19,10 +23,25 @@ func (s *Storage) copy(ctx context.Context, src string, dst string, opt pairStor
 	rs := s.absPath(src)
 	rd := s.absPath(dst)
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	ro := s.root.getObjectByPath(rs)
 	if ro == nil {
 		return services.ErrObjectNotExist
 	}
+	// Conditions of copy are checked against the source object.
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	if err = cond.check(ro); err != nil {
+		return err
+	}
 
 	r := s.root.getObjectByPath(rd)
 	if r != nil && r.mode.IsDir() {
@@ -36,9 +55,17 @@ func (s *Storage) copy(ctx context.Context, src string, dst string, opt pairStor
 
 	o.length = ro.length
 	o.mode = ro.mode
+	o.md5 = ro.md5
+	o.lastModified = time.Now()
+	if opt.HasUserMetadata {
+		o.userMetadata = formatUserMetadata(opt.UserMetadata)
+	} else {
+		o.userMetadata = formatUserMetadata(ro.userMetadata)
+	}
 
 	o.data = make([]byte, ro.length)
 	copy(o.data, ro.data)
+	s.addVersion(rd, o)
 	return nil
 }
 
@@ -53,11 +80,22 @@ func (s *Storage) create(path string, opt pairStorageCreate) (o *types.Object) {
 }
 
 func (s *Storage) createAppend(ctx context.Context, path string, opt pairStorageCreateAppend) (o *types.Object, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	child := s.root.insertChildByPath(s.absPath(path))
 	if child == nil {
 		return nil, services.ErrObjectModeInvalid
 	}
 	child.mode = types.ModeRead | types.ModeAppend
+	child.appendData(nil)
+	child.lastModified = time.Now()
+	// Appendable objects are not versioned.
+	child.versionID = ""
+	child.userMetadata = nil
+	if opt.HasUserMetadata {
+		child.userMetadata = formatUserMetadata(opt.UserMetadata)
+	}
 
 	o = types.NewObject(s, true)
 	o.ID = s.absPath(path)
@@ -69,6 +107,9 @@ func (s *Storage) createAppend(ctx context.Context, path string, opt pairStorage
 }
 
 func (s *Storage) createDir(ctx context.Context, path string, opt pairStorageCreateDir) (o *types.Object, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.root.makeDirAll(strings.Split(s.absPath(path), "/")) == nil {
 		return nil, services.ErrObjectModeInvalid
 	}
@@ -81,16 +122,43 @@ func (s *Storage) createDir(ctx context.Context, path string, opt pairStorageCre
 }
 
 func (s *Storage) delete(ctx context.Context, path string, opt pairStorageDelete) (err error) {
-	o := s.root.getObjectByPath(s.absPath(path))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rp := s.absPath(path)
+	var o *object
+	if opt.HasVersionID {
+		o = s.getVersion(rp, opt.VersionID)
+	} else {
+		o = s.root.getObjectByPath(rp)
+	}
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	if err = cond.check(o); err != nil {
+		return err
+	}
 	if o == nil {
 		return nil
 	}
+	if opt.HasVersionID {
+		s.removeVersion(rp, opt.VersionID)
+		return nil
+	}
 	o.parent.removeChild(o.name)
 	return nil
 }
 
 func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (oi *types.ObjectIterator, err error) {
 	fn := types.NextObjectFunc(func(ctx context.Context, page *types.ObjectPage) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
 		o := s.root.getObjectByPath(s.absPath(path))
 		if o == nil {
 			// If the object is not exist, we should return IterateDone instead.
@@ -110,6 +178,16 @@ func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (o
 			xo.Path = s.relPath(path + "/" + k)
 			xo.Mode = v.mode
 			xo.SetContentLength(v.length)
+			xo.SetLastModified(v.lastModified)
+			if v.versionID != "" {
+				xo.SetVersionID(v.versionID)
+			}
+			if v.userMetadata != nil {
+				xo.SetUserMetadata(formatUserMetadata(v.userMetadata))
+			}
+			if v.mode.IsRead() {
+				setObjectContentMD5(xo, v.contentMD5())
+			}
 
 			page.Data = append(page.Data, xo)
 		}
@@ -118,6 +196,37 @@ func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (o
 	return types.NewObjectIterator(ctx, fn, nil), nil
 }
 
+func (s *Storage) listVersion(ctx context.Context, path string, opt pairStorageListVersion) (oi *types.ObjectIterator, err error) {
+	rp := s.absPath(path)
+
+	fn := types.NextObjectFunc(func(ctx context.Context, page *types.ObjectPage) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		vs := s.versions[rp]
+		// Versions are stored from the oldest to the newest.
+		for i := len(vs) - 1; i >= 0; i-- {
+			v := vs[i]
+
+			o := types.NewObject(s, true)
+			o.ID = rp
+			o.Path = path
+			o.Mode = types.ModeRead
+			o.SetContentLength(v.length)
+			o.SetLastModified(v.lastModified)
+			setObjectContentMD5(o, v.md5)
+			o.SetVersionID(v.id)
+			if v.userMetadata != nil {
+				o.SetUserMetadata(formatUserMetadata(v.userMetadata))
+			}
+
+			page.Data = append(page.Data, o)
+		}
+		return types.IterateDone
+	})
+	return types.NewObjectIterator(ctx, fn, nil), nil
+}
+
 func (s *Storage) metadata(opt pairStorageMetadata) (meta *types.StorageMeta) {
 	return &types.StorageMeta{
 		Name:    "memory",
@@ -129,6 +238,9 @@ func (s *Storage) move(ctx context.Context, src string, dst string, opt pairStor
 	rs := s.absPath(src)
 	rd := s.absPath(dst)
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	rso := s.root.getObjectByPath(rs)
 	if rso == nil {
 		return services.ErrObjectNotExist
@@ -139,17 +251,70 @@ func (s *Storage) move(ctx context.Context, src string, dst string, opt pairStor
 		return services.ErrObjectModeInvalid
 	}
 
-	ps := strings.Split(dst, "/")
+	ps := strings.Split(rd, "/")
+	last := len(ps) - 1
+	p := s.root.makeDirAll(ps[:last])
+	if p == nil {
+		return services.ErrObjectModeInvalid
+	}
+
 	rso.parent.removeChild(rso.name)
-	rso.parent.insertChild(ps[len(ps)-1], rso)
+	rso.name = ps[last]
+	rso.parent = p
+	p.insertChild(rso.name, rso)
+
+	s.moveVersions(rs, rd)
 	return
 }
 
+func (s *Storage) querySignHTTPDelete(ctx context.Context, path string, expire time.Duration, opt pairStorageQuerySignHTTPDelete) (req *http.Request, err error) {
+	if s.signer == nil {
+		return nil, errHTTPSignerNotConfigured
+	}
+	return s.signer.SignDelete(path, expire)
+}
+
+func (s *Storage) querySignHTTPRead(ctx context.Context, path string, expire time.Duration, opt pairStorageQuerySignHTTPRead) (req *http.Request, err error) {
+	if s.signer == nil {
+		return nil, errHTTPSignerNotConfigured
+	}
+	return s.signer.SignRead(path, expire)
+}
+
+func (s *Storage) querySignHTTPWrite(ctx context.Context, path string, size int64, expire time.Duration, opt pairStorageQuerySignHTTPWrite) (req *http.Request, err error) {
+	if s.signer == nil {
+		return nil, errHTTPSignerNotConfigured
+	}
+	return s.signer.SignWrite(path, size, expire)
+}
+
 func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairStorageRead) (n int64, err error) {
-	o := s.root.getObjectByPath(s.absPath(path))
+	s.mu.Lock()
+	var o *object
+	if opt.HasVersionID {
+		o = s.getVersion(s.absPath(path), opt.VersionID)
+	} else {
+		o = s.root.getObjectByPath(s.absPath(path))
+	}
 	if o == nil {
+		s.mu.Unlock()
 		return 0, services.ErrObjectNotExist
 	}
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	if err = cond.check(o); err != nil {
+		s.mu.Unlock()
+		return 0, err
+	}
+	// Data will be replaced instead of modified by write, it's safe to use it without lock.
+	data := o.data
+	s.mu.Unlock()
 
 	offset := int64(0)
 	if opt.HasOffset {
@@ -161,9 +326,9 @@ func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairSt
 
 	var written int
 	if !opt.HasSize {
-		written, err = w.Write(o.data[offset:])
+		written, err = w.Write(data[offset:])
 	} else {
-		written, err = w.Write(o.data[offset : offset+opt.Size])
+		written, err = w.Write(data[offset : offset+opt.Size])
 	}
 
 	if err != nil {
@@ -173,16 +338,45 @@ func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairSt
 }
 
 func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o *types.Object, err error) {
-	ro := s.root.getObjectByPath(s.absPath(path))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ro *object
+	if opt.HasVersionID {
+		ro = s.getVersion(s.absPath(path), opt.VersionID)
+	} else {
+		ro = s.root.getObjectByPath(s.absPath(path))
+	}
 	if ro == nil {
 		return nil, services.ErrObjectNotExist
 	}
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	if err = cond.check(ro); err != nil {
+		return nil, err
+	}
 
 	o = types.NewObject(s, true)
 	o.ID = s.absPath(path)
 	o.Path = path
 	o.Mode = ro.mode
 	o.SetContentLength(ro.length)
+	o.SetLastModified(ro.lastModified)
+	if ro.versionID != "" {
+		o.SetVersionID(ro.versionID)
+	}
+	if ro.userMetadata != nil {
+		o.SetUserMetadata(formatUserMetadata(ro.userMetadata))
+	}
+	if ro.mode.IsRead() {
+		setObjectContentMD5(o, ro.contentMD5())
+	}
 	return o, nil
 }
 
@@ -193,36 +387,70 @@ func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int6
 		return 0, fmt.Errorf("reader is nil but size is not nil")
 	}
 
-	o := s.root.insertChildByPath(s.absPath(path))
-	if o == nil {
-		return 0, services.ErrObjectModeInvalid
-	}
-
 	if opt.HasIoCallback {
 		r = iowrap.CallbackReader(r, opt.IoCallback)
 	}
 
-	o.mode = types.ModeRead
-	o.data = make([]byte, size)
+	// Read all data before inserting the object, so that there will be no
+	// partial object if read failed or checksum mismatched.
+	data := make([]byte, size)
+	if size > 0 {
+		// Use io.ReadFull here so that readers like io.Pipe which return
+		// partial data in a single Read could be written completely.
+		read, err := io.ReadFull(r, data)
+		if err != nil {
+			return int64(read), err
+		}
+	}
 
-	if size == 0 {
-		return size, nil
+	sum := md5.Sum(data)
+	contentMD5 := base64.StdEncoding.EncodeToString(sum[:])
+	if opt.HasContentMd5 && opt.ContentMd5 != contentMD5 {
+		return 0, services.ErrChecksumMismatch
 	}
 
-	// TODO: we need to add integration tests for this case.
-	read, err := r.Read(o.data)
-	// Update o.length even after read met error.
-	o.data = o.data[:read]
-	o.length = int64(read)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rp := s.absPath(path)
+	cond := conditions{
+		HasIfMatch:         opt.HasIfMatch,
+		IfMatch:            opt.IfMatch,
+		HasIfModifiedSince: opt.HasIfModifiedSince,
+		IfModifiedSince:    opt.IfModifiedSince,
+		HasIfNoneMatch:     opt.HasIfNoneMatch,
+		IfNoneMatch:        opt.IfNoneMatch,
+	}
+	if err = cond.check(s.root.getObjectByPath(rp)); err != nil {
+		return 0, err
+	}
 
-	if err != nil {
-		return int64(read), err
+	o := s.root.insertChildByPath(rp)
+	if o == nil {
+		return 0, services.ErrObjectModeInvalid
 	}
 
-	return int64(read), nil
+	o.mode = types.ModeRead
+	o.data = data
+	o.length = size
+	o.md5 = contentMD5
+	o.lastModified = time.Now()
+	o.userMetadata = nil
+	if opt.HasUserMetadata {
+		o.userMetadata = formatUserMetadata(opt.UserMetadata)
+	}
+	s.addVersion(rp, o)
+	return size, nil
 }
 
 func (s *Storage) writeAppend(ctx context.Context, o *types.Object, r io.Reader, size int64, opt pairStorageWriteAppend) (n int64, err error) {
+	// Read data before holding the lock, readers could be slow.
+	buf := make([]byte, size)
+	read, err := r.Read(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	ro := s.root.getObjectByPath(o.ID)
 	if ro == nil {
 		ro = s.root.insertChildByPath(o.ID)
@@ -231,10 +459,9 @@ func (s *Storage) writeAppend(ctx context.Context, o *types.Object, r io.Reader,
 		}
 	}
 
-	buf := make([]byte, size)
-	read, err := r.Read(buf)
-	ro.data = append(ro.data, buf[:read]...)
-	ro.length += int64(read)
+	ro.appendData(buf[:read])
+	ro.lastModified = time.Now()
+	ro.versionID = ""
 	if err != nil {
 		return int64(read), nil
 	}
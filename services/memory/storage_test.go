@@ -0,0 +1,380 @@
+package memory
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+func TestStorage_WriteContentMd5(t *testing.T) {
+	store, err := NewStorager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := []byte("hello, world")
+	sum := md5.Sum(content)
+	contentMd5 := base64.StdEncoding.EncodeToString(sum[:])
+
+	_, err = store.Write("a", bytes.NewReader(content), int64(len(content)), pairs.WithContentMd5(contentMd5))
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	o, err := store.Stat("a")
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if v := o.MustGetContentMd5(); v != contentMd5 {
+		t.Errorf("content_md5 expected %s, actual %s", contentMd5, v)
+	}
+	if v := o.MustGetEtag(); v != contentMd5 {
+		t.Errorf("etag expected %s, actual %s", contentMd5, v)
+	}
+
+	it, err := store.List("")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	o, err = it.Next()
+	if err != nil {
+		t.Fatalf("list next: %v", err)
+	}
+	if v := o.MustGetContentMd5(); v != contentMd5 {
+		t.Errorf("listed content_md5 expected %s, actual %s", contentMd5, v)
+	}
+
+	_, err = store.Write("b", bytes.NewReader(content), int64(len(content)), pairs.WithContentMd5("invalid"))
+	if !errors.Is(err, services.ErrChecksumMismatch) {
+		t.Errorf("write with mismatched content_md5 expected %v, actual %v", services.ErrChecksumMismatch, err)
+	}
+	_, err = store.Stat("b")
+	if !errors.Is(err, services.ErrObjectNotExist) {
+		t.Errorf("object with mismatched content_md5 should not exist, actual %v", err)
+	}
+}
+
+func TestStorage_WritePartialReader(t *testing.T) {
+	store, err := NewStorager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := []byte("hello, world")
+	// OneByteReader returns partial data in every Read like io.Pipe.
+	_, err = store.Write("a", iotest.OneByteReader(bytes.NewReader(content)), int64(len(content)))
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var buf bytes.Buffer
+	_, err = store.Read("a", &buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !bytes.Equal(content, buf.Bytes()) {
+		t.Errorf("content expected %q, actual %q", content, buf.Bytes())
+	}
+
+	_, err = store.Write("b", bytes.NewReader(content), int64(len(content))+1)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("write with short reader expected %v, actual %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func TestStorage_Conditions(t *testing.T) {
+	store, err := NewStorager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := []byte("hello, world")
+	write := func(ps ...types.Pair) error {
+		_, err := store.Write("a", bytes.NewReader(content), int64(len(content)), ps...)
+		return err
+	}
+
+	// Create if not exists.
+	if err = write(pairs.WithIfNoneMatch("*")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err = write(pairs.WithIfNoneMatch("*")); !errors.Is(err, services.ErrPreconditionFailed) {
+		t.Errorf("write existing object expected %v, actual %v", services.ErrPreconditionFailed, err)
+	}
+
+	o, err := store.Stat("a")
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	etag := o.MustGetEtag()
+	lastModified := o.MustGetLastModified()
+
+	// Optimistic concurrency.
+	if err = write(pairs.WithIfMatch(`"` + etag + `"`)); err != nil {
+		t.Errorf("write with matched etag: %v", err)
+	}
+	if err = write(pairs.WithIfMatch("invalid")); !errors.Is(err, services.ErrPreconditionFailed) {
+		t.Errorf("write with mismatched etag expected %v, actual %v", services.ErrPreconditionFailed, err)
+	}
+
+	var buf bytes.Buffer
+	if _, err = store.Read("a", &buf, pairs.WithIfNoneMatch(etag)); !errors.Is(err, services.ErrPreconditionFailed) {
+		t.Errorf("read with matched etag expected %v, actual %v", services.ErrPreconditionFailed, err)
+	}
+	if _, err = store.Stat("a", pairs.WithIfModifiedSince(time.Now().Add(time.Hour))); !errors.Is(err, services.ErrPreconditionFailed) {
+		t.Errorf("stat not modified object expected %v, actual %v", services.ErrPreconditionFailed, err)
+	}
+	if _, err = store.Stat("a", pairs.WithIfModifiedSince(lastModified.Add(-time.Second))); err != nil {
+		t.Errorf("stat modified object: %v", err)
+	}
+
+	if err = store.Copy("a", "b", pairs.WithIfMatch("invalid")); !errors.Is(err, services.ErrPreconditionFailed) {
+		t.Errorf("copy with mismatched etag expected %v, actual %v", services.ErrPreconditionFailed, err)
+	}
+	if err = store.Delete("a", pairs.WithIfMatch("invalid")); !errors.Is(err, services.ErrPreconditionFailed) {
+		t.Errorf("delete with mismatched etag expected %v, actual %v", services.ErrPreconditionFailed, err)
+	}
+	if err = store.Delete("a", pairs.WithIfMatch(etag)); err != nil {
+		t.Errorf("delete with matched etag: %v", err)
+	}
+	if err = store.Delete("a", pairs.WithIfMatch(etag)); !errors.Is(err, services.ErrPreconditionFailed) {
+		t.Errorf("delete not exist object expected %v, actual %v", services.ErrPreconditionFailed, err)
+	}
+}
+
+func TestStorage_ConcurrentAppend(t *testing.T) {
+	store, err := NewStorager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o, err := store.CreateAppend("a")
+	if err != nil {
+		t.Fatalf("create append: %v", err)
+	}
+
+	content := []byte("hello, world")
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_, err := store.WriteAppend(o, bytes.NewReader(content), int64(len(content)))
+			if err != nil {
+				t.Errorf("write append: %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			// Conditions will read content md5 of the object.
+			_, err := store.Stat("a", pairs.WithIfNoneMatch("invalid"))
+			if err != nil {
+				t.Errorf("stat: %v", err)
+				return
+			}
+			it, err := store.List("")
+			if err != nil {
+				t.Errorf("list: %v", err)
+				return
+			}
+			if _, err = it.Next(); err != nil {
+				t.Errorf("list next: %v", err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	o, err = store.Stat("a")
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	expected := bytes.Repeat(content, 100)
+	sum := md5.Sum(expected)
+	if v := o.MustGetContentLength(); v != int64(len(expected)) {
+		t.Errorf("content length expected %d, actual %d", len(expected), v)
+	}
+	if v := o.MustGetContentMd5(); v != base64.StdEncoding.EncodeToString(sum[:]) {
+		t.Errorf("content md5 mismatched, actual %s", v)
+	}
+}
+
+func TestStorage_DeleteLatestVersion(t *testing.T) {
+	store, err := NewStorager(WithEnableVersioning())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []string{"v1", "v2"} {
+		if _, err = store.Write("a", bytes.NewReader([]byte(v)), int64(len(v))); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	o, err := store.Stat("a")
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+
+	// Delete the latest version should restore the previous one.
+	if err = store.Delete("a", pairs.WithVersionID(o.MustGetVersionID())); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err = store.Read("a", &buf); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if buf.String() != "v1" {
+		t.Errorf("read expected %s, actual %s", "v1", buf.String())
+	}
+
+	// Delete without version_id should keep all versions.
+	if err = store.Delete("a"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	it, err := store.ListVersion("a")
+	if err != nil {
+		t.Fatalf("list version: %v", err)
+	}
+	versions, err := it.Collect()
+	if err != nil {
+		t.Fatalf("list version: %v", err)
+	}
+	if len(versions) != 1 {
+		t.Errorf("versions expected %d, actual %d", 1, len(versions))
+	}
+}
+
+func TestStorage_Versioning(t *testing.T) {
+	store, err := NewStorager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Versions are not kept by default.
+	for _, v := range []string{"v1", "v2"} {
+		if _, err = store.Write("a", bytes.NewReader([]byte(v)), int64(len(v))); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	o, err := store.Stat("a")
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if _, ok := o.GetVersionID(); ok {
+		t.Errorf("object should not have version id")
+	}
+	it, err := store.ListVersion("a")
+	if err != nil {
+		t.Fatalf("list version: %v", err)
+	}
+	versions, err := it.Collect()
+	if err != nil {
+		t.Fatalf("list version: %v", err)
+	}
+	if len(versions) != 0 {
+		t.Errorf("versions expected %d, actual %d", 0, len(versions))
+	}
+
+	store, err = NewStorager(WithEnableVersioning())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"v1", "v2"} {
+		if _, err = store.Write("a", bytes.NewReader([]byte(v)), int64(len(v))); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	// Versions are moved along with the object.
+	if err = store.Move("a", "dir/b"); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	it, err = store.ListVersion("a")
+	if err != nil {
+		t.Fatalf("list version: %v", err)
+	}
+	if versions, err = it.Collect(); err != nil || len(versions) != 0 {
+		t.Errorf("versions of src expected %d, actual %d, %v", 0, len(versions), err)
+	}
+	it, err = store.ListVersion("dir/b")
+	if err != nil {
+		t.Fatalf("list version: %v", err)
+	}
+	if versions, err = it.Collect(); err != nil || len(versions) != 2 {
+		t.Fatalf("versions of dst expected %d, actual %d, %v", 2, len(versions), err)
+	}
+	var buf bytes.Buffer
+	if _, err = store.Read("dir/b", &buf, pairs.WithVersionID(versions[1].MustGetVersionID())); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if buf.String() != "v1" {
+		t.Errorf("read expected %s, actual %s", "v1", buf.String())
+	}
+}
+
+func TestStorage_UserMetadata(t *testing.T) {
+	store, err := NewStorager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := []byte("hello, world")
+	_, err = store.Write("a", bytes.NewReader(content), int64(len(content)),
+		pairs.WithUserMetadata(map[string]string{"Pipeline": "daily"}))
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	metadata := func(path string) map[string]string {
+		o, err := store.Stat(path)
+		if err != nil {
+			t.Fatalf("stat: %v", err)
+		}
+		um, _ := o.GetUserMetadata()
+		return um
+	}
+
+	// Keys will be normalized into lower case.
+	if um := metadata("a"); um["pipeline"] != "daily" {
+		t.Errorf("user metadata expected %s, actual %v", "daily", um)
+	}
+
+	// Copy without user_metadata keeps src's user metadata.
+	if err = store.Copy("a", "b"); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if um := metadata("b"); um["pipeline"] != "daily" {
+		t.Errorf("user metadata expected %s, actual %v", "daily", um)
+	}
+
+	// Copy with user_metadata replaces src's user metadata.
+	err = store.Copy("a", "c", pairs.WithUserMetadata(map[string]string{"stage": "archive"}))
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if um := metadata("c"); len(um) != 1 || um["stage"] != "archive" {
+		t.Errorf("user metadata expected %s, actual %v", "archive", um)
+	}
+
+	// Overwrite without user_metadata clears user metadata.
+	if _, err = store.Write("a", bytes.NewReader(content), int64(len(content))); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if um := metadata("a"); len(um) != 0 {
+		t.Errorf("user metadata expected empty, actual %v", um)
+	}
+}
@@ -25,3 +25,13 @@ func TestCopy(t *testing.T) {
 func TestMove(t *testing.T) {
 	tests.TestMover(t, setupTest(t))
 }
+
+func TestVersion(t *testing.T) {
+	tests.TestVersioner(t, setupVersionTest(t))
+}
+
+func TestHTTPSigner(t *testing.T) {
+	tests.TestStorageHTTPSignerRead(t, setupHTTPSignerTest(t))
+	tests.TestStorageHTTPSignerWrite(t, setupHTTPSignerTest(t))
+	tests.TestStorageHTTPSignerDelete(t, setupHTTPSignerTest(t))
+}
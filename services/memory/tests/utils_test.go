@@ -1,8 +1,10 @@
 package tests
 
 import (
+	"net/http/httptest"
 	"testing"
 
+	"go.beyondstorage.io/v5/pkg/httpsign"
 	"go.beyondstorage.io/v5/types"
 
 	"go.beyondstorage.io/services/memory"
@@ -17,3 +19,34 @@ func setupTest(t *testing.T) types.Storager {
 	}
 	return store
 }
+
+func setupVersionTest(t *testing.T) types.Storager {
+	t.Log("Setup version test for memory")
+
+	store, err := memory.NewStorager(memory.WithEnableVersioning())
+	if err != nil {
+		t.Errorf("new storager: %v", err)
+	}
+	return store
+}
+
+// setupHTTPSignerTest creates a storager which is served by httpsign.Handler.
+func setupHTTPSignerTest(t *testing.T) types.Storager {
+	t.Log("Setup http signer test for memory")
+
+	key := "test-key"
+	srv := httptest.NewUnstartedServer(nil)
+	t.Cleanup(srv.Close)
+
+	store, err := memory.NewStorager(
+		memory.WithHTTPSignerEndpoint("http://"+srv.Listener.Addr().String()),
+		memory.WithHTTPSignerKey(key),
+	)
+	if err != nil {
+		t.Fatalf("new storager: %v", err)
+	}
+
+	srv.Config.Handler = httpsign.NewHandler(store, []byte(key))
+	srv.Start()
+	return store
+}
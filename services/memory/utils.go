@@ -3,8 +3,12 @@ package memory
 import (
 	"fmt"
 	"path"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"go.beyondstorage.io/v5/pkg/httpsign"
 	"go.beyondstorage.io/v5/services"
 	"go.beyondstorage.io/v5/types"
 )
@@ -50,6 +54,15 @@ type Storage struct {
 
 	workDir string
 	root    *object
+	// mu makes conditions checking and operations atomic.
+	mu sync.Mutex
+	// versions stores all versions of objects from the oldest to the newest,
+	// protected by mu.
+	versions    map[string][]*version
+	versionSeed int64
+	// signer is used by query_sign_http_* operations, nil means the
+	// http signer is not configured.
+	signer *httpsign.Signer
 
 	types.UnimplementedStorager
 }
@@ -73,14 +86,27 @@ func (f *Factory) newStorage() (st *Storage, err error) {
 	root := newObject("", nil, types.ModeDir)
 	root.parent = root
 
-	return &Storage{
+	st = &Storage{
 		f:        *f,
 		features: f.storageFeatures(),
 		root:     root,
 		workDir:  "/",
-	}, nil
+		versions: make(map[string][]*version),
+	}
+
+	if f.HTTPSignerEndpoint != "" {
+		st.signer, err = httpsign.NewSigner(f.HTTPSignerEndpoint, []byte(f.HTTPSignerKey))
+		if err != nil {
+			return nil, services.InitError{Op: "new_storager", Type: Type, Err: err}
+		}
+	}
+	return st, nil
 }
 
+// errHTTPSignerNotConfigured is returned by query_sign_http_* operations while
+// http_signer_endpoint is not set.
+var errHTTPSignerNotConfigured = fmt.Errorf("http signer is not configured: %w", services.ErrCapabilityInsufficient)
+
 // formatError converts errors returned by SDK into errors defined in go-storage and go-service-*.
 // The original error SHOULD NOT be wrapped.
 func (s *Storage) formatError(op string, err error, path ...string) error {
@@ -103,3 +129,151 @@ func (s *Storage) absPath(p string) string {
 func (s *Storage) relPath(p string) string {
 	return strings.TrimPrefix(p, s.workDir)
 }
+
+// addVersion records current data of o as a new version of absPath, o will
+// not be versioned while enable_versioning is not set.
+//
+// Caller must hold s.mu.
+func (s *Storage) addVersion(absPath string, o *object) {
+	if !s.f.EnableVersioning {
+		o.versionID = ""
+		return
+	}
+
+	s.versionSeed++
+	o.versionID = strconv.FormatInt(s.versionSeed, 10)
+
+	s.versions[absPath] = append(s.versions[absPath], &version{
+		id:           o.versionID,
+		data:         o.data,
+		length:       o.length,
+		md5:          o.contentMD5(),
+		lastModified: o.lastModified,
+		userMetadata: o.userMetadata,
+	})
+}
+
+// getVersion returns the specified version of absPath, nil means the version is not exist.
+//
+// Caller must hold s.mu.
+func (s *Storage) getVersion(absPath, id string) *object {
+	for _, v := range s.versions[absPath] {
+		if v.id == id {
+			return v.object()
+		}
+	}
+	return nil
+}
+
+// removeVersion removes the specified version of absPath.
+//
+// If the version is the current one, the previous version will be restored.
+//
+// Caller must hold s.mu.
+func (s *Storage) removeVersion(absPath, id string) {
+	vs := s.versions[absPath]
+	for k, v := range vs {
+		if v.id != id {
+			continue
+		}
+		vs = append(vs[:k:k], vs[k+1:]...)
+		break
+	}
+	if len(vs) == 0 {
+		delete(s.versions, absPath)
+	} else {
+		s.versions[absPath] = vs
+	}
+
+	o := s.root.getObjectByPath(absPath)
+	if o == nil || o.versionID != id {
+		return
+	}
+	if len(vs) == 0 {
+		o.parent.removeChild(o.name)
+		return
+	}
+	latest := vs[len(vs)-1]
+	o.data = latest.data
+	o.length = latest.length
+	o.md5 = latest.md5
+	o.lastModified = latest.lastModified
+	o.versionID = latest.id
+	o.userMetadata = latest.userMetadata
+}
+
+// moveVersions moves versions of src and objects under src to dst, versions
+// of the replaced dst object are dropped.
+//
+// Caller must hold s.mu.
+func (s *Storage) moveVersions(src, dst string) {
+	moved := make(map[string][]*version)
+	for k, vs := range s.versions {
+		if k == src || strings.HasPrefix(k, src+"/") {
+			moved[dst+strings.TrimPrefix(k, src)] = vs
+			delete(s.versions, k)
+		}
+	}
+	delete(s.versions, dst)
+	for k, vs := range moved {
+		s.versions[k] = vs
+	}
+}
+
+// formatUserMetadata normalizes keys of user metadata into lower case.
+//
+// The returned map is always a copy, so it's safe to be stored or returned to users.
+func formatUserMetadata(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	um := make(map[string]string, len(m))
+	for k, v := range m {
+		um[strings.ToLower(k)] = v
+	}
+	return um
+}
+
+// setObjectContentMD5 sets the content md5 of o, and uses it as etag as well.
+//
+// Objects are stored in memory without any other identity, RFC-14 allows
+// services to expose the content md5 as etag in this case.
+func setObjectContentMD5(o *types.Object, v string) {
+	o.SetContentMd5(v)
+	o.SetEtag(v)
+}
+
+// conditions holds the if_* pairs of an operation, etag related ones are
+// compared with the content md5 of objects.
+type conditions struct {
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+}
+
+// check checks conditions against o, nil o means the object is not exist.
+func (c conditions) check(o *object) error {
+	if c.HasIfMatch {
+		if o == nil || (c.IfMatch != "*" && !etagMatch(c.IfMatch, o.contentMD5())) {
+			return services.ErrPreconditionFailed
+		}
+	}
+	if c.HasIfNoneMatch && o != nil {
+		if c.IfNoneMatch == "*" || etagMatch(c.IfNoneMatch, o.contentMD5()) {
+			return services.ErrPreconditionFailed
+		}
+	}
+	// if_modified_since will be ignored while object is not exist.
+	if c.HasIfModifiedSince && o != nil && !o.lastModified.After(c.IfModifiedSince) {
+		return services.ErrPreconditionFailed
+	}
+	return nil
+}
+
+// etagMatch reports whether etag a and b are the same, quotes will be ignored.
+func etagMatch(a, b string) bool {
+	return strings.Trim(a, `"`) == strings.Trim(b, `"`)
+}
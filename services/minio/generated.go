@@ -448,7 +448,9 @@ func (s *Storage) CompleteMultipartWithContext(ctx context.Context, o *types.Obj
 }
 
 type pairStorageCopy struct {
-	pairs []types.Pair
+	pairs           []types.Pair
+	HasUserMetadata bool
+	UserMetadata    map[string]string
 }
 
 func (s *Storage) parsePairStorageCopy(opts []types.Pair) (pairStorageCopy, error) {
@@ -457,6 +459,12 @@ func (s *Storage) parsePairStorageCopy(opts []types.Pair) (pairStorageCopy, erro
 
 	for _, v := range opts {
 		switch v.Key {
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageCopy{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -733,6 +741,31 @@ func (s *Storage) FetchWithContext(ctx context.Context, path string, url string,
 	return
 }
 
+type pairStorageGetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageGetLifecycle(opts []types.Pair) (pairStorageGetLifecycle, error) {
+	result :=
+		pairStorageGetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageGetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) GetLifecycle(pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	err = types.NewOperationNotImplementedError("get_lifecycle")
+	return
+}
+func (s *Storage) GetLifecycleWithContext(ctx context.Context, pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	err = types.NewOperationNotImplementedError("get_lifecycle")
+	return
+}
+
 type pairStorageList struct {
 	pairs       []types.Pair
 	HasListMode bool
@@ -826,6 +859,31 @@ func (s *Storage) ListMultipartWithContext(ctx context.Context, o *types.Object,
 	return
 }
 
+type pairStorageListVersion struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageListVersion(opts []types.Pair) (pairStorageListVersion, error) {
+	result :=
+		pairStorageListVersion{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageListVersion{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) ListVersion(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	err = types.NewOperationNotImplementedError("list_version")
+	return
+}
+func (s *Storage) ListVersionWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	err = types.NewOperationNotImplementedError("list_version")
+	return
+}
+
 type pairStorageMetadata struct {
 	pairs []types.Pair
 }
@@ -1110,6 +1168,81 @@ func (s *Storage) ReadWithContext(ctx context.Context, path string, w io.Writer,
 	return s.read(ctx, strings.ReplaceAll(path, "\\", "/"), w, opt)
 }
 
+type pairStorageRestore struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageRestore(opts []types.Pair) (pairStorageRestore, error) {
+	result :=
+		pairStorageRestore{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageRestore{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) Restore(path string, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("restore")
+	return
+}
+func (s *Storage) RestoreWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("restore")
+	return
+}
+
+type pairStorageSetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetLifecycle(opts []types.Pair) (pairStorageSetLifecycle, error) {
+	result :=
+		pairStorageSetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetLifecycle(rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_lifecycle")
+	return
+}
+func (s *Storage) SetLifecycleWithContext(ctx context.Context, rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_lifecycle")
+	return
+}
+
+type pairStorageSetStorageClass struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetStorageClass(opts []types.Pair) (pairStorageSetStorageClass, error) {
+	result :=
+		pairStorageSetStorageClass{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetStorageClass{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetStorageClass(path string, class string, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_storage_class")
+	return
+}
+func (s *Storage) SetStorageClassWithContext(ctx context.Context, path string, class string, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_storage_class")
+	return
+}
+
 type pairStorageStat struct {
 	pairs         []types.Pair
 	HasObjectMode bool
@@ -1163,6 +1296,8 @@ type pairStorageWrite struct {
 	IoCallback      func([]byte)
 	HasStorageClass bool
 	StorageClass    string
+	HasUserMetadata bool
+	UserMetadata    map[string]string
 }
 
 func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, error) {
@@ -1195,6 +1330,12 @@ func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, er
 			}
 			result.HasStorageClass = true
 			result.StorageClass = v.Value.(string)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageWrite{}, services.PairUnsupportedError{Pair: v}
 		}
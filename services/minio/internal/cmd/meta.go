@@ -42,6 +42,9 @@ var Metadata = def.Metadata{
 			Write:    true,
 		},
 
+		Copy: []def.Pair{
+			def.PairUserMetadata,
+		},
 		Create: []def.Pair{
 			def.PairObjectMode,
 		},
@@ -61,6 +64,7 @@ var Metadata = def.Metadata{
 			def.PairContentType,
 			def.PairIoCallback,
 			pairStorageClass,
+			def.PairUserMetadata,
 		},
 		Stat: []def.Pair{
 			def.PairObjectMode,
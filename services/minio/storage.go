@@ -25,6 +25,10 @@ func (s *Storage) copy(ctx context.Context, src string, dst string, opt pairStor
 		Bucket: s.bucket,
 		Object: s.getAbsPath(dst),
 	}
+	if opt.HasUserMetadata {
+		dstOpts.ReplaceMetadata = true
+		dstOpts.UserMetadata = formatUserMetadata(opt.UserMetadata)
+	}
 	_, err = s.client.CopyObject(ctx, dstOpts, srcOpts)
 	return err
 }
@@ -179,6 +183,9 @@ func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int6
 	if opt.HasStorageClass {
 		options.StorageClass = opt.StorageClass
 	}
+	if opt.HasUserMetadata {
+		options.UserMetadata = formatUserMetadata(opt.UserMetadata)
+	}
 	_, err = s.client.PutObject(ctx, s.bucket, rp, r, size, options)
 	if err != nil {
 		return 0, err
@@ -246,7 +246,7 @@ func (s *Storage) formatFileObject(v minio.ObjectInfo) (o *types.Object, err err
 	o.SetContentLength(v.Size)
 	o.SetContentType(v.ContentType)
 	o.SetLastModified(v.LastModified)
-	o.SetUserMetadata(v.UserMetadata)
+	o.SetUserMetadata(formatUserMetadata(v.UserMetadata))
 	o.SetSystemMetadata(ObjectSystemMetadata{
 		StorageClass: v.StorageClass,
 	})
@@ -254,6 +254,19 @@ func (s *Storage) formatFileObject(v minio.ObjectInfo) (o *types.Object, err err
 	return
 }
 
+// formatUserMetadata returns a copy of user metadata with keys normalized
+// into lower case.
+func formatUserMetadata(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	um := make(map[string]string, len(m))
+	for k, v := range m {
+		um[strings.ToLower(k)] = v
+	}
+	return um
+}
+
 func (s *Storage) newObject(done bool) *types.Object {
 	return types.NewObject(s, done)
 }
@@ -197,6 +197,8 @@ func (f *Factory) storageFeatures() (s types.StorageFeatures) {
 	s.Delete = true
 	s.List = true
 	s.Metadata = true
+	s.Restore = true
+	s.SetStorageClass = true
 	s.Stat = true
 	s.Write = true
 	s.WriteEmptyObject = true
@@ -721,6 +723,31 @@ func (s *Storage) FetchWithContext(ctx context.Context, path string, url string,
 	return
 }
 
+type pairStorageGetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageGetLifecycle(opts []types.Pair) (pairStorageGetLifecycle, error) {
+	result :=
+		pairStorageGetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageGetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) GetLifecycle(pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	err = types.NewOperationNotImplementedError("get_lifecycle")
+	return
+}
+func (s *Storage) GetLifecycleWithContext(ctx context.Context, pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	err = types.NewOperationNotImplementedError("get_lifecycle")
+	return
+}
+
 type pairStorageList struct {
 	pairs       []types.Pair
 	HasListMode bool
@@ -814,6 +841,31 @@ func (s *Storage) ListMultipartWithContext(ctx context.Context, o *types.Object,
 	return
 }
 
+type pairStorageListVersion struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageListVersion(opts []types.Pair) (pairStorageListVersion, error) {
+	result :=
+		pairStorageListVersion{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageListVersion{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) ListVersion(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	err = types.NewOperationNotImplementedError("list_version")
+	return
+}
+func (s *Storage) ListVersionWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	err = types.NewOperationNotImplementedError("list_version")
+	return
+}
+
 type pairStorageMetadata struct {
 	pairs []types.Pair
 }
@@ -1088,6 +1140,109 @@ func (s *Storage) ReadWithContext(ctx context.Context, path string, w io.Writer,
 	return
 }
 
+type pairStorageRestore struct {
+	pairs          []types.Pair
+	HasRestoreDays bool
+	RestoreDays    int
+}
+
+func (s *Storage) parsePairStorageRestore(opts []types.Pair) (pairStorageRestore, error) {
+	result :=
+		pairStorageRestore{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "restore_days":
+			if result.HasRestoreDays {
+				continue
+			}
+			result.HasRestoreDays = true
+			result.RestoreDays = v.Value.(int)
+		default:
+			return pairStorageRestore{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) Restore(path string, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.RestoreWithContext(ctx, path, pairs...)
+}
+func (s *Storage) RestoreWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("restore", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.Restore...)
+	var opt pairStorageRestore
+
+	opt, err = s.parsePairStorageRestore(pairs)
+	if err != nil {
+		return
+	}
+	return s.restore(ctx, strings.ReplaceAll(path, "\\", "/"), opt)
+}
+
+type pairStorageSetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetLifecycle(opts []types.Pair) (pairStorageSetLifecycle, error) {
+	result :=
+		pairStorageSetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetLifecycle(rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_lifecycle")
+	return
+}
+func (s *Storage) SetLifecycleWithContext(ctx context.Context, rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	err = types.NewOperationNotImplementedError("set_lifecycle")
+	return
+}
+
+type pairStorageSetStorageClass struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetStorageClass(opts []types.Pair) (pairStorageSetStorageClass, error) {
+	result :=
+		pairStorageSetStorageClass{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetStorageClass{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetStorageClass(path string, class string, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.SetStorageClassWithContext(ctx, path, class, pairs...)
+}
+func (s *Storage) SetStorageClassWithContext(ctx context.Context, path string, class string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("set_storage_class", err, path, class)
+	}()
+	pairs = append(pairs, s.defaultPairs.SetStorageClass...)
+	var opt pairStorageSetStorageClass
+
+	opt, err = s.parsePairStorageSetStorageClass(pairs)
+	if err != nil {
+		return
+	}
+	return s.setStorageClass(ctx, strings.ReplaceAll(path, "\\", "/"), class, opt)
+}
+
 type pairStorageStat struct {
 	pairs         []types.Pair
 	HasObjectMode bool
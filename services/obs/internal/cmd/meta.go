@@ -32,13 +32,15 @@ var Metadata = def.Metadata{
 			VirtualDir:       true,
 			WriteEmptyObject: true,
 
-			Create:   true,
-			Delete:   true,
-			List:     true,
-			Metadata: true,
-			Read:     false,
-			Stat:     true,
-			Write:    true,
+			Create:          true,
+			Delete:          true,
+			List:            true,
+			Metadata:        true,
+			Read:            false,
+			Restore:         true,
+			SetStorageClass: true,
+			Stat:            true,
+			Write:           true,
 		},
 
 		Create: []def.Pair{
@@ -61,6 +63,9 @@ var Metadata = def.Metadata{
 			def.PairIoCallback,
 			pairStorageClass,
 		},
+		Restore: []def.Pair{
+			def.PairRestoreDays,
+		},
 		Stat: []def.Pair{
 			def.PairObjectMode,
 		},
@@ -209,6 +209,41 @@ func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairSt
 	return io.Copy(w, rc)
 }
 
+func (s *Storage) restore(ctx context.Context, path string, opt pairStorageRestore) (err error) {
+	input := &obs.RestoreObjectInput{
+		Bucket: s.bucket,
+		Key:    s.getAbsPath(path),
+		Days:   defaultRestoreDays,
+	}
+	if opt.HasRestoreDays {
+		input.Days = opt.RestoreDays
+	}
+
+	_, err = s.client.RestoreObject(input)
+	if err != nil {
+		// Restore should be idempotent, so we need to omit the error while
+		// the object is being restored.
+		if e, ok := err.(obs.ObsError); ok && e.Code == "RestoreAlreadyInProgress" {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) setStorageClass(ctx context.Context, path string, class string, opt pairStorageSetStorageClass) (err error) {
+	input := &obs.SetObjectMetadataInput{
+		Bucket: s.bucket,
+		Key:    s.getAbsPath(path),
+		// REPLACE_NEW will only replace the storage class and keep other metadata.
+		MetadataDirective: obs.ReplaceNew,
+		StorageClass:      obs.StorageClassType(class),
+	}
+
+	_, err = s.client.SetObjectMetadata(input)
+	return err
+}
+
 func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o *types.Object, err error) {
 	rp := s.getAbsPath(path)
 
@@ -221,11 +256,14 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 		rp += "/"
 	}
 
-	input := &obs.GetObjectInput{}
-	input.Bucket = s.bucket
-	input.Key = rp
+	// GetObject will fail on archived objects, so we use GetObjectMetadata
+	// to get the restore status.
+	input := &obs.GetObjectMetadataInput{
+		Bucket: s.bucket,
+		Key:    rp,
+	}
 
-	output, err := s.client.GetObject(input)
+	output, err := s.client.GetObjectMetadata(input)
 	if err != nil {
 		return nil, err
 	}
@@ -249,6 +287,9 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 	if output.ETag != "" {
 		o.SetEtag(output.ETag)
 	}
+	if v := formatRestoreStatus(output.StorageClass, output.Restore); v != "" {
+		o.SetRestoreStatus(v)
+	}
 
 	var sm ObjectSystemMetadata
 	if v := output.StorageClass; v != "" {
@@ -177,14 +177,48 @@ func (s *Storage) formatError(op string, err error, path ...string) error {
 		return nil
 	}
 
+	if isObjectArchivedError(op, err) {
+		err = fmt.Errorf("%w: %v", services.ErrObjectArchived, err)
+	} else {
+		err = formatError(err)
+	}
+
 	return services.StorageError{
 		Op:       op,
-		Err:      formatError(err),
+		Err:      err,
 		Storager: s,
 		Path:     path,
 	}
 }
 
+// isObjectArchivedError checks whether err is returned for reading archived
+// objects. InvalidObjectState is also returned by other operations, for
+// example restoring objects which are not archived, so only read is checked.
+func isObjectArchivedError(op string, err error) bool {
+	e, ok := err.(obs.ObsError)
+	return op == "read" && ok && e.Code == "InvalidObjectState"
+}
+
+// defaultRestoreDays is the days of restored copy that will be kept if
+// restore_days is not set.
+const defaultRestoreDays = 1
+
+// formatRestoreStatus converts the x-obs-restore header into restore status.
+//
+// ref: https://support.huaweicloud.com/intl/en-us/api-obs/obs_04_0084.html
+func formatRestoreStatus(class obs.StorageClassType, restore string) string {
+	switch {
+	case strings.Contains(restore, `ongoing-request="true"`):
+		return types.RestoreStatusOngoing
+	case restore != "":
+		return types.RestoreStatusRestored
+	case class == obs.StorageClassCold || class == "GLACIER":
+		return types.RestoreStatusArchived
+	default:
+		return ""
+	}
+}
+
 // formatError converts errors returned by SDK into errors defined in go-storage and go-service-*.
 // The original error SHOULD NOT be wrapped.
 func formatError(err error) error {
@@ -235,10 +235,15 @@ func (f *Factory) storageFeatures() (s types.StorageFeatures) {
 	s.CreateLink = true
 	s.CreateMultipart = true
 	s.Delete = true
+	s.GetLifecycle = true
 	s.List = true
 	s.ListMultipart = true
+	s.ListVersion = true
 	s.Metadata = true
 	s.Read = true
+	s.Restore = true
+	s.SetLifecycle = true
+	s.SetStorageClass = true
 	s.Stat = true
 	s.Write = true
 	s.WriteAppend = true
@@ -583,6 +588,8 @@ type pairStorageCreateAppend struct {
 	ServerSideEncryption    string
 	HasStorageClass         bool
 	StorageClass            string
+	HasUserMetadata         bool
+	UserMetadata            map[string]string
 }
 
 func (s *Storage) parsePairStorageCreateAppend(opts []types.Pair) (pairStorageCreateAppend, error) {
@@ -609,6 +616,12 @@ func (s *Storage) parsePairStorageCreateAppend(opts []types.Pair) (pairStorageCr
 			}
 			result.HasStorageClass = true
 			result.StorageClass = v.Value.(string)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageCreateAppend{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -749,6 +762,8 @@ type pairStorageCreateMultipart struct {
 	ServerSideEncryptionKeyID    string
 	HasStorageClass              bool
 	StorageClass                 string
+	HasUserMetadata              bool
+	UserMetadata                 map[string]string
 }
 
 func (s *Storage) parsePairStorageCreateMultipart(opts []types.Pair) (pairStorageCreateMultipart, error) {
@@ -787,6 +802,12 @@ func (s *Storage) parsePairStorageCreateMultipart(opts []types.Pair) (pairStorag
 			}
 			result.HasStorageClass = true
 			result.StorageClass = v.Value.(string)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageCreateMultipart{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -843,6 +864,8 @@ type pairStorageDelete struct {
 	MultipartID    string
 	HasObjectMode  bool
 	ObjectMode     types.ObjectMode
+	HasVersionID   bool
+	VersionID      string
 }
 
 func (s *Storage) parsePairStorageDelete(opts []types.Pair) (pairStorageDelete, error) {
@@ -863,6 +886,12 @@ func (s *Storage) parsePairStorageDelete(opts []types.Pair) (pairStorageDelete,
 			}
 			result.HasObjectMode = true
 			result.ObjectMode = v.Value.(types.ObjectMode)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageDelete{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -913,6 +942,41 @@ func (s *Storage) FetchWithContext(ctx context.Context, path string, url string,
 	return
 }
 
+type pairStorageGetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageGetLifecycle(opts []types.Pair) (pairStorageGetLifecycle, error) {
+	result :=
+		pairStorageGetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageGetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) GetLifecycle(pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	ctx := context.Background()
+	return s.GetLifecycleWithContext(ctx, pairs...)
+}
+func (s *Storage) GetLifecycleWithContext(ctx context.Context, pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	defer func() {
+		err =
+			s.formatError("get_lifecycle", err)
+	}()
+	pairs = append(pairs, s.defaultPairs.GetLifecycle...)
+	var opt pairStorageGetLifecycle
+
+	opt, err = s.parsePairStorageGetLifecycle(pairs)
+	if err != nil {
+		return
+	}
+	return s.getLifecycle(ctx, opt)
+}
+
 type pairStorageList struct {
 	pairs       []types.Pair
 	HasListMode bool
@@ -1016,6 +1080,41 @@ func (s *Storage) ListMultipartWithContext(ctx context.Context, o *types.Object,
 	return s.listMultipart(ctx, o, opt)
 }
 
+type pairStorageListVersion struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageListVersion(opts []types.Pair) (pairStorageListVersion, error) {
+	result :=
+		pairStorageListVersion{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageListVersion{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) ListVersion(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	ctx := context.Background()
+	return s.ListVersionWithContext(ctx, path, pairs...)
+}
+func (s *Storage) ListVersionWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	defer func() {
+		err =
+			s.formatError("list_version", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.ListVersion...)
+	var opt pairStorageListVersion
+
+	opt, err = s.parsePairStorageListVersion(pairs)
+	if err != nil {
+		return
+	}
+	return s.listVersion(ctx, strings.ReplaceAll(path, "\\", "/"), opt)
+}
+
 type pairStorageMetadata struct {
 	pairs []types.Pair
 }
@@ -1242,13 +1341,21 @@ func (s *Storage) QuerySignHTTPWriteMultipartWithContext(ctx context.Context, o
 }
 
 type pairStorageRead struct {
-	pairs         []types.Pair
-	HasIoCallback bool
-	IoCallback    func([]byte)
-	HasOffset     bool
-	Offset        int64
-	HasSize       bool
-	Size          int64
+	pairs              []types.Pair
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasIoCallback      bool
+	IoCallback         func([]byte)
+	HasOffset          bool
+	Offset             int64
+	HasSize            bool
+	Size               int64
+	HasVersionID       bool
+	VersionID          string
 }
 
 func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, error) {
@@ -1257,6 +1364,24 @@ func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, erro
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
@@ -1275,6 +1400,12 @@ func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, erro
 			}
 			result.HasSize = true
 			result.Size = v.Value.(int64)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageRead{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -1300,12 +1431,133 @@ func (s *Storage) ReadWithContext(ctx context.Context, path string, w io.Writer,
 	return s.read(ctx, strings.ReplaceAll(path, "\\", "/"), w, opt)
 }
 
-type pairStorageStat struct {
+type pairStorageRestore struct {
 	pairs          []types.Pair
-	HasMultipartID bool
-	MultipartID    string
-	HasObjectMode  bool
-	ObjectMode     types.ObjectMode
+	HasRestoreDays bool
+	RestoreDays    int
+}
+
+func (s *Storage) parsePairStorageRestore(opts []types.Pair) (pairStorageRestore, error) {
+	result :=
+		pairStorageRestore{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "restore_days":
+			if result.HasRestoreDays {
+				continue
+			}
+			result.HasRestoreDays = true
+			result.RestoreDays = v.Value.(int)
+		default:
+			return pairStorageRestore{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) Restore(path string, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.RestoreWithContext(ctx, path, pairs...)
+}
+func (s *Storage) RestoreWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("restore", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.Restore...)
+	var opt pairStorageRestore
+
+	opt, err = s.parsePairStorageRestore(pairs)
+	if err != nil {
+		return
+	}
+	return s.restore(ctx, strings.ReplaceAll(path, "\\", "/"), opt)
+}
+
+type pairStorageSetLifecycle struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetLifecycle(opts []types.Pair) (pairStorageSetLifecycle, error) {
+	result :=
+		pairStorageSetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetLifecycle(rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.SetLifecycleWithContext(ctx, rules, pairs...)
+}
+func (s *Storage) SetLifecycleWithContext(ctx context.Context, rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("set_lifecycle", err)
+	}()
+	pairs = append(pairs, s.defaultPairs.SetLifecycle...)
+	var opt pairStorageSetLifecycle
+
+	opt, err = s.parsePairStorageSetLifecycle(pairs)
+	if err != nil {
+		return
+	}
+	return s.setLifecycle(ctx, rules, opt)
+}
+
+type pairStorageSetStorageClass struct {
+	pairs []types.Pair
+}
+
+func (s *Storage) parsePairStorageSetStorageClass(opts []types.Pair) (pairStorageSetStorageClass, error) {
+	result :=
+		pairStorageSetStorageClass{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		default:
+			return pairStorageSetStorageClass{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetStorageClass(path string, class string, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.SetStorageClassWithContext(ctx, path, class, pairs...)
+}
+func (s *Storage) SetStorageClassWithContext(ctx context.Context, path string, class string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("set_storage_class", err, path, class)
+	}()
+	pairs = append(pairs, s.defaultPairs.SetStorageClass...)
+	var opt pairStorageSetStorageClass
+
+	opt, err = s.parsePairStorageSetStorageClass(pairs)
+	if err != nil {
+		return
+	}
+	return s.setStorageClass(ctx, strings.ReplaceAll(path, "\\", "/"), class, opt)
+}
+
+type pairStorageStat struct {
+	pairs              []types.Pair
+	HasIfMatch         bool
+	IfMatch            string
+	HasIfModifiedSince bool
+	IfModifiedSince    time.Time
+	HasIfNoneMatch     bool
+	IfNoneMatch        string
+	HasMultipartID     bool
+	MultipartID        string
+	HasObjectMode      bool
+	ObjectMode         types.ObjectMode
+	HasVersionID       bool
+	VersionID          string
 }
 
 func (s *Storage) parsePairStorageStat(opts []types.Pair) (pairStorageStat, error) {
@@ -1314,6 +1566,24 @@ func (s *Storage) parsePairStorageStat(opts []types.Pair) (pairStorageStat, erro
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "multipart_id":
 			if result.HasMultipartID {
 				continue
@@ -1326,6 +1596,12 @@ func (s *Storage) parsePairStorageStat(opts []types.Pair) (pairStorageStat, erro
 			}
 			result.HasObjectMode = true
 			result.ObjectMode = v.Value.(types.ObjectMode)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageStat{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -1357,6 +1633,8 @@ type pairStorageWrite struct {
 	ContentMd5                   string
 	HasContentType               bool
 	ContentType                  string
+	HasIfNoneMatch               bool
+	IfNoneMatch                  string
 	HasIoCallback                bool
 	IoCallback                   func([]byte)
 	HasServerSideDataEncryption  bool
@@ -1367,6 +1645,8 @@ type pairStorageWrite struct {
 	ServerSideEncryptionKeyID    string
 	HasStorageClass              bool
 	StorageClass                 string
+	HasUserMetadata              bool
+	UserMetadata                 map[string]string
 }
 
 func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, error) {
@@ -1387,6 +1667,12 @@ func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, er
 			}
 			result.HasContentType = true
 			result.ContentType = v.Value.(string)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
@@ -1417,6 +1703,12 @@ func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, er
 			}
 			result.HasStorageClass = true
 			result.StorageClass = v.Value.(string)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageWrite{}, services.PairUnsupportedError{Pair: v}
 		}
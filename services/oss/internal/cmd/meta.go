@@ -45,10 +45,15 @@ var Metadata = def.Metadata{
 			CreateLink:        true,
 			CreateMultipart:   true,
 			Delete:            true,
+			GetLifecycle:      true,
 			List:              true,
 			ListMultipart:     true,
+			ListVersion:       true,
 			Metadata:          true,
 			Read:              true,
+			Restore:           true,
+			SetLifecycle:      true,
+			SetStorageClass:   true,
 			Stat:              true,
 			Write:             true,
 			WriteAppend:       true,
@@ -66,6 +71,7 @@ var Metadata = def.Metadata{
 			def.PairContentType,
 			pairStorageClass,
 			pairServerSideEncryption,
+			def.PairUserMetadata,
 		},
 		CreateMultipart: []def.Pair{
 			def.PairContentType,
@@ -73,10 +79,12 @@ var Metadata = def.Metadata{
 			pairServerSideEncryption,
 			pairServerSideDataEncryption,
 			pairServerSideEncryptionKeyId,
+			def.PairUserMetadata,
 		},
 		Delete: []def.Pair{
 			def.PairMultipartID,
 			def.PairObjectMode,
+			def.PairVersionID,
 		},
 		List: []def.Pair{
 			def.PairListMode,
@@ -85,15 +93,21 @@ var Metadata = def.Metadata{
 			def.PairOffset,
 			def.PairIoCallback,
 			def.PairSize,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
+			def.PairVersionID,
 		},
 		Write: []def.Pair{
 			def.PairContentMD5,
 			def.PairContentType,
 			def.PairIoCallback,
+			def.PairIfNoneMatch,
 			pairStorageClass,
 			pairServerSideEncryption,
 			pairServerSideDataEncryption,
 			pairServerSideEncryptionKeyId,
+			def.PairUserMetadata,
 		},
 		WriteAppend: []def.Pair{
 			def.PairContentMD5,
@@ -102,9 +116,16 @@ var Metadata = def.Metadata{
 		WriteMultipart: []def.Pair{
 			def.PairContentMD5,
 		},
+		Restore: []def.Pair{
+			def.PairRestoreDays,
+		},
 		Stat: []def.Pair{
 			def.PairMultipartID,
 			def.PairObjectMode,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
+			def.PairVersionID,
 		},
 	},
 }
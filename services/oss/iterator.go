@@ -8,6 +8,9 @@ type objectPageStatus struct {
 	prefix       string
 	marker       string
 	partIdMarker string
+
+	// Only used for version object
+	versionIdMarker string
 }
 
 func (i *objectPageStatus) ContinuationToken() string {
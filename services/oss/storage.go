@@ -103,6 +103,10 @@ func (s *Storage) createAppend(ctx context.Context, path string, opt pairStorage
 		options = append(options, oss.ServerSideEncryption(opt.ServerSideEncryption))
 	}
 
+	if opt.HasUserMetadata {
+		options = append(options, formatUserMetadataOptions(opt.UserMetadata)...)
+	}
+
 	offset, err := s.bucket.AppendObject(rp, nil, 0, options...)
 	if err != nil {
 		return
@@ -199,6 +203,10 @@ func (s *Storage) createMultipart(ctx context.Context, path string, opt pairStor
 		options = append(options, oss.ServerSideEncryptionKeyID(opt.ServerSideEncryptionKeyID))
 	}
 
+	if opt.HasUserMetadata {
+		options = append(options, formatUserMetadataOptions(opt.UserMetadata)...)
+	}
+
 	output, err := s.bucket.InitiateMultipartUpload(rp, options...)
 	if err != nil {
 		return
@@ -247,13 +255,37 @@ func (s *Storage) delete(ctx context.Context, path string, opt pairStorageDelete
 	// References
 	// - [GSP-46](https://github.com/beyondstorage/specs/blob/master/rfcs/46-idempotent-delete.md)
 	// - https://help.aliyun.com/document_detail/31982.html
-	err = s.bucket.DeleteObject(rp)
+	options := make([]oss.Option, 0)
+	if opt.HasVersionID {
+		options = append(options, oss.VersionId(opt.VersionID))
+	}
+	err = s.bucket.DeleteObject(rp, options...)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+func (s *Storage) getLifecycle(ctx context.Context, opt pairStorageGetLifecycle) (rules []*types.LifecycleRule, err error) {
+	output, err := s.getLifecycleRules()
+	if err != nil {
+		return nil, err
+	}
+	return s.parseLifecycleRules(output), nil
+}
+
+// getLifecycleRules returns all native lifecycle rules of the bucket.
+func (s *Storage) getLifecycleRules() ([]oss.LifecycleRule, error) {
+	output, err := s.bucket.Client.GetBucketLifecycle(s.bucket.BucketName)
+	if err != nil {
+		if e, ok := err.(oss.ServiceError); ok && e.Code == "NoSuchLifecycle" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return output.Rules, nil
+}
+
 func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (oi *types.ObjectIterator, err error) {
 	if !opt.HasListMode {
 		// Support `ListModePrefix` as the default `ListMode`.
@@ -299,6 +331,14 @@ func (s *Storage) listMultipart(ctx context.Context, o *types.Object, opt pairSt
 	return types.NewPartIterator(ctx, s.nextPartPage, input), nil
 }
 
+func (s *Storage) listVersion(ctx context.Context, path string, opt pairStorageListVersion) (oi *types.ObjectIterator, err error) {
+	input := &objectPageStatus{
+		maxKeys: 200,
+		prefix:  s.getAbsPath(path),
+	}
+	return types.NewObjectIterator(ctx, s.nextVersionObjectPage, input), nil
+}
+
 func (s *Storage) metadata(opt pairStorageMetadata) (meta *types.StorageMeta) {
 	meta = types.NewStorageMeta()
 	meta.Name = s.bucket.BucketName
@@ -382,6 +422,52 @@ func (s *Storage) nextObjectPageByPrefix(ctx context.Context, page *types.Object
 	return nil
 }
 
+func (s *Storage) nextVersionObjectPage(ctx context.Context, page *types.ObjectPage) error {
+	input := page.Status.(*objectPageStatus)
+
+	output, err := s.bucket.ListObjectVersions(
+		oss.KeyMarker(input.marker),
+		oss.VersionIdMarker(input.versionIdMarker),
+		oss.MaxKeys(input.maxKeys),
+		oss.Prefix(input.prefix),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range output.ObjectVersions {
+		// Prefix could match other objects, only versions of this path are needed.
+		if v.Key != input.prefix {
+			continue
+		}
+
+		o := s.newObject(true)
+		o.ID = v.Key
+		o.Path = s.getRelPath(v.Key)
+		o.Mode |= types.ModeRead
+		o.SetContentLength(v.Size)
+		o.SetLastModified(v.LastModified)
+		if v.ETag != "" {
+			o.SetEtag(v.ETag)
+		}
+		o.SetVersionID(v.VersionId)
+
+		var sm ObjectSystemMetadata
+		sm.StorageClass = v.StorageClass
+		o.SetSystemMetadata(sm)
+
+		page.Data = append(page.Data, o)
+	}
+
+	if !output.IsTruncated {
+		return types.IterateDone
+	}
+
+	input.marker = output.NextKeyMarker
+	input.versionIdMarker = output.NextVersionIdMarker
+	return nil
+}
+
 func (s *Storage) nextPartObjectPageByPrefix(ctx context.Context, page *types.ObjectPage) error {
 	input := page.Status.(*objectPageStatus)
 
@@ -474,6 +560,18 @@ func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairSt
 	} else if opt.HasOffset && opt.HasSize {
 		options = append(options, oss.Range(opt.Offset, opt.Offset+opt.Size-1))
 	}
+	if opt.HasIfMatch {
+		options = append(options, oss.IfMatch(opt.IfMatch))
+	}
+	if opt.HasIfModifiedSince {
+		options = append(options, oss.IfModifiedSince(opt.IfModifiedSince))
+	}
+	if opt.HasIfNoneMatch {
+		options = append(options, oss.IfNoneMatch(opt.IfNoneMatch))
+	}
+	if opt.HasVersionID {
+		options = append(options, oss.VersionId(opt.VersionID))
+	}
 	output, err := s.bucket.GetObject(rp, options...)
 	if err != nil {
 		return 0, err
@@ -488,6 +586,60 @@ func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairSt
 	return io.Copy(w, rc)
 }
 
+func (s *Storage) restore(ctx context.Context, path string, opt pairStorageRestore) (err error) {
+	rp := s.getAbsPath(path)
+
+	if opt.HasRestoreDays {
+		err = s.bucket.RestoreObjectDetail(rp, oss.RestoreConfiguration{Days: int32(opt.RestoreDays)})
+	} else {
+		err = s.bucket.RestoreObject(rp)
+	}
+	if err != nil {
+		// Restore should be idempotent, so we need to omit the error while
+		// the object is being restored.
+		if e, ok := err.(oss.ServiceError); ok && e.Code == "RestoreAlreadyInProgress" {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) setLifecycle(ctx context.Context, rules []*types.LifecycleRule, opt pairStorageSetLifecycle) (err error) {
+	existing, err := s.getLifecycleRules()
+	if err != nil {
+		return err
+	}
+	// Only rules managed by this storager will be replaced, rules outside
+	// the work dir or not representable are kept untouched.
+	output := make([]oss.LifecycleRule, 0, len(existing)+len(rules))
+	for _, v := range existing {
+		if !s.isManagedLifecycleRule(v) {
+			output = append(output, v)
+		}
+	}
+	output = append(output, s.formatLifecycleRules(rules)...)
+
+	// SetBucketLifecycle doesn't accept empty rules, we need to delete the
+	// whole configuration instead.
+	if len(output) == 0 {
+		return s.bucket.Client.DeleteBucketLifecycle(s.bucket.BucketName)
+	}
+	return s.bucket.Client.SetBucketLifecycle(s.bucket.BucketName, output)
+}
+
+func (s *Storage) setStorageClass(ctx context.Context, path string, class string, opt pairStorageSetStorageClass) (err error) {
+	rp := s.getAbsPath(path)
+
+	// OSS doesn't support changing storage class in place, we need to copy
+	// the object to itself with the new storage class.
+	_, err = s.bucket.CopyObject(rp, rp,
+		oss.ObjectStorageClass(oss.StorageClassType(class)),
+		oss.MetadataDirective(oss.MetaCopy),
+	)
+	return err
+}
+
 func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o *types.Object, err error) {
 	rp := s.getAbsPath(path)
 
@@ -532,7 +684,24 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 		rp += "/"
 	}
 
-	output, err := s.bucket.GetObjectMeta(rp)
+	options := make([]oss.Option, 0)
+	if opt.HasIfMatch {
+		options = append(options, oss.IfMatch(opt.IfMatch))
+	}
+	if opt.HasIfModifiedSince {
+		options = append(options, oss.IfModifiedSince(opt.IfModifiedSince))
+	}
+	if opt.HasIfNoneMatch {
+		options = append(options, oss.IfNoneMatch(opt.IfNoneMatch))
+	}
+
+	if opt.HasVersionID {
+		options = append(options, oss.VersionId(opt.VersionID))
+	}
+
+	// GetObjectMeta only returns basic metadata and doesn't support
+	// conditional headers, so we use GetObjectDetailedMeta instead.
+	output, err := s.bucket.GetObjectDetailedMeta(rp, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -568,11 +737,21 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 	if v := output.Get(headers.ETag); v != "" {
 		o.SetEtag(v)
 	}
+	if v := output.Get(versionIDHeader); v != "" {
+		o.SetVersionID(v)
+	}
+	if v := formatUserMetadata(output); len(v) > 0 {
+		o.SetUserMetadata(v)
+	}
 
 	if v := output.Get(headers.ContentType); v != "" {
 		o.SetContentType(v)
 	}
 
+	if v := formatRestoreStatus(output.Get(storageClassHeader), output.Get(restoreHeader)); v != "" {
+		o.SetRestoreStatus(v)
+	}
+
 	var sm ObjectSystemMetadata
 	if v := output.Get(storageClassHeader); v != "" {
 		sm.StorageClass = v
@@ -589,6 +768,14 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 }
 
 func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int64, opt pairStorageWrite) (n int64, err error) {
+	// OSS PutObject doesn't support conditional headers, it could only
+	// forbid overwriting existing objects via x-oss-forbid-overwrite.
+	//
+	// ref: https://www.alibabacloud.com/help/doc-detail/31978.htm
+	if opt.HasIfNoneMatch && opt.IfNoneMatch != "*" {
+		err = fmt.Errorf("if_none_match %q on write: %w", opt.IfNoneMatch, services.ErrCapabilityInsufficient)
+		return
+	}
 	if size > writeSizeMaximum {
 		err = fmt.Errorf("size limit exceeded: %w", services.ErrRestrictionDissatisfied)
 		return
@@ -614,6 +801,9 @@ func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int6
 	if opt.HasContentMd5 {
 		options = append(options, oss.ContentMD5(opt.ContentMd5))
 	}
+	if opt.HasIfNoneMatch {
+		options = append(options, oss.ForbidOverWrite(true))
+	}
 	if opt.HasStorageClass {
 		options = append(options, oss.StorageClass(oss.StorageClassType(opt.StorageClass)))
 	}
@@ -627,6 +817,10 @@ func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int6
 		options = append(options, oss.ServerSideEncryptionKeyID(opt.ServerSideEncryptionKeyID))
 	}
 
+	if opt.HasUserMetadata {
+		options = append(options, formatUserMetadataOptions(opt.UserMetadata)...)
+	}
+
 	err = s.bucket.PutObject(rp, r, options...)
 	if err != nil {
 		return
@@ -1,6 +1,7 @@
 package oss
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -144,6 +145,10 @@ func (f *Factory) newService() (srv *Service, err error) {
 const (
 	// ref: https://www.alibabacloud.com/help/doc-detail/31984.htm
 	storageClassHeader = "x-oss-storage-class"
+	// ref: https://www.alibabacloud.com/help/doc-detail/31985.htm
+	versionIDHeader = "x-oss-version-id"
+	// ref: https://www.alibabacloud.com/help/doc-detail/52930.htm
+	restoreHeader = "x-oss-restore"
 
 	// ref: https://www.alibabacloud.com/help/doc-detail/51374.htm
 	StorageClassStandard = "STANDARD"
@@ -151,8 +156,27 @@ const (
 	StorageClassArchive  = "Archive"
 )
 
+// formatRestoreStatus converts the x-oss-restore header into restore status.
+//
+// ref: https://www.alibabacloud.com/help/doc-detail/31984.htm
+func formatRestoreStatus(class, restore string) string {
+	switch {
+	case strings.Contains(restore, `ongoing-request="true"`):
+		return typ.RestoreStatusOngoing
+	case restore != "":
+		return typ.RestoreStatusRestored
+	case class == string(oss.StorageArchive) || class == string(oss.StorageColdArchive):
+		return typ.RestoreStatusArchived
+	default:
+		return ""
+	}
+}
+
 func formatError(err error) error {
-	if _, ok := err.(services.InternalError); ok {
+	// write rejects unsupported if_none_match with a wrapped
+	// ErrCapabilityInsufficient, keep it instead of ErrUnexpected.
+	var ie services.InternalError
+	if errors.As(err, &ie) {
 		return err
 	}
 
@@ -163,6 +187,8 @@ func formatError(err error) error {
 			switch e.StatusCode {
 			case 404:
 				return fmt.Errorf("%w: %v", services.ErrObjectNotExist, err)
+			case 304, 412:
+				return fmt.Errorf("%w: %v", services.ErrPreconditionFailed, err)
 			default:
 				return fmt.Errorf("%w, %v", services.ErrUnexpected, err)
 			}
@@ -170,6 +196,10 @@ func formatError(err error) error {
 			return fmt.Errorf("%w: %v", services.ErrObjectNotExist, err)
 		case "AccessDenied":
 			return fmt.Errorf("%w: %v", services.ErrPermissionDenied, err)
+		// PutObject will return FileAlreadyExists while x-oss-forbid-overwrite
+		// is set and the object exists.
+		case "PreconditionFailed", "NotModified", "FileAlreadyExists":
+			return fmt.Errorf("%w: %v", services.ErrPreconditionFailed, err)
 		}
 	case oss.UnexpectedStatusCodeError:
 		switch e.Got() {
@@ -177,6 +207,8 @@ func formatError(err error) error {
 			return fmt.Errorf("%w: %v", services.ErrObjectNotExist, err)
 		case 403:
 			return fmt.Errorf("%w: %v", services.ErrPermissionDenied, err)
+		case 304, 412:
+			return fmt.Errorf("%w: %v", services.ErrPreconditionFailed, err)
 		}
 	}
 
@@ -239,14 +271,28 @@ func (s *Storage) formatError(op string, err error, path ...string) error {
 		return nil
 	}
 
+	if isObjectArchivedError(op, err) {
+		err = fmt.Errorf("%w: %v", services.ErrObjectArchived, err)
+	} else {
+		err = formatError(err)
+	}
+
 	return services.StorageError{
 		Op:       op,
-		Err:      formatError(err),
+		Err:      err,
 		Storager: s,
 		Path:     path,
 	}
 }
 
+// isObjectArchivedError checks whether err is returned for reading archived
+// objects. InvalidObjectState is also returned by other operations, for
+// example restoring objects which are not archived, so only read is checked.
+func isObjectArchivedError(op string, err error) bool {
+	e, ok := err.(oss.ServiceError)
+	return op == "read" && ok && e.Code == "InvalidObjectState"
+}
+
 func (s *Storage) formatFileObject(v oss.ObjectProperties) (o *typ.Object, err error) {
 	o = s.newObject(false)
 	o.ID = v.Key
@@ -292,6 +338,28 @@ const (
 	ServerSideDataEncryptionSM4 = "SM4"
 )
 
+// formatUserMetadataOptions converts user metadata into oss options.
+func formatUserMetadataOptions(m map[string]string) []oss.Option {
+	options := make([]oss.Option, 0, len(m))
+	for k, v := range m {
+		options = append(options, oss.Meta(strings.ToLower(k), v))
+	}
+	return options
+}
+
+// formatUserMetadata extracts user metadata from headers, keys will be
+// normalized into lower case.
+func formatUserMetadata(h http.Header) map[string]string {
+	um := make(map[string]string)
+	for k := range h {
+		if !strings.HasPrefix(k, oss.HTTPHeaderOssMetaPrefix) {
+			continue
+		}
+		um[strings.ToLower(strings.TrimPrefix(k, oss.HTTPHeaderOssMetaPrefix))] = h.Get(k)
+	}
+	return um
+}
+
 // OSS response error code.
 //
 // ref: https://error-center.alibabacloud.com/status/product/Oss
@@ -328,3 +396,90 @@ const (
 	// ref: https://help.aliyun.com/document_detail/31981.html?spm=a2c4g.11186623.6.1684.479a3ea7S8dRgB#title-22f-5c3-0sv
 	appendTotalSizeMaximum = 5 * 1024 * 1024 * 1024
 )
+
+// formatLifecycleRules converts lifecycle rules into oss lifecycle rules.
+func (s *Storage) formatLifecycleRules(rules []*typ.LifecycleRule) []oss.LifecycleRule {
+	output := make([]oss.LifecycleRule, 0, len(rules))
+	for _, r := range rules {
+		rule := oss.LifecycleRule{
+			ID:     r.ID,
+			Prefix: s.getAbsPath(r.Prefix),
+			Status: "Enabled",
+		}
+		if r.Disabled {
+			rule.Status = "Disabled"
+		}
+		if r.ExpireDays > 0 {
+			rule.Expiration = &oss.LifecycleExpiration{Days: r.ExpireDays}
+		}
+		for _, t := range r.Transitions {
+			rule.Transitions = append(rule.Transitions, oss.LifecycleTransition{
+				Days:         t.Days,
+				StorageClass: oss.StorageClassType(t.StorageClass),
+			})
+		}
+		if r.AbortMultipartDays > 0 {
+			rule.AbortMultipartUpload = &oss.LifecycleAbortMultipartUpload{Days: r.AbortMultipartDays}
+		}
+		output = append(output, rule)
+	}
+	return output
+}
+
+// parseLifecycleRules converts oss lifecycle rules into lifecycle rules.
+//
+// Only rules managed by this storager will be returned.
+func (s *Storage) parseLifecycleRules(rules []oss.LifecycleRule) []*typ.LifecycleRule {
+	output := make([]*typ.LifecycleRule, 0, len(rules))
+	for _, v := range rules {
+		if !s.isManagedLifecycleRule(v) {
+			continue
+		}
+
+		rule := &typ.LifecycleRule{
+			ID:       v.ID,
+			Prefix:   s.getRelPath(v.Prefix),
+			Disabled: v.Status != "Enabled",
+		}
+		if v.Expiration != nil {
+			rule.ExpireDays = v.Expiration.Days
+		}
+		for _, t := range v.Transitions {
+			rule.Transitions = append(rule.Transitions, typ.LifecycleTransition{
+				Days:         t.Days,
+				StorageClass: string(t.StorageClass),
+			})
+		}
+		if v.AbortMultipartUpload != nil {
+			rule.AbortMultipartDays = v.AbortMultipartUpload.Days
+		}
+		output = append(output, rule)
+	}
+	return output
+}
+
+// isManagedLifecycleRule checks whether rule is under the work dir and could
+// be represented by LifecycleRule.
+//
+// Other rules, like rules filtered by tags or expired at a date, are invisible
+// to get_lifecycle and will be kept untouched by set_lifecycle.
+func (s *Storage) isManagedLifecycleRule(rule oss.LifecycleRule) bool {
+	if len(rule.Tags) > 0 || !strings.HasPrefix(rule.Prefix, strings.TrimPrefix(s.workDir, "/")) {
+		return false
+	}
+	if v := rule.Expiration; v != nil && (v.Date != "" || v.CreatedBeforeDate != "" || v.ExpiredObjectDeleteMarker != nil) {
+		return false
+	}
+	if v := rule.AbortMultipartUpload; v != nil && v.CreatedBeforeDate != "" {
+		return false
+	}
+	if rule.NonVersionExpiration != nil || rule.NonVersionTransition != nil || len(rule.NonVersionTransitions) > 0 {
+		return false
+	}
+	for _, t := range rule.Transitions {
+		if t.CreatedBeforeDate != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -317,13 +317,18 @@ func (f *Factory) storageFeatures() (s types.StorageFeatures) {
 	s.CreateLink = true
 	s.CreateMultipart = true
 	s.Delete = true
+	s.GetLifecycle = true
 	s.List = true
 	s.ListMultipart = true
+	s.ListVersion = true
 	s.Metadata = true
 	s.QuerySignHTTPRead = true
 	s.QuerySignHTTPWrite = true
 	s.QuerySignHTTPWriteMultipart = true
 	s.Read = true
+	s.Restore = true
+	s.SetLifecycle = true
+	s.SetStorageClass = true
 	s.Stat = true
 	s.Write = true
 	s.WriteMultipart = true
@@ -844,6 +849,8 @@ type pairStorageCreateMultipart struct {
 	ServerSideEncryptionCustomerAlgorithm    string
 	HasServerSideEncryptionCustomerKey       bool
 	ServerSideEncryptionCustomerKey          []byte
+	HasUserMetadata                          bool
+	UserMetadata                             map[string]string
 }
 
 func (s *Storage) parsePairStorageCreateMultipart(opts []types.Pair) (pairStorageCreateMultipart, error) {
@@ -894,6 +901,12 @@ func (s *Storage) parsePairStorageCreateMultipart(opts []types.Pair) (pairStorag
 			}
 			result.HasServerSideEncryptionCustomerKey = true
 			result.ServerSideEncryptionCustomerKey = v.Value.([]byte)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageCreateMultipart{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -948,10 +961,14 @@ type pairStorageDelete struct {
 	pairs                  []types.Pair
 	HasExpectedBucketOwner bool
 	ExpectedBucketOwner    string
+	HasIfMatch             bool
+	IfMatch                string
 	HasMultipartID         bool
 	MultipartID            string
 	HasObjectMode          bool
 	ObjectMode             types.ObjectMode
+	HasVersionID           bool
+	VersionID              string
 }
 
 func (s *Storage) parsePairStorageDelete(opts []types.Pair) (pairStorageDelete, error) {
@@ -966,6 +983,12 @@ func (s *Storage) parsePairStorageDelete(opts []types.Pair) (pairStorageDelete,
 			}
 			result.HasExpectedBucketOwner = true
 			result.ExpectedBucketOwner = v.Value.(string)
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
 		case "multipart_id":
 			if result.HasMultipartID {
 				continue
@@ -978,6 +1001,12 @@ func (s *Storage) parsePairStorageDelete(opts []types.Pair) (pairStorageDelete,
 			}
 			result.HasObjectMode = true
 			result.ObjectMode = v.Value.(types.ObjectMode)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageDelete{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -1028,6 +1057,49 @@ func (s *Storage) FetchWithContext(ctx context.Context, path string, url string,
 	return
 }
 
+type pairStorageGetLifecycle struct {
+	pairs                  []types.Pair
+	HasExpectedBucketOwner bool
+	ExpectedBucketOwner    string
+}
+
+func (s *Storage) parsePairStorageGetLifecycle(opts []types.Pair) (pairStorageGetLifecycle, error) {
+	result :=
+		pairStorageGetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "expected_bucket_owner":
+			if result.HasExpectedBucketOwner {
+				continue
+			}
+			result.HasExpectedBucketOwner = true
+			result.ExpectedBucketOwner = v.Value.(string)
+		default:
+			return pairStorageGetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) GetLifecycle(pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	ctx := context.Background()
+	return s.GetLifecycleWithContext(ctx, pairs...)
+}
+func (s *Storage) GetLifecycleWithContext(ctx context.Context, pairs ...types.Pair) (rules []*types.LifecycleRule, err error) {
+	defer func() {
+		err =
+			s.formatError("get_lifecycle", err)
+	}()
+	pairs = append(pairs, s.defaultPairs.GetLifecycle...)
+	var opt pairStorageGetLifecycle
+
+	opt, err = s.parsePairStorageGetLifecycle(pairs)
+	if err != nil {
+		return
+	}
+	return s.getLifecycle(ctx, opt)
+}
+
 type pairStorageList struct {
 	pairs                  []types.Pair
 	HasExpectedBucketOwner bool
@@ -1147,6 +1219,49 @@ func (s *Storage) ListMultipartWithContext(ctx context.Context, o *types.Object,
 	return s.listMultipart(ctx, o, opt)
 }
 
+type pairStorageListVersion struct {
+	pairs                  []types.Pair
+	HasExpectedBucketOwner bool
+	ExpectedBucketOwner    string
+}
+
+func (s *Storage) parsePairStorageListVersion(opts []types.Pair) (pairStorageListVersion, error) {
+	result :=
+		pairStorageListVersion{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "expected_bucket_owner":
+			if result.HasExpectedBucketOwner {
+				continue
+			}
+			result.HasExpectedBucketOwner = true
+			result.ExpectedBucketOwner = v.Value.(string)
+		default:
+			return pairStorageListVersion{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) ListVersion(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	ctx := context.Background()
+	return s.ListVersionWithContext(ctx, path, pairs...)
+}
+func (s *Storage) ListVersionWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	defer func() {
+		err =
+			s.formatError("list_version", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.ListVersion...)
+	var opt pairStorageListVersion
+
+	opt, err = s.parsePairStorageListVersion(pairs)
+	if err != nil {
+		return
+	}
+	return s.listVersion(ctx, strings.ReplaceAll(path, "\\", "/"), opt)
+}
+
 type pairStorageMetadata struct {
 	pairs []types.Pair
 }
@@ -1550,6 +1665,12 @@ type pairStorageRead struct {
 	pairs                                    []types.Pair
 	HasExpectedBucketOwner                   bool
 	ExpectedBucketOwner                      string
+	HasIfMatch                               bool
+	IfMatch                                  string
+	HasIfModifiedSince                       bool
+	IfModifiedSince                          time.Time
+	HasIfNoneMatch                           bool
+	IfNoneMatch                              string
 	HasIoCallback                            bool
 	IoCallback                               func([]byte)
 	HasOffset                                bool
@@ -1560,6 +1681,8 @@ type pairStorageRead struct {
 	ServerSideEncryptionCustomerKey          []byte
 	HasSize                                  bool
 	Size                                     int64
+	HasVersionID                             bool
+	VersionID                                string
 }
 
 func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, error) {
@@ -1574,6 +1697,24 @@ func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, erro
 			}
 			result.HasExpectedBucketOwner = true
 			result.ExpectedBucketOwner = v.Value.(string)
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
@@ -1604,6 +1745,12 @@ func (s *Storage) parsePairStorageRead(opts []types.Pair) (pairStorageRead, erro
 			}
 			result.HasSize = true
 			result.Size = v.Value.(int64)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageRead{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -1629,10 +1776,153 @@ func (s *Storage) ReadWithContext(ctx context.Context, path string, w io.Writer,
 	return s.read(ctx, strings.ReplaceAll(path, "\\", "/"), w, opt)
 }
 
+type pairStorageRestore struct {
+	pairs                  []types.Pair
+	HasExpectedBucketOwner bool
+	ExpectedBucketOwner    string
+	HasRestoreDays         bool
+	RestoreDays            int
+}
+
+func (s *Storage) parsePairStorageRestore(opts []types.Pair) (pairStorageRestore, error) {
+	result :=
+		pairStorageRestore{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "expected_bucket_owner":
+			if result.HasExpectedBucketOwner {
+				continue
+			}
+			result.HasExpectedBucketOwner = true
+			result.ExpectedBucketOwner = v.Value.(string)
+		case "restore_days":
+			if result.HasRestoreDays {
+				continue
+			}
+			result.HasRestoreDays = true
+			result.RestoreDays = v.Value.(int)
+		default:
+			return pairStorageRestore{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) Restore(path string, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.RestoreWithContext(ctx, path, pairs...)
+}
+func (s *Storage) RestoreWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("restore", err, path)
+	}()
+	pairs = append(pairs, s.defaultPairs.Restore...)
+	var opt pairStorageRestore
+
+	opt, err = s.parsePairStorageRestore(pairs)
+	if err != nil {
+		return
+	}
+	return s.restore(ctx, strings.ReplaceAll(path, "\\", "/"), opt)
+}
+
+type pairStorageSetLifecycle struct {
+	pairs                  []types.Pair
+	HasExpectedBucketOwner bool
+	ExpectedBucketOwner    string
+}
+
+func (s *Storage) parsePairStorageSetLifecycle(opts []types.Pair) (pairStorageSetLifecycle, error) {
+	result :=
+		pairStorageSetLifecycle{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "expected_bucket_owner":
+			if result.HasExpectedBucketOwner {
+				continue
+			}
+			result.HasExpectedBucketOwner = true
+			result.ExpectedBucketOwner = v.Value.(string)
+		default:
+			return pairStorageSetLifecycle{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetLifecycle(rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.SetLifecycleWithContext(ctx, rules, pairs...)
+}
+func (s *Storage) SetLifecycleWithContext(ctx context.Context, rules []*types.LifecycleRule, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("set_lifecycle", err)
+	}()
+	pairs = append(pairs, s.defaultPairs.SetLifecycle...)
+	var opt pairStorageSetLifecycle
+
+	opt, err = s.parsePairStorageSetLifecycle(pairs)
+	if err != nil {
+		return
+	}
+	return s.setLifecycle(ctx, rules, opt)
+}
+
+type pairStorageSetStorageClass struct {
+	pairs                  []types.Pair
+	HasExpectedBucketOwner bool
+	ExpectedBucketOwner    string
+}
+
+func (s *Storage) parsePairStorageSetStorageClass(opts []types.Pair) (pairStorageSetStorageClass, error) {
+	result :=
+		pairStorageSetStorageClass{pairs: opts}
+
+	for _, v := range opts {
+		switch v.Key {
+		case "expected_bucket_owner":
+			if result.HasExpectedBucketOwner {
+				continue
+			}
+			result.HasExpectedBucketOwner = true
+			result.ExpectedBucketOwner = v.Value.(string)
+		default:
+			return pairStorageSetStorageClass{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return result, nil
+}
+func (s *Storage) SetStorageClass(path string, class string, pairs ...types.Pair) (err error) {
+	ctx := context.Background()
+	return s.SetStorageClassWithContext(ctx, path, class, pairs...)
+}
+func (s *Storage) SetStorageClassWithContext(ctx context.Context, path string, class string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err =
+			s.formatError("set_storage_class", err, path, class)
+	}()
+	pairs = append(pairs, s.defaultPairs.SetStorageClass...)
+	var opt pairStorageSetStorageClass
+
+	opt, err = s.parsePairStorageSetStorageClass(pairs)
+	if err != nil {
+		return
+	}
+	return s.setStorageClass(ctx, strings.ReplaceAll(path, "\\", "/"), class, opt)
+}
+
 type pairStorageStat struct {
 	pairs                                    []types.Pair
 	HasExpectedBucketOwner                   bool
 	ExpectedBucketOwner                      string
+	HasIfMatch                               bool
+	IfMatch                                  string
+	HasIfModifiedSince                       bool
+	IfModifiedSince                          time.Time
+	HasIfNoneMatch                           bool
+	IfNoneMatch                              string
 	HasMultipartID                           bool
 	MultipartID                              string
 	HasObjectMode                            bool
@@ -1641,6 +1931,8 @@ type pairStorageStat struct {
 	ServerSideEncryptionCustomerAlgorithm    string
 	HasServerSideEncryptionCustomerKey       bool
 	ServerSideEncryptionCustomerKey          []byte
+	HasVersionID                             bool
+	VersionID                                string
 }
 
 func (s *Storage) parsePairStorageStat(opts []types.Pair) (pairStorageStat, error) {
@@ -1655,6 +1947,24 @@ func (s *Storage) parsePairStorageStat(opts []types.Pair) (pairStorageStat, erro
 			}
 			result.HasExpectedBucketOwner = true
 			result.ExpectedBucketOwner = v.Value.(string)
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "multipart_id":
 			if result.HasMultipartID {
 				continue
@@ -1679,6 +1989,12 @@ func (s *Storage) parsePairStorageStat(opts []types.Pair) (pairStorageStat, erro
 			}
 			result.HasServerSideEncryptionCustomerKey = true
 			result.ServerSideEncryptionCustomerKey = v.Value.([]byte)
+		case "version_id":
+			if result.HasVersionID {
+				continue
+			}
+			result.HasVersionID = true
+			result.VersionID = v.Value.(string)
 		default:
 			return pairStorageStat{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -1712,6 +2028,10 @@ type pairStorageWrite struct {
 	ContentType                              string
 	HasExpectedBucketOwner                   bool
 	ExpectedBucketOwner                      string
+	HasIfMatch                               bool
+	IfMatch                                  string
+	HasIfNoneMatch                           bool
+	IfNoneMatch                              string
 	HasIoCallback                            bool
 	IoCallback                               func([]byte)
 	HasServerSideEncryption                  bool
@@ -1728,6 +2048,8 @@ type pairStorageWrite struct {
 	ServerSideEncryptionCustomerKey          []byte
 	HasStorageClass                          bool
 	StorageClass                             string
+	HasUserMetadata                          bool
+	UserMetadata                             map[string]string
 }
 
 func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, error) {
@@ -1754,6 +2076,18 @@ func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, er
 			}
 			result.HasExpectedBucketOwner = true
 			result.ExpectedBucketOwner = v.Value.(string)
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
@@ -1802,6 +2136,12 @@ func (s *Storage) parsePairStorageWrite(opts []types.Pair) (pairStorageWrite, er
 			}
 			result.HasStorageClass = true
 			result.StorageClass = v.Value.(string)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageWrite{}, services.PairUnsupportedError{Pair: v}
 		}
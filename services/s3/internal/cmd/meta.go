@@ -71,13 +71,18 @@ var Metadata = def.Metadata{
 			CreateLink:                  true,
 			CreateMultipart:             true,
 			Delete:                      true,
+			GetLifecycle:                true,
 			List:                        true,
 			ListMultipart:               true,
+			ListVersion:                 true,
 			Metadata:                    true,
 			QuerySignHTTPRead:           true,
 			QuerySignHTTPWrite:          true,
 			QuerySignHTTPWriteMultipart: true,
 			Read:                        true,
+			Restore:                     true,
+			SetLifecycle:                true,
+			SetStorageClass:             true,
 			Stat:                        true,
 			Write:                       true,
 			WriteMultipart:              true,
@@ -93,25 +98,49 @@ var Metadata = def.Metadata{
 		},
 		Delete: []def.Pair{
 			pairExpectedBucketOwner,
+			def.PairIfMatch,
 			def.PairMultipartID,
 			def.PairObjectMode,
+			def.PairVersionID,
+		},
+		GetLifecycle: []def.Pair{
+			pairExpectedBucketOwner,
 		},
 		List: []def.Pair{
 			def.PairListMode,
 			pairExpectedBucketOwner,
 		},
+		ListVersion: []def.Pair{
+			pairExpectedBucketOwner,
+		},
 		Read: []def.Pair{
 			def.PairOffset,
 			def.PairIoCallback,
 			def.PairSize,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
 			pairExpectedBucketOwner,
 			pairServerSideEncryptionCustomerAlgorithm,
 			pairServerSideEncryptionCustomerKey,
+			def.PairVersionID,
+		},
+		Restore: []def.Pair{
+			pairExpectedBucketOwner,
+			def.PairRestoreDays,
+		},
+		SetLifecycle: []def.Pair{
+			pairExpectedBucketOwner,
+		},
+		SetStorageClass: []def.Pair{
+			pairExpectedBucketOwner,
 		},
 		Write: []def.Pair{
 			def.PairContentMD5,
 			def.PairContentType,
 			def.PairIoCallback,
+			def.PairIfMatch,
+			def.PairIfNoneMatch,
 			pairStorageClass,
 			pairExpectedBucketOwner,
 			pairServerSideEncryptionBucketKeyEnable,
@@ -120,13 +149,18 @@ var Metadata = def.Metadata{
 			pairServerSideEncryptionAwsKmsKeyId,
 			pairServerSideEncryptionContext,
 			pairServerSideEncryption,
+			def.PairUserMetadata,
 		},
 		Stat: []def.Pair{
 			def.PairMultipartID,
 			def.PairObjectMode,
+			def.PairIfMatch,
+			def.PairIfModifiedSince,
+			def.PairIfNoneMatch,
 			pairExpectedBucketOwner,
 			pairServerSideEncryptionCustomerAlgorithm,
 			pairServerSideEncryptionCustomerKey,
+			def.PairVersionID,
 		},
 		CreateMultipart: []def.Pair{
 			pairExpectedBucketOwner,
@@ -136,6 +170,7 @@ var Metadata = def.Metadata{
 			pairServerSideEncryptionAwsKmsKeyId,
 			pairServerSideEncryptionContext,
 			pairServerSideEncryption,
+			def.PairUserMetadata,
 		},
 		WriteMultipart: []def.Pair{
 			def.PairIoCallback,
@@ -12,10 +12,13 @@ type objectPageStatus struct {
 	// Only used for object
 	continuationToken string
 
-	// Only used for part object
+	// Only used for part object and version object
 	keyMarker      string
 	uploadIdMarker string
 
+	// Only used for version object
+	versionIdMarker string
+
 	expectedBucketOwner string
 }
 
@@ -35,6 +38,9 @@ func (i *objectPageStatus) ContinuationToken() string {
 	if i.uploadIdMarker != "" {
 		return i.continuationToken + "/" + i.uploadIdMarker
 	}
+	if i.versionIdMarker != "" {
+		return i.keyMarker + "/" + i.versionIdMarker
+	}
 	return i.continuationToken
 }
 
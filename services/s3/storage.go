@@ -7,12 +7,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 
 	ps "go.beyondstorage.io/v5/pairs"
 	"go.beyondstorage.io/v5/pkg/iowrap"
@@ -240,13 +242,50 @@ func (s *Storage) delete(ctx context.Context, path string, opt pairStorageDelete
 	// References
 	// - [GSP-46](https://github.com/beyondstorage/specs/blob/master/rfcs/46-idempotent-delete.md)
 	// - https://docs.aws.amazon.com/AmazonS3/latest/API/API_DeleteObject.html
-	_, err = s.service.DeleteObject(ctx, input)
+	var ifMatch *string
+	if opt.HasIfMatch {
+		ifMatch = &opt.IfMatch
+	}
+	_, err = s.service.DeleteObject(ctx, input, func(o *s3.Options) {
+		addConditionalHeaders(o, ifMatch, nil)
+	})
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+func (s *Storage) getLifecycle(ctx context.Context, opt pairStorageGetLifecycle) (rules []*types.LifecycleRule, err error) {
+	var owner *string
+	if opt.HasExpectedBucketOwner {
+		owner = &opt.ExpectedBucketOwner
+	}
+
+	output, err := s.getLifecycleRules(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	return s.parseLifecycleRules(output), nil
+}
+
+// getLifecycleRules returns all native lifecycle rules of the bucket.
+func (s *Storage) getLifecycleRules(ctx context.Context, expectedBucketOwner *string) ([]s3types.LifecycleRule, error) {
+	input := &s3.GetBucketLifecycleConfigurationInput{
+		Bucket:              aws.String(s.name),
+		ExpectedBucketOwner: expectedBucketOwner,
+	}
+
+	output, err := s.service.GetBucketLifecycleConfiguration(ctx, input)
+	if err != nil {
+		e := &smithy.GenericAPIError{}
+		if errors.As(err, &e) && e.Code == "NoSuchLifecycleConfiguration" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return output.Rules, nil
+}
+
 func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (oi *types.ObjectIterator, err error) {
 	if !opt.HasListMode {
 		// Support `ListModePrefix` as the default `ListMode`.
@@ -287,6 +326,18 @@ func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (o
 	return types.NewObjectIterator(ctx, nextFn, input), nil
 }
 
+func (s *Storage) listVersion(ctx context.Context, path string, opt pairStorageListVersion) (oi *types.ObjectIterator, err error) {
+	input := &objectPageStatus{
+		maxKeys: 200,
+		prefix:  s.getAbsPath(path),
+	}
+	if opt.HasExpectedBucketOwner {
+		input.expectedBucketOwner = opt.ExpectedBucketOwner
+	}
+
+	return types.NewObjectIterator(ctx, s.nextVersionObjectPage, input), nil
+}
+
 func (s *Storage) listMultipart(ctx context.Context, o *types.Object, opt pairStorageListMultipart) (pi *types.PartIterator, err error) {
 	input := &partPageStatus{
 		maxParts: 200,
@@ -389,6 +440,59 @@ func (s *Storage) nextObjectPageByPrefix(ctx context.Context, page *types.Object
 	return nil
 }
 
+func (s *Storage) nextVersionObjectPage(ctx context.Context, page *types.ObjectPage) error {
+	input := page.Status.(*objectPageStatus)
+
+	listInput := &s3.ListObjectVersionsInput{
+		Bucket:  &s.name,
+		MaxKeys: int32(input.maxKeys),
+		Prefix:  &input.prefix,
+	}
+	if input.keyMarker != "" {
+		listInput.KeyMarker = &input.keyMarker
+	}
+	if input.versionIdMarker != "" {
+		listInput.VersionIdMarker = &input.versionIdMarker
+	}
+	if input.expectedBucketOwner != "" {
+		listInput.ExpectedBucketOwner = &input.expectedBucketOwner
+	}
+	output, err := s.service.ListObjectVersions(ctx, listInput)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range output.Versions {
+		// Prefix could match other objects, only versions of this path are needed.
+		if aws.ToString(v.Key) != input.prefix {
+			continue
+		}
+
+		o := s.newObject(true)
+		o.ID = *v.Key
+		o.Path = s.getRelPath(*v.Key)
+		o.Mode |= types.ModeRead
+		o.SetContentLength(v.Size)
+		o.SetLastModified(aws.ToTime(v.LastModified))
+		if v.ETag != nil {
+			o.SetEtag(*v.ETag)
+		}
+		o.SetVersionID(aws.ToString(v.VersionId))
+
+		var sm ObjectSystemMetadata
+		sm.StorageClass = string(v.StorageClass)
+		o.SetSystemMetadata(sm)
+
+		page.Data = append(page.Data, o)
+	}
+	if !output.IsTruncated {
+		return types.IterateDone
+	}
+	input.keyMarker = aws.ToString(output.NextKeyMarker)
+	input.versionIdMarker = aws.ToString(output.NextVersionIdMarker)
+	return nil
+}
+
 func (s *Storage) nextPartObjectPageByPrefix(ctx context.Context, page *types.ObjectPage) error {
 	input := page.Status.(*objectPageStatus)
 	listInput := &s3.ListMultipartUploadsInput{
@@ -580,6 +684,115 @@ func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairSt
 	return io.Copy(w, rc)
 }
 
+func (s *Storage) restore(ctx context.Context, path string, opt pairStorageRestore) (err error) {
+	days := defaultRestoreDays
+	if opt.HasRestoreDays {
+		days = opt.RestoreDays
+	}
+
+	input := &s3.RestoreObjectInput{
+		Bucket: aws.String(s.name),
+		Key:    aws.String(s.getAbsPath(path)),
+		RestoreRequest: &s3types.RestoreRequest{
+			Days: int32(days),
+		},
+	}
+	if opt.HasExpectedBucketOwner {
+		input.ExpectedBucketOwner = &opt.ExpectedBucketOwner
+	}
+
+	_, err = s.service.RestoreObject(ctx, input)
+	if err != nil {
+		// Restore should be idempotent, so we need to omit the error while
+		// the object is being restored.
+		e := &smithy.GenericAPIError{}
+		if errors.As(err, &e) && e.Code == "RestoreAlreadyInProgress" {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) setLifecycle(ctx context.Context, rules []*types.LifecycleRule, opt pairStorageSetLifecycle) (err error) {
+	var owner *string
+	if opt.HasExpectedBucketOwner {
+		owner = &opt.ExpectedBucketOwner
+	}
+
+	existing, err := s.getLifecycleRules(ctx, owner)
+	if err != nil {
+		return err
+	}
+	// Only rules managed by this storager will be replaced, rules outside
+	// the work dir or not representable are kept untouched.
+	output := make([]s3types.LifecycleRule, 0, len(existing)+len(rules))
+	for _, v := range existing {
+		if !s.isManagedLifecycleRule(v) {
+			output = append(output, v)
+		}
+	}
+	output = append(output, s.formatLifecycleRules(rules)...)
+
+	// PutBucketLifecycleConfiguration doesn't accept empty rules, we need to
+	// delete the whole configuration instead.
+	if len(output) == 0 {
+		_, err = s.service.DeleteBucketLifecycle(ctx, &s3.DeleteBucketLifecycleInput{
+			Bucket:              aws.String(s.name),
+			ExpectedBucketOwner: owner,
+		})
+		return err
+	}
+
+	_, err = s.service.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:              aws.String(s.name),
+		ExpectedBucketOwner: owner,
+		LifecycleConfiguration: &s3types.BucketLifecycleConfiguration{
+			Rules: output,
+		},
+	})
+	return err
+}
+
+func (s *Storage) setStorageClass(ctx context.Context, path string, class string, opt pairStorageSetStorageClass) (err error) {
+	rp := s.getAbsPath(path)
+
+	// S3 doesn't support changing storage class in place, we need to copy
+	// the object to itself with the new storage class.
+	//
+	// A single CopyObject could only copy objects up to 5GB, larger objects
+	// need multipart copy which is not supported yet.
+	headInput := &s3.HeadObjectInput{
+		Bucket: aws.String(s.name),
+		Key:    aws.String(rp),
+	}
+	if opt.HasExpectedBucketOwner {
+		headInput.ExpectedBucketOwner = &opt.ExpectedBucketOwner
+	}
+	output, err := s.service.HeadObject(ctx, headInput)
+	if err != nil {
+		return err
+	}
+	if output.ContentLength > copySizeMaximum {
+		return fmt.Errorf("set storage class for object larger than 5GB: %w", services.ErrCapabilityInsufficient)
+	}
+
+	input := &s3.CopyObjectInput{
+		Bucket:            aws.String(s.name),
+		Key:               aws.String(rp),
+		CopySource:        aws.String(url.PathEscape(s.name + "/" + rp)),
+		MetadataDirective: s3types.MetadataDirectiveCopy,
+		StorageClass:      s3types.StorageClass(class),
+	}
+	if opt.HasExpectedBucketOwner {
+		input.ExpectedBucketOwner = &opt.ExpectedBucketOwner
+		input.ExpectedSourceBucketOwner = &opt.ExpectedBucketOwner
+	}
+
+	_, err = s.service.CopyObject(ctx, input)
+	return err
+}
+
 func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o *types.Object, err error) {
 	rp := s.getAbsPath(path)
 
@@ -621,6 +834,18 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 	if opt.HasExpectedBucketOwner {
 		input.ExpectedBucketOwner = &opt.ExpectedBucketOwner
 	}
+	if opt.HasIfMatch {
+		input.IfMatch = &opt.IfMatch
+	}
+	if opt.HasIfModifiedSince {
+		input.IfModifiedSince = &opt.IfModifiedSince
+	}
+	if opt.HasIfNoneMatch {
+		input.IfNoneMatch = &opt.IfNoneMatch
+	}
+	if opt.HasVersionID {
+		input.VersionId = &opt.VersionID
+	}
 	if opt.HasServerSideEncryptionCustomerAlgorithm {
 		input.SSECustomerAlgorithm, input.SSECustomerKey, input.SSECustomerKeyMD5, err = calculateEncryptionHeaders(opt.ServerSideEncryptionCustomerAlgorithm, opt.ServerSideEncryptionCustomerKey)
 		if err != nil {
@@ -651,6 +876,9 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 				o.SetLinkTarget("/" + target)
 			}
 		}
+		if um := formatUserMetadata(metadata); len(um) > 0 {
+			o.SetUserMetadata(um)
+		}
 	}
 
 	if o.Mode&types.ModeLink == 0 && o.Mode&types.ModeRead == 0 {
@@ -668,6 +896,12 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 	if output.ETag != nil {
 		o.SetEtag(*output.ETag)
 	}
+	if output.VersionId != nil {
+		o.SetVersionID(*output.VersionId)
+	}
+	if v := formatRestoreStatus(output.StorageClass, aws.ToString(output.Restore)); v != "" {
+		o.SetRestoreStatus(v)
+	}
 
 	var sm ObjectSystemMetadata
 	//output.StorageClass's type is s3types.StorageClass, which is equivalent to string
@@ -716,8 +950,18 @@ func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int6
 		return
 	}
 
+	var ifMatch, ifNoneMatch *string
+	if opt.HasIfMatch {
+		ifMatch = &opt.IfMatch
+	}
+	if opt.HasIfNoneMatch {
+		ifNoneMatch = &opt.IfNoneMatch
+	}
+
 	input.Body = r
-	_, err = s.service.PutObject(ctx, input)
+	_, err = s.service.PutObject(ctx, input, func(o *s3.Options) {
+		addConditionalHeaders(o, ifMatch, ifNoneMatch)
+	})
 	if err != nil {
 		return
 	}
@@ -16,6 +16,7 @@ import (
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
 	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 
 	"go.beyondstorage.io/credential"
 	"go.beyondstorage.io/endpoint"
@@ -206,8 +207,31 @@ const (
 	StorageClassDeepArchive        = string(s3types.ObjectStorageClassDeepArchive)
 )
 
+// defaultRestoreDays is the days of restored copy that will be kept if
+// restore_days is not set.
+const defaultRestoreDays = 1
+
+// formatRestoreStatus converts the x-amz-restore header into restore status.
+//
+// ref: https://docs.aws.amazon.com/AmazonS3/latest/API/API_HeadObject.html#API_HeadObject_ResponseSyntax
+func formatRestoreStatus(class s3types.StorageClass, restore string) string {
+	switch {
+	case strings.Contains(restore, `ongoing-request="true"`):
+		return typ.RestoreStatusOngoing
+	case restore != "":
+		return typ.RestoreStatusRestored
+	case class == s3types.StorageClassGlacier || class == s3types.StorageClassDeepArchive:
+		return typ.RestoreStatusArchived
+	default:
+		return ""
+	}
+}
+
 func formatError(err error) error {
-	if _, ok := err.(services.InternalError); ok {
+	// setStorageClass wraps ErrCapabilityInsufficient with the reason, which
+	// should be returned as is.
+	var ie services.InternalError
+	if errors.As(err, &ie) {
 		return err
 	}
 
@@ -219,6 +243,11 @@ func formatError(err error) error {
 			return fmt.Errorf("%w: %v", services.ErrObjectNotExist, err)
 		case "AccessDenied":
 			return fmt.Errorf("%w: %v", services.ErrPermissionDenied, err)
+		// GET and HEAD will return 304 Not Modified while if_none_match or
+		// if_modified_since is not satisfied, and conditional writes will
+		// return 409 ConditionalRequestConflict while racing with others.
+		case "PreconditionFailed", "NotModified", "ConditionalRequestConflict":
+			return fmt.Errorf("%w: %v", services.ErrPreconditionFailed, err)
 		}
 	}
 
@@ -291,14 +320,28 @@ func (s *Storage) formatError(op string, err error, path ...string) error {
 		return nil
 	}
 
+	if isObjectArchivedError(op, err) {
+		err = fmt.Errorf("%w: %v", services.ErrObjectArchived, err)
+	} else {
+		err = formatError(err)
+	}
+
 	return services.StorageError{
 		Op:       op,
-		Err:      formatError(err),
+		Err:      err,
 		Storager: s,
 		Path:     path,
 	}
 }
 
+// isObjectArchivedError checks whether err is returned for reading archived
+// objects. InvalidObjectState is also returned by other operations, for
+// example restoring objects which are not archived, so only read is checked.
+func isObjectArchivedError(op string, err error) bool {
+	var ae smithy.APIError
+	return op == "read" && errors.As(err, &ae) && ae.ErrorCode() == "InvalidObjectState"
+}
+
 func (s *Storage) formatFileObject(v s3types.Object) (o *typ.Object, err error) {
 	o = s.newObject(false)
 	o.ID = *v.Key
@@ -322,6 +365,21 @@ func (s *Storage) formatFileObject(v s3types.Object) (o *typ.Object, err error)
 	return
 }
 
+// formatUserMetadata normalizes keys of user metadata into lower case.
+//
+// Metadata used by go-storage internally will be omitted.
+func formatUserMetadata(m map[string]string) map[string]string {
+	um := make(map[string]string, len(m))
+	for k, v := range m {
+		k = strings.ToLower(k)
+		if k == metadataLinkTargetHeader {
+			continue
+		}
+		um[k] = v
+	}
+	return um
+}
+
 func (s *Storage) newObject(done bool) *typ.Object {
 	return typ.NewObject(s, done)
 }
@@ -358,6 +416,9 @@ const (
 	// writeSizeMaximum is the maximum size for each object with a single PUT operation, 5GB.
 	// ref: https://docs.aws.amazon.com/AmazonS3/latest/userguide/upload-objects.html
 	writeSizeMaximum = 5 * 1024 * 1024 * 1024
+	// copySizeMaximum is the maximum size for each object with a single COPY operation, 5GB.
+	// ref: https://docs.aws.amazon.com/AmazonS3/latest/API/API_CopyObject.html
+	copySizeMaximum = 5 * 1024 * 1024 * 1024
 )
 
 func (s *Storage) formatGetObjectInput(path string, opt pairStorageRead) (input *s3.GetObjectInput, err error) {
@@ -379,6 +440,18 @@ func (s *Storage) formatGetObjectInput(path string, opt pairStorageRead) (input
 	if opt.HasExpectedBucketOwner {
 		input.ExpectedBucketOwner = &opt.ExpectedBucketOwner
 	}
+	if opt.HasIfMatch {
+		input.IfMatch = &opt.IfMatch
+	}
+	if opt.HasIfModifiedSince {
+		input.IfModifiedSince = &opt.IfModifiedSince
+	}
+	if opt.HasIfNoneMatch {
+		input.IfNoneMatch = &opt.IfNoneMatch
+	}
+	if opt.HasVersionID {
+		input.VersionId = &opt.VersionID
+	}
 	if opt.HasServerSideEncryptionCustomerAlgorithm {
 		input.SSECustomerAlgorithm, input.SSECustomerKey, input.SSECustomerKeyMD5, err = calculateEncryptionHeaders(opt.ServerSideEncryptionCustomerAlgorithm, opt.ServerSideEncryptionCustomerKey)
 		if err != nil {
@@ -426,6 +499,9 @@ func (s *Storage) formatPutObjectInput(path string, size int64, opt pairStorageW
 	if opt.HasServerSideEncryption {
 		input.ServerSideEncryption = s3types.ServerSideEncryption(opt.ServerSideEncryption)
 	}
+	if opt.HasUserMetadata {
+		input.Metadata = formatUserMetadata(opt.UserMetadata)
+	}
 
 	return
 }
@@ -466,10 +542,26 @@ func (s *Storage) formatDeleteObjectInput(path string, opt pairStorageDelete) (i
 	if opt.HasExpectedBucketOwner {
 		input.ExpectedBucketOwner = &opt.ExpectedBucketOwner
 	}
+	if opt.HasVersionID {
+		input.VersionId = &opt.VersionID
+	}
 
 	return
 }
 
+// addConditionalHeaders sets conditional headers for PutObject and
+// DeleteObject, they are not modeled by the aws sdk we are using.
+//
+// ref: https://docs.aws.amazon.com/AmazonS3/latest/userguide/conditional-requests.html
+func addConditionalHeaders(o *s3.Options, ifMatch, ifNoneMatch *string) {
+	if ifMatch != nil {
+		o.APIOptions = append(o.APIOptions, smithyhttp.SetHeaderValue("If-Match", *ifMatch))
+	}
+	if ifNoneMatch != nil {
+		o.APIOptions = append(o.APIOptions, smithyhttp.SetHeaderValue("If-None-Match", *ifNoneMatch))
+	}
+}
+
 func (s *Storage) formatCreateMultipartUploadInput(path string, opt pairStorageCreateMultipart) (input *s3.CreateMultipartUploadInput, err error) {
 	rp := s.getAbsPath(path)
 
@@ -500,6 +592,9 @@ func (s *Storage) formatCreateMultipartUploadInput(path string, opt pairStorageC
 	if opt.HasServerSideEncryption {
 		input.ServerSideEncryption = s3types.ServerSideEncryption(opt.ServerSideEncryption)
 	}
+	if opt.HasUserMetadata {
+		input.Metadata = formatUserMetadata(opt.UserMetadata)
+	}
 
 	return
 }
@@ -552,3 +647,107 @@ func (s *Storage) formatUploadPartInput(o *typ.Object, size int64, index int, op
 
 	return
 }
+
+// formatLifecycleRules converts lifecycle rules into s3 lifecycle rules.
+func (s *Storage) formatLifecycleRules(rules []*typ.LifecycleRule) []s3types.LifecycleRule {
+	output := make([]s3types.LifecycleRule, 0, len(rules))
+	for _, r := range rules {
+		rule := s3types.LifecycleRule{
+			Filter: &s3types.LifecycleRuleFilterMemberPrefix{Value: s.getAbsPath(r.Prefix)},
+			Status: s3types.ExpirationStatusEnabled,
+		}
+		if r.ID != "" {
+			rule.ID = aws.String(r.ID)
+		}
+		if r.Disabled {
+			rule.Status = s3types.ExpirationStatusDisabled
+		}
+		if r.ExpireDays > 0 {
+			rule.Expiration = &s3types.LifecycleExpiration{Days: int32(r.ExpireDays)}
+		}
+		for _, t := range r.Transitions {
+			rule.Transitions = append(rule.Transitions, s3types.Transition{
+				Days:         int32(t.Days),
+				StorageClass: s3types.TransitionStorageClass(t.StorageClass),
+			})
+		}
+		if r.AbortMultipartDays > 0 {
+			rule.AbortIncompleteMultipartUpload = &s3types.AbortIncompleteMultipartUpload{
+				DaysAfterInitiation: int32(r.AbortMultipartDays),
+			}
+		}
+		output = append(output, rule)
+	}
+	return output
+}
+
+// parseLifecycleRules converts s3 lifecycle rules into lifecycle rules.
+//
+// Only rules managed by this storager will be returned.
+func (s *Storage) parseLifecycleRules(rules []s3types.LifecycleRule) []*typ.LifecycleRule {
+	output := make([]*typ.LifecycleRule, 0, len(rules))
+	for _, v := range rules {
+		if !s.isManagedLifecycleRule(v) {
+			continue
+		}
+		prefix, _ := lifecycleRulePrefix(v)
+
+		rule := &typ.LifecycleRule{
+			ID:       aws.ToString(v.ID),
+			Prefix:   s.getRelPath(prefix),
+			Disabled: v.Status == s3types.ExpirationStatusDisabled,
+		}
+		if v.Expiration != nil {
+			rule.ExpireDays = int(v.Expiration.Days)
+		}
+		for _, t := range v.Transitions {
+			rule.Transitions = append(rule.Transitions, typ.LifecycleTransition{
+				Days:         int(t.Days),
+				StorageClass: string(t.StorageClass),
+			})
+		}
+		if v.AbortIncompleteMultipartUpload != nil {
+			rule.AbortMultipartDays = int(v.AbortIncompleteMultipartUpload.DaysAfterInitiation)
+		}
+		output = append(output, rule)
+	}
+	return output
+}
+
+// isManagedLifecycleRule checks whether rule is under the work dir and could
+// be represented by LifecycleRule.
+//
+// Other rules, like rules filtered by tags or expired at a date, are invisible
+// to get_lifecycle and will be kept untouched by set_lifecycle.
+func (s *Storage) isManagedLifecycleRule(rule s3types.LifecycleRule) bool {
+	prefix, ok := lifecycleRulePrefix(rule)
+	if !ok || !strings.HasPrefix(prefix, strings.TrimPrefix(s.workDir, "/")) {
+		return false
+	}
+	if v := rule.Expiration; v != nil && (v.Date != nil || v.ExpiredObjectDeleteMarker) {
+		return false
+	}
+	if rule.NoncurrentVersionExpiration != nil || len(rule.NoncurrentVersionTransitions) > 0 {
+		return false
+	}
+	for _, t := range rule.Transitions {
+		if t.Date != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// lifecycleRulePrefix returns the prefix of rule, ok will be false if rule is
+// not filtered by prefix only.
+func lifecycleRulePrefix(rule s3types.LifecycleRule) (prefix string, ok bool) {
+	switch f := rule.Filter.(type) {
+	case nil:
+		// Prefix is deprecated, but still returned by rules created via it.
+		return aws.ToString(rule.Prefix), true
+	case *s3types.LifecycleRuleFilterMemberPrefix:
+		return f.Value, true
+	default:
+		return "", false
+	}
+}
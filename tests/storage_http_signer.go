@@ -158,6 +158,10 @@ func TestStorageHTTPSignerDelete(t *testing.T, store types.Storager) {
 			})
 		})
 
+		if !store.Features().CreateMultipart {
+			return
+		}
+
 		Convey("When Delete with multipart id via QuerySignHTTPDelete", func() {
 			path := uuid.New().String()
 			o, err := store.CreateMultipart(path)
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/google/uuid"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"go.beyondstorage.io/v5/pairs"
+	"go.beyondstorage.io/v5/pkg/randbytes"
+	"go.beyondstorage.io/v5/services"
+	"go.beyondstorage.io/v5/types"
+)
+
+func TestVersioner(t *testing.T, store types.Storager) {
+	Convey("Given a basic Storager", t, func() {
+
+		Convey("When overwrite a file", func() {
+			path := uuid.New().String()
+
+			contents := make([][]byte, 0, 2)
+			for i := 0; i < 2; i++ {
+				size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
+				content, _ := io.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+				contents = append(contents, content)
+
+				_, err := store.Write(path, bytes.NewReader(content), size)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			it, err := store.ListVersion(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			versions, err := it.Collect()
+
+			defer func() {
+				for _, v := range versions {
+					err := store.Delete(path, pairs.WithVersionID(v.MustGetVersionID()))
+					if err != nil {
+						t.Error(err)
+					}
+				}
+			}()
+
+			Convey("ListVersion should return all versions", func() {
+				So(err, ShouldBeNil)
+				So(versions, ShouldHaveLength, 2)
+				So(versions[0].MustGetVersionID(), ShouldNotEqual, versions[1].MustGetVersionID())
+			})
+
+			Convey("Read with version_id should get the old content", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf, pairs.WithVersionID(versions[1].MustGetVersionID()))
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, len(contents[0]))
+				So(md5.Sum(buf.Bytes()), ShouldResemble, md5.Sum(contents[0]))
+			})
+
+			Convey("Stat with version_id should get the old object", func() {
+				o, err := store.Stat(path, pairs.WithVersionID(versions[1].MustGetVersionID()))
+
+				So(err, ShouldBeNil)
+				So(o.MustGetVersionID(), ShouldEqual, versions[1].MustGetVersionID())
+				So(o.MustGetContentLength(), ShouldEqual, len(contents[0]))
+			})
+
+			Convey("Delete with version_id should only remove the version", func() {
+				err := store.Delete(path, pairs.WithVersionID(versions[1].MustGetVersionID()))
+				So(err, ShouldBeNil)
+
+				_, err = store.Stat(path, pairs.WithVersionID(versions[1].MustGetVersionID()))
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+
+				var buf bytes.Buffer
+				_, err = store.Read(path, &buf)
+				So(err, ShouldBeNil)
+				So(md5.Sum(buf.Bytes()), ShouldResemble, md5.Sum(contents[1]))
+			})
+		})
+
+		Convey("When ListVersion a not existing file", func() {
+			it, err := store.ListVersion(uuid.New().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			versions, err := it.Collect()
+
+			Convey("The iterator should be empty", func() {
+				So(err, ShouldBeNil)
+				So(versions, ShouldBeEmpty)
+			})
+		})
+	})
+}
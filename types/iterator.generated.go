@@ -29,16 +29,44 @@ type BlockIterator struct {
 	done  bool
 
 	o BlockPage
+
+	// pages will be set while prefetching, and token is the continuation token of current page.
+	pages chan prefetchedBlockPage
+	token string
+}
+
+// prefetchedBlockPage is a page fetched in background.
+type prefetchedBlockPage struct {
+	data  []*Block
+	token string
+	err   error
 }
 
 func NewBlockIterator(ctx context.Context, next NextBlockFunc, status Continuable) *BlockIterator {
 	return &BlockIterator{ctx: ctx, next: next, o: BlockPage{Status: status}}
 }
 func (it *BlockIterator) ContinuationToken() string {
+	// Status is owned by the prefetching goroutine, use the token of current page instead.
+	if it.pages != nil {
+		return it.token
+	}
+	// Iterators like merged iterator don't have status.
+	if it.o.Status == nil {
+		return ""
+	}
 	return it.o.Status.ContinuationToken()
 }
 func (it *BlockIterator) Next() (object *Block, err error) {
-	// Consume Data via index.
+	object, err = it.fetch()
+	if err != nil && !errors.Is(err, IterateDone) {
+		return nil, fmt.Errorf("iterator next failed: %w", err)
+	}
+	return
+}
+
+// fetch returns the next item without wrapping the error, so that iterators built upon this one will
+// not wrap errors twice.
+func (it *BlockIterator) fetch() (object *Block, err error) {
 	// Consume Data via index.
 	if it.index < len(it.o.Data) {
 		it.index++
@@ -52,9 +80,9 @@ func (it *BlockIterator) Next() (object *Block, err error) {
 	// Reset buf before call next.
 	it.o.Data = it.o.Data[:0]
 
-	err = it.next(it.ctx, &it.o)
+	err = it.nextPage()
 	if err != nil && !errors.Is(err, IterateDone) {
-		return nil, fmt.Errorf("iterator next failed: %w", err)
+		return nil, err
 	}
 	// Make iterator to done so that we will not fetch from upstream anymore.
 	if err != nil {
@@ -69,6 +97,226 @@ func (it *BlockIterator) Next() (object *Block, err error) {
 	return it.o.Data[0], nil
 }
 
+// nextPage fetches the next page into it.o.Data.
+func (it *BlockIterator) nextPage() error {
+	if it.pages == nil {
+		return it.next(it.ctx, &it.o)
+	}
+
+	p, ok := <-it.pages
+	if !ok {
+		// Prefetching goroutine exits without sending page only if ctx is done.
+		it.done = true
+		if err := it.ctx.Err(); err != nil {
+			return err
+		}
+		return IterateDone
+	}
+	it.o.Data = p.data
+	it.token = p.token
+	if p.err != nil && !errors.Is(p.err, IterateDone) {
+		// Prefetching goroutine has exited, fallback to fetch pages synchronously
+		// so that the failed page could be retried.
+		it.pages = nil
+	}
+	return p.err
+}
+
+// Prefetch enables fetching pages in background while the caller consumes
+// current page, at most n pages will be fetched ahead.
+//
+// ContinuationToken always returns the token of the page being consumed. The
+// iterator itself is still not safe for concurrent use.
+//
+// The prefetching goroutine exits after all pages fetched, an error returned or
+// the iterator's context is done, so callers that stop iterating early should
+// cancel the context passed to list.
+func (it *BlockIterator) Prefetch(n int) *BlockIterator {
+	if n <= 0 || it.done || it.pages != nil {
+		return it
+	}
+
+	it.token = it.ContinuationToken()
+	pages := make(chan prefetchedBlockPage, n)
+	go func() {
+		defer close(pages)
+
+		page := BlockPage{Status: it.o.Status}
+		for {
+			err := it.next(it.ctx, &page)
+
+			p := prefetchedBlockPage{data: page.Data, err: err}
+			if page.Status != nil {
+				p.token = page.Status.ContinuationToken()
+			}
+			select {
+			case pages <- p:
+			case <-it.ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+			// Data has been handed over, fetch into a new slice.
+			page.Data = nil
+		}
+	}()
+	it.pages = pages
+	return it
+}
+
+// Collect returns all remaining items in this iterator.
+func (it *BlockIterator) Collect() ([]*Block, error) {
+	items := make([]*Block, 0)
+	for {
+		o, err := it.Next()
+		if errors.Is(err, IterateDone) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, o)
+	}
+}
+
+// ForEach calls fn for every remaining item in this iterator.
+//
+// ForEach stops at the first error returned by fn or while ctx is done.
+func (it *BlockIterator) ForEach(ctx context.Context, fn func(*Block) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		o, err := it.Next()
+		if errors.Is(err, IterateDone) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = fn(o); err != nil {
+			return err
+		}
+	}
+}
+
+// Filter returns an iterator which only contains items that fn returns true.
+func (it *BlockIterator) Filter(fn func(*Block) bool) *BlockIterator {
+	return NewBlockIterator(it.ctx, func(ctx context.Context, page *BlockPage) error {
+		for {
+			o, err := it.fetch()
+			if err != nil {
+				return err
+			}
+			if fn(o) {
+				page.Data = append(page.Data, o)
+				return nil
+			}
+		}
+	}, it)
+}
+
+// Map returns an iterator which contains items converted by fn.
+func (it *BlockIterator) Map(fn func(*Block) (*Block, error)) *BlockIterator {
+	return NewBlockIterator(it.ctx, func(ctx context.Context, page *BlockPage) error {
+		o, err := it.fetch()
+		if err != nil {
+			return err
+		}
+		if o, err = fn(o); err != nil {
+			return err
+		}
+		page.Data = append(page.Data, o)
+		return nil
+	}, it)
+}
+
+// Take returns an iterator which contains at most n items.
+func (it *BlockIterator) Take(n int) *BlockIterator {
+	taken := 0
+	return NewBlockIterator(it.ctx, func(ctx context.Context, page *BlockPage) error {
+		if taken >= n {
+			return IterateDone
+		}
+		o, err := it.fetch()
+		if err != nil {
+			return err
+		}
+		taken++
+		page.Data = append(page.Data, o)
+		return nil
+	}, it)
+}
+
+// Channel sends every remaining item in this iterator to the returned channel.
+//
+// The item channel will be closed after all items sent, the error channel will
+// receive the error returned by ForEach and be closed after that.
+func (it *BlockIterator) Channel(ctx context.Context) (<-chan *Block, <-chan error) {
+	ch := make(chan *Block)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(ch)
+
+		err := it.ForEach(ctx, func(o *Block) error {
+			select {
+			case ch <- o:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return ch, errc
+}
+
+// MergeBlockIterators merges iterators sorted by less into one sorted iterator.
+//
+// Items which are equal will be returned in the order of input iterators.
+func MergeBlockIterators(ctx context.Context, less func(a, b *Block) bool, its ...*BlockIterator) *BlockIterator {
+	heads := make([]*Block, len(its))
+	// valid marks iterators that have a head, and stale marks iterators that need to fetch a new head.
+	valid := make([]bool, len(its))
+	stale := make([]bool, len(its))
+	for i := range stale {
+		stale[i] = true
+	}
+	return NewBlockIterator(ctx, func(ctx context.Context, page *BlockPage) error {
+		for i, x := range its {
+			if !stale[i] {
+				continue
+			}
+			o, err := x.fetch()
+			if err != nil && !errors.Is(err, IterateDone) {
+				return err
+			}
+			stale[i] = false
+			heads[i], valid[i] = o, err == nil
+		}
+
+		idx := -1
+		for i := range heads {
+			if valid[i] && (idx < 0 || less(heads[i], heads[idx])) {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			return IterateDone
+		}
+		page.Data = append(page.Data, heads[idx])
+		stale[idx] = true
+		return nil
+	}, nil)
+}
+
 // NextObjectFunc is the func used in iterator.
 //
 // Notes
@@ -87,16 +335,44 @@ type ObjectIterator struct {
 	done  bool
 
 	o ObjectPage
+
+	// pages will be set while prefetching, and token is the continuation token of current page.
+	pages chan prefetchedObjectPage
+	token string
+}
+
+// prefetchedObjectPage is a page fetched in background.
+type prefetchedObjectPage struct {
+	data  []*Object
+	token string
+	err   error
 }
 
 func NewObjectIterator(ctx context.Context, next NextObjectFunc, status Continuable) *ObjectIterator {
 	return &ObjectIterator{ctx: ctx, next: next, o: ObjectPage{Status: status}}
 }
 func (it *ObjectIterator) ContinuationToken() string {
+	// Status is owned by the prefetching goroutine, use the token of current page instead.
+	if it.pages != nil {
+		return it.token
+	}
+	// Iterators like merged iterator don't have status.
+	if it.o.Status == nil {
+		return ""
+	}
 	return it.o.Status.ContinuationToken()
 }
 func (it *ObjectIterator) Next() (object *Object, err error) {
-	// Consume Data via index.
+	object, err = it.fetch()
+	if err != nil && !errors.Is(err, IterateDone) {
+		return nil, fmt.Errorf("iterator next failed: %w", err)
+	}
+	return
+}
+
+// fetch returns the next item without wrapping the error, so that iterators built upon this one will
+// not wrap errors twice.
+func (it *ObjectIterator) fetch() (object *Object, err error) {
 	// Consume Data via index.
 	if it.index < len(it.o.Data) {
 		it.index++
@@ -110,9 +386,9 @@ func (it *ObjectIterator) Next() (object *Object, err error) {
 	// Reset buf before call next.
 	it.o.Data = it.o.Data[:0]
 
-	err = it.next(it.ctx, &it.o)
+	err = it.nextPage()
 	if err != nil && !errors.Is(err, IterateDone) {
-		return nil, fmt.Errorf("iterator next failed: %w", err)
+		return nil, err
 	}
 	// Make iterator to done so that we will not fetch from upstream anymore.
 	if err != nil {
@@ -127,6 +403,226 @@ func (it *ObjectIterator) Next() (object *Object, err error) {
 	return it.o.Data[0], nil
 }
 
+// nextPage fetches the next page into it.o.Data.
+func (it *ObjectIterator) nextPage() error {
+	if it.pages == nil {
+		return it.next(it.ctx, &it.o)
+	}
+
+	p, ok := <-it.pages
+	if !ok {
+		// Prefetching goroutine exits without sending page only if ctx is done.
+		it.done = true
+		if err := it.ctx.Err(); err != nil {
+			return err
+		}
+		return IterateDone
+	}
+	it.o.Data = p.data
+	it.token = p.token
+	if p.err != nil && !errors.Is(p.err, IterateDone) {
+		// Prefetching goroutine has exited, fallback to fetch pages synchronously
+		// so that the failed page could be retried.
+		it.pages = nil
+	}
+	return p.err
+}
+
+// Prefetch enables fetching pages in background while the caller consumes
+// current page, at most n pages will be fetched ahead.
+//
+// ContinuationToken always returns the token of the page being consumed. The
+// iterator itself is still not safe for concurrent use.
+//
+// The prefetching goroutine exits after all pages fetched, an error returned or
+// the iterator's context is done, so callers that stop iterating early should
+// cancel the context passed to list.
+func (it *ObjectIterator) Prefetch(n int) *ObjectIterator {
+	if n <= 0 || it.done || it.pages != nil {
+		return it
+	}
+
+	it.token = it.ContinuationToken()
+	pages := make(chan prefetchedObjectPage, n)
+	go func() {
+		defer close(pages)
+
+		page := ObjectPage{Status: it.o.Status}
+		for {
+			err := it.next(it.ctx, &page)
+
+			p := prefetchedObjectPage{data: page.Data, err: err}
+			if page.Status != nil {
+				p.token = page.Status.ContinuationToken()
+			}
+			select {
+			case pages <- p:
+			case <-it.ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+			// Data has been handed over, fetch into a new slice.
+			page.Data = nil
+		}
+	}()
+	it.pages = pages
+	return it
+}
+
+// Collect returns all remaining items in this iterator.
+func (it *ObjectIterator) Collect() ([]*Object, error) {
+	items := make([]*Object, 0)
+	for {
+		o, err := it.Next()
+		if errors.Is(err, IterateDone) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, o)
+	}
+}
+
+// ForEach calls fn for every remaining item in this iterator.
+//
+// ForEach stops at the first error returned by fn or while ctx is done.
+func (it *ObjectIterator) ForEach(ctx context.Context, fn func(*Object) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		o, err := it.Next()
+		if errors.Is(err, IterateDone) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = fn(o); err != nil {
+			return err
+		}
+	}
+}
+
+// Filter returns an iterator which only contains items that fn returns true.
+func (it *ObjectIterator) Filter(fn func(*Object) bool) *ObjectIterator {
+	return NewObjectIterator(it.ctx, func(ctx context.Context, page *ObjectPage) error {
+		for {
+			o, err := it.fetch()
+			if err != nil {
+				return err
+			}
+			if fn(o) {
+				page.Data = append(page.Data, o)
+				return nil
+			}
+		}
+	}, it)
+}
+
+// Map returns an iterator which contains items converted by fn.
+func (it *ObjectIterator) Map(fn func(*Object) (*Object, error)) *ObjectIterator {
+	return NewObjectIterator(it.ctx, func(ctx context.Context, page *ObjectPage) error {
+		o, err := it.fetch()
+		if err != nil {
+			return err
+		}
+		if o, err = fn(o); err != nil {
+			return err
+		}
+		page.Data = append(page.Data, o)
+		return nil
+	}, it)
+}
+
+// Take returns an iterator which contains at most n items.
+func (it *ObjectIterator) Take(n int) *ObjectIterator {
+	taken := 0
+	return NewObjectIterator(it.ctx, func(ctx context.Context, page *ObjectPage) error {
+		if taken >= n {
+			return IterateDone
+		}
+		o, err := it.fetch()
+		if err != nil {
+			return err
+		}
+		taken++
+		page.Data = append(page.Data, o)
+		return nil
+	}, it)
+}
+
+// Channel sends every remaining item in this iterator to the returned channel.
+//
+// The item channel will be closed after all items sent, the error channel will
+// receive the error returned by ForEach and be closed after that.
+func (it *ObjectIterator) Channel(ctx context.Context) (<-chan *Object, <-chan error) {
+	ch := make(chan *Object)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(ch)
+
+		err := it.ForEach(ctx, func(o *Object) error {
+			select {
+			case ch <- o:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return ch, errc
+}
+
+// MergeObjectIterators merges iterators sorted by less into one sorted iterator.
+//
+// Items which are equal will be returned in the order of input iterators.
+func MergeObjectIterators(ctx context.Context, less func(a, b *Object) bool, its ...*ObjectIterator) *ObjectIterator {
+	heads := make([]*Object, len(its))
+	// valid marks iterators that have a head, and stale marks iterators that need to fetch a new head.
+	valid := make([]bool, len(its))
+	stale := make([]bool, len(its))
+	for i := range stale {
+		stale[i] = true
+	}
+	return NewObjectIterator(ctx, func(ctx context.Context, page *ObjectPage) error {
+		for i, x := range its {
+			if !stale[i] {
+				continue
+			}
+			o, err := x.fetch()
+			if err != nil && !errors.Is(err, IterateDone) {
+				return err
+			}
+			stale[i] = false
+			heads[i], valid[i] = o, err == nil
+		}
+
+		idx := -1
+		for i := range heads {
+			if valid[i] && (idx < 0 || less(heads[i], heads[idx])) {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			return IterateDone
+		}
+		page.Data = append(page.Data, heads[idx])
+		stale[idx] = true
+		return nil
+	}, nil)
+}
+
 // NextPartFunc is the func used in iterator.
 //
 // Notes
@@ -145,16 +641,44 @@ type PartIterator struct {
 	done  bool
 
 	o PartPage
+
+	// pages will be set while prefetching, and token is the continuation token of current page.
+	pages chan prefetchedPartPage
+	token string
+}
+
+// prefetchedPartPage is a page fetched in background.
+type prefetchedPartPage struct {
+	data  []*Part
+	token string
+	err   error
 }
 
 func NewPartIterator(ctx context.Context, next NextPartFunc, status Continuable) *PartIterator {
 	return &PartIterator{ctx: ctx, next: next, o: PartPage{Status: status}}
 }
 func (it *PartIterator) ContinuationToken() string {
+	// Status is owned by the prefetching goroutine, use the token of current page instead.
+	if it.pages != nil {
+		return it.token
+	}
+	// Iterators like merged iterator don't have status.
+	if it.o.Status == nil {
+		return ""
+	}
 	return it.o.Status.ContinuationToken()
 }
 func (it *PartIterator) Next() (object *Part, err error) {
-	// Consume Data via index.
+	object, err = it.fetch()
+	if err != nil && !errors.Is(err, IterateDone) {
+		return nil, fmt.Errorf("iterator next failed: %w", err)
+	}
+	return
+}
+
+// fetch returns the next item without wrapping the error, so that iterators built upon this one will
+// not wrap errors twice.
+func (it *PartIterator) fetch() (object *Part, err error) {
 	// Consume Data via index.
 	if it.index < len(it.o.Data) {
 		it.index++
@@ -168,9 +692,9 @@ func (it *PartIterator) Next() (object *Part, err error) {
 	// Reset buf before call next.
 	it.o.Data = it.o.Data[:0]
 
-	err = it.next(it.ctx, &it.o)
+	err = it.nextPage()
 	if err != nil && !errors.Is(err, IterateDone) {
-		return nil, fmt.Errorf("iterator next failed: %w", err)
+		return nil, err
 	}
 	// Make iterator to done so that we will not fetch from upstream anymore.
 	if err != nil {
@@ -185,6 +709,226 @@ func (it *PartIterator) Next() (object *Part, err error) {
 	return it.o.Data[0], nil
 }
 
+// nextPage fetches the next page into it.o.Data.
+func (it *PartIterator) nextPage() error {
+	if it.pages == nil {
+		return it.next(it.ctx, &it.o)
+	}
+
+	p, ok := <-it.pages
+	if !ok {
+		// Prefetching goroutine exits without sending page only if ctx is done.
+		it.done = true
+		if err := it.ctx.Err(); err != nil {
+			return err
+		}
+		return IterateDone
+	}
+	it.o.Data = p.data
+	it.token = p.token
+	if p.err != nil && !errors.Is(p.err, IterateDone) {
+		// Prefetching goroutine has exited, fallback to fetch pages synchronously
+		// so that the failed page could be retried.
+		it.pages = nil
+	}
+	return p.err
+}
+
+// Prefetch enables fetching pages in background while the caller consumes
+// current page, at most n pages will be fetched ahead.
+//
+// ContinuationToken always returns the token of the page being consumed. The
+// iterator itself is still not safe for concurrent use.
+//
+// The prefetching goroutine exits after all pages fetched, an error returned or
+// the iterator's context is done, so callers that stop iterating early should
+// cancel the context passed to list.
+func (it *PartIterator) Prefetch(n int) *PartIterator {
+	if n <= 0 || it.done || it.pages != nil {
+		return it
+	}
+
+	it.token = it.ContinuationToken()
+	pages := make(chan prefetchedPartPage, n)
+	go func() {
+		defer close(pages)
+
+		page := PartPage{Status: it.o.Status}
+		for {
+			err := it.next(it.ctx, &page)
+
+			p := prefetchedPartPage{data: page.Data, err: err}
+			if page.Status != nil {
+				p.token = page.Status.ContinuationToken()
+			}
+			select {
+			case pages <- p:
+			case <-it.ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+			// Data has been handed over, fetch into a new slice.
+			page.Data = nil
+		}
+	}()
+	it.pages = pages
+	return it
+}
+
+// Collect returns all remaining items in this iterator.
+func (it *PartIterator) Collect() ([]*Part, error) {
+	items := make([]*Part, 0)
+	for {
+		o, err := it.Next()
+		if errors.Is(err, IterateDone) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, o)
+	}
+}
+
+// ForEach calls fn for every remaining item in this iterator.
+//
+// ForEach stops at the first error returned by fn or while ctx is done.
+func (it *PartIterator) ForEach(ctx context.Context, fn func(*Part) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		o, err := it.Next()
+		if errors.Is(err, IterateDone) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = fn(o); err != nil {
+			return err
+		}
+	}
+}
+
+// Filter returns an iterator which only contains items that fn returns true.
+func (it *PartIterator) Filter(fn func(*Part) bool) *PartIterator {
+	return NewPartIterator(it.ctx, func(ctx context.Context, page *PartPage) error {
+		for {
+			o, err := it.fetch()
+			if err != nil {
+				return err
+			}
+			if fn(o) {
+				page.Data = append(page.Data, o)
+				return nil
+			}
+		}
+	}, it)
+}
+
+// Map returns an iterator which contains items converted by fn.
+func (it *PartIterator) Map(fn func(*Part) (*Part, error)) *PartIterator {
+	return NewPartIterator(it.ctx, func(ctx context.Context, page *PartPage) error {
+		o, err := it.fetch()
+		if err != nil {
+			return err
+		}
+		if o, err = fn(o); err != nil {
+			return err
+		}
+		page.Data = append(page.Data, o)
+		return nil
+	}, it)
+}
+
+// Take returns an iterator which contains at most n items.
+func (it *PartIterator) Take(n int) *PartIterator {
+	taken := 0
+	return NewPartIterator(it.ctx, func(ctx context.Context, page *PartPage) error {
+		if taken >= n {
+			return IterateDone
+		}
+		o, err := it.fetch()
+		if err != nil {
+			return err
+		}
+		taken++
+		page.Data = append(page.Data, o)
+		return nil
+	}, it)
+}
+
+// Channel sends every remaining item in this iterator to the returned channel.
+//
+// The item channel will be closed after all items sent, the error channel will
+// receive the error returned by ForEach and be closed after that.
+func (it *PartIterator) Channel(ctx context.Context) (<-chan *Part, <-chan error) {
+	ch := make(chan *Part)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(ch)
+
+		err := it.ForEach(ctx, func(o *Part) error {
+			select {
+			case ch <- o:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return ch, errc
+}
+
+// MergePartIterators merges iterators sorted by less into one sorted iterator.
+//
+// Items which are equal will be returned in the order of input iterators.
+func MergePartIterators(ctx context.Context, less func(a, b *Part) bool, its ...*PartIterator) *PartIterator {
+	heads := make([]*Part, len(its))
+	// valid marks iterators that have a head, and stale marks iterators that need to fetch a new head.
+	valid := make([]bool, len(its))
+	stale := make([]bool, len(its))
+	for i := range stale {
+		stale[i] = true
+	}
+	return NewPartIterator(ctx, func(ctx context.Context, page *PartPage) error {
+		for i, x := range its {
+			if !stale[i] {
+				continue
+			}
+			o, err := x.fetch()
+			if err != nil && !errors.Is(err, IterateDone) {
+				return err
+			}
+			stale[i] = false
+			heads[i], valid[i] = o, err == nil
+		}
+
+		idx := -1
+		for i := range heads {
+			if valid[i] && (idx < 0 || less(heads[i], heads[idx])) {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			return IterateDone
+		}
+		page.Data = append(page.Data, heads[idx])
+		stale[idx] = true
+		return nil
+	}, nil)
+}
+
 // NextStoragerFunc is the func used in iterator.
 //
 // Notes
@@ -203,16 +947,44 @@ type StoragerIterator struct {
 	done  bool
 
 	o StoragerPage
+
+	// pages will be set while prefetching, and token is the continuation token of current page.
+	pages chan prefetchedStoragerPage
+	token string
+}
+
+// prefetchedStoragerPage is a page fetched in background.
+type prefetchedStoragerPage struct {
+	data  []Storager
+	token string
+	err   error
 }
 
 func NewStoragerIterator(ctx context.Context, next NextStoragerFunc, status Continuable) *StoragerIterator {
 	return &StoragerIterator{ctx: ctx, next: next, o: StoragerPage{Status: status}}
 }
 func (it *StoragerIterator) ContinuationToken() string {
+	// Status is owned by the prefetching goroutine, use the token of current page instead.
+	if it.pages != nil {
+		return it.token
+	}
+	// Iterators like merged iterator don't have status.
+	if it.o.Status == nil {
+		return ""
+	}
 	return it.o.Status.ContinuationToken()
 }
 func (it *StoragerIterator) Next() (object Storager, err error) {
-	// Consume Data via index.
+	object, err = it.fetch()
+	if err != nil && !errors.Is(err, IterateDone) {
+		return nil, fmt.Errorf("iterator next failed: %w", err)
+	}
+	return
+}
+
+// fetch returns the next item without wrapping the error, so that iterators built upon this one will
+// not wrap errors twice.
+func (it *StoragerIterator) fetch() (object Storager, err error) {
 	// Consume Data via index.
 	if it.index < len(it.o.Data) {
 		it.index++
@@ -226,9 +998,9 @@ func (it *StoragerIterator) Next() (object Storager, err error) {
 	// Reset buf before call next.
 	it.o.Data = it.o.Data[:0]
 
-	err = it.next(it.ctx, &it.o)
+	err = it.nextPage()
 	if err != nil && !errors.Is(err, IterateDone) {
-		return nil, fmt.Errorf("iterator next failed: %w", err)
+		return nil, err
 	}
 	// Make iterator to done so that we will not fetch from upstream anymore.
 	if err != nil {
@@ -242,3 +1014,223 @@ func (it *StoragerIterator) Next() (object Storager, err error) {
 	it.index = 1
 	return it.o.Data[0], nil
 }
+
+// nextPage fetches the next page into it.o.Data.
+func (it *StoragerIterator) nextPage() error {
+	if it.pages == nil {
+		return it.next(it.ctx, &it.o)
+	}
+
+	p, ok := <-it.pages
+	if !ok {
+		// Prefetching goroutine exits without sending page only if ctx is done.
+		it.done = true
+		if err := it.ctx.Err(); err != nil {
+			return err
+		}
+		return IterateDone
+	}
+	it.o.Data = p.data
+	it.token = p.token
+	if p.err != nil && !errors.Is(p.err, IterateDone) {
+		// Prefetching goroutine has exited, fallback to fetch pages synchronously
+		// so that the failed page could be retried.
+		it.pages = nil
+	}
+	return p.err
+}
+
+// Prefetch enables fetching pages in background while the caller consumes
+// current page, at most n pages will be fetched ahead.
+//
+// ContinuationToken always returns the token of the page being consumed. The
+// iterator itself is still not safe for concurrent use.
+//
+// The prefetching goroutine exits after all pages fetched, an error returned or
+// the iterator's context is done, so callers that stop iterating early should
+// cancel the context passed to list.
+func (it *StoragerIterator) Prefetch(n int) *StoragerIterator {
+	if n <= 0 || it.done || it.pages != nil {
+		return it
+	}
+
+	it.token = it.ContinuationToken()
+	pages := make(chan prefetchedStoragerPage, n)
+	go func() {
+		defer close(pages)
+
+		page := StoragerPage{Status: it.o.Status}
+		for {
+			err := it.next(it.ctx, &page)
+
+			p := prefetchedStoragerPage{data: page.Data, err: err}
+			if page.Status != nil {
+				p.token = page.Status.ContinuationToken()
+			}
+			select {
+			case pages <- p:
+			case <-it.ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+			// Data has been handed over, fetch into a new slice.
+			page.Data = nil
+		}
+	}()
+	it.pages = pages
+	return it
+}
+
+// Collect returns all remaining items in this iterator.
+func (it *StoragerIterator) Collect() ([]Storager, error) {
+	items := make([]Storager, 0)
+	for {
+		o, err := it.Next()
+		if errors.Is(err, IterateDone) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, o)
+	}
+}
+
+// ForEach calls fn for every remaining item in this iterator.
+//
+// ForEach stops at the first error returned by fn or while ctx is done.
+func (it *StoragerIterator) ForEach(ctx context.Context, fn func(Storager) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		o, err := it.Next()
+		if errors.Is(err, IterateDone) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = fn(o); err != nil {
+			return err
+		}
+	}
+}
+
+// Filter returns an iterator which only contains items that fn returns true.
+func (it *StoragerIterator) Filter(fn func(Storager) bool) *StoragerIterator {
+	return NewStoragerIterator(it.ctx, func(ctx context.Context, page *StoragerPage) error {
+		for {
+			o, err := it.fetch()
+			if err != nil {
+				return err
+			}
+			if fn(o) {
+				page.Data = append(page.Data, o)
+				return nil
+			}
+		}
+	}, it)
+}
+
+// Map returns an iterator which contains items converted by fn.
+func (it *StoragerIterator) Map(fn func(Storager) (Storager, error)) *StoragerIterator {
+	return NewStoragerIterator(it.ctx, func(ctx context.Context, page *StoragerPage) error {
+		o, err := it.fetch()
+		if err != nil {
+			return err
+		}
+		if o, err = fn(o); err != nil {
+			return err
+		}
+		page.Data = append(page.Data, o)
+		return nil
+	}, it)
+}
+
+// Take returns an iterator which contains at most n items.
+func (it *StoragerIterator) Take(n int) *StoragerIterator {
+	taken := 0
+	return NewStoragerIterator(it.ctx, func(ctx context.Context, page *StoragerPage) error {
+		if taken >= n {
+			return IterateDone
+		}
+		o, err := it.fetch()
+		if err != nil {
+			return err
+		}
+		taken++
+		page.Data = append(page.Data, o)
+		return nil
+	}, it)
+}
+
+// Channel sends every remaining item in this iterator to the returned channel.
+//
+// The item channel will be closed after all items sent, the error channel will
+// receive the error returned by ForEach and be closed after that.
+func (it *StoragerIterator) Channel(ctx context.Context) (<-chan Storager, <-chan error) {
+	ch := make(chan Storager)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(ch)
+
+		err := it.ForEach(ctx, func(o Storager) error {
+			select {
+			case ch <- o:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return ch, errc
+}
+
+// MergeStoragerIterators merges iterators sorted by less into one sorted iterator.
+//
+// Items which are equal will be returned in the order of input iterators.
+func MergeStoragerIterators(ctx context.Context, less func(a, b Storager) bool, its ...*StoragerIterator) *StoragerIterator {
+	heads := make([]Storager, len(its))
+	// valid marks iterators that have a head, and stale marks iterators that need to fetch a new head.
+	valid := make([]bool, len(its))
+	stale := make([]bool, len(its))
+	for i := range stale {
+		stale[i] = true
+	}
+	return NewStoragerIterator(ctx, func(ctx context.Context, page *StoragerPage) error {
+		for i, x := range its {
+			if !stale[i] {
+				continue
+			}
+			o, err := x.fetch()
+			if err != nil && !errors.Is(err, IterateDone) {
+				return err
+			}
+			stale[i] = false
+			heads[i], valid[i] = o, err == nil
+		}
+
+		idx := -1
+		for i := range heads {
+			if valid[i] && (idx < 0 || less(heads[i], heads[idx])) {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			return IterateDone
+		}
+		page.Data = append(page.Data, heads[idx])
+		stale[idx] = true
+		return nil
+	}, nil)
+}
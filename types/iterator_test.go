@@ -0,0 +1,207 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestObjectIterator returns an iterator which contains objects with paths, two objects per page.
+func newTestObjectIterator(paths ...string) *ObjectIterator {
+	fn := func(ctx context.Context, page *ObjectPage) error {
+		for i := 0; i < 2; i++ {
+			if len(paths) == 0 {
+				return IterateDone
+			}
+			page.Data = append(page.Data, &Object{Path: paths[0]})
+			paths = paths[1:]
+		}
+		return nil
+	}
+	return NewObjectIterator(context.Background(), fn, nil)
+}
+
+func objectPaths(t *testing.T, it *ObjectIterator) []string {
+	objects, err := it.Collect()
+	assert.Nil(t, err)
+
+	paths := make([]string, 0, len(objects))
+	for _, o := range objects {
+		paths = append(paths, o.Path)
+	}
+	return paths
+}
+
+func TestObjectIteratorCollect(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, objectPaths(t, newTestObjectIterator("a", "b", "c")))
+	assert.Equal(t, []string{}, objectPaths(t, newTestObjectIterator()))
+	assert.Equal(t, "", newTestObjectIterator().ContinuationToken())
+}
+
+func TestObjectIteratorFilterMapTake(t *testing.T) {
+	it := newTestObjectIterator("a.txt", "b.log", "c.txt", "d.txt", "e.txt").
+		Filter(func(o *Object) bool {
+			return strings.HasSuffix(o.Path, ".txt")
+		}).
+		Map(func(o *Object) (*Object, error) {
+			return &Object{Path: strings.ToUpper(o.Path)}, nil
+		}).
+		Take(3)
+	assert.Equal(t, []string{"A.TXT", "C.TXT", "D.TXT"}, objectPaths(t, it))
+}
+
+func TestObjectIteratorError(t *testing.T) {
+	expected := errors.New("map error")
+
+	it := newTestObjectIterator("a", "b").Map(func(o *Object) (*Object, error) {
+		return nil, expected
+	})
+	_, err := it.Next()
+	assert.ErrorIs(t, err, expected)
+	assert.Equal(t, "iterator next failed: map error", err.Error())
+}
+
+func TestObjectIteratorForEach(t *testing.T) {
+	paths := make([]string, 0)
+	err := newTestObjectIterator("a", "b", "c").ForEach(context.Background(), func(o *Object) error {
+		paths = append(paths, o.Path)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, paths)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = newTestObjectIterator("a").ForEach(ctx, func(o *Object) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestObjectIteratorChannel(t *testing.T) {
+	ch, errc := newTestObjectIterator("a", "b", "c").Channel(context.Background())
+
+	paths := make([]string, 0)
+	for o := range ch {
+		paths = append(paths, o.Path)
+	}
+	assert.Nil(t, <-errc)
+	assert.Equal(t, []string{"a", "b", "c"}, paths)
+}
+
+func TestMergeObjectIterators(t *testing.T) {
+	less := func(a, b *Object) bool {
+		return a.Path < b.Path
+	}
+
+	it := MergeObjectIterators(context.Background(), less,
+		newTestObjectIterator("a", "d", "e"),
+		newTestObjectIterator(),
+		newTestObjectIterator("b", "d", "f", "g"),
+		newTestObjectIterator("c"),
+	)
+	assert.Equal(t, []string{"a", "b", "c", "d", "d", "e", "f", "g"}, objectPaths(t, it))
+	assert.Equal(t, []string{}, objectPaths(t, MergeObjectIterators(context.Background(), less)))
+}
+
+// testPageStatus returns pages of two objects from paths, and records fetched pages.
+type testPageStatus struct {
+	paths   []string
+	offset  int
+	fetched chan int
+	// failed makes the next fetch fail once.
+	failed bool
+}
+
+func (s *testPageStatus) ContinuationToken() string {
+	if s.offset >= len(s.paths) {
+		return ""
+	}
+	return s.paths[s.offset]
+}
+
+func newTestPagedIterator(ctx context.Context, s *testPageStatus) *ObjectIterator {
+	fn := func(ctx context.Context, page *ObjectPage) error {
+		s := page.Status.(*testPageStatus)
+		if s.failed {
+			s.failed = false
+			return errors.New("fetch failed")
+		}
+		if s.fetched != nil {
+			s.fetched <- s.offset
+		}
+		for i := 0; i < 2 && s.offset < len(s.paths); i++ {
+			page.Data = append(page.Data, &Object{Path: s.paths[s.offset]})
+			s.offset++
+		}
+		if s.offset >= len(s.paths) {
+			return IterateDone
+		}
+		return nil
+	}
+	return NewObjectIterator(ctx, fn, s)
+}
+
+func TestObjectIteratorPrefetch(t *testing.T) {
+	paths := []string{"a", "b", "c", "d", "e"}
+
+	// Tokens while consuming should be the same as the synchronous iterator.
+	tokens := func(it *ObjectIterator) []string {
+		tokens := []string{it.ContinuationToken()}
+		for {
+			_, err := it.Next()
+			if errors.Is(err, IterateDone) {
+				return tokens
+			}
+			assert.Nil(t, err)
+			tokens = append(tokens, it.ContinuationToken())
+		}
+	}
+	expected := tokens(newTestPagedIterator(context.Background(), &testPageStatus{paths: paths}))
+	assert.Equal(t, []string{"a", "c", "c", "e", "e", ""}, expected)
+
+	it := newTestPagedIterator(context.Background(), &testPageStatus{paths: paths}).Prefetch(2)
+	assert.Equal(t, expected, tokens(it))
+
+	it = newTestPagedIterator(context.Background(), &testPageStatus{paths: paths}).Prefetch(1)
+	assert.Equal(t, paths, objectPaths(t, it))
+}
+
+func TestObjectIteratorPrefetchLookahead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := &testPageStatus{
+		paths:   []string{"a", "b", "c", "d", "e", "f", "g", "h"},
+		fetched: make(chan int),
+	}
+	it := newTestPagedIterator(ctx, s).Prefetch(1)
+
+	// One page buffered and one page in flight.
+	assert.Equal(t, 0, <-s.fetched)
+	assert.Equal(t, 2, <-s.fetched)
+	select {
+	case <-s.fetched:
+		t.Fatal("fetched more pages than lookahead")
+	default:
+	}
+
+	o, err := it.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "a", o.Path)
+	assert.Equal(t, 4, <-s.fetched)
+}
+
+func TestObjectIteratorPrefetchError(t *testing.T) {
+	s := &testPageStatus{paths: []string{"a", "b", "c"}, failed: true}
+	it := newTestPagedIterator(context.Background(), s).Prefetch(2)
+
+	_, err := it.Next()
+	assert.Error(t, err)
+
+	// Failed page could be retried after error.
+	assert.Equal(t, []string{"a", "b", "c"}, objectPaths(t, it))
+}
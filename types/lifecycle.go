@@ -0,0 +1,46 @@
+package types
+
+import "strings"
+
+// LifecycleRule is a service neutral lifecycle rule of a storage.
+//
+// A rule could contain multiple actions, services which only support one
+// action per rule will split it into multiple native rules.
+type LifecycleRule struct {
+	// ID is the unique identifier of this rule.
+	//
+	// Services which don't support rule id will ignore it, and ID will be
+	// empty while getting rules from them.
+	ID string
+	// Prefix limits this rule to objects whose path starts with it.
+	//
+	// Prefix is relative to the work dir of storager, empty Prefix means the
+	// rule applies to all objects under the work dir.
+	Prefix string
+	// Disabled means this rule is kept but not applied.
+	Disabled bool
+
+	// ExpireDays is the number of days after the last modified time of an
+	// object that it will be deleted, 0 means objects will not expire.
+	ExpireDays int
+	// Transitions are the storage class transitions of objects.
+	Transitions []LifecycleTransition
+	// AbortMultipartDays is the number of days after the initiation of an
+	// incomplete multipart upload that it will be aborted, 0 means multipart
+	// uploads will not be aborted.
+	AbortMultipartDays int
+}
+
+// LifecycleTransition transits objects into another storage class.
+type LifecycleTransition struct {
+	// Days is the number of days after the last modified time of an object
+	// that it will be transited.
+	Days int
+	// StorageClass is the service specific storage class to transit into.
+	StorageClass string
+}
+
+// Match checks whether the object at path is applied by this rule.
+func (r *LifecycleRule) Match(path string) bool {
+	return !r.Disabled && strings.HasPrefix(path, r.Prefix)
+}
@@ -21,8 +21,10 @@ const (
 	objectIndexMode               uint64 = 1 << 9
 	objectIndexMultipartID        uint64 = 1 << 10
 	objectIndexPath               uint64 = 1 << 11
-	objectIndexSystemMetadata     uint64 = 1 << 12
-	objectIndexUserMetadata       uint64 = 1 << 13
+	objectIndexRestoreStatus      uint64 = 1 << 12
+	objectIndexSystemMetadata     uint64 = 1 << 13
+	objectIndexUserMetadata       uint64 = 1 << 14
+	objectIndexVersionID          uint64 = 1 << 15
 )
 
 // Object is the smallest unit in go-storage.
@@ -56,10 +58,17 @@ type Object struct { // AppendOffset is the offset of the append object.
 	//
 	// Path SHOULD be Unix style.
 	Path string
+	// RestoreStatus is the restore status of an archived object.
+	//
+	// Available values are "archived", "ongoing" and "restored", see types.RestoreStatusArchived
+	// and so on.
+	restoreStatus string
 	// SystemMetadata stores system defined metadata.
 	systemMetadata interface{}
-	// UserMetadata stores user defined metadata.
+	// UserMetadata stores user defined metadata, keys are normalized into lower case.
 	userMetadata map[string]string
+	// VersionID is the version of the object.
+	versionID string
 	// client is the client in which Object is alive.
 	client Storager
 	// bit used as a bitmap for object value, 0 means not set, 1 means set
@@ -350,6 +359,48 @@ func (o *Object) SetPath(v string) *Object {
 	return o
 }
 
+// GetRestoreStatus will get RestoreStatus from Object.
+//
+// RestoreStatus is the restore status of an archived object.
+//
+// Available values are "archived", "ongoing" and "restored", see types.RestoreStatusArchived
+// and so on.
+func (o *Object) GetRestoreStatus() (string, bool) {
+	o.stat()
+
+	if o.bit&objectIndexRestoreStatus != 0 {
+		return o.restoreStatus, true
+	}
+	return "", false
+}
+
+// MustGetRestoreStatus will get RestoreStatus from Object.
+//
+// RestoreStatus is the restore status of an archived object.
+//
+// Available values are "archived", "ongoing" and "restored", see types.RestoreStatusArchived
+// and so on.
+func (o *Object) MustGetRestoreStatus() string {
+	o.stat()
+
+	if o.bit&objectIndexRestoreStatus == 0 {
+		panic(fmt.Sprintf("object restore_status is not set"))
+	}
+	return o.restoreStatus
+}
+
+// SetRestoreStatus will get RestoreStatus into Object.
+//
+// RestoreStatus is the restore status of an archived object.
+//
+// Available values are "archived", "ongoing" and "restored", see types.RestoreStatusArchived
+// and so on.
+func (o *Object) SetRestoreStatus(v string) *Object {
+	o.restoreStatus = v
+	o.bit |= objectIndexRestoreStatus
+	return o
+}
+
 // GetSystemMetadata will get SystemMetadata from Object.
 //
 // SystemMetadata stores system defined metadata.
@@ -385,7 +436,7 @@ func (o *Object) SetSystemMetadata(v interface{}) *Object {
 
 // GetUserMetadata will get UserMetadata from Object.
 //
-// UserMetadata stores user defined metadata.
+// UserMetadata stores user defined metadata, keys are normalized into lower case.
 func (o *Object) GetUserMetadata() (map[string]string, bool) {
 	o.stat()
 
@@ -397,7 +448,7 @@ func (o *Object) GetUserMetadata() (map[string]string, bool) {
 
 // MustGetUserMetadata will get UserMetadata from Object.
 //
-// UserMetadata stores user defined metadata.
+// UserMetadata stores user defined metadata, keys are normalized into lower case.
 func (o *Object) MustGetUserMetadata() map[string]string {
 	o.stat()
 
@@ -409,12 +460,45 @@ func (o *Object) MustGetUserMetadata() map[string]string {
 
 // SetUserMetadata will get UserMetadata into Object.
 //
-// UserMetadata stores user defined metadata.
+// UserMetadata stores user defined metadata, keys are normalized into lower case.
 func (o *Object) SetUserMetadata(v map[string]string) *Object {
 	o.userMetadata = v
 	o.bit |= objectIndexUserMetadata
 	return o
 }
+
+// GetVersionID will get VersionID from Object.
+//
+// VersionID is the version of the object.
+func (o *Object) GetVersionID() (string, bool) {
+	o.stat()
+
+	if o.bit&objectIndexVersionID != 0 {
+		return o.versionID, true
+	}
+	return "", false
+}
+
+// MustGetVersionID will get VersionID from Object.
+//
+// VersionID is the version of the object.
+func (o *Object) MustGetVersionID() string {
+	o.stat()
+
+	if o.bit&objectIndexVersionID == 0 {
+		panic(fmt.Sprintf("object version_id is not set"))
+	}
+	return o.versionID
+}
+
+// SetVersionID will get VersionID into Object.
+//
+// VersionID is the version of the object.
+func (o *Object) SetVersionID(v string) *Object {
+	o.versionID = v
+	o.bit |= objectIndexVersionID
+	return o
+}
 func (o *Object) clone(xo *Object) {
 	o.appendOffset = xo.appendOffset
 	o.contentDisposition = xo.contentDisposition
@@ -428,7 +512,9 @@ func (o *Object) clone(xo *Object) {
 	o.Mode = xo.Mode
 	o.multipartID = xo.multipartID
 	o.Path = xo.Path
+	o.restoreStatus = xo.restoreStatus
 	o.systemMetadata = xo.systemMetadata
 	o.userMetadata = xo.userMetadata
+	o.versionID = xo.versionID
 	o.bit = xo.bit
 }
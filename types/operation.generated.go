@@ -138,7 +138,7 @@ type Storager interface {
 	//
 	// - Copy only copy one and only one object.
 	//   - Service DON'T NEED to support copy a non-empty directory or copy files recursively.
-	//   - User NEED to implement copy a non-empty directory and copy recursively by themself.
+	//   - User NEED to implement copy a non-empty directory and copy recursively by themself, or use pkg/recursive.CopyAll.
 	//   - Copy a file to a directory SHOULD return ErrObjectModeInvalid.
 	// - Copy SHOULD NOT return an error as dst object exists.
 	//   - Service that has native support for overwrite doesn't NEED to check the dst object exists or not.
@@ -146,6 +146,7 @@ type Storager interface {
 	// if exists.
 	// - A successful copy opration should be complete, which means the dst object's content and metadata
 	// should be the same as src object.
+	//   - Copy SHOULD replace dst object's user metadata with the user_metadata pair if it's set.
 	Copy(src string, dst string, pairs ...Pair) (err error)
 	// CopyWithContext will copy an Object or multiple object in the service.
 	//
@@ -153,7 +154,7 @@ type Storager interface {
 	//
 	// - Copy only copy one and only one object.
 	//   - Service DON'T NEED to support copy a non-empty directory or copy files recursively.
-	//   - User NEED to implement copy a non-empty directory and copy recursively by themself.
+	//   - User NEED to implement copy a non-empty directory and copy recursively by themself, or use pkg/recursive.CopyAll.
 	//   - Copy a file to a directory SHOULD return ErrObjectModeInvalid.
 	// - Copy SHOULD NOT return an error as dst object exists.
 	//   - Service that has native support for overwrite doesn't NEED to check the dst object exists or not.
@@ -161,6 +162,7 @@ type Storager interface {
 	// if exists.
 	// - A successful copy opration should be complete, which means the dst object's content and metadata
 	// should be the same as src object.
+	//   - Copy SHOULD replace dst object's user metadata with the user_metadata pair if it's set.
 	CopyWithContext(ctx context.Context, src string, dst string, pairs ...Pair) (err error)
 
 	// Create will create a new object without any api call.
@@ -271,7 +273,7 @@ type Storager interface {
 	//
 	// - Delete only delete one and only one object.
 	//   - Service DON'T NEED to support remove all.
-	//   - User NEED to implement remove_all by themself.
+	//   - User NEED to implement remove_all by themself, or use pkg/recursive.RemoveAll.
 	// - Delete is idempotent.
 	//   - Successful delete always return nil error.
 	//   - Delete SHOULD never return ObjectNotExist
@@ -283,7 +285,7 @@ type Storager interface {
 	//
 	// - Delete only delete one and only one object.
 	//   - Service DON'T NEED to support remove all.
-	//   - User NEED to implement remove_all by themself.
+	//   - User NEED to implement remove_all by themself, or use pkg/recursive.RemoveAll.
 	// - Delete is idempotent.
 	//   - Successful delete always return nil error.
 	//   - Delete SHOULD never return ObjectNotExist
@@ -307,6 +309,25 @@ type Storager interface {
 	// should be the same as requiring from the url.
 	FetchWithContext(ctx context.Context, path string, url string, pairs ...Pair) (err error)
 
+	// GetLifecycle will get lifecycle rules of the storage.
+	//
+	// ## Behavior
+	//
+	// - GetLifecycle SHOULD return an empty slice without error if no rule has been set.
+	// - Prefix of returned rules SHOULD be relative to the work dir, and rules outside the work dir SHOULD
+	// be skipped.
+	// - Rules which could not be represented by LifecycleRule SHOULD be skipped.
+	GetLifecycle(pairs ...Pair) (rules []*LifecycleRule, err error)
+	// GetLifecycleWithContext will get lifecycle rules of the storage.
+	//
+	// ## Behavior
+	//
+	// - GetLifecycle SHOULD return an empty slice without error if no rule has been set.
+	// - Prefix of returned rules SHOULD be relative to the work dir, and rules outside the work dir SHOULD
+	// be skipped.
+	// - Rules which could not be represented by LifecycleRule SHOULD be skipped.
+	GetLifecycleWithContext(ctx context.Context, pairs ...Pair) (rules []*LifecycleRule, err error)
+
 	// List will return list a specific path.
 	//
 	// ## Behavior
@@ -334,6 +355,29 @@ type Storager interface {
 	// ListMultipartWithContext will list parts belong to this multipart.
 	ListMultipartWithContext(ctx context.Context, o *Object, pairs ...Pair) (pi *PartIterator, err error)
 
+	// ListVersion will list all versions of the object at path.
+	//
+	// ## Behavior
+	//
+	// - ListVersion SHOULD return versions from the newest to the oldest.
+	// - Every returned object SHOULD carry a VersionID which could be used in read, stat and delete via
+	// the version_id pair.
+	// - ListVersion SHOULD NOT return delete markers.
+	// - ListVersion SHOULD NOT return an error as the object doesn't exist, an empty iterator will be returned
+	// instead.
+	ListVersion(path string, pairs ...Pair) (oi *ObjectIterator, err error)
+	// ListVersionWithContext will list all versions of the object at path.
+	//
+	// ## Behavior
+	//
+	// - ListVersion SHOULD return versions from the newest to the oldest.
+	// - Every returned object SHOULD carry a VersionID which could be used in read, stat and delete via
+	// the version_id pair.
+	// - ListVersion SHOULD NOT return delete markers.
+	// - ListVersion SHOULD NOT return an error as the object doesn't exist, an empty iterator will be returned
+	// instead.
+	ListVersionWithContext(ctx context.Context, path string, pairs ...Pair) (oi *ObjectIterator, err error)
+
 	// Metadata will return current storager metadata.
 	Metadata(pairs ...Pair) (meta *StorageMeta)
 
@@ -343,7 +387,7 @@ type Storager interface {
 	//
 	// - Move only move one and only one object.
 	//   - Service DON'T NEED to support move a non-empty directory.
-	//   - User NEED to implement move a non-empty directory by themself.
+	//   - User NEED to implement move a non-empty directory by themself, or use pkg/recursive.MoveAll.
 	//   - Move a file to a directory SHOULD return ErrObjectModeInvalid.
 	// - Move SHOULD NOT return an error as dst object exists.
 	//   - Service that has native support for overwrite doesn't NEED to check the dst object exists or not.
@@ -358,7 +402,7 @@ type Storager interface {
 	//
 	// - Move only move one and only one object.
 	//   - Service DON'T NEED to support move a non-empty directory.
-	//   - User NEED to implement move a non-empty directory by themself.
+	//   - User NEED to implement move a non-empty directory by themself, or use pkg/recursive.MoveAll.
 	//   - Move a file to a directory SHOULD return ErrObjectModeInvalid.
 	// - Move SHOULD NOT return an error as dst object exists.
 	//   - Service that has native support for overwrite doesn't NEED to check the dst object exists or not.
@@ -420,6 +464,61 @@ type Storager interface {
 	// ReadWithContext will read the file's data.
 	ReadWithContext(ctx context.Context, path string, w io.Writer, pairs ...Pair) (n int64, err error)
 
+	// Restore will restore an archived object so that it could be read.
+	//
+	// ## Behavior
+	//
+	// - Restore SHOULD only start the restoration and return without waiting for it to finish.
+	// - Restore SHOULD NOT return an error if the object is being restored or has been restored.
+	// - Stat SHOULD return restore_status so that the progress of restoration could be checked.
+	Restore(path string, pairs ...Pair) (err error)
+	// RestoreWithContext will restore an archived object so that it could be read.
+	//
+	// ## Behavior
+	//
+	// - Restore SHOULD only start the restoration and return without waiting for it to finish.
+	// - Restore SHOULD NOT return an error if the object is being restored or has been restored.
+	// - Stat SHOULD return restore_status so that the progress of restoration could be checked.
+	RestoreWithContext(ctx context.Context, path string, pairs ...Pair) (err error)
+
+	// SetLifecycle will replace lifecycle rules of the storage.
+	//
+	// ## Behavior
+	//
+	// - SetLifecycle SHOULD replace all rules returned by GetLifecycle.
+	// - SetLifecycle SHOULD keep rules invisible to GetLifecycle untouched, like rules outside the
+	// work dir or not representable by LifecycleRule.
+	// - SetLifecycle with empty rules SHOULD remove all rules returned by GetLifecycle.
+	// - SetLifecycle SHOULD return an error wrapping ErrCapabilityInsufficient if any action of rules
+	// is not supported.
+	SetLifecycle(rules []*LifecycleRule, pairs ...Pair) (err error)
+	// SetLifecycleWithContext will replace lifecycle rules of the storage.
+	//
+	// ## Behavior
+	//
+	// - SetLifecycle SHOULD replace all rules returned by GetLifecycle.
+	// - SetLifecycle SHOULD keep rules invisible to GetLifecycle untouched, like rules outside the
+	// work dir or not representable by LifecycleRule.
+	// - SetLifecycle with empty rules SHOULD remove all rules returned by GetLifecycle.
+	// - SetLifecycle SHOULD return an error wrapping ErrCapabilityInsufficient if any action of rules
+	// is not supported.
+	SetLifecycleWithContext(ctx context.Context, rules []*LifecycleRule, pairs ...Pair) (err error)
+
+	// SetStorageClass will change the storage class of an existing object.
+	//
+	// ## Behavior
+	//
+	// - The content and user metadata of the object SHOULD NOT be changed.
+	// - Class is service specific, and it's the same as the storage_class pair of write.
+	SetStorageClass(path string, class string, pairs ...Pair) (err error)
+	// SetStorageClassWithContext will change the storage class of an existing object.
+	//
+	// ## Behavior
+	//
+	// - The content and user metadata of the object SHOULD NOT be changed.
+	// - Class is service specific, and it's the same as the storage_class pair of write.
+	SetStorageClassWithContext(ctx context.Context, path string, class string, pairs ...Pair) (err error)
+
 	// Stat will stat a path to get info of an object.
 	//
 	// ## Behavior
@@ -603,6 +702,14 @@ func (s UnimplementedStorager) FetchWithContext(ctx context.Context, path string
 	err = NewOperationNotImplementedError("fetch")
 	return
 }
+func (s UnimplementedStorager) GetLifecycle(pairs ...Pair) (rules []*LifecycleRule, err error) {
+	err = NewOperationNotImplementedError("get_lifecycle")
+	return
+}
+func (s UnimplementedStorager) GetLifecycleWithContext(ctx context.Context, pairs ...Pair) (rules []*LifecycleRule, err error) {
+	err = NewOperationNotImplementedError("get_lifecycle")
+	return
+}
 func (s UnimplementedStorager) List(path string, pairs ...Pair) (oi *ObjectIterator, err error) {
 	err = NewOperationNotImplementedError("list")
 	return
@@ -627,6 +734,14 @@ func (s UnimplementedStorager) ListMultipartWithContext(ctx context.Context, o *
 	err = NewOperationNotImplementedError("list_multipart")
 	return
 }
+func (s UnimplementedStorager) ListVersion(path string, pairs ...Pair) (oi *ObjectIterator, err error) {
+	err = NewOperationNotImplementedError("list_version")
+	return
+}
+func (s UnimplementedStorager) ListVersionWithContext(ctx context.Context, path string, pairs ...Pair) (oi *ObjectIterator, err error) {
+	err = NewOperationNotImplementedError("list_version")
+	return
+}
 func (s UnimplementedStorager) Metadata(pairs ...Pair) (meta *StorageMeta) {
 	return
 }
@@ -702,6 +817,30 @@ func (s UnimplementedStorager) ReadWithContext(ctx context.Context, path string,
 	err = NewOperationNotImplementedError("read")
 	return
 }
+func (s UnimplementedStorager) Restore(path string, pairs ...Pair) (err error) {
+	err = NewOperationNotImplementedError("restore")
+	return
+}
+func (s UnimplementedStorager) RestoreWithContext(ctx context.Context, path string, pairs ...Pair) (err error) {
+	err = NewOperationNotImplementedError("restore")
+	return
+}
+func (s UnimplementedStorager) SetLifecycle(rules []*LifecycleRule, pairs ...Pair) (err error) {
+	err = NewOperationNotImplementedError("set_lifecycle")
+	return
+}
+func (s UnimplementedStorager) SetLifecycleWithContext(ctx context.Context, rules []*LifecycleRule, pairs ...Pair) (err error) {
+	err = NewOperationNotImplementedError("set_lifecycle")
+	return
+}
+func (s UnimplementedStorager) SetStorageClass(path string, class string, pairs ...Pair) (err error) {
+	err = NewOperationNotImplementedError("set_storage_class")
+	return
+}
+func (s UnimplementedStorager) SetStorageClassWithContext(ctx context.Context, path string, class string, pairs ...Pair) (err error) {
+	err = NewOperationNotImplementedError("set_storage_class")
+	return
+}
 func (s UnimplementedStorager) Stat(path string, pairs ...Pair) (o *Object, err error) {
 	err = NewOperationNotImplementedError("stat")
 	return
@@ -766,9 +905,11 @@ type DefaultStoragePairs struct {
 	CreatePage                     []Pair
 	Delete                         []Pair
 	Fetch                          []Pair
+	GetLifecycle                   []Pair
 	List                           []Pair
 	ListBlock                      []Pair
 	ListMultipart                  []Pair
+	ListVersion                    []Pair
 	Metadata                       []Pair
 	Move                           []Pair
 	QuerySignHTTPCompleteMultipart []Pair
@@ -779,6 +920,9 @@ type DefaultStoragePairs struct {
 	QuerySignHTTPWrite             []Pair
 	QuerySignHTTPWriteMultipart    []Pair
 	Read                           []Pair
+	Restore                        []Pair
+	SetLifecycle                   []Pair
+	SetStorageClass                []Pair
 	Stat                           []Pair
 	Write                          []Pair
 	WriteAppend                    []Pair
@@ -817,6 +961,11 @@ type StorageFeatures struct {
 	WriteMultipart                 bool
 	CompleteMultipart              bool
 	ListMultipart                  bool
+	ListVersion                    bool
+	Restore                        bool
+	SetStorageClass                bool
+	GetLifecycle                   bool
+	SetLifecycle                   bool
 	CreatePage                     bool
 	WritePage                      bool
 	QuerySignHTTPRead              bool
@@ -886,6 +1035,16 @@ func (s StorageFeatures) Has(name string) bool {
 		return s.CompleteMultipart
 	case "list_multipart":
 		return s.ListMultipart
+	case "list_version":
+		return s.ListVersion
+	case "restore":
+		return s.Restore
+	case "set_storage_class":
+		return s.SetStorageClass
+	case "get_lifecycle":
+		return s.GetLifecycle
+	case "set_lifecycle":
+		return s.SetLifecycle
 	case "create_page":
 		return s.CreatePage
 	case "write_page":
@@ -0,0 +1,13 @@
+package types
+
+// All available restore status of archived objects.
+const (
+	// RestoreStatusArchived means the object is archived, and it needs to be
+	// restored before read.
+	RestoreStatusArchived = "archived"
+	// RestoreStatusOngoing means the restoration of the object is in progress.
+	RestoreStatusOngoing = "ongoing"
+	// RestoreStatusRestored means the object has been restored and could be
+	// read until the restored copy expires.
+	RestoreStatusRestored = "restored"
+)